@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN section_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN translation_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE section ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_content_translation_id ON content(translation_id);
+
+-- +migrate Down
+DROP INDEX idx_content_translation_id;
+ALTER TABLE section DROP COLUMN lang;
+ALTER TABLE content DROP COLUMN translation_id;
+ALTER TABLE content DROP COLUMN lang;
+ALTER TABLE content DROP COLUMN section_id;
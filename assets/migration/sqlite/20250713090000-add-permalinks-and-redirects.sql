@@ -0,0 +1,23 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN published_at TIMESTAMP;
+ALTER TABLE section ADD COLUMN permalink TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE redirect (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    source_path TEXT NOT NULL UNIQUE,
+    target_path TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 301,
+    auto INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE redirect;
+ALTER TABLE section DROP COLUMN permalink;
+ALTER TABLE content DROP COLUMN published_at;
+ALTER TABLE content DROP COLUMN slug;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE redirect ADD COLUMN wildcard INTEGER NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE redirect DROP COLUMN wildcard;
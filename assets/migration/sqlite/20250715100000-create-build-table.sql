@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE build (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    trigger TEXT NOT NULL DEFAULT '',
+    user_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    pages_rendered INTEGER NOT NULL DEFAULT 0,
+    pages_skipped INTEGER NOT NULL DEFAULT 0,
+    pages_deleted INTEGER NOT NULL DEFAULT 0,
+    warnings TEXT NOT NULL DEFAULT '[]',
+    errors TEXT NOT NULL DEFAULT '[]',
+    output_hash TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_build_started_at ON build(started_at);
+
+-- +migrate Down
+DROP INDEX idx_build_started_at;
+DROP TABLE build;
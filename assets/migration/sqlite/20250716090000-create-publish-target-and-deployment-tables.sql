@@ -0,0 +1,45 @@
+-- +migrate Up
+CREATE TABLE publish_target (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL UNIQUE,
+    environment TEXT NOT NULL DEFAULT 'staging',
+    base_url TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT '',
+    settings TEXT NOT NULL DEFAULT '{}',
+    credentials_enc BLOB,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE deployment (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    target_id TEXT NOT NULL DEFAULT '',
+    target_name TEXT NOT NULL DEFAULT '',
+    environment TEXT NOT NULL DEFAULT '',
+    build_id TEXT NOT NULL DEFAULT '',
+    user_id TEXT NOT NULL DEFAULT '',
+    promoted_from TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    changes INTEGER NOT NULL DEFAULT 0,
+    ref TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_deployment_target_id ON deployment(target_id);
+CREATE INDEX idx_deployment_build_id ON deployment(build_id);
+
+-- +migrate Down
+DROP INDEX idx_deployment_build_id;
+DROP INDEX idx_deployment_target_id;
+DROP TABLE deployment;
+DROP TABLE publish_target;
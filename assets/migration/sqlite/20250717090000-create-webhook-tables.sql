@@ -0,0 +1,43 @@
+-- +migrate Up
+CREATE TABLE webhook (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    events TEXT NOT NULL DEFAULT '[]',
+    active INTEGER NOT NULL DEFAULT 1,
+    secret_enc BLOB,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE webhook_delivery (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    webhook_id TEXT NOT NULL DEFAULT '',
+    webhook_name TEXT NOT NULL DEFAULT '',
+    event_id TEXT NOT NULL DEFAULT '',
+    event TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    attempt INTEGER NOT NULL DEFAULT 1,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    payload TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_delivery_webhook_id ON webhook_delivery(webhook_id);
+CREATE INDEX idx_webhook_delivery_delivered_at ON webhook_delivery(delivered_at);
+
+-- +migrate Down
+DROP INDEX idx_webhook_delivery_delivered_at;
+DROP INDEX idx_webhook_delivery_webhook_id;
+DROP TABLE webhook_delivery;
+DROP TABLE webhook;
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE api_token (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    scopes TEXT NOT NULL DEFAULT '[]',
+    prefix TEXT NOT NULL DEFAULT '',
+    token_hash TEXT NOT NULL UNIQUE,
+    user_id TEXT NOT NULL DEFAULT '',
+    last_used_at TIMESTAMP,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE api_idempotency_key (
+    token_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    body BLOB,
+    created_at TIMESTAMP,
+    PRIMARY KEY (token_id, key)
+);
+
+CREATE INDEX idx_api_idempotency_key_created_at ON api_idempotency_key(created_at);
+
+-- +migrate Down
+DROP INDEX idx_api_idempotency_key_created_at;
+DROP TABLE api_idempotency_key;
+DROP TABLE api_token;
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE term (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT 'tag',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (kind, slug)
+);
+
+CREATE TABLE content_term (
+    content_id TEXT NOT NULL,
+    term_id TEXT NOT NULL,
+    PRIMARY KEY (content_id, term_id)
+);
+
+CREATE INDEX idx_content_term_term_id ON content_term(term_id);
+
+-- +migrate Down
+DROP INDEX idx_content_term_term_id;
+DROP TABLE content_term;
+DROP TABLE term;
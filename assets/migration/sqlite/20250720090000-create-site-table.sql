@@ -0,0 +1,171 @@
+-- +migrate Up
+CREATE TABLE site (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    org_id TEXT NOT NULL DEFAULT '',
+    team_id TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- Everything created so far belongs to the default site.
+INSERT INTO site (id, short_id, name, slug, description, created_at, updated_at)
+VALUES ('6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01', '5a1b3e7f9c01', 'Default', 'default', '', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+ALTER TABLE content ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE section ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE layout ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE build ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE deployment ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+
+CREATE INDEX idx_content_site_id ON content(site_id);
+CREATE INDEX idx_section_site_id ON section(site_id);
+CREATE INDEX idx_layout_site_id ON layout(site_id);
+CREATE INDEX idx_build_site_id ON build(site_id);
+CREATE INDEX idx_deployment_site_id ON deployment(site_id);
+
+-- Tables with unique columns are rebuilt so that uniqueness is per site.
+CREATE TABLE redirect_site (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    source_path TEXT NOT NULL,
+    target_path TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 301,
+    wildcard INTEGER NOT NULL DEFAULT 0,
+    auto INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, source_path)
+);
+
+INSERT INTO redirect_site (id, short_id, source_path, target_path, status_code, wildcard, auto, created_by, updated_by, created_at, updated_at)
+SELECT id, short_id, source_path, target_path, status_code, wildcard, auto, created_by, updated_by, created_at, updated_at FROM redirect;
+
+DROP TABLE redirect;
+ALTER TABLE redirect_site RENAME TO redirect;
+
+CREATE TABLE publish_target_site (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    name TEXT NOT NULL,
+    environment TEXT NOT NULL DEFAULT 'staging',
+    base_url TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT '',
+    settings TEXT NOT NULL DEFAULT '{}',
+    credentials_enc BLOB,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, name)
+);
+
+INSERT INTO publish_target_site (id, short_id, name, environment, base_url, kind, settings, credentials_enc, created_by, updated_by, created_at, updated_at)
+SELECT id, short_id, name, environment, base_url, kind, settings, credentials_enc, created_by, updated_by, created_at, updated_at FROM publish_target;
+
+DROP TABLE publish_target;
+ALTER TABLE publish_target_site RENAME TO publish_target;
+
+CREATE TABLE term_site (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    kind TEXT NOT NULL DEFAULT 'tag',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, kind, slug)
+);
+
+INSERT INTO term_site (id, short_id, kind, name, slug, created_by, updated_by, created_at, updated_at)
+SELECT id, short_id, kind, name, slug, created_by, updated_by, created_at, updated_at FROM term;
+
+DROP TABLE term;
+ALTER TABLE term_site RENAME TO term;
+
+-- +migrate Down
+CREATE TABLE term_all (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT 'tag',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (kind, slug)
+);
+
+INSERT OR IGNORE INTO term_all (id, short_id, kind, name, slug, created_by, updated_by, created_at, updated_at)
+SELECT id, short_id, kind, name, slug, created_by, updated_by, created_at, updated_at FROM term;
+
+DROP TABLE term;
+ALTER TABLE term_all RENAME TO term;
+
+CREATE TABLE publish_target_all (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL UNIQUE,
+    environment TEXT NOT NULL DEFAULT 'staging',
+    base_url TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT '',
+    settings TEXT NOT NULL DEFAULT '{}',
+    credentials_enc BLOB,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+INSERT OR IGNORE INTO publish_target_all (id, short_id, name, environment, base_url, kind, settings, credentials_enc, created_by, updated_by, created_at, updated_at)
+SELECT id, short_id, name, environment, base_url, kind, settings, credentials_enc, created_by, updated_by, created_at, updated_at FROM publish_target;
+
+DROP TABLE publish_target;
+ALTER TABLE publish_target_all RENAME TO publish_target;
+
+CREATE TABLE redirect_all (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    source_path TEXT NOT NULL UNIQUE,
+    target_path TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 301,
+    auto INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    wildcard INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT OR IGNORE INTO redirect_all (id, short_id, source_path, target_path, status_code, auto, created_by, updated_by, created_at, updated_at, wildcard)
+SELECT id, short_id, source_path, target_path, status_code, auto, created_by, updated_by, created_at, updated_at, wildcard FROM redirect;
+
+DROP TABLE redirect;
+ALTER TABLE redirect_all RENAME TO redirect;
+
+DROP INDEX idx_deployment_site_id;
+DROP INDEX idx_build_site_id;
+DROP INDEX idx_layout_site_id;
+DROP INDEX idx_section_site_id;
+DROP INDEX idx_content_site_id;
+
+ALTER TABLE deployment DROP COLUMN site_id;
+ALTER TABLE build DROP COLUMN site_id;
+ALTER TABLE layout DROP COLUMN site_id;
+ALTER TABLE section DROP COLUMN site_id;
+ALTER TABLE content DROP COLUMN site_id;
+
+DROP TABLE site;
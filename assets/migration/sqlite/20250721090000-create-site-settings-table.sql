@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE site_settings (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL UNIQUE,
+    title TEXT NOT NULL DEFAULT '',
+    base_url TEXT NOT NULL DEFAULT '',
+    default_lang TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL DEFAULT '',
+    author_email TEXT NOT NULL DEFAULT '',
+    author_url TEXT NOT NULL DEFAULT '',
+    social_links TEXT NOT NULL DEFAULT '[]',
+    analytics TEXT NOT NULL DEFAULT '',
+    date_format TEXT NOT NULL DEFAULT 'January 2, 2006',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- The default site keeps the title its footer showed so far.
+INSERT INTO site_settings (id, short_id, site_id, title, default_lang, created_at, updated_at)
+VALUES ('0b7e4d2a-3c1f-4e8b-9a6d-2f5c8e1b4a70', '2f5c8e1b4a70', '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01', 'Hermes', 'en', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+UPDATE layout SET code = REPLACE(code, '<p>&copy; 2025 Hermes</p>', '<p>&copy; {{ .Site.Year }} {{ .Site.Title }}</p>');
+
+-- +migrate Down
+UPDATE layout SET code = REPLACE(code, '<p>&copy; {{ .Site.Year }} {{ .Site.Title }}</p>', '<p>&copy; 2025 Hermes</p>');
+
+DROP TABLE site_settings;
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE content_type (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    fields TEXT NOT NULL DEFAULT '[]',
+    layout_id TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, slug)
+);
+
+ALTER TABLE content ADD COLUMN type_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN fields TEXT NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_content_type_id ON content(type_id);
+
+-- +migrate Down
+DROP INDEX idx_content_type_id;
+
+ALTER TABLE content DROP COLUMN fields;
+ALTER TABLE content DROP COLUMN type_id;
+
+DROP TABLE content_type;
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE data_file (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    name TEXT NOT NULL DEFAULT '',
+    format TEXT NOT NULL DEFAULT 'json',
+    content TEXT NOT NULL DEFAULT '',
+    schema TEXT NOT NULL DEFAULT '[]',
+    source TEXT NOT NULL DEFAULT 'db',
+    path TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, name)
+);
+
+ALTER TABLE build ADD COLUMN data_hashes TEXT NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE build DROP COLUMN data_hashes;
+
+DROP TABLE data_file;
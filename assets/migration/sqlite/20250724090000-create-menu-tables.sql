@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE menu (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, name)
+);
+
+CREATE TABLE menu_item (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    menu_id TEXT NOT NULL,
+    parent_id TEXT NOT NULL DEFAULT '',
+    label TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT 'url',
+    target_id TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_menu_item_menu_id ON menu_item(menu_id);
+
+-- +migrate Down
+DROP INDEX idx_menu_item_menu_id;
+DROP TABLE menu_item;
+DROP TABLE menu;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN toc_min_depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE content ADD COLUMN toc_max_depth INTEGER NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE content DROP COLUMN toc_max_depth;
+ALTER TABLE content DROP COLUMN toc_min_depth;
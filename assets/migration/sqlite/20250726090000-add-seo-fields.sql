@@ -0,0 +1,33 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN meta_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN meta_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN canonical_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN social_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN noindex INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE section ADD COLUMN meta_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE section ADD COLUMN meta_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE section ADD COLUMN canonical_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE section ADD COLUMN social_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE section ADD COLUMN noindex INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE site_settings ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE site_settings ADD COLUMN social_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE site_settings ADD COLUMN logo TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE site_settings DROP COLUMN logo;
+ALTER TABLE site_settings DROP COLUMN social_image;
+ALTER TABLE site_settings DROP COLUMN description;
+
+ALTER TABLE section DROP COLUMN noindex;
+ALTER TABLE section DROP COLUMN social_image;
+ALTER TABLE section DROP COLUMN canonical_url;
+ALTER TABLE section DROP COLUMN meta_description;
+ALTER TABLE section DROP COLUMN meta_title;
+
+ALTER TABLE content DROP COLUMN noindex;
+ALTER TABLE content DROP COLUMN social_image;
+ALTER TABLE content DROP COLUMN canonical_url;
+ALTER TABLE content DROP COLUMN meta_description;
+ALTER TABLE content DROP COLUMN meta_title;
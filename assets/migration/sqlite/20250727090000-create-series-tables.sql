@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE series (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, slug)
+);
+
+CREATE TABLE series_part (
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    series_id TEXT NOT NULL,
+    content_id TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (site_id, content_id)
+);
+
+CREATE INDEX idx_series_part_series_id ON series_part(series_id);
+
+-- +migrate Down
+DROP INDEX idx_series_part_series_id;
+DROP TABLE series_part;
+DROP TABLE series;
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE profile (
+    id TEXT PRIMARY KEY,
+    short_id TEXT NOT NULL DEFAULT '',
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    user_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    slug TEXT NOT NULL DEFAULT '',
+    bio TEXT NOT NULL DEFAULT '',
+    avatar TEXT NOT NULL DEFAULT '',
+    links TEXT NOT NULL DEFAULT '[]',
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (site_id, slug)
+);
+
+CREATE TABLE content_author (
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    content_id TEXT NOT NULL,
+    profile_id TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (site_id, content_id, profile_id)
+);
+
+CREATE INDEX idx_content_author_profile_id ON content_author(profile_id);
+
+-- +migrate Down
+DROP INDEX idx_content_author_profile_id;
+DROP TABLE content_author;
+DROP TABLE profile;
@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE content_lock (
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    content_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (site_id, content_id)
+);
+
+-- +migrate Down
+DROP TABLE content_lock;
+ALTER TABLE content DROP COLUMN version;
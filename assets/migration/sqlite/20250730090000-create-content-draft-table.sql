@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE content_draft (
+    site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01',
+    content_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    form TEXT NOT NULL DEFAULT '',
+    saved_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (site_id, content_id, user_id)
+);
+
+-- +migrate Down
+DROP TABLE content_draft;
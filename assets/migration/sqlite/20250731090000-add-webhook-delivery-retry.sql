@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE webhook_delivery ADD COLUMN retry_at TIMESTAMP;
+
+CREATE INDEX idx_webhook_delivery_retry_at ON webhook_delivery(retry_at);
+
+-- +migrate Down
+DROP INDEX idx_webhook_delivery_retry_at;
+ALTER TABLE webhook_delivery DROP COLUMN retry_at;
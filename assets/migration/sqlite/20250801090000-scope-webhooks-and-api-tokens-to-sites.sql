@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Webhooks and API tokens created so far belong to the default site.
+ALTER TABLE webhook ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE webhook_delivery ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE api_token ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+ALTER TABLE api_idempotency_key ADD COLUMN site_id TEXT NOT NULL DEFAULT '6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01';
+
+CREATE INDEX idx_webhook_site_id ON webhook(site_id);
+CREATE INDEX idx_webhook_delivery_site_id ON webhook_delivery(site_id);
+CREATE INDEX idx_api_token_site_id ON api_token(site_id);
+
+-- +migrate Down
+DROP INDEX idx_api_token_site_id;
+DROP INDEX idx_webhook_delivery_site_id;
+DROP INDEX idx_webhook_site_id;
+
+ALTER TABLE api_idempotency_key DROP COLUMN site_id;
+ALTER TABLE api_token DROP COLUMN site_id;
+ALTER TABLE webhook_delivery DROP COLUMN site_id;
+ALTER TABLE webhook DROP COLUMN site_id;
@@ -0,0 +1,24 @@
+-- Res: IdempotencyKey
+-- Table: api_idempotency_key
+
+-- Create
+INSERT INTO api_idempotency_key (
+    token_id, site_id, key, request_hash, status_code, body, created_at
+) VALUES (
+    :token_id, :site_id, :key, :request_hash, :status_code, :body, :created_at
+) ON CONFLICT (token_id, key) DO NOTHING;
+
+-- Get
+SELECT * FROM api_idempotency_key WHERE token_id = :token_id AND key = :key AND site_id = :site_id;
+
+-- Update
+UPDATE api_idempotency_key SET
+    status_code = :status_code,
+    body = :body
+WHERE token_id = :token_id AND key = :key AND site_id = :site_id;
+
+-- Delete
+DELETE FROM api_idempotency_key WHERE token_id = :token_id AND key = :key AND site_id = :site_id;
+
+-- DeleteExpired
+DELETE FROM api_idempotency_key WHERE created_at < :created_at;
@@ -0,0 +1,21 @@
+-- Res: APIToken
+-- Table: api_token
+
+-- Create
+INSERT INTO api_token (
+    id, short_id, site_id, name, scopes, prefix, token_hash, user_id, last_used_at, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :scopes, :prefix, :token_hash, :user_id, :last_used_at, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM api_token WHERE site_id = :site_id ORDER BY name;
+
+-- GetByHash
+SELECT * FROM api_token WHERE token_hash = :token_hash;
+
+-- Touch
+UPDATE api_token SET last_used_at = :last_used_at WHERE id = :id;
+
+-- Delete
+DELETE FROM api_token WHERE id = :id AND site_id = :site_id;
@@ -0,0 +1,31 @@
+-- Res: Build
+-- Table: build
+
+-- Create
+INSERT INTO build (
+    id, short_id, site_id, trigger, user_id, status, started_at, finished_at, duration_ms, pages_rendered, pages_skipped, pages_deleted, warnings, errors, output_hash, data_hashes, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :trigger, :user_id, :status, :started_at, :finished_at, :duration_ms, :pages_rendered, :pages_skipped, :pages_deleted, :warnings, :errors, :output_hash, :data_hashes, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM build WHERE site_id = :site_id ORDER BY started_at DESC;
+
+-- Get
+SELECT * FROM build WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE build SET
+    status = :status,
+    finished_at = :finished_at,
+    duration_ms = :duration_ms,
+    pages_rendered = :pages_rendered,
+    pages_skipped = :pages_skipped,
+    pages_deleted = :pages_deleted,
+    warnings = :warnings,
+    errors = :errors,
+    output_hash = :output_hash,
+    data_hashes = :data_hashes,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
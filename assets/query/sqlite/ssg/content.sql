@@ -3,22 +3,40 @@
 
 -- Create
 INSERT INTO content (
-    id, short_id, user_id, heading, body, status, created_by, updated_by, created_at, updated_at
+    id, short_id, site_id, user_id, section_id, type_id, fields, toc_min_depth, toc_max_depth, meta_title, meta_description, canonical_url, social_image, noindex, lang, translation_id, slug, heading, body, status, published_at, created_by, updated_by, created_at, updated_at
 ) VALUES (
-    :id, :short_id, :user_id, :heading, :body, :status, :created_by, :updated_by, :created_at, :updated_at
+    :id, :short_id, :site_id, :user_id, :section_id, :type_id, :fields, :toc_min_depth, :toc_max_depth, :meta_title, :meta_description, :canonical_url, :social_image, :noindex, :lang, :translation_id, :slug, :heading, :body, :status, :published_at, :created_by, :updated_by, :created_at, :updated_at
 );
 
 -- GetAll
-SELECT * FROM content;
+SELECT * FROM content WHERE site_id = :site_id;
 
 -- Get
-SELECT * FROM content WHERE id = :id;
+SELECT * FROM content WHERE id = :id AND site_id = :site_id;
+
+-- GetTranslations
+SELECT * FROM content WHERE (translation_id = :translation_id OR id = :translation_id) AND site_id = :site_id;
 
 -- Update
 UPDATE content SET
+    section_id = :section_id,
+    type_id = :type_id,
+    fields = :fields,
+    toc_min_depth = :toc_min_depth,
+    toc_max_depth = :toc_max_depth,
+    meta_title = :meta_title,
+    meta_description = :meta_description,
+    canonical_url = :canonical_url,
+    social_image = :social_image,
+    noindex = :noindex,
+    lang = :lang,
+    translation_id = :translation_id,
+    slug = :slug,
     heading = :heading,
     body = :body,
     status = :status,
+    published_at = :published_at,
     updated_by = :updated_by,
-    updated_at = :updated_at
-WHERE id = :id;
\ No newline at end of file
+    updated_at = :updated_at,
+    version = version + 1
+WHERE id = :id AND site_id = :site_id AND version = :version;
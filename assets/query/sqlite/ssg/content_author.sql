@@ -0,0 +1,14 @@
+-- Res: ContentAuthor
+-- Table: content_author
+
+-- Create
+INSERT INTO content_author (site_id, content_id, profile_id, position) VALUES (:site_id, :content_id, :profile_id, :position);
+
+-- GetAll
+SELECT content_id, profile_id, position FROM content_author WHERE site_id = :site_id ORDER BY content_id, position;
+
+-- DeleteByContent
+DELETE FROM content_author WHERE content_id = :content_id AND site_id = :site_id;
+
+-- DeleteByProfile
+DELETE FROM content_author WHERE profile_id = :profile_id AND site_id = :site_id;
@@ -0,0 +1,16 @@
+-- Res: ContentDraft
+-- Table: content_draft
+
+-- Save
+INSERT INTO content_draft (site_id, content_id, user_id, version, form, saved_at)
+VALUES (:site_id, :content_id, :user_id, :version, :form, :saved_at)
+ON CONFLICT (site_id, content_id, user_id) DO UPDATE SET
+    version = excluded.version,
+    form = excluded.form,
+    saved_at = excluded.saved_at;
+
+-- Get
+SELECT content_id, user_id, version, form, saved_at FROM content_draft WHERE content_id = :content_id AND user_id = :user_id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM content_draft WHERE content_id = :content_id AND user_id = :user_id AND site_id = :site_id;
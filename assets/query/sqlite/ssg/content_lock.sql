@@ -0,0 +1,20 @@
+-- Res: ContentLock
+-- Table: content_lock
+
+-- Acquire
+INSERT INTO content_lock (site_id, content_id, user_id, acquired_at, expires_at)
+VALUES (:site_id, :content_id, :user_id, :acquired_at, :expires_at)
+ON CONFLICT (site_id, content_id) DO UPDATE SET
+    user_id = excluded.user_id,
+    acquired_at = CASE WHEN content_lock.user_id = excluded.user_id THEN content_lock.acquired_at ELSE excluded.acquired_at END,
+    expires_at = excluded.expires_at
+WHERE content_lock.user_id = excluded.user_id OR content_lock.expires_at <= excluded.acquired_at;
+
+-- Get
+SELECT content_id, user_id, acquired_at, expires_at FROM content_lock WHERE content_id = :content_id AND site_id = :site_id;
+
+-- GetAll
+SELECT content_id, user_id, acquired_at, expires_at FROM content_lock WHERE site_id = :site_id;
+
+-- Release
+DELETE FROM content_lock WHERE content_id = :content_id AND user_id = :user_id AND site_id = :site_id;
@@ -0,0 +1,26 @@
+-- Res: ContentType
+-- Table: content_type
+
+-- Create
+INSERT INTO content_type (
+    id, short_id, site_id, name, slug, description, fields, layout_id, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :slug, :description, :fields, :layout_id, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM content_type WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM content_type WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE content_type SET
+    name = :name,
+    slug = :slug,
+    description = :description,
+    fields = :fields,
+    layout_id = :layout_id,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
@@ -0,0 +1,30 @@
+-- Res: DataFile
+-- Table: data_file
+
+-- Create
+INSERT INTO data_file (
+    id, short_id, site_id, name, format, content, schema, source, path, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :format, :content, :schema, :source, :path, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM data_file WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM data_file WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE data_file SET
+    name = :name,
+    format = :format,
+    content = :content,
+    schema = :schema,
+    source = :source,
+    path = :path,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM data_file WHERE id = :id AND site_id = :site_id;
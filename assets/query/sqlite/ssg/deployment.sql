@@ -0,0 +1,26 @@
+-- Res: Deployment
+-- Table: deployment
+
+-- Create
+INSERT INTO deployment (
+    id, short_id, site_id, target_id, target_name, environment, build_id, user_id, promoted_from, status, changes, ref, error, started_at, finished_at, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :target_id, :target_name, :environment, :build_id, :user_id, :promoted_from, :status, :changes, :ref, :error, :started_at, :finished_at, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- Update
+UPDATE deployment SET
+    status = :status,
+    changes = :changes,
+    ref = :ref,
+    error = :error,
+    finished_at = :finished_at,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- GetByTarget
+SELECT * FROM deployment WHERE target_id = :target_id AND site_id = :site_id ORDER BY started_at DESC;
+
+-- GetByBuild
+SELECT * FROM deployment WHERE build_id = :build_id AND site_id = :site_id ORDER BY started_at DESC;
@@ -3,13 +3,13 @@
 
 -- Create
 INSERT INTO layout (
-    id, short_id, name, description, code, created_by, updated_by, created_at, updated_at
+    id, short_id, site_id, name, description, code, created_by, updated_by, created_at, updated_at
 ) VALUES (
-    :id, :short_id, :name, :description, :code, :created_by, :updated_by, :created_at, :updated_at
+    :id, :short_id, :site_id, :name, :description, :code, :created_by, :updated_by, :created_at, :updated_at
 );
 
 -- GetAll
-SELECT * FROM layout;
+SELECT * FROM layout WHERE site_id = :site_id;
 
 -- Get
-SELECT * FROM layout WHERE id = :id;
+SELECT * FROM layout WHERE id = :id AND site_id = :site_id;
@@ -0,0 +1,26 @@
+-- Res: Menu
+-- Table: menu
+
+-- Create
+INSERT INTO menu (
+    id, short_id, site_id, name, description, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :description, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM menu WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM menu WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE menu SET
+    name = :name,
+    description = :description,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM menu WHERE id = :id AND site_id = :site_id;
@@ -0,0 +1,36 @@
+-- Res: MenuItem
+-- Table: menu_item
+
+-- Create
+INSERT INTO menu_item (
+    id, short_id, site_id, menu_id, parent_id, label, kind, target_id, url, position, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :menu_id, :parent_id, :label, :kind, :target_id, :url, :position, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM menu_item WHERE site_id = :site_id ORDER BY menu_id, position, label;
+
+-- Get
+SELECT * FROM menu_item WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE menu_item SET
+    parent_id = :parent_id,
+    label = :label,
+    kind = :kind,
+    target_id = :target_id,
+    url = :url,
+    position = :position,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Move
+UPDATE menu_item SET parent_id = :parent_id, position = :position, updated_at = :updated_at WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM menu_item WHERE id = :id AND site_id = :site_id;
+
+-- DeleteByMenu
+DELETE FROM menu_item WHERE menu_id = :menu_id AND site_id = :site_id;
@@ -0,0 +1,30 @@
+-- Res: Profile
+-- Table: profile
+
+-- Create
+INSERT INTO profile (
+    id, short_id, site_id, user_id, name, slug, bio, avatar, links, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :user_id, :name, :slug, :bio, :avatar, :links, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM profile WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM profile WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE profile SET
+    user_id = :user_id,
+    name = :name,
+    slug = :slug,
+    bio = :bio,
+    avatar = :avatar,
+    links = :links,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM profile WHERE id = :id AND site_id = :site_id;
@@ -0,0 +1,33 @@
+-- Res: PublishTarget
+-- Table: publish_target
+
+-- Create
+INSERT INTO publish_target (
+    id, short_id, site_id, name, environment, base_url, kind, settings, credentials_enc, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :environment, :base_url, :kind, :settings, :credentials_enc, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM publish_target WHERE site_id = :site_id ORDER BY environment, name;
+
+-- Get
+SELECT * FROM publish_target WHERE id = :id AND site_id = :site_id;
+
+-- GetByName
+SELECT * FROM publish_target WHERE name = :name AND site_id = :site_id;
+
+-- Update
+UPDATE publish_target SET
+    name = :name,
+    environment = :environment,
+    base_url = :base_url,
+    kind = :kind,
+    settings = :settings,
+    credentials_enc = :credentials_enc,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM publish_target WHERE id = :id AND site_id = :site_id;
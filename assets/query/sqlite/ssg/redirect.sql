@@ -0,0 +1,35 @@
+-- Res: Redirect
+-- Table: redirect
+
+-- Create
+INSERT INTO redirect (
+    id, short_id, site_id, source_path, target_path, status_code, wildcard, auto, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :source_path, :target_path, :status_code, :wildcard, :auto, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM redirect WHERE site_id = :site_id ORDER BY source_path;
+
+-- Get
+SELECT * FROM redirect WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE redirect SET
+    source_path = :source_path,
+    target_path = :target_path,
+    status_code = :status_code,
+    wildcard = :wildcard,
+    auto = :auto,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM redirect WHERE id = :id AND site_id = :site_id;
+
+-- Retarget
+UPDATE redirect SET target_path = :new_target, updated_at = :updated_at WHERE target_path = :old_target AND site_id = :site_id;
+
+-- DeleteBySource
+DELETE FROM redirect WHERE source_path = :source_path AND site_id = :site_id;
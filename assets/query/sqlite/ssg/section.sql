@@ -3,13 +3,13 @@
 
 -- Create
 INSERT INTO section (
-    id, short_id, name, description, path, layout_id, image, header, created_by, updated_by, created_at, updated_at
+    id, short_id, site_id, name, description, path, layout_id, lang, permalink, image, header, meta_title, meta_description, canonical_url, social_image, noindex, created_by, updated_by, created_at, updated_at
 ) VALUES (
-    :id, :short_id, :name, :description, :path, :layout_id, :image, :header, :created_by, :updated_by, :created_at, :updated_at
+    :id, :short_id, :site_id, :name, :description, :path, :layout_id, :lang, :permalink, :image, :header, :meta_title, :meta_description, :canonical_url, :social_image, :noindex, :created_by, :updated_by, :created_at, :updated_at
 );
 
 -- GetAll
-SELECT * FROM section;
+SELECT * FROM section WHERE site_id = :site_id;
 
 -- Get
-SELECT * FROM section WHERE id = :id;
+SELECT * FROM section WHERE id = :id AND site_id = :site_id;
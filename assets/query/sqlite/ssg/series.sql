@@ -0,0 +1,27 @@
+-- Res: Series
+-- Table: series
+
+-- Create
+INSERT INTO series (
+    id, short_id, site_id, name, slug, description, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :slug, :description, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM series WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM series WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE series SET
+    name = :name,
+    slug = :slug,
+    description = :description,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM series WHERE id = :id AND site_id = :site_id;
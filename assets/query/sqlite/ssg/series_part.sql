@@ -0,0 +1,11 @@
+-- Res: SeriesPart
+-- Table: series_part
+
+-- Create
+INSERT INTO series_part (site_id, series_id, content_id, position) VALUES (:site_id, :series_id, :content_id, :position);
+
+-- GetAll
+SELECT series_id, content_id, position FROM series_part WHERE site_id = :site_id ORDER BY series_id, position;
+
+-- DeleteBySeries
+DELETE FROM series_part WHERE series_id = :series_id AND site_id = :site_id;
@@ -0,0 +1,29 @@
+-- Res: Site
+-- Table: site
+
+-- Create
+INSERT INTO site (
+    id, short_id, name, slug, description, org_id, team_id, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :name, :slug, :description, :org_id, :team_id, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM site ORDER BY name;
+
+-- Get
+SELECT * FROM site WHERE id = :id;
+
+-- GetBySlug
+SELECT * FROM site WHERE slug = :slug;
+
+-- Update
+UPDATE site SET
+    name = :name,
+    slug = :slug,
+    description = :description,
+    org_id = :org_id,
+    team_id = :team_id,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id;
@@ -0,0 +1,27 @@
+-- Res: SiteSettings
+-- Table: site_settings
+
+-- Get
+SELECT * FROM site_settings WHERE site_id = :site_id;
+
+-- Save
+INSERT INTO site_settings (
+    id, short_id, site_id, title, base_url, default_lang, author_name, author_email, author_url, social_links, analytics, date_format, description, social_image, logo, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :title, :base_url, :default_lang, :author_name, :author_email, :author_url, :social_links, :analytics, :date_format, :description, :social_image, :logo, :created_by, :updated_by, :created_at, :updated_at
+)
+ON CONFLICT (site_id) DO UPDATE SET
+    title = excluded.title,
+    base_url = excluded.base_url,
+    default_lang = excluded.default_lang,
+    author_name = excluded.author_name,
+    author_email = excluded.author_email,
+    author_url = excluded.author_url,
+    social_links = excluded.social_links,
+    analytics = excluded.analytics,
+    date_format = excluded.date_format,
+    description = excluded.description,
+    social_image = excluded.social_image,
+    logo = excluded.logo,
+    updated_by = excluded.updated_by,
+    updated_at = excluded.updated_at;
@@ -0,0 +1,26 @@
+-- Res: Term
+-- Table: term
+
+-- Create
+INSERT INTO term (
+    id, short_id, site_id, kind, name, slug, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :kind, :name, :slug, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM term WHERE site_id = :site_id ORDER BY kind, name;
+
+-- GetByContent
+SELECT term.* FROM term
+JOIN content_term ON content_term.term_id = term.id
+WHERE content_term.content_id = :content_id AND term.site_id = :site_id
+ORDER BY term.kind, term.name;
+
+-- AddToContent
+INSERT OR IGNORE INTO content_term (content_id, term_id) VALUES (:content_id, :term_id);
+
+-- GetAllLinks
+SELECT content_term.content_id, content_term.term_id FROM content_term
+JOIN term ON term.id = content_term.term_id
+WHERE term.site_id = :site_id;
@@ -0,0 +1,29 @@
+-- Res: Webhook
+-- Table: webhook
+
+-- Create
+INSERT INTO webhook (
+    id, short_id, site_id, name, url, events, active, secret_enc, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :name, :url, :events, :active, :secret_enc, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM webhook WHERE site_id = :site_id ORDER BY name;
+
+-- Get
+SELECT * FROM webhook WHERE id = :id AND site_id = :site_id;
+
+-- Update
+UPDATE webhook SET
+    name = :name,
+    url = :url,
+    events = :events,
+    active = :active,
+    secret_enc = :secret_enc,
+    updated_by = :updated_by,
+    updated_at = :updated_at
+WHERE id = :id AND site_id = :site_id;
+
+-- Delete
+DELETE FROM webhook WHERE id = :id AND site_id = :site_id;
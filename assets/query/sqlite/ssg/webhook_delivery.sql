@@ -0,0 +1,24 @@
+-- Res: WebhookDelivery
+-- Table: webhook_delivery
+
+-- Create
+INSERT INTO webhook_delivery (
+    id, short_id, site_id, webhook_id, webhook_name, event_id, event, url, attempt, status_code, error, duration_ms, payload, delivered_at, retry_at, created_by, updated_by, created_at, updated_at
+) VALUES (
+    :id, :short_id, :site_id, :webhook_id, :webhook_name, :event_id, :event, :url, :attempt, :status_code, :error, :duration_ms, :payload, :delivered_at, :retry_at, :created_by, :updated_by, :created_at, :updated_at
+);
+
+-- GetAll
+SELECT * FROM webhook_delivery WHERE site_id = :site_id ORDER BY delivered_at DESC LIMIT 200;
+
+-- GetByWebhook
+SELECT * FROM webhook_delivery WHERE webhook_id = :webhook_id AND site_id = :site_id ORDER BY delivered_at DESC LIMIT 200;
+
+-- Get
+SELECT * FROM webhook_delivery WHERE id = :id AND site_id = :site_id;
+
+-- GetPending
+SELECT * FROM webhook_delivery WHERE retry_at IS NOT NULL ORDER BY retry_at;
+
+-- ClearRetry
+UPDATE webhook_delivery SET retry_at = NULL WHERE id = :id;
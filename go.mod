@@ -12,3 +12,17 @@ require (
 )
 
 require github.com/gorilla/securecookie v1.1.2
+
+require github.com/yuin/goldmark v1.7.8
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/pkg/sftp v1.13.9
+	golang.org/x/net v0.39.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/kr/fs v0.1.0 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+)
@@ -241,15 +241,14 @@ func (a *App) Mount(path string, handler http.Handler) {
 
 func (a *App) MountAPI(version, path string, handler http.Handler) {
 	version = fmt.Sprintf("/%s", version)
-	versionPath := fmt.Sprintf("%s%s", path, version)
 	router, exists := a.APIRouters[version]
 	if !exists {
-		name := fmt.Sprintf("api-router-%s", versionPath)
-		router = NewWebRouter(name, a.opts...)
-		router.Mount(path, handler)
-		a.APIRouters[versionPath] = router
+		name := fmt.Sprintf("api-router-%s", version)
+		router = NewAPIRouter(name, a.opts...)
+		a.APIRouters[version] = router
+		a.APIRouter.Mount(version, router)
 	}
-	a.APIRouter.Mount(version, router)
+	router.Mount(path, handler)
 }
 
 func (a *App) MountRes(path string, handler http.Handler) {
@@ -9,6 +9,9 @@ var Flags = map[string]interface{}{
 	Key.ServerAPIEnabled: true,
 	Key.ServerResPath:    "/res",
 
+	// Site the commands work on.
+	Key.SSGSite: "default",
+
 	Key.SecHashKey:  "0123456789abcdef0123456789abcdef",
 	Key.SecBlockKey: "0123456789abcdef0123456789abcdef",
 }
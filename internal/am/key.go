@@ -32,6 +32,24 @@ type Keys struct {
 
 	RenderWebErrors string
 	RenderAPIErrors string
+
+	SSGOutputDir       string
+	SSGLanguages       string
+	SSGDefaultLang     string
+	SSGLangFallback    string
+	SSGReportsDir      string
+	SSGStaticDir       string
+	SSGDataDir         string
+	SSGCheckExternal   string
+	SSGReleasesDir     string
+	SSGReleasesKeep    string
+	SSGWebhookAttempts string
+	SSGSite            string
+	SSGRelatedTerms    string
+	SSGRelatedText     string
+	SSGRelatedLimit    string
+	SSGArchivePageSize string
+	SSGEditLockTTL     string
 }
 
 var Key = Keys{
@@ -66,4 +84,22 @@ var Key = Keys{
 
 	RenderWebErrors: "render.web.errors",
 	RenderAPIErrors: "render.api.errors",
+
+	SSGOutputDir:       "ssg.output.dir",
+	SSGLanguages:       "ssg.languages",
+	SSGDefaultLang:     "ssg.default.lang",
+	SSGLangFallback:    "ssg.lang.fallback",
+	SSGReportsDir:      "ssg.reports.dir",
+	SSGStaticDir:       "ssg.static.dir",
+	SSGDataDir:         "ssg.data.dir",
+	SSGCheckExternal:   "ssg.check.external",
+	SSGReleasesDir:     "ssg.releases.dir",
+	SSGReleasesKeep:    "ssg.releases.keep",
+	SSGWebhookAttempts: "ssg.webhook.attempts",
+	SSGSite:            "ssg.site",
+	SSGRelatedTerms:    "ssg.related.terms",
+	SSGRelatedText:     "ssg.related.text",
+	SSGRelatedLimit:    "ssg.related.limit",
+	SSGArchivePageSize: "ssg.archive.page.size",
+	SSGEditLockTTL:     "ssg.edit.lock.ttl",
 }
@@ -3,7 +3,6 @@ package am
 import (
 	"context"
 	"database/sql"
-	"embed"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -25,7 +24,7 @@ const (
 type Migrator struct {
 	Core
 	db         *sql.DB
-	assetsFS   embed.FS
+	assetsFS   fs.FS
 	engine     string
 	migrations sync.Map
 }
@@ -37,7 +36,7 @@ type Migration struct {
 	Down     string
 }
 
-func NewMigrator(assetsFS embed.FS, engine string, opts ...Option) *Migrator {
+func NewMigrator(assetsFS fs.FS, engine string, opts ...Option) *Migrator {
 	name := fmt.Sprintf("%s-migrator", engine)
 	core := NewCore(name, opts...)
 	return &Migrator{
@@ -132,7 +131,7 @@ func (m *Migrator) loadFileMigrations() ([]Migration, error) {
 				return fmt.Errorf("invalid migration filename: %s", filename)
 			}
 
-			content, err := m.assetsFS.ReadFile(path)
+			content, err := fs.ReadFile(m.assetsFS, path)
 			if err != nil {
 				return fmt.Errorf("cannot read migration file %s: %w", path, err)
 			}
@@ -2,7 +2,6 @@ package am
 
 import (
 	"context"
-	"embed"
 	"errors"
 	"io/fs"
 	"path/filepath"
@@ -13,11 +12,11 @@ import (
 type QueryManager struct {
 	Core
 	queries  sync.Map
-	assetsFS embed.FS
+	assetsFS fs.FS
 	engine   string
 }
 
-func NewQueryManager(assetsFS embed.FS, engine string, opts ...Option) *QueryManager {
+func NewQueryManager(assetsFS fs.FS, engine string, opts ...Option) *QueryManager {
 	core := NewCore("query-manager", opts...)
 	qm := &QueryManager{
 		Core:     core,
@@ -43,7 +42,7 @@ func (qm *QueryManager) Load() {
 }
 
 func (qm *QueryManager) loadQueries(path string) {
-	content, err := qm.assetsFS.ReadFile(path)
+	content, err := fs.ReadFile(qm.assetsFS, path)
 	if err != nil {
 		qm.Log().Error("Failed to read query file: ", err)
 		return
@@ -14,6 +14,10 @@ const (
 	ErrorCodeInternalError = "INTERNAL_ERROR"
 	ErrorCodeBadRequest    = "BAD_REQUEST"
 	ErrorCodeNotFound      = "NOT_FOUND"
+	ErrorCodeUnauthorized  = "UNAUTHORIZED"
+	ErrorCodeForbidden     = "FORBIDDEN"
+	ErrorCodeConflict      = "CONFLICT"
+	ErrorCodeUnprocessable = "UNPROCESSABLE"
 )
 
 type Response struct {
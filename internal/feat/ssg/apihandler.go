@@ -0,0 +1,408 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/adrianpk/hermes/internal/feat/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	apiMaxBody = 1 << 20
+
+	// ReplayedHeader is set on responses replayed for a reused idempotency
+	// key.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// SiteHeader holds the slug of the site a request works on. The site of
+	// the API token is used if it is not set.
+	SiteHeader = "X-Hermes-Site"
+)
+
+type apiTokenCtxKey struct{}
+
+// APIHandler serves the JSON API CI jobs and git hooks use to trigger builds
+// and publishes. Requests are authenticated with API tokens.
+type APIHandler struct {
+	*am.Handler
+	service Service
+	auth    auth.Service
+}
+
+func NewAPIHandler(service Service, authService auth.Service, options ...am.Option) *APIHandler {
+	handler := am.NewHandler("api-handler", options...)
+	return &APIHandler{
+		Handler: handler,
+		service: service,
+		auth:    authService,
+	}
+}
+
+// ListBuilds lists the builds, most recent first.
+func (h *APIHandler) ListBuilds(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("API list builds")
+	ctx := r.Context()
+
+	builds, err := h.service.GetBuilds(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]map[string]any, len(builds))
+	for i, b := range builds {
+		data[i] = apiBuildData(b, r.URL.Path+"/"+b.ID().String())
+	}
+
+	am.Respond(w, http.StatusOK, am.NewSuccessResponse("Builds", data))
+}
+
+// TriggerBuild starts a build and answers right away with the URL to poll for
+// its status.
+func (h *APIHandler) TriggerBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("API trigger build")
+	ctx := r.Context()
+	token := apiToken(ctx)
+
+	build, err := h.service.StartBuild(ctx, BuildTriggerAPI, token.UserID)
+	if err != nil {
+		h.Err(w, err, "Cannot start build", http.StatusInternalServerError)
+		return
+	}
+
+	statusURL := r.URL.Path + "/" + build.ID().String()
+	w.Header().Set("Location", statusURL)
+	am.Respond(w, http.StatusAccepted, am.NewSuccessResponse("Build started", apiBuildData(build, statusURL)))
+}
+
+// GetBuild reports the status of a build.
+func (h *APIHandler) GetBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("API get build")
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	build, err := h.service.GetBuild(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Fail(w, http.StatusNotFound, am.ErrorCodeNotFound, "Build not found", id)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	am.Respond(w, http.StatusOK, am.NewSuccessResponse("Build "+build.Status, apiBuildData(build, r.URL.Path)))
+}
+
+type publishRequest struct {
+	Target string `json:"target"` // Target name or ID
+	DryRun bool   `json:"dry_run"`
+}
+
+// PublishBuild publishes the output of a build to a target, or reports what
+// would change when dry_run is set.
+func (h *APIHandler) PublishBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("API publish build")
+	ctx := r.Context()
+	token := apiToken(ctx)
+
+	id := chi.URLParam(r, "id")
+
+	var req publishRequest
+	err := json.NewDecoder(io.LimitReader(r.Body, apiMaxBody)).Decode(&req)
+	if err != nil || req.Target == "" {
+		h.Fail(w, http.StatusBadRequest, am.ErrorCodeBadRequest, am.ErrBadRequest, "a target is required")
+		return
+	}
+
+	target, err := h.target(ctx, req.Target)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Fail(w, http.StatusNotFound, am.ErrorCodeNotFound, "Target not found", req.Target)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	author, err := h.author(ctx, token)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	deployment, result, err := h.service.PublishBuild(ctx, id, target.ID().String(), token.UserID, author, req.DryRun)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		h.Fail(w, http.StatusNotFound, am.ErrorCodeNotFound, "Build not found", id)
+		return
+	case errors.Is(err, ErrPublishNotConfigured):
+		h.Fail(w, http.StatusUnprocessableEntity, am.ErrorCodeUnprocessable, "Target is not fully configured", target.Name)
+		return
+	case errors.Is(err, ErrNoRelease):
+		h.Fail(w, http.StatusConflict, am.ErrorCodeConflict, "The output of this build is not available", id)
+		return
+	case err != nil && deployment.IsZero():
+		h.Err(w, err, "Cannot publish build", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"build_id": id,
+		"target":   target.Name,
+		"dry_run":  result.DryRun,
+		"changes":  apiChanges(result.Changes),
+	}
+	if !deployment.IsZero() {
+		data["deployment"] = deploymentEventData(deployment, target)
+	}
+
+	if err != nil {
+		am.Respond(w, http.StatusBadGateway, am.Response{
+			Status:  am.StatusError,
+			Message: "Publishing failed",
+			Data:    data,
+			Error:   &am.APIError{Code: am.ErrorCodeInternalError, Details: err.Error()},
+		})
+		return
+	}
+
+	msg := "Build published"
+	if result.DryRun {
+		msg = "Dry run"
+	}
+	am.Respond(w, http.StatusOK, am.NewSuccessResponse(msg, data))
+}
+
+// target finds a publish target by ID or, failing that, by name.
+func (h *APIHandler) target(ctx context.Context, ref string) (PublishTarget, error) {
+	if _, err := uuid.Parse(ref); err == nil {
+		return h.service.GetPublishTarget(ctx, ref)
+	}
+	return h.service.GetPublishTargetByName(ctx, ref)
+}
+
+// Err logs the error and writes it as a JSON error response.
+func (h *APIHandler) Err(w http.ResponseWriter, err error, msg string, code int) {
+	h.Log().Errorf("%s: %v", msg, err)
+	h.Fail(w, code, am.ErrorCodeInternalError, msg, "")
+}
+
+// Fail writes a JSON error response.
+func (h *APIHandler) Fail(w http.ResponseWriter, status int, code, msg, details string) {
+	am.Respond(w, status, am.NewErrorResponse(msg, code, details))
+}
+
+// Middlewares
+
+// Authenticate rejects requests without a valid API token in the bearer
+// Authorization header.
+func (h *APIHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := am.BearerToken(r)
+		if secret == "" {
+			h.Fail(w, http.StatusUnauthorized, am.ErrorCodeUnauthorized, "API token required", "")
+			return
+		}
+
+		token, err := h.service.AuthenticateAPIToken(r.Context(), secret)
+		if errors.Is(err, ErrInvalidAPIToken) {
+			h.Fail(w, http.StatusUnauthorized, am.ErrorCodeUnauthorized, "Invalid API token", "")
+			return
+		}
+		if err != nil {
+			h.Err(w, err, "Cannot authenticate API token", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), apiTokenCtxKey{}, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Site scopes requests to the site named in the site header or, failing
+// that, in the site query parameter. Requests that name neither work on the
+// site of the API token. Tokens only act on their own site, requests for
+// another one are rejected.
+func (h *APIHandler) Site(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		tokenSiteID := apiToken(ctx).SiteID
+		if tokenSiteID == uuid.Nil {
+			tokenSiteID = DefaultSite().ID()
+		}
+
+		slug := r.Header.Get(SiteHeader)
+		if slug == "" {
+			slug = r.URL.Query().Get("site")
+		}
+
+		var site Site
+		var err error
+		if slug == "" {
+			site, err = h.service.GetSite(ctx, tokenSiteID.String())
+		} else {
+			site, err = h.service.GetSiteBySlug(ctx, slug)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			h.Fail(w, http.StatusNotFound, am.ErrorCodeNotFound, "Site not found", slug)
+			return
+		}
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+			return
+		}
+
+		if site.ID() != tokenSiteID {
+			h.Fail(w, http.StatusForbidden, am.ErrorCodeForbidden, "API token is not valid for this site", site.SlugValue)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithSite(ctx, site)))
+	})
+}
+
+// RequireScope rejects requests made with tokens not granted the scope.
+func (h *APIHandler) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !apiToken(r.Context()).Allows(scope) {
+				h.Fail(w, http.StatusForbidden, am.ErrorCodeForbidden, "API token lacks the required scope", scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Idempotent makes requests sent with an Idempotency-Key header safe to
+// retry. The first response for a key is stored and replayed to later
+// requests with the same key. Reusing a key for a different request is
+// rejected, as is a retry while the first request is still running. Server
+// errors are not stored so that the request can be retried.
+func (h *APIHandler) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyVal := r.Header.Get(IdempotencyHeader)
+		if keyVal == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(keyVal) > idempotencyKeyMaxLen {
+			h.Fail(w, http.StatusBadRequest, am.ErrorCodeBadRequest, "Idempotency key too long", "max "+strconv.Itoa(idempotencyKeyMaxLen)+" characters")
+			return
+		}
+
+		ctx := r.Context()
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, apiMaxBody))
+		if err != nil {
+			h.Fail(w, http.StatusBadRequest, am.ErrorCodeBadRequest, "Cannot read request body", err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		// Form bodies have already been consumed by the method override.
+		body = append(body, r.PostForm.Encode()...)
+
+		key := NewIdempotencyKey(apiToken(ctx).ID(), keyVal, requestHash(r.Method, r.URL.Path, body))
+
+		stored, err := h.service.ReserveIdempotencyKey(ctx, key)
+		switch {
+		case errors.Is(err, ErrIdempotencyKeyExists) && !stored.Matches(key.RequestHash):
+			h.Fail(w, http.StatusUnprocessableEntity, am.ErrorCodeUnprocessable, "Idempotency key already used for a different request", keyVal)
+			return
+		case errors.Is(err, ErrIdempotencyKeyExists) && stored.IsPending():
+			h.Fail(w, http.StatusConflict, am.ErrorCodeConflict, "A request with this idempotency key is in progress", keyVal)
+			return
+		case errors.Is(err, ErrIdempotencyKeyExists):
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(ReplayedHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			_, _ = w.Write(stored.Body)
+			return
+		case err != nil:
+			h.Err(w, err, "Cannot reserve idempotency key", http.StatusInternalServerError)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// The request context may be done by now, the outcome is recorded anyway.
+		ctx = context.WithoutCancel(ctx)
+		if rec.status >= http.StatusInternalServerError {
+			err = h.service.ReleaseIdempotencyKey(ctx, key)
+		} else {
+			key.StatusCode = rec.status
+			key.Body = rec.body.Bytes()
+			err = h.service.CompleteIdempotencyKey(ctx, key)
+		}
+		if err != nil {
+			h.Log().Errorf("Cannot record idempotency key %s: %v", keyVal, err)
+		}
+	})
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// apiToken returns the token the request was authenticated with.
+func apiToken(ctx context.Context) APIToken {
+	token, _ := ctx.Value(apiTokenCtxKey{}).(APIToken)
+	return token
+}
+
+// author returns the identity publishes made with a token are recorded with,
+// the one of the user who owns it.
+func (h *APIHandler) author(ctx context.Context, token APIToken) (Author, error) {
+	user, err := h.auth.GetUser(ctx, token.UserID)
+	if err != nil {
+		return Author{}, fmt.Errorf("cannot get owner of token %s: %w", token.Prefix, err)
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Username
+	}
+	return Author{Name: name, Email: user.Email}, nil
+}
+
+func apiBuildData(build Build, url string) map[string]any {
+	data := buildEventData(build)
+	data["pages_skipped"] = build.PagesSkipped
+	data["pages_deleted"] = build.PagesDeleted
+	data["live"] = build.Live
+	data["url"] = url
+	return data
+}
+
+func apiChanges(changes []Change) []string {
+	list := make([]string, len(changes))
+	for i, c := range changes {
+		list[i] = c.String()
+	}
+	return list
+}
@@ -0,0 +1,243 @@
+package ssg
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/adrianpk/hermes/internal/feat/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// tokenRepo keeps API tokens and idempotency keys in memory.
+type tokenRepo struct {
+	Repo
+	tokens []APIToken
+	keys   map[string]IdempotencyKey
+}
+
+func (r *tokenRepo) GetAPITokenByHash(ctx context.Context, hash string) (APIToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return APIToken{}, sql.ErrNoRows
+}
+
+func (r *tokenRepo) TouchAPIToken(ctx context.Context, token APIToken) error {
+	return nil
+}
+
+func (r *tokenRepo) CreateIdempotencyKey(ctx context.Context, key IdempotencyKey) error {
+	if _, ok := r.keys[key.Key]; ok {
+		return ErrIdempotencyKeyExists
+	}
+	r.keys[key.Key] = key
+	return nil
+}
+
+func (r *tokenRepo) GetIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) (IdempotencyKey, error) {
+	return r.keys[key], nil
+}
+
+func (r *tokenRepo) UpdateIdempotencyKey(ctx context.Context, key IdempotencyKey) error {
+	r.keys[key.Key] = key
+	return nil
+}
+
+func (r *tokenRepo) DeleteIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) error {
+	delete(r.keys, key)
+	return nil
+}
+
+func (r *tokenRepo) DeleteExpiredIdempotencyKeys(ctx context.Context, cutoff time.Time) error {
+	return nil
+}
+
+// publishService records the author of the publishes made through it.
+type publishService struct {
+	Service
+	target PublishTarget
+	author Author
+}
+
+func (s *publishService) GetPublishTargetByName(ctx context.Context, name string) (PublishTarget, error) {
+	return s.target, nil
+}
+
+func (s *publishService) PublishBuild(ctx context.Context, id, targetID string, userID uuid.UUID, author Author, dryRun bool) (Deployment, PublishResult, error) {
+	s.author = author
+	return Deployment{}, PublishResult{DryRun: dryRun}, nil
+}
+
+// userService holds the users of the tokens.
+type userService struct {
+	auth.Service
+	users map[uuid.UUID]auth.User
+}
+
+func (s *userService) GetUser(ctx context.Context, id uuid.UUID) (auth.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return auth.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func TestAPIPublishAuthor(t *testing.T) {
+	owner := uuid.New()
+	token := NewAPIToken("ci", owner, ScopePublish)
+	token.GenCreateValues()
+	target := NewPublishTarget("staging", EnvStaging, PublishTargetLocal)
+	target.GenCreateValues()
+
+	svc := &publishService{target: target}
+	users := &userService{users: map[uuid.UUID]auth.User{owner: {Name: "Jane Doe", Email: "jane@example.com"}}}
+	h := NewAPIHandler(svc, users, am.WithLog(am.NewLogger("error")))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", uuid.NewString())
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, apiTokenCtxKey{}, token)
+	req := httptest.NewRequest(http.MethodPost, "/builds/x/publish", strings.NewReader(`{"target":"staging","dry_run":true}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.PublishBuild(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if want := (Author{Name: "Jane Doe", Email: "jane@example.com"}); svc.author != want {
+		t.Errorf("author = %+v, want %+v", svc.author, want)
+	}
+}
+
+func TestAPIIdempotentRequests(t *testing.T) {
+	secret, prefix, hash, err := newAPIToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := NewAPIToken("ci", uuid.Nil, ScopeBuildsWrite)
+	token.GenCreateValues()
+	token.Prefix, token.TokenHash = prefix, hash
+
+	repo := &tokenRepo{tokens: []APIToken{token}, keys: map[string]IdempotencyKey{}}
+	opts := []am.Option{am.WithLog(am.NewLogger("error"))}
+	svc := &BaseService{Service: am.NewService("ssg-service", opts...), repo: repo}
+	h := NewAPIHandler(svc, nil, opts...)
+
+	var calls int
+	status := http.StatusAccepted
+	handler := h.Authenticate(h.RequireScope(ScopeBuildsWrite)(h.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		am.Respond(w, status, am.NewSuccessResponse("Build started", calls))
+	}))))
+
+	send := func(auth, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/builds", strings.NewReader(body))
+		req.Header.Set("Authorization", auth)
+		req.Header.Set(IdempotencyHeader, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	bearer := "Bearer " + secret
+
+	if rec := send("Bearer hms_nope", "k1", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("invalid token status = %d, want 401", rec.Code)
+	}
+
+	first := send(bearer, "k1", "")
+	replay := send(bearer, "k1", "")
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if replay.Code != http.StatusAccepted || replay.Body.String() != first.Body.String() || replay.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("replay = %d %q, want %d %q", replay.Code, replay.Body.String(), first.Code, first.Body.String())
+	}
+
+	if rec := send(bearer, "k1", `{"other":true}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key status = %d, want 422", rec.Code)
+	}
+
+	repo.keys["k2"] = NewIdempotencyKey(token.ID(), "k2", requestHash(http.MethodPost, "/builds", nil))
+	if rec := send(bearer, "k2", ""); rec.Code != http.StatusConflict {
+		t.Errorf("pending key status = %d, want 409", rec.Code)
+	}
+
+	// Server errors release the key so that the request can be retried.
+	status = http.StatusInternalServerError
+	send(bearer, "k3", "")
+	status = http.StatusAccepted
+	if rec := send(bearer, "k3", ""); rec.Code != http.StatusAccepted || calls != 3 {
+		t.Errorf("retry after error = %d with %d calls, want 202 with 3", rec.Code, calls)
+	}
+}
+
+// siteService holds the sites API requests can name.
+type siteService struct {
+	Service
+	sites []Site
+}
+
+func (s *siteService) GetSite(ctx context.Context, id string) (Site, error) {
+	for _, site := range s.sites {
+		if site.ID().String() == id {
+			return site, nil
+		}
+	}
+	return Site{}, sql.ErrNoRows
+}
+
+func (s *siteService) GetSiteBySlug(ctx context.Context, slug string) (Site, error) {
+	for _, site := range s.sites {
+		if site.SlugValue == slug {
+			return site, nil
+		}
+	}
+	return Site{}, sql.ErrNoRows
+}
+
+func TestAPISiteOfToken(t *testing.T) {
+	docs := NewSite("Docs", "")
+	docs.GenCreateValues()
+	token := NewAPIToken("ci", uuid.Nil, ScopeBuildsRead)
+	token.GenCreateValues()
+	token.SiteID = docs.ID()
+
+	h := NewAPIHandler(&siteService{sites: []Site{DefaultSite(), docs}}, nil, am.WithLog(am.NewLogger("error")))
+	var got Site
+	handler := h.Site(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = SiteFrom(r.Context())
+	}))
+
+	send := func(slug string) *httptest.ResponseRecorder {
+		ctx := context.WithValue(context.Background(), apiTokenCtxKey{}, token)
+		req := httptest.NewRequest(http.MethodGet, "/builds", nil).WithContext(ctx)
+		if slug != "" {
+			req.Header.Set(SiteHeader, slug)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(""); rec.Code != http.StatusOK || got.ID() != docs.ID() {
+		t.Errorf("without a site = %d on %q, want 200 on %q", rec.Code, got.SlugValue, docs.SlugValue)
+	}
+	if rec := send(docs.SlugValue); rec.Code != http.StatusOK {
+		t.Errorf("token site status = %d, want 200", rec.Code)
+	}
+	if rec := send(DefaultSiteSlug); rec.Code != http.StatusForbidden {
+		t.Errorf("other site status = %d, want 403", rec.Code)
+	}
+	if rec := send("missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown site status = %d, want 404", rec.Code)
+	}
+}
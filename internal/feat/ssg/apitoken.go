@@ -0,0 +1,104 @@
+package ssg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	apiTokenType = "api-token"
+
+	apiTokenPrefix    = "hms_"
+	apiTokenPrefixLen = len(apiTokenPrefix) + 8
+)
+
+// API token scopes.
+const (
+	ScopeBuildsRead  = "builds:read"
+	ScopeBuildsWrite = "builds:write"
+	ScopePublish     = "publish"
+)
+
+var apiTokenScopes = []string{
+	ScopeBuildsRead,
+	ScopeBuildsWrite,
+	ScopePublish,
+}
+
+// APIToken grants CI jobs and git hooks access to the API of the site it was
+// created on. Only a hash of the token is stored, the token itself is shown
+// once when created.
+type APIToken struct {
+	*am.BaseModel
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	Prefix     string    `json:"prefix"` // First characters of the token so it can be told apart
+	TokenHash  string    `json:"-"`
+	UserID     uuid.UUID `json:"user_id"`
+	SiteID     uuid.UUID `json:"site_id"` // Site the token acts on, set when it is created
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+func NewAPIToken(name string, userID uuid.UUID, scopes ...string) APIToken {
+	return APIToken{
+		BaseModel: am.NewModel(am.WithType(apiTokenType)),
+		Name:      name,
+		Scopes:    scopes,
+		UserID:    userID,
+	}
+}
+
+func (t APIToken) IsZero() bool {
+	return t.BaseModel == nil || t.BaseModel.IsZero()
+}
+
+// Allows reports whether the token was granted the scope.
+func (t APIToken) Allows(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *APIToken) Slug() string {
+	return am.Normalize(t.Name) + "-" + t.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (t *APIToken) UnmarshalJSON(data []byte) error {
+	type Alias APIToken
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*t = APIToken(*temp)
+	if t.BaseModel == nil {
+		t.BaseModel = am.NewModel(am.WithType(apiTokenType))
+	}
+	return nil
+}
+
+// newAPIToken returns a random token along with its display prefix and hash.
+func newAPIToken() (token, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", "", "", err
+	}
+	token = apiTokenPrefix + hex.EncodeToString(b)
+	return token, token[:apiTokenPrefixLen], hashAPIToken(token), nil
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,33 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APITokenDA struct {
+	ID         uuid.UUID  `db:"id"`
+	ShortID    string     `db:"short_id"`
+	SiteID     string     `db:"site_id"`
+	Name       string     `db:"name"`
+	Scopes     string     `db:"scopes"`
+	Prefix     string     `db:"prefix"`
+	TokenHash  string     `db:"token_hash"`
+	UserID     string     `db:"user_id"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedBy  *string    `db:"created_by"`
+	UpdatedBy  *string    `db:"updated_by"`
+	CreatedAt  *time.Time `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
+
+type IdempotencyKeyDA struct {
+	TokenID     string     `db:"token_id"`
+	SiteID      string     `db:"site_id"`
+	Key         string     `db:"key"`
+	RequestHash string     `db:"request_hash"`
+	StatusCode  int        `db:"status_code"`
+	Body        []byte     `db:"body"`
+	CreatedAt   *time.Time `db:"created_at"`
+}
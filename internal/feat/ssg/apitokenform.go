@@ -0,0 +1,69 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+type APITokenForm struct {
+	*am.BaseForm
+	Name   string   `form:"name" required:"true"`
+	Scopes []string `form:"scopes"`
+}
+
+func NewAPITokenForm(r *http.Request) APITokenForm {
+	return APITokenForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func APITokenFormFromRequest(r *http.Request) (tf APITokenForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return tf, err
+	}
+
+	return APITokenForm{
+		BaseForm: am.NewBaseForm(r),
+		Name:     strings.TrimSpace(r.Form.Get("name")),
+		Scopes:   r.Form["scopes"],
+	}, nil
+}
+
+// Grants reports whether the scope is checked.
+func (form APITokenForm) Grants(scope string) bool {
+	return contains(form.Scopes, scope)
+}
+
+func (form *APITokenForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 64),
+		validScopes("scopes", form.Scopes),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+func validScopes(field string, scopes []string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if len(scopes) == 0 {
+			v.AddFieldError(field, "", fmt.Sprintf("%s: select at least one scope", field))
+			return v, nil
+		}
+		for _, s := range scopes {
+			if !contains(apiTokenScopes, s) {
+				v.AddFieldError(field, s, fmt.Sprintf("%s: unknown scope %s", field, s))
+			}
+		}
+		return v, nil
+	}
+}
@@ -0,0 +1,1314 @@
+package ssg
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	// ArchiveVersion is bumped whenever the archive layout changes in a way
+	// older versions cannot read.
+	ArchiveVersion = 1
+	archiveFormat  = "hermes-site"
+	archiveSite    = "site.json"
+	archiveStatic  = "static/"
+)
+
+var ErrInvalidArchive = errors.New("invalid site archive")
+
+// restoreKinds are the kinds of entity a restore reports on, in the order
+// they are restored.
+var restoreKinds = []string{
+	"settings", "layouts", "content_types", "sections", "terms", "data_files", "profiles",
+	"contents", "menus", "series", "redirects", "publish_targets", "webhooks", "media",
+}
+
+// Archive is the site.json document of a site archive. It follows the seed
+// data conventions: entities carry a ref and point to each other by ref
+// rather than by ID, so they can be restored into a database that already
+// has data. Refs are the IDs entities had on the exporting site.
+// Credentials and webhook secrets are not exported.
+type Archive struct {
+	Format         string                 `json:"format"`
+	Version        int                    `json:"version"`
+	ExportedAt     time.Time              `json:"exported_at"`
+	Settings       *ArchiveSettings       `json:"settings"` // Nil if the site kept the defaults
+	Layouts        []ArchiveLayout        `json:"layouts"`
+	ContentTypes   []ArchiveContentType   `json:"content_types"`
+	Sections       []ArchiveSection       `json:"sections"`
+	Terms          []ArchiveTerm          `json:"terms"`
+	DataFiles      []ArchiveDataFile      `json:"data_files"`
+	Profiles       []ArchiveProfile       `json:"profiles"`
+	Contents       []ArchiveContent       `json:"contents"`
+	Menus          []ArchiveMenu          `json:"menus"`
+	Series         []ArchiveSeries        `json:"series"`
+	Redirects      []ArchiveRedirect      `json:"redirects"`
+	PublishTargets []ArchivePublishTarget `json:"publish_targets"`
+	Webhooks       []ArchiveWebhook       `json:"webhooks"`
+	Media          []string               `json:"media"` // Paths of the static files bundled under static/
+}
+
+type ArchiveSettings struct {
+	Title       string       `json:"title"`
+	BaseURL     string       `json:"base_url"`
+	DefaultLang string       `json:"default_lang"`
+	AuthorName  string       `json:"author_name"`
+	AuthorEmail string       `json:"author_email"`
+	AuthorURL   string       `json:"author_url"`
+	SocialLinks []SocialLink `json:"social_links"`
+	Analytics   string       `json:"analytics"`
+	DateFormat  string       `json:"date_format"`
+	Description string       `json:"description"`
+	SocialImage string       `json:"social_image"`
+	Logo        string       `json:"logo"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type ArchiveLayout struct {
+	Ref         string    `json:"ref"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Code        string    `json:"code"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ArchiveContentType struct {
+	Ref         string     `json:"ref"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	Fields      []FieldDef `json:"fields"`
+	LayoutRef   string     `json:"layout_ref"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ArchiveSection struct {
+	Ref         string    `json:"ref"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Path        string    `json:"path"`
+	LayoutRef   string    `json:"layout_ref"`
+	Lang        string    `json:"lang"`
+	Permalink   string    `json:"permalink"`
+	Image       string    `json:"image"`
+	Header      string    `json:"header"`
+	CreatedAt   time.Time `json:"created_at"`
+	PageMeta
+}
+
+type ArchiveTerm struct {
+	Ref  string `json:"ref"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type ArchiveDataFile struct {
+	Name      string     `json:"name"`
+	Format    string     `json:"format"`
+	Content   string     `json:"content"`
+	Schema    []FieldDef `json:"schema"`
+	Source    string     `json:"source"`
+	Path      string     `json:"path"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ArchiveProfile struct {
+	Ref       string       `json:"ref"`
+	Name      string       `json:"name"`
+	Slug      string       `json:"slug"`
+	Bio       string       `json:"bio"`
+	Avatar    string       `json:"avatar"`
+	Links     []SocialLink `json:"links"`
+	Account   bool         `json:"account"` // True if the profile belonged to a user of the exporting site
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ArchiveContent struct {
+	Ref            string            `json:"ref"`
+	SectionRef     string            `json:"section_ref"`
+	TranslationRef string            `json:"translation_ref"`
+	TypeRef        string            `json:"type_ref"`
+	Lang           string            `json:"lang"`
+	Slug           string            `json:"slug"`
+	Heading        string            `json:"heading"`
+	Body           string            `json:"body"`
+	Status         string            `json:"status"`
+	PublishedAt    time.Time         `json:"published_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+	TermRefs       []string          `json:"term_refs"`
+	Fields         map[string]string `json:"fields"`
+	TOCMinDepth    int               `json:"toc_min_depth"`
+	TOCMaxDepth    int               `json:"toc_max_depth"`
+	AuthorRefs     []string          `json:"author_refs"` // Profiles credited in the byline in order
+	PageMeta
+}
+
+type ArchiveMenu struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Items       []ArchiveMenuItem `json:"items"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type ArchiveMenuItem struct {
+	Ref       string `json:"ref"`
+	ParentRef string `json:"parent_ref"`
+	Label     string `json:"label"`
+	Kind      string `json:"kind"`
+	TargetRef string `json:"target_ref"` // Section or content the item points at
+	URL       string `json:"url"`
+	Position  int    `json:"position"`
+}
+
+type ArchiveSeries struct {
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	PartRefs    []string  `json:"part_refs"` // Contents of the series in order
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ArchiveRedirect struct {
+	SourcePath string `json:"source_path"`
+	TargetPath string `json:"target_path"`
+	StatusCode int    `json:"status_code"`
+	Wildcard   bool   `json:"wildcard"`
+	Auto       bool   `json:"auto"`
+}
+
+type ArchivePublishTarget struct {
+	Name        string            `json:"name"`
+	Environment string            `json:"environment"`
+	BaseURL     string            `json:"base_url"`
+	Kind        string            `json:"kind"`
+	Settings    map[string]string `json:"settings"`
+}
+
+type ArchiveWebhook struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+}
+
+// RestoreOptions tune how an archive is restored.
+type RestoreOptions struct {
+	DryRun bool // Report what would be restored without saving anything
+}
+
+// RestoreReport counts what a restore created and what it skipped because an
+// equivalent entity already existed.
+type RestoreReport struct {
+	Version  int            `json:"version"`
+	DryRun   bool           `json:"dry_run"`
+	Created  map[string]int `json:"created"`
+	Skipped  map[string]int `json:"skipped"`
+	Warnings []string       `json:"warnings"`
+}
+
+func newRestoreReport(archive Archive, opts RestoreOptions) RestoreReport {
+	return RestoreReport{
+		Version: archive.Version,
+		DryRun:  opts.DryRun,
+		Created: map[string]int{},
+		Skipped: map[string]int{},
+	}
+}
+
+func (r *RestoreReport) warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// exportArchive collects every site entity into an archive document.
+func (svc *BaseService) exportArchive(ctx context.Context) (Archive, error) {
+	archive := Archive{Format: archiveFormat, Version: ArchiveVersion, ExportedAt: am.Now()}
+
+	settings, err := svc.repo.GetSiteSettings(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return archive, fmt.Errorf("cannot get site settings: %w", err)
+	}
+	if err == nil && !settings.IsDefault() {
+		archive.Settings = &ArchiveSettings{
+			Title:       settings.Title,
+			BaseURL:     settings.BaseURL,
+			DefaultLang: settings.DefaultLang,
+			AuthorName:  settings.AuthorName,
+			AuthorEmail: settings.AuthorEmail,
+			AuthorURL:   settings.AuthorURL,
+			SocialLinks: settings.SocialLinks,
+			Analytics:   settings.Analytics,
+			DateFormat:  settings.DateFormat,
+			Description: settings.Description,
+			SocialImage: settings.SocialImage,
+			Logo:        settings.Logo,
+			CreatedAt:   settings.CreatedAt(),
+		}
+	}
+
+	layouts, err := svc.repo.GetAllLayouts(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get layouts: %w", err)
+	}
+	for _, l := range layouts {
+		archive.Layouts = append(archive.Layouts, ArchiveLayout{
+			Ref:         l.ID().String(),
+			Name:        l.Name,
+			Description: l.Description,
+			Code:        l.Code,
+			CreatedAt:   l.CreatedAt(),
+		})
+	}
+
+	types, err := svc.repo.GetContentTypes(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get content types: %w", err)
+	}
+	for _, ct := range types {
+		archive.ContentTypes = append(archive.ContentTypes, ArchiveContentType{
+			Ref:         ct.ID().String(),
+			Name:        ct.Name,
+			Slug:        ct.SlugValue,
+			Description: ct.Description,
+			Fields:      ct.Fields,
+			LayoutRef:   refOf(ct.LayoutID),
+			CreatedAt:   ct.CreatedAt(),
+		})
+	}
+
+	sections, err := svc.repo.GetSections(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get sections: %w", err)
+	}
+	for _, s := range sections {
+		archive.Sections = append(archive.Sections, ArchiveSection{
+			Ref:         s.ID().String(),
+			Name:        s.Name,
+			Description: s.Description,
+			Path:        s.Path,
+			LayoutRef:   refOf(s.LayoutID),
+			Lang:        s.Lang,
+			Permalink:   s.Permalink,
+			Image:       s.Image,
+			Header:      s.Header,
+			CreatedAt:   s.CreatedAt(),
+			PageMeta:    s.PageMeta,
+		})
+	}
+
+	terms, err := svc.repo.GetTerms(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get terms: %w", err)
+	}
+	for _, t := range terms {
+		archive.Terms = append(archive.Terms, ArchiveTerm{Ref: t.ID().String(), Kind: t.Kind, Name: t.Name, Slug: t.SlugValue})
+	}
+
+	files, err := svc.repo.GetDataFiles(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get data files: %w", err)
+	}
+	for _, f := range files {
+		archive.DataFiles = append(archive.DataFiles, ArchiveDataFile{
+			Name:      f.Name,
+			Format:    f.Format,
+			Content:   f.Content,
+			Schema:    f.Schema,
+			Source:    f.Source,
+			Path:      f.Path,
+			CreatedAt: f.CreatedAt(),
+		})
+	}
+
+	profiles, err := svc.repo.GetProfiles(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get profiles: %w", err)
+	}
+	for _, p := range profiles {
+		archive.Profiles = append(archive.Profiles, ArchiveProfile{
+			Ref:       p.ID().String(),
+			Name:      p.Name,
+			Slug:      p.SlugValue,
+			Bio:       p.Bio,
+			Avatar:    p.Avatar,
+			Links:     p.Links,
+			Account:   p.UserID != uuid.Nil,
+			CreatedAt: p.CreatedAt(),
+		})
+	}
+	authors, err := svc.repo.GetContentAuthors(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get content authors: %w", err)
+	}
+	sort.SliceStable(authors, func(i, j int) bool { return authors[i].Position < authors[j].Position })
+
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get contents: %w", err)
+	}
+	for _, c := range contents {
+		cterms, err := svc.repo.GetContentTerms(ctx, c.ID())
+		if err != nil {
+			return archive, fmt.Errorf("cannot get terms of %s: %w", c.ID(), err)
+		}
+		var termRefs []string
+		for _, t := range cterms {
+			termRefs = append(termRefs, t.ID().String())
+		}
+		var authorRefs []string
+		for _, id := range contentAuthors(authors, c.ID()) {
+			authorRefs = append(authorRefs, id.String())
+		}
+
+		archive.Contents = append(archive.Contents, ArchiveContent{
+			Ref:            c.ID().String(),
+			SectionRef:     refOf(c.SectionID),
+			TranslationRef: refOf(c.TranslationKey()),
+			TypeRef:        refOf(c.TypeID),
+			Lang:           c.Lang,
+			Slug:           c.SlugValue,
+			Heading:        c.Heading,
+			Body:           c.Body,
+			Status:         c.Status,
+			PublishedAt:    c.PublishedAt,
+			CreatedAt:      c.CreatedAt(),
+			TermRefs:       termRefs,
+			Fields:         c.Fields,
+			TOCMinDepth:    c.TOCMinDepth,
+			TOCMaxDepth:    c.TOCMaxDepth,
+			AuthorRefs:     authorRefs,
+			PageMeta:       c.PageMeta,
+		})
+	}
+
+	menus, err := svc.repo.GetMenus(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get menus: %w", err)
+	}
+	items, err := svc.repo.GetMenuItems(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get menu items: %w", err)
+	}
+	for _, m := range menus {
+		entry := ArchiveMenu{Name: m.Name, Description: m.Description, CreatedAt: m.CreatedAt()}
+		for _, i := range items {
+			if i.MenuID != m.ID() {
+				continue
+			}
+			entry.Items = append(entry.Items, ArchiveMenuItem{
+				Ref:       i.ID().String(),
+				ParentRef: refOf(i.ParentID),
+				Label:     i.Label,
+				Kind:      i.Kind,
+				TargetRef: refOf(i.TargetID),
+				URL:       i.URL,
+				Position:  i.Position,
+			})
+		}
+		archive.Menus = append(archive.Menus, entry)
+	}
+
+	series, err := svc.repo.GetAllSeries(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get series: %w", err)
+	}
+	parts, err := svc.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get series parts: %w", err)
+	}
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].Position < parts[j].Position })
+	for _, sr := range series {
+		entry := ArchiveSeries{Name: sr.Name, Slug: sr.SlugValue, Description: sr.Description, CreatedAt: sr.CreatedAt()}
+		for _, p := range parts {
+			if p.SeriesID == sr.ID() {
+				entry.PartRefs = append(entry.PartRefs, p.ContentID.String())
+			}
+		}
+		archive.Series = append(archive.Series, entry)
+	}
+
+	redirects, err := svc.repo.GetAllRedirects(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get redirects: %w", err)
+	}
+	for _, r := range redirects {
+		archive.Redirects = append(archive.Redirects, ArchiveRedirect{
+			SourcePath: r.SourcePath,
+			TargetPath: r.TargetPath,
+			StatusCode: r.StatusCode,
+			Wildcard:   r.Wildcard,
+			Auto:       r.Auto,
+		})
+	}
+
+	targets, err := svc.repo.GetPublishTargets(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get publish targets: %w", err)
+	}
+	for _, t := range targets {
+		archive.PublishTargets = append(archive.PublishTargets, ArchivePublishTarget{
+			Name:        t.Name,
+			Environment: t.Environment,
+			BaseURL:     t.BaseURL,
+			Kind:        t.Kind,
+			Settings:    t.Settings,
+		})
+	}
+
+	hooks, err := svc.repo.GetWebhooks(ctx)
+	if err != nil {
+		return archive, fmt.Errorf("cannot get webhooks: %w", err)
+	}
+	for _, h := range hooks {
+		archive.Webhooks = append(archive.Webhooks, ArchiveWebhook{Name: h.Name, URL: h.URL, Events: h.Events, Active: h.Active})
+	}
+
+	return archive, nil
+}
+
+// writeArchive writes the archive document followed by the static files as a
+// gzipped tarball. The document goes first so that a restore can validate it
+// before touching any file.
+func writeArchive(w io.Writer, archive *Archive, staticDir string) error {
+	files, err := staticFiles(staticDir)
+	if err != nil {
+		return fmt.Errorf("cannot read static dir: %w", err)
+	}
+	archive.Media = files
+
+	doc, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err = tw.WriteHeader(&tar.Header{Name: archiveSite, Mode: 0o644, Size: int64(len(doc)), ModTime: archive.ExportedAt})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		err = addArchiveFile(tw, filepath.Join(staticDir, filepath.FromSlash(name)), archiveStatic+name)
+		if err != nil {
+			return fmt.Errorf("cannot add %s: %w", name, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addArchiveFile(tw *tar.Writer, file, name string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: info.Size(), ModTime: info.ModTime()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// staticFiles lists the files of the static dir as slash separated paths.
+func staticFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && p == dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// readArchive opens a site archive and decodes its document. The returned
+// reader is positioned at the first static file.
+func readArchive(r io.Reader) (Archive, *tar.Reader, error) {
+	var archive Archive
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return archive, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != archiveSite {
+		return archive, nil, fmt.Errorf("%w: %s must be the first entry", ErrInvalidArchive, archiveSite)
+	}
+	err = json.NewDecoder(tr).Decode(&archive)
+	if err != nil {
+		return archive, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	if archive.Format != archiveFormat {
+		return archive, nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, archive.Format)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return archive, nil, fmt.Errorf("%w: version %d is not supported, up to %d is", ErrInvalidArchive, archive.Version, ArchiveVersion)
+	}
+	return archive, tr, nil
+}
+
+// siteRestore creates the entities of an archive that do not exist yet. Site
+// settings are restored only if the site still has the ones it was set up
+// with. Other entities are matched by their natural key: layouts by name,
+// content types by slug, sections by path, terms by kind and slug, data files
+// by name, profiles by slug, contents by URL, menus by name, series by slug,
+// redirects by source, publish targets by name and webhooks by URL. Refs of
+// matched entities map to the existing IDs so that everything restored points
+// to the right place.
+type siteRestore struct {
+	svc    *BaseService
+	userID uuid.UUID
+	opts   RestoreOptions
+	report *RestoreReport
+	ids    map[string]uuid.UUID // Archive ref to ID in this database
+	groups map[string]uuid.UUID // Archive translation ref to translation ID in this database
+}
+
+func (rs *siteRestore) run(ctx context.Context, archive Archive) error {
+	steps := []func(context.Context, Archive) error{
+		rs.settings,
+		rs.layouts,
+		rs.contentTypes,
+		rs.sections,
+		rs.terms,
+		rs.dataFiles,
+		rs.profiles,
+		rs.contents,
+		rs.menus,
+		rs.series,
+		rs.redirects,
+		rs.publishTargets,
+		rs.webhooks,
+	}
+	for _, step := range steps {
+		err := step(ctx, archive)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *siteRestore) created(kind string) {
+	rs.report.Created[kind]++
+}
+
+func (rs *siteRestore) skipped(kind string) {
+	rs.report.Skipped[kind]++
+}
+
+func (rs *siteRestore) settings(ctx context.Context, archive Archive) error {
+	as := archive.Settings
+	if as == nil {
+		return nil
+	}
+
+	saved, err := rs.svc.repo.GetSiteSettings(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err == nil && !saved.IsDefault() {
+		rs.skipped("settings")
+		return nil
+	}
+
+	settings := SiteSettings{
+		BaseModel:   restoredModel(siteSettingsType, as.CreatedAt, rs.userID),
+		Title:       as.Title,
+		BaseURL:     as.BaseURL,
+		DefaultLang: as.DefaultLang,
+		AuthorName:  as.AuthorName,
+		AuthorEmail: as.AuthorEmail,
+		AuthorURL:   as.AuthorURL,
+		SocialLinks: as.SocialLinks,
+		Analytics:   as.Analytics,
+		DateFormat:  as.DateFormat,
+		Description: as.Description,
+		SocialImage: as.SocialImage,
+		Logo:        as.Logo,
+	}
+	// Restored settings count as saved.
+	settings.GenUpdateValues(rs.userID)
+	if !rs.opts.DryRun {
+		err = rs.svc.repo.SaveSiteSettings(ctx, settings)
+		if err != nil {
+			return fmt.Errorf("cannot restore site settings: %w", err)
+		}
+	}
+	rs.created("settings")
+	return nil
+}
+
+func (rs *siteRestore) layouts(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetAllLayouts(ctx)
+	if err != nil {
+		return err
+	}
+	byName := map[string]uuid.UUID{}
+	for _, l := range existing {
+		byName[l.Name] = l.ID()
+	}
+
+	for _, al := range archive.Layouts {
+		if id, ok := byName[al.Name]; ok {
+			rs.ids[al.Ref] = id
+			rs.skipped("layouts")
+			continue
+		}
+
+		layout := Layout{
+			BaseModel:   restoredModel(layoutType, al.CreatedAt, rs.userID),
+			Name:        al.Name,
+			Description: al.Description,
+			Code:        al.Code,
+		}
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateLayout(ctx, layout)
+			if err != nil {
+				return fmt.Errorf("cannot restore layout %s: %w", al.Name, err)
+			}
+		}
+		rs.ids[al.Ref] = layout.ID()
+		byName[al.Name] = layout.ID()
+		rs.created("layouts")
+	}
+	return nil
+}
+
+func (rs *siteRestore) contentTypes(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetContentTypes(ctx)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]uuid.UUID{}
+	for _, ct := range existing {
+		bySlug[ct.SlugValue] = ct.ID()
+	}
+
+	for _, at := range archive.ContentTypes {
+		if id, ok := bySlug[at.Slug]; ok {
+			rs.ids[at.Ref] = id
+			rs.skipped("content_types")
+			continue
+		}
+
+		ct := ContentType{
+			BaseModel:   restoredModel(contentTypeType, at.CreatedAt, rs.userID),
+			Name:        at.Name,
+			SlugValue:   at.Slug,
+			Description: at.Description,
+			Fields:      at.Fields,
+			LayoutID:    rs.ids[at.LayoutRef],
+		}
+		if at.LayoutRef != "" && ct.LayoutID == uuid.Nil {
+			rs.report.warn("Content type %s uses a layout missing from the archive", at.Name)
+		}
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateContentType(ctx, ct)
+			if err != nil {
+				return fmt.Errorf("cannot restore content type %s: %w", at.Name, err)
+			}
+		}
+		rs.ids[at.Ref] = ct.ID()
+		bySlug[at.Slug] = ct.ID()
+		rs.created("content_types")
+	}
+	return nil
+}
+
+func (rs *siteRestore) sections(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetSections(ctx)
+	if err != nil {
+		return err
+	}
+	byPath := map[string]uuid.UUID{}
+	for _, s := range existing {
+		byPath[s.Path] = s.ID()
+	}
+
+	for _, as := range archive.Sections {
+		if id, ok := byPath[as.Path]; ok {
+			rs.ids[as.Ref] = id
+			rs.skipped("sections")
+			continue
+		}
+
+		section := Section{
+			BaseModel:   restoredModel(sectionType, as.CreatedAt, rs.userID),
+			Name:        as.Name,
+			Description: as.Description,
+			Path:        as.Path,
+			LayoutID:    rs.ids[as.LayoutRef],
+			Lang:        as.Lang,
+			Permalink:   as.Permalink,
+			Image:       as.Image,
+			Header:      as.Header,
+			PageMeta:    as.PageMeta,
+		}
+		if as.LayoutRef != "" && section.LayoutID == uuid.Nil {
+			rs.report.warn("Section %s uses a layout missing from the archive", as.Path)
+		}
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateSection(ctx, section)
+			if err != nil {
+				return fmt.Errorf("cannot restore section %s: %w", as.Path, err)
+			}
+		}
+		rs.ids[as.Ref] = section.ID()
+		byPath[as.Path] = section.ID()
+		rs.created("sections")
+	}
+	return nil
+}
+
+func (rs *siteRestore) terms(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetTerms(ctx)
+	if err != nil {
+		return err
+	}
+	byKey := map[string]uuid.UUID{}
+	for _, t := range existing {
+		byKey[t.Kind+"/"+t.SlugValue] = t.ID()
+	}
+
+	for _, at := range archive.Terms {
+		if id, ok := byKey[at.Kind+"/"+at.Slug]; ok {
+			rs.ids[at.Ref] = id
+			rs.skipped("terms")
+			continue
+		}
+
+		term := NewTerm(at.Kind, at.Name)
+		term.SlugValue = at.Slug
+		term.GenCreateValues(rs.userID)
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateTerm(ctx, term)
+			if err != nil {
+				return fmt.Errorf("cannot restore term %s: %w", at.Name, err)
+			}
+		}
+		rs.ids[at.Ref] = term.ID()
+		byKey[at.Kind+"/"+at.Slug] = term.ID()
+		rs.created("terms")
+	}
+	return nil
+}
+
+func (rs *siteRestore) dataFiles(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetDataFiles(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, f := range existing {
+		names[f.Name] = true
+	}
+
+	for _, af := range archive.DataFiles {
+		if names[af.Name] {
+			rs.skipped("data_files")
+			continue
+		}
+
+		file := DataFile{
+			BaseModel: restoredModel(dataFileType, af.CreatedAt, rs.userID),
+			Name:      af.Name,
+			Format:    af.Format,
+			Content:   af.Content,
+			Schema:    af.Schema,
+			Source:    af.Source,
+			Path:      af.Path,
+		}
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateDataFile(ctx, file)
+			if err != nil {
+				return fmt.Errorf("cannot restore data file %s: %w", af.Name, err)
+			}
+		}
+		names[af.Name] = true
+		rs.created("data_files")
+	}
+	return nil
+}
+
+// profiles restores author profiles as guest authors, since the users they
+// belonged to are not part of the archive.
+func (rs *siteRestore) profiles(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]uuid.UUID{}
+	for _, p := range existing {
+		bySlug[p.SlugValue] = p.ID()
+	}
+
+	for _, ap := range archive.Profiles {
+		if id, ok := bySlug[ap.Slug]; ok {
+			rs.ids[ap.Ref] = id
+			rs.skipped("profiles")
+			continue
+		}
+
+		profile := Profile{
+			BaseModel: restoredModel(profileType, ap.CreatedAt, rs.userID),
+			Name:      ap.Name,
+			SlugValue: ap.Slug,
+			Bio:       ap.Bio,
+			Avatar:    ap.Avatar,
+			Links:     ap.Links,
+		}
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateProfile(ctx, profile)
+			if err != nil {
+				return fmt.Errorf("cannot restore profile %s: %w", ap.Name, err)
+			}
+		}
+		rs.ids[ap.Ref] = profile.ID()
+		bySlug[ap.Slug] = profile.ID()
+		rs.created("profiles")
+		if ap.Account {
+			rs.report.warn("Profile %s was restored as a guest author, link it to its user", ap.Name)
+		}
+	}
+	return nil
+}
+
+func (rs *siteRestore) contents(ctx context.Context, archive Archive) error {
+	sections, err := rs.svc.repo.GetSections(ctx)
+	if err != nil {
+		return err
+	}
+	existing, err := rs.svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	// On dry runs the restored sections were not stored, so URLs are worked
+	// out from the archive ones.
+	if rs.opts.DryRun {
+		for _, as := range archive.Sections {
+			sections = append(sections, Section{BaseModel: am.NewModel(am.WithID(rs.ids[as.Ref])), Path: as.Path, Permalink: as.Permalink, Lang: as.Lang})
+		}
+	}
+
+	byURL := map[string]Content{}
+	for _, c := range existing {
+		byURL[rs.svc.contentURL(c, sections)] = c
+	}
+
+	for _, ac := range groupHeadsFirst(archive.Contents) {
+		content := Content{
+			BaseModel:   restoredModel(contentType, ac.CreatedAt, rs.userID),
+			UserID:      rs.userID,
+			SectionID:   rs.ids[ac.SectionRef],
+			TypeID:      rs.ids[ac.TypeRef],
+			Fields:      ac.Fields,
+			TOCMinDepth: ac.TOCMinDepth,
+			TOCMaxDepth: ac.TOCMaxDepth,
+			PageMeta:    ac.PageMeta,
+			Lang:        ac.Lang,
+			SlugValue:   ac.Slug,
+			Heading:     ac.Heading,
+			Body:        ac.Body,
+			Status:      ac.Status,
+			PublishedAt: ac.PublishedAt,
+		}
+
+		url := rs.svc.contentURL(content, sections)
+		if other, ok := byURL[url]; ok {
+			rs.ids[ac.Ref] = other.ID()
+			if _, ok := rs.groups[ac.TranslationRef]; !ok {
+				rs.groups[ac.TranslationRef] = other.TranslationKey()
+			}
+			rs.skipped("contents")
+			continue
+		}
+
+		content.TranslationID = content.ID()
+		if id, ok := rs.groups[ac.TranslationRef]; ok {
+			content.TranslationID = id
+		} else {
+			rs.groups[ac.TranslationRef] = content.ID()
+		}
+
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateContent(ctx, content)
+			if err != nil {
+				return fmt.Errorf("cannot restore content %s: %w", url, err)
+			}
+			for _, ref := range ac.TermRefs {
+				termID, ok := rs.ids[ref]
+				if !ok {
+					continue
+				}
+				err = rs.svc.repo.AddContentTerm(ctx, content.ID(), termID)
+				if err != nil {
+					return fmt.Errorf("cannot restore terms of %s: %w", url, err)
+				}
+			}
+			var authorIDs []uuid.UUID
+			for _, ref := range ac.AuthorRefs {
+				if id, ok := rs.ids[ref]; ok {
+					authorIDs = append(authorIDs, id)
+				}
+			}
+			if len(authorIDs) > 0 {
+				err = rs.svc.repo.SetContentAuthors(ctx, content.ID(), authorIDs)
+				if err != nil {
+					return fmt.Errorf("cannot restore authors of %s: %w", url, err)
+				}
+			}
+		}
+		rs.ids[ac.Ref] = content.ID()
+		byURL[url] = content
+		rs.created("contents")
+	}
+	return nil
+}
+
+// menus restores the menus missing from the site along with their items.
+// Menus the site already has keep their items.
+func (rs *siteRestore) menus(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetMenus(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, m := range existing {
+		names[m.Name] = true
+	}
+
+	for _, m := range archive.Menus {
+		if names[m.Name] {
+			rs.skipped("menus")
+			continue
+		}
+
+		menu := Menu{
+			BaseModel:   restoredModel(menuType, m.CreatedAt, rs.userID),
+			Name:        m.Name,
+			Description: m.Description,
+		}
+		// Items may come before their parents, so all of them get an ID first.
+		items := make([]MenuItem, len(m.Items))
+		for i, ai := range m.Items {
+			items[i] = MenuItem{
+				BaseModel: restoredModel(menuItemType, m.CreatedAt, rs.userID),
+				MenuID:    menu.ID(),
+				Label:     ai.Label,
+				Kind:      ai.Kind,
+				URL:       ai.URL,
+				Position:  ai.Position,
+			}
+			rs.ids[ai.Ref] = items[i].ID()
+		}
+		for i, ai := range m.Items {
+			items[i].ParentID = rs.ids[ai.ParentRef]
+			items[i].TargetID = rs.ids[ai.TargetRef]
+			if ai.TargetRef != "" && items[i].TargetID == uuid.Nil {
+				rs.report.warn("Menu %s has an item pointing at a %s missing from the archive", m.Name, ai.Kind)
+			}
+		}
+
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateMenu(ctx, menu)
+			if err != nil {
+				return fmt.Errorf("cannot restore menu %s: %w", m.Name, err)
+			}
+			for _, item := range items {
+				err = rs.svc.repo.CreateMenuItem(ctx, item)
+				if err != nil {
+					return fmt.Errorf("cannot restore items of menu %s: %w", m.Name, err)
+				}
+			}
+		}
+		names[m.Name] = true
+		rs.created("menus")
+	}
+	return nil
+}
+
+// series restores the series missing from the site along with their parts.
+// Series the site already has keep their parts.
+func (rs *siteRestore) series(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetAllSeries(ctx)
+	if err != nil {
+		return err
+	}
+	slugs := map[string]bool{}
+	for _, sr := range existing {
+		slugs[sr.SlugValue] = true
+	}
+
+	for _, as := range archive.Series {
+		if slugs[as.Slug] {
+			rs.skipped("series")
+			continue
+		}
+
+		series := Series{
+			BaseModel:   restoredModel(seriesType, as.CreatedAt, rs.userID),
+			Name:        as.Name,
+			SlugValue:   as.Slug,
+			Description: as.Description,
+		}
+		var contentIDs []uuid.UUID
+		for _, ref := range as.PartRefs {
+			id, ok := rs.ids[ref]
+			if !ok {
+				rs.report.warn("Series %s has a part missing from the archive", as.Name)
+				continue
+			}
+			contentIDs = append(contentIDs, id)
+		}
+
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateSeries(ctx, series)
+			if err != nil {
+				return fmt.Errorf("cannot restore series %s: %w", as.Name, err)
+			}
+			err = rs.svc.repo.SetSeriesParts(ctx, series.ID(), contentIDs)
+			if err != nil {
+				return fmt.Errorf("cannot restore parts of series %s: %w", as.Name, err)
+			}
+		}
+		slugs[as.Slug] = true
+		rs.created("series")
+	}
+	return nil
+}
+
+func (rs *siteRestore) redirects(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetAllRedirects(ctx)
+	if err != nil {
+		return err
+	}
+	sources := map[string]bool{}
+	for _, r := range existing {
+		sources[r.SourcePath] = true
+	}
+
+	for _, ar := range archive.Redirects {
+		if sources[ar.SourcePath] {
+			rs.skipped("redirects")
+			continue
+		}
+
+		redirect := NewRedirect(ar.SourcePath, ar.TargetPath, ar.StatusCode)
+		redirect.Wildcard = ar.Wildcard
+		redirect.Auto = ar.Auto
+		redirect.GenCreateValues(rs.userID)
+		if !rs.opts.DryRun {
+			err = rs.svc.repo.CreateRedirect(ctx, redirect)
+			if err != nil {
+				return fmt.Errorf("cannot restore redirect %s: %w", ar.SourcePath, err)
+			}
+		}
+		sources[ar.SourcePath] = true
+		rs.created("redirects")
+	}
+	return nil
+}
+
+func (rs *siteRestore) publishTargets(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetPublishTargets(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, t := range existing {
+		names[t.Name] = true
+	}
+
+	for _, at := range archive.PublishTargets {
+		if names[at.Name] {
+			rs.skipped("publish_targets")
+			continue
+		}
+
+		target := NewPublishTarget(at.Name, at.Environment, at.Kind)
+		target.BaseURL = at.BaseURL
+		target.Settings = at.Settings
+		target.GenCreateValues(rs.userID)
+		if !rs.opts.DryRun {
+			err = rs.svc.CreatePublishTarget(ctx, target)
+			if err != nil {
+				return fmt.Errorf("cannot restore publish target %s: %w", at.Name, err)
+			}
+		}
+		names[at.Name] = true
+		rs.created("publish_targets")
+		rs.report.warn("Publish target %s was restored without credentials", at.Name)
+	}
+	return nil
+}
+
+func (rs *siteRestore) webhooks(ctx context.Context, archive Archive) error {
+	existing, err := rs.svc.repo.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	urls := map[string]bool{}
+	for _, h := range existing {
+		urls[h.URL] = true
+	}
+
+	for _, ah := range archive.Webhooks {
+		if urls[ah.URL] {
+			rs.skipped("webhooks")
+			continue
+		}
+
+		hook := NewWebhook(ah.Name, ah.URL, ah.Events...)
+		hook.Active = ah.Active
+		hook.GenCreateValues(rs.userID)
+		if !rs.opts.DryRun {
+			err = rs.svc.CreateWebhook(ctx, hook)
+			if err != nil {
+				return fmt.Errorf("cannot restore webhook %s: %w", ah.Name, err)
+			}
+		}
+		urls[ah.URL] = true
+		rs.created("webhooks")
+		rs.report.warn("Webhook %s was restored with a new secret", ah.Name)
+	}
+	return nil
+}
+
+// groupHeadsFirst orders contents so that the one whose ID names its
+// translation group comes before the rest of the group, which then keeps
+// being named after it.
+func groupHeadsFirst(contents []ArchiveContent) []ArchiveContent {
+	ordered := make([]ArchiveContent, 0, len(contents))
+	for _, c := range contents {
+		if c.Ref == c.TranslationRef {
+			ordered = append(ordered, c)
+		}
+	}
+	for _, c := range contents {
+		if c.Ref != c.TranslationRef {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+// restoreFiles copies the static files of an archive into the static dir.
+// Files that already exist are left alone.
+func (rs *siteRestore) restoreFiles(tr *tar.Reader, staticDir string) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(hdr.Name, archiveStatic) {
+			continue
+		}
+
+		// Cleaning against the root keeps entries from escaping the static dir.
+		rel := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(hdr.Name, archiveStatic)), "/")
+		if rel == "" {
+			continue
+		}
+		dst := filepath.Join(staticDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(dst); err == nil {
+			rs.skipped("media")
+			continue
+		}
+
+		if !rs.opts.DryRun {
+			err = writeArchiveFile(tr, dst)
+			if err != nil {
+				return fmt.Errorf("cannot restore %s: %w", rel, err)
+			}
+		}
+		rs.created("media")
+	}
+}
+
+func writeArchiveFile(r io.Reader, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// restoredModel returns a new model that keeps the creation time it had on the
+// exporting site, which drafts use in their permalinks.
+func restoredModel(modelType string, createdAt time.Time, userID uuid.UUID) *am.BaseModel {
+	if createdAt.IsZero() {
+		createdAt = am.Now()
+	}
+	m := am.NewModel(am.WithType(modelType), am.WithCreatedAt(createdAt), am.WithUpdatedAt(createdAt),
+		am.WithCreatedBy(userID), am.WithUpdatedBy(userID))
+	m.GenID()
+	m.GenShortID()
+	return m
+}
+
+func refOf(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
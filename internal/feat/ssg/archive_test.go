@@ -0,0 +1,526 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// siteRepo keeps the entities of a site archive in memory.
+type siteRepo struct {
+	Repo
+	settings  SiteSettings
+	layouts   []Layout
+	types     []ContentType
+	sections  []Section
+	contents  []Content
+	terms     []Term
+	files     []DataFile
+	links     map[uuid.UUID][]uuid.UUID
+	menus     []Menu
+	items     []MenuItem
+	series    []Series
+	parts     []SeriesPart
+	redirects []Redirect
+	profiles  []Profile
+	authors   []ContentAuthor
+}
+
+func newSiteRepo() *siteRepo {
+	return &siteRepo{links: map[uuid.UUID][]uuid.UUID{}}
+}
+
+func (r *siteRepo) GetAllLayouts(ctx context.Context) ([]Layout, error) {
+	return r.layouts, nil
+}
+
+func (r *siteRepo) GetSections(ctx context.Context) ([]Section, error) {
+	return r.sections, nil
+}
+
+func (r *siteRepo) GetAllContent(ctx context.Context) ([]Content, error) {
+	return r.contents, nil
+}
+
+func (r *siteRepo) GetTerms(ctx context.Context) ([]Term, error) {
+	return r.terms, nil
+}
+
+func (r *siteRepo) GetAllRedirects(ctx context.Context) ([]Redirect, error) {
+	return r.redirects, nil
+}
+
+func (r *siteRepo) GetSiteSettings(ctx context.Context) (SiteSettings, error) {
+	if r.settings.IsZero() {
+		return SiteSettings{}, sql.ErrNoRows
+	}
+	return r.settings, nil
+}
+
+func (r *siteRepo) SaveSiteSettings(ctx context.Context, settings SiteSettings) error {
+	r.settings = settings
+	return nil
+}
+
+func (r *siteRepo) GetContentTypes(ctx context.Context) ([]ContentType, error) {
+	return r.types, nil
+}
+
+func (r *siteRepo) CreateContentType(ctx context.Context, ct ContentType) error {
+	r.types = append(r.types, ct)
+	return nil
+}
+
+func (r *siteRepo) GetDataFiles(ctx context.Context) ([]DataFile, error) {
+	return r.files, nil
+}
+
+func (r *siteRepo) CreateDataFile(ctx context.Context, file DataFile) error {
+	r.files = append(r.files, file)
+	return nil
+}
+
+func (r *siteRepo) GetMenus(ctx context.Context) ([]Menu, error) {
+	return r.menus, nil
+}
+
+func (r *siteRepo) GetMenuItems(ctx context.Context) ([]MenuItem, error) {
+	return r.items, nil
+}
+
+func (r *siteRepo) CreateMenu(ctx context.Context, menu Menu) error {
+	r.menus = append(r.menus, menu)
+	return nil
+}
+
+func (r *siteRepo) CreateMenuItem(ctx context.Context, item MenuItem) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *siteRepo) GetAllSeries(ctx context.Context) ([]Series, error) {
+	return r.series, nil
+}
+
+func (r *siteRepo) GetSeriesParts(ctx context.Context) ([]SeriesPart, error) {
+	return r.parts, nil
+}
+
+func (r *siteRepo) CreateSeries(ctx context.Context, series Series) error {
+	r.series = append(r.series, series)
+	return nil
+}
+
+func (r *siteRepo) SetSeriesParts(ctx context.Context, seriesID uuid.UUID, contentIDs []uuid.UUID) error {
+	for i, id := range contentIDs {
+		r.parts = append(r.parts, SeriesPart{SeriesID: seriesID, ContentID: id, Position: i})
+	}
+	return nil
+}
+
+func (r *siteRepo) GetProfiles(ctx context.Context) ([]Profile, error) {
+	return r.profiles, nil
+}
+
+func (r *siteRepo) GetContentAuthors(ctx context.Context) ([]ContentAuthor, error) {
+	return r.authors, nil
+}
+
+func (r *siteRepo) CreateProfile(ctx context.Context, profile Profile) error {
+	r.profiles = append(r.profiles, profile)
+	return nil
+}
+
+func (r *siteRepo) SetContentAuthors(ctx context.Context, contentID uuid.UUID, profileIDs []uuid.UUID) error {
+	for i, id := range profileIDs {
+		r.authors = append(r.authors, ContentAuthor{ContentID: contentID, ProfileID: id, Position: i})
+	}
+	return nil
+}
+
+func (r *siteRepo) GetPublishTargets(ctx context.Context) ([]PublishTarget, error) {
+	return nil, nil
+}
+
+func (r *siteRepo) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	return nil, nil
+}
+
+func (r *siteRepo) CreateLayout(ctx context.Context, l Layout) error {
+	r.layouts = append(r.layouts, l)
+	return nil
+}
+
+func (r *siteRepo) CreateSection(ctx context.Context, s Section) error {
+	r.sections = append(r.sections, s)
+	return nil
+}
+
+func (r *siteRepo) CreateContent(ctx context.Context, c Content) error {
+	r.contents = append(r.contents, c)
+	return nil
+}
+
+func (r *siteRepo) CreateTerm(ctx context.Context, t Term) error {
+	r.terms = append(r.terms, t)
+	return nil
+}
+
+func (r *siteRepo) CreateRedirect(ctx context.Context, rd Redirect) error {
+	r.redirects = append(r.redirects, rd)
+	return nil
+}
+
+func (r *siteRepo) AddContentTerm(ctx context.Context, contentID, termID uuid.UUID) error {
+	r.links[contentID] = append(r.links[contentID], termID)
+	return nil
+}
+
+func (r *siteRepo) GetContentTerms(ctx context.Context, contentID uuid.UUID) ([]Term, error) {
+	var terms []Term
+	for _, id := range r.links[contentID] {
+		for _, t := range r.terms {
+			if t.ID() == id {
+				terms = append(terms, t)
+			}
+		}
+	}
+	return terms, nil
+}
+
+func newArchiveService(t *testing.T, repo Repo) *BaseService {
+	t.Helper()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{am.Key.SSGStaticDir: filepath.Join(t.TempDir(), "static")})
+	opts := []am.Option{am.WithCfg(cfg), am.WithLog(am.NewLogger("error"))}
+	return &BaseService{
+		Service: am.NewService("ssg-service", opts...),
+		repo:    repo,
+		gen:     NewGenerator(embed.FS{}, repo, opts...),
+	}
+}
+
+func TestSiteArchiveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	src := newSiteRepo()
+	layout := Layout{BaseModel: am.NewModel(am.WithType(layoutType)), Name: "main", Code: `{{ define "layout" }}{{ end }}`}
+	layout.GenCreateValues()
+	blog := NewSection("Blog", "", "/blog", layout.ID())
+	blog.GenCreateValues()
+	blog.MetaDescription = "Notes on Go"
+	post := NewContent("Hello", "Body")
+	post.GenCreateValues()
+	post.SectionID, post.SlugValue, post.Status = blog.ID(), "hello", StatusPublished
+	post.TOCMinDepth, post.TOCMaxDepth = 2, 3
+	post.PageMeta = PageMeta{MetaTitle: "Hello, Go", CanonicalURL: "https://example.com/hello/", SocialImage: "/img/hello.png", NoIndex: true}
+	post.TranslationID = post.ID()
+	translation := NewContent("Hola", "Cuerpo")
+	translation.GenCreateValues()
+	translation.SectionID, translation.SlugValue, translation.Lang = blog.ID(), "hola", "es"
+	translation.TranslationID = post.ID()
+	tag := NewTerm(TermTag, "Go")
+	tag.GenCreateValues()
+	src.layouts, src.sections = []Layout{layout}, []Section{blog}
+	src.contents, src.terms = []Content{translation, post}, []Term{tag}
+	src.links[post.ID()] = []uuid.UUID{tag.ID()}
+	src.redirects = []Redirect{NewRedirect("/old/", "/blog/hello/", 301)}
+
+	exporter := newArchiveService(t, src)
+	if err := writeArchiveFile(bytes.NewReader([]byte("png")), filepath.Join(exporter.gen.StaticDir(ctx), "img", "logo.png")); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := exporter.ExportSite(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newSiteRepo()
+	restorer := newArchiveService(t, dst)
+	report, err := restorer.RestoreSite(ctx, bytes.NewReader(buf.Bytes()), uuid.Nil, RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for kind, want := range map[string]int{"layouts": 1, "sections": 1, "terms": 1, "contents": 2, "redirects": 1, "media": 1} {
+		if got := report.Created[kind]; got != want {
+			t.Errorf("created %s = %d, want %d", kind, got, want)
+		}
+	}
+	if dst.sections[0].LayoutID != dst.layouts[0].ID() || dst.sections[0].ID() == blog.ID() {
+		t.Errorf("section not mapped to the restored layout")
+	}
+	var restoredPost, restoredTranslation Content
+	for _, c := range dst.contents {
+		if c.SectionID != dst.sections[0].ID() {
+			t.Errorf("content %s not mapped to the restored section", c.SlugValue)
+		}
+		if c.SlugValue == "hello" {
+			restoredPost = c
+		} else {
+			restoredTranslation = c
+		}
+	}
+	if dst.sections[0].MetaDescription != "Notes on Go" {
+		t.Errorf("section SEO metadata not restored: %+v", dst.sections[0].PageMeta)
+	}
+	if restoredPost.PageMeta != post.PageMeta {
+		t.Errorf("content SEO metadata not restored: %+v", restoredPost.PageMeta)
+	}
+	if restoredPost.TOCMinDepth != 2 || restoredPost.TOCMaxDepth != 3 {
+		t.Errorf("table of contents depth not restored: %d to %d", restoredPost.TOCMinDepth, restoredPost.TOCMaxDepth)
+	}
+	if restoredTranslation.TranslationID != restoredPost.ID() {
+		t.Errorf("translation group not kept: %s, want %s", restoredTranslation.TranslationID, restoredPost.ID())
+	}
+	if terms := dst.links[restoredPost.ID()]; len(terms) != 1 || terms[0] != dst.terms[0].ID() {
+		t.Errorf("content terms not restored: %v", terms)
+	}
+	if _, err := os.Stat(filepath.Join(restorer.gen.StaticDir(ctx), "img", "logo.png")); err != nil {
+		t.Errorf("static file not restored: %v", err)
+	}
+
+	// Restoring again keeps what is already there.
+	report, err = restorer.RestoreSite(ctx, bytes.NewReader(buf.Bytes()), uuid.Nil, RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Created) != 0 || report.Skipped["contents"] != 2 || len(dst.contents) != 2 {
+		t.Errorf("second restore created %v, want nothing", report.Created)
+	}
+}
+
+// restoreExported exports src and restores it into dst.
+func restoreExported(t *testing.T, src, dst *siteRepo) RestoreReport {
+	t.Helper()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if _, err := newArchiveService(t, src).ExportSite(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	report, err := newArchiveService(t, dst).RestoreSite(ctx, &buf, uuid.Nil, RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestSiteArchiveSettings(t *testing.T) {
+	src := newSiteRepo()
+	src.settings = NewSiteSettings("Notes", "en")
+	src.settings.GenCreateValues(uuid.New())
+	src.settings.BaseURL = "https://example.com"
+	src.settings.SocialLinks = []SocialLink{{Name: "GitHub", URL: "https://github.com/notes"}}
+	src.settings.AuthorName = "Ann"
+	src.settings.Logo = "/img/logo.png"
+
+	// The settings a site is set up with are not worth keeping.
+	dst := newSiteRepo()
+	dst.settings = NewSiteSettings("Hermes", "en")
+	dst.settings.GenCreateValues()
+	report := restoreExported(t, src, dst)
+	if report.Created["settings"] != 1 {
+		t.Fatalf("created settings = %d, want 1", report.Created["settings"])
+	}
+	got := dst.settings
+	if got.Title != "Notes" || got.BaseURL != "https://example.com" || got.AuthorName != "Ann" || got.Logo != "/img/logo.png" ||
+		len(got.SocialLinks) != 1 || got.SocialLinks[0].URL != "https://github.com/notes" {
+		t.Errorf("settings not restored: %+v", got)
+	}
+
+	// Settings already saved on the site are kept.
+	dst.settings.Title = "Kept"
+	report = restoreExported(t, src, dst)
+	if report.Skipped["settings"] != 1 || dst.settings.Title != "Kept" {
+		t.Errorf("saved settings overwritten: %+v", dst.settings)
+	}
+}
+
+func TestSiteArchiveContentTypes(t *testing.T) {
+	src := newSiteRepo()
+	layout := Layout{BaseModel: am.NewModel(am.WithType(layoutType)), Name: "recipe"}
+	layout.GenCreateValues()
+	recipe := NewContentType("Recipe", "")
+	recipe.GenCreateValues()
+	recipe.LayoutID = layout.ID()
+	recipe.Fields = []FieldDef{{Name: "servings", Label: "Servings", Type: FieldNumber, Required: true}}
+	post := NewContent("Soup", "Body")
+	post.GenCreateValues()
+	post.SlugValue, post.TypeID, post.Fields = "soup", recipe.ID(), map[string]string{"servings": "4"}
+	post.TranslationID = post.ID()
+	src.layouts, src.types, src.contents = []Layout{layout}, []ContentType{recipe}, []Content{post}
+
+	dst := newSiteRepo()
+	report := restoreExported(t, src, dst)
+	if report.Created["content_types"] != 1 || len(dst.types) != 1 {
+		t.Fatalf("created content types = %d, want 1", report.Created["content_types"])
+	}
+	got := dst.types[0]
+	if got.SlugValue != "recipe" || got.LayoutID != dst.layouts[0].ID() || len(got.Fields) != 1 || got.Fields[0] != recipe.Fields[0] {
+		t.Errorf("content type not restored: %+v", got)
+	}
+	if c := dst.contents[0]; c.TypeID != got.ID() || c.Fields["servings"] != "4" {
+		t.Errorf("content type and fields of content not restored: %s %v", c.TypeID, c.Fields)
+	}
+}
+
+func TestSiteArchiveDataFiles(t *testing.T) {
+	src := newSiteRepo()
+	plans := NewDataFile("plans", DataJSON, `[{"name": "Basic"}]`)
+	plans.GenCreateValues()
+	plans.Schema = []FieldDef{{Name: "name", Type: FieldText, Required: true}}
+	src.files = []DataFile{plans}
+
+	dst := newSiteRepo()
+	kept := NewDataFile("authors", DataYAML, "- Ann\n")
+	kept.GenCreateValues()
+	dst.files = []DataFile{kept}
+
+	report := restoreExported(t, src, dst)
+	if report.Created["data_files"] != 1 || len(dst.files) != 2 {
+		t.Fatalf("created data files = %d, want 1", report.Created["data_files"])
+	}
+	got := dst.files[1]
+	if got.Name != "plans" || got.Format != DataJSON || got.Content != plans.Content || len(got.Schema) != 1 {
+		t.Errorf("data file not restored: %+v", got)
+	}
+
+	report = restoreExported(t, src, dst)
+	if report.Skipped["data_files"] != 1 || len(dst.files) != 2 {
+		t.Errorf("second restore created %v, want nothing", report.Created)
+	}
+}
+
+func TestSiteArchiveMenus(t *testing.T) {
+	src := newSiteRepo()
+	blog := NewSection("Blog", "", "/blog", uuid.Nil)
+	blog.GenCreateValues()
+	main := NewMenu("main", "")
+	main.GenCreateValues()
+	// The child comes first to check that parents are mapped regardless of order.
+	parent := NewMenuItem(main.ID(), "Blog", MenuItemSection)
+	parent.GenCreateValues()
+	parent.TargetID = blog.ID()
+	child := NewMenuItem(main.ID(), "Docs", MenuItemURL)
+	child.GenCreateValues()
+	child.ParentID, child.URL, child.Position = parent.ID(), "https://docs.example.com", 1
+	src.sections, src.menus, src.items = []Section{blog}, []Menu{main}, []MenuItem{child, parent}
+
+	dst := newSiteRepo()
+	report := restoreExported(t, src, dst)
+	if report.Created["menus"] != 1 || len(dst.menus) != 1 || len(dst.items) != 2 {
+		t.Fatalf("created menus = %d with %d items, want 1 with 2", report.Created["menus"], len(dst.items))
+	}
+	gotChild, gotParent := dst.items[0], dst.items[1]
+	if gotParent.MenuID != dst.menus[0].ID() || gotParent.TargetID != dst.sections[0].ID() || gotParent.ParentID != uuid.Nil {
+		t.Errorf("parent item not restored: %+v", gotParent)
+	}
+	if gotChild.ParentID != gotParent.ID() || gotChild.URL != child.URL || gotChild.Position != 1 {
+		t.Errorf("child item not restored: %+v", gotChild)
+	}
+
+	report = restoreExported(t, src, dst)
+	if report.Skipped["menus"] != 1 || len(dst.items) != 2 {
+		t.Errorf("second restore created %v, want nothing", report.Created)
+	}
+}
+
+func TestSiteArchiveSeries(t *testing.T) {
+	src := newSiteRepo()
+	var parts []Content
+	for _, slug := range []string{"one", "two"} {
+		c := NewContent(slug, "Body")
+		c.GenCreateValues()
+		c.SlugValue = slug
+		c.TranslationID = c.ID()
+		parts = append(parts, c)
+	}
+	tour := NewSeries("Go tour", "")
+	tour.GenCreateValues()
+	src.contents, src.series = parts, []Series{tour}
+	// Parts are listed out of order to check they are exported by position.
+	src.parts = []SeriesPart{
+		{SeriesID: tour.ID(), ContentID: parts[1].ID(), Position: 1},
+		{SeriesID: tour.ID(), ContentID: parts[0].ID(), Position: 0},
+	}
+
+	dst := newSiteRepo()
+	report := restoreExported(t, src, dst)
+	if report.Created["series"] != 1 || len(dst.series) != 1 || len(dst.parts) != 2 {
+		t.Fatalf("created series = %d with %d parts, want 1 with 2", report.Created["series"], len(dst.parts))
+	}
+	for i, p := range dst.parts {
+		if p.SeriesID != dst.series[0].ID() || p.ContentID != dst.contents[i].ID() || p.Position != i {
+			t.Errorf("part %d not restored in order: %+v", i, p)
+		}
+	}
+
+	report = restoreExported(t, src, dst)
+	if report.Skipped["series"] != 1 || len(dst.parts) != 2 {
+		t.Errorf("second restore created %v, want nothing", report.Created)
+	}
+}
+
+func TestSiteArchiveProfiles(t *testing.T) {
+	src := newSiteRepo()
+	ann := NewProfile("Ann", "Writes about Go")
+	ann.GenCreateValues()
+	ann.UserID = uuid.New()
+	ann.Links = []SocialLink{{Name: "Site", URL: "https://ann.example.com"}}
+	bob := NewProfile("Bob", "")
+	bob.GenCreateValues()
+	post := NewContent("Hello", "Body")
+	post.GenCreateValues()
+	post.SlugValue = "hello"
+	post.TranslationID = post.ID()
+	src.profiles, src.contents = []Profile{ann, bob}, []Content{post}
+	// Bob comes first in the byline.
+	src.authors = []ContentAuthor{
+		{ContentID: post.ID(), ProfileID: ann.ID(), Position: 1},
+		{ContentID: post.ID(), ProfileID: bob.ID(), Position: 0},
+	}
+
+	dst := newSiteRepo()
+	report := restoreExported(t, src, dst)
+	if report.Created["profiles"] != 2 || len(dst.profiles) != 2 {
+		t.Fatalf("created profiles = %d, want 2", report.Created["profiles"])
+	}
+	gotAnn, gotBob := dst.profiles[0], dst.profiles[1]
+	if gotAnn.SlugValue != ann.SlugValue || gotAnn.Bio != ann.Bio || len(gotAnn.Links) != 1 || gotAnn.UserID != uuid.Nil {
+		t.Errorf("profile not restored as a guest author: %+v", gotAnn)
+	}
+	if len(report.Warnings) != 1 {
+		t.Errorf("expected a warning for the profile of a user, got %v", report.Warnings)
+	}
+	want := []uuid.UUID{gotBob.ID(), gotAnn.ID()}
+	if got := contentAuthors(dst.authors, dst.contents[0].ID()); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("authors = %v, want %v", got, want)
+	}
+
+	report = restoreExported(t, src, dst)
+	if report.Skipped["profiles"] != 2 || len(dst.profiles) != 2 {
+		t.Errorf("second restore created %v, want nothing", report.Created)
+	}
+}
+
+func TestReadArchiveVersion(t *testing.T) {
+	var buf bytes.Buffer
+	archive := Archive{Format: archiveFormat, Version: ArchiveVersion + 1}
+	if err := writeArchive(&buf, &archive, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := readArchive(&buf)
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("expected ErrInvalidArchive for a newer version, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package ssg
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	buildType = "build"
+)
+
+const (
+	BuildTriggerManual = "manual"
+	BuildTriggerCLI    = "cli"
+	BuildTriggerAPI    = "api"
+)
+
+const (
+	BuildRunning   = "running"
+	BuildSucceeded = "succeeded"
+	BuildFailed    = "failed"
+)
+
+// Build is a site generation run.
+type Build struct {
+	*am.BaseModel
+	Trigger       string            `json:"trigger"`
+	UserID        uuid.UUID         `json:"user_id"`
+	Status        string            `json:"status"`
+	StartedAt     time.Time         `json:"started_at"`
+	FinishedAt    time.Time         `json:"finished_at"`
+	PagesRendered int               `json:"pages_rendered"`
+	PagesSkipped  int               `json:"pages_skipped"` // Pages not generated because of the language fallback policy or section language
+	PagesDeleted  int               `json:"pages_deleted"` // Pages of the previous output no longer generated
+	Warnings      []string          `json:"warnings"`
+	Errors        []string          `json:"errors"`
+	OutputHash    string            `json:"output_hash"`
+	DataHashes    map[string]string `json:"data_hashes"` // Hash of each data file the layouts used, by name
+	// Set from the release tree, not persisted.
+	Live     bool `json:"live"`     // True if the build output is the one being served
+	Retained bool `json:"retained"` // True if the build output is kept and can be rolled back to
+}
+
+// CanRollback reports whether the site can be switched back to the output of
+// this build.
+func (b Build) CanRollback() bool {
+	return b.Status == BuildSucceeded && b.Retained && !b.Live
+}
+
+// BuildStats is what a generator reports about a run.
+type BuildStats struct {
+	PagesRendered int
+	PagesSkipped  int
+	PagesDeleted  int
+	Warnings      []string
+	OutputHash    string
+	DataHashes    map[string]string
+}
+
+func NewBuild(trigger string, userID uuid.UUID) Build {
+	return Build{
+		BaseModel: am.NewModel(am.WithType(buildType)),
+		Trigger:   trigger,
+		UserID:    userID,
+		Status:    BuildRunning,
+		StartedAt: am.Now(),
+	}
+}
+
+func (b Build) IsZero() bool {
+	return b.BaseModel == nil || b.BaseModel.IsZero()
+}
+
+// Duration returns how long the build took, or has been running for.
+func (b Build) Duration() time.Duration {
+	if b.FinishedAt.IsZero() {
+		return time.Since(b.StartedAt).Round(time.Millisecond)
+	}
+	return b.FinishedAt.Sub(b.StartedAt).Round(time.Millisecond)
+}
+
+// Finish records the result of the run.
+func (b *Build) Finish(stats BuildStats, err error) {
+	b.FinishedAt = am.Now()
+	b.PagesRendered = stats.PagesRendered
+	b.PagesSkipped = stats.PagesSkipped
+	b.PagesDeleted = stats.PagesDeleted
+	b.Warnings = stats.Warnings
+	b.OutputHash = stats.OutputHash
+	b.DataHashes = stats.DataHashes
+	b.Status = BuildSucceeded
+	if err != nil {
+		b.Status = BuildFailed
+		b.Errors = append(b.Errors, err.Error())
+	}
+}
+
+func (b *Build) Slug() string {
+	return b.StartedAt.Format("20060102-150405") + "-" + b.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (b *Build) UnmarshalJSON(data []byte) error {
+	type Alias Build
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*b = Build(*temp)
+	if b.BaseModel == nil {
+		b.BaseModel = am.NewModel(am.WithType(buildType))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BuildDA struct {
+	ID            uuid.UUID  `db:"id"`
+	ShortID       string     `db:"short_id"`
+	SiteID        string     `db:"site_id"`
+	Trigger       string     `db:"trigger"`
+	UserID        string     `db:"user_id"`
+	Status        string     `db:"status"`
+	StartedAt     *time.Time `db:"started_at"`
+	FinishedAt    *time.Time `db:"finished_at"`
+	DurationMS    int64      `db:"duration_ms"`
+	PagesRendered int        `db:"pages_rendered"`
+	PagesSkipped  int        `db:"pages_skipped"`
+	PagesDeleted  int        `db:"pages_deleted"`
+	Warnings      string     `db:"warnings"`
+	Errors        string     `db:"errors"`
+	OutputHash    string     `db:"output_hash"`
+	DataHashes    string     `db:"data_hashes"`
+	CreatedBy     *string    `db:"created_by"`
+	UpdatedBy     *string    `db:"updated_by"`
+	CreatedAt     *time.Time `db:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+}
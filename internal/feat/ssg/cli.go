@@ -0,0 +1,375 @@
+package ssg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+const cliUsage = `usage: hermes [-ssg.site <slug>] <command> [args]
+
+Commands work on the default site unless -ssg.site chooses another one.
+
+commands:
+  generate            generate and release the site
+  sync-data           sync the data files of the site from its data dir
+  builds              list builds
+  rollback <build-id> make the output of a previous build live again
+  targets             list publish targets
+  publish [-dry-run] <target> [build-id]
+                      publish a build to a target, the live one by default
+  promote <target> [build-id]
+                      publish to production a build already on staging
+  import [-dry-run] [-no-media] hugo|jekyll|wordpress <path>
+                      import a Hugo or Jekyll site dir or a WordPress export
+  export [-o file]    write the site and its static files to an archive
+  restore [-dry-run] <file>
+                      restore a site archive, keeping what already exists`
+
+// CLI runs site maintenance commands from the command line.
+type CLI struct {
+	svc  Service
+	out  io.Writer
+	site string
+}
+
+// NewCLI returns a CLI whose commands work on the site with the given slug.
+func NewCLI(svc Service, out io.Writer, site string) *CLI {
+	return &CLI{svc: svc, out: out, site: site}
+}
+
+// Run executes the command named by the first argument.
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	site, err := c.svc.GetSiteBySlug(ctx, c.site)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no site with slug %q", c.site)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot get site %s: %w", c.site, err)
+	}
+	ctx = WithSite(ctx, site)
+
+	if len(args) == 0 {
+		return fmt.Errorf("%w\n%s", ErrUnknownCommand, cliUsage)
+	}
+
+	switch args[0] {
+	case "generate":
+		return c.generate(ctx)
+	case "sync-data":
+		return c.syncData(ctx)
+	case "builds":
+		return c.builds(ctx)
+	case "rollback":
+		if len(args) != 2 {
+			return fmt.Errorf("missing build ID\n%s", cliUsage)
+		}
+		return c.rollback(ctx, args[1])
+	case "targets":
+		return c.targets(ctx)
+	case "publish":
+		return c.publish(ctx, args[1:])
+	case "promote":
+		return c.promote(ctx, args[1:])
+	case "import":
+		return c.importSite(ctx, args[1:])
+	case "export":
+		return c.export(ctx, args[1:])
+	case "restore":
+		return c.restore(ctx, args[1:])
+	default:
+		return fmt.Errorf("%w: %s\n%s", ErrUnknownCommand, args[0], cliUsage)
+	}
+}
+
+func (c *CLI) generate(ctx context.Context) error {
+	build, err := c.svc.GenerateSite(ctx, BuildTriggerCLI, uuid.Nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "Build %s released: %d pages rendered, %d warnings\n", build.ID(), build.PagesRendered, len(build.Warnings))
+	return nil
+}
+
+func (c *CLI) syncData(ctx context.Context) error {
+	report, err := c.svc.SyncDataFiles(ctx, uuid.Nil)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range report.Errors {
+		fmt.Fprintf(c.out, "skipped %s\n", e)
+	}
+	fmt.Fprintf(c.out, "Data files synced: %d created, %d updated, %d deleted\n", len(report.Created), len(report.Updated), len(report.Deleted))
+	return nil
+}
+
+func (c *CLI) builds(ctx context.Context) error {
+	builds, err := c.svc.GetBuilds(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tTRIGGER\tSTATUS\tPAGES\tRELEASE")
+	for _, b := range builds {
+		release := ""
+		switch {
+		case b.Live:
+			release = "live"
+		case b.Retained:
+			release = "retained"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", b.ID(), b.StartedAt.Format("2006-01-02 15:04:05"), b.Trigger, b.Status, b.PagesRendered, release)
+	}
+	return w.Flush()
+}
+
+func (c *CLI) rollback(ctx context.Context, id string) error {
+	build, err := c.svc.RollbackBuild(ctx, id)
+	if err != nil {
+		return fmt.Errorf("cannot rollback to build %s: %w", id, err)
+	}
+
+	fmt.Fprintf(c.out, "Site rolled back to build %s\n", build.ID())
+	return nil
+}
+
+func (c *CLI) targets(ctx context.Context) error {
+	targets, err := c.svc.GetPublishTargets(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tENVIRONMENT\tKIND\tBASE URL")
+	for _, t := range targets {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, t.Environment, t.Kind, t.BaseURL)
+	}
+	return w.Flush()
+}
+
+func (c *CLI) publish(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	dryRun := fs.Bool("dry-run", false, "report the changes without publishing them")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	target, id, err := c.targetAndBuild(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	_, result, err := c.svc.PublishBuild(ctx, id, target.ID().String(), uuid.Nil, Author{}, *dryRun)
+	if err != nil {
+		return fmt.Errorf("cannot publish build %s to %s: %w", id, target.Name, err)
+	}
+
+	c.printResult(id, target, result)
+	return nil
+}
+
+func (c *CLI) promote(ctx context.Context, args []string) error {
+	target, id, err := c.targetAndBuild(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	_, result, err := c.svc.PromoteBuild(ctx, id, target.ID().String(), uuid.Nil, Author{})
+	if err != nil {
+		return fmt.Errorf("cannot promote build %s to %s: %w", id, target.Name, err)
+	}
+
+	c.printResult(id, target, result)
+	return nil
+}
+
+// targetAndBuild resolves the target name and optional build ID arguments.
+func (c *CLI) targetAndBuild(ctx context.Context, args []string) (PublishTarget, string, error) {
+	if len(args) == 0 {
+		return PublishTarget{}, "", fmt.Errorf("missing target\n%s", cliUsage)
+	}
+
+	target, err := c.svc.GetPublishTargetByName(ctx, args[0])
+	if err != nil {
+		return target, "", fmt.Errorf("cannot get target %s: %w", args[0], err)
+	}
+
+	if len(args) > 1 {
+		return target, args[1], nil
+	}
+
+	id, err := c.liveBuildID(ctx)
+	return target, id, err
+}
+
+func (c *CLI) printResult(id string, target PublishTarget, result PublishResult) {
+	for _, change := range result.Changes {
+		fmt.Fprintln(c.out, change.String())
+	}
+	switch {
+	case result.DryRun:
+		fmt.Fprintf(c.out, "Dry run: %d files would change\n", len(result.Changes))
+	case !result.HasChanges():
+		fmt.Fprintf(c.out, "Nothing to publish, %s is up to date\n", target.Name)
+	default:
+		fmt.Fprintf(c.out, "Build %s published to %s, %d files changed\n", id, target.Name, len(result.Changes))
+	}
+}
+
+func (c *CLI) importSite(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without saving anything")
+	noMedia := fs.Bool("no-media", false, "do not copy or download media files")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("missing format or path\n%s", cliUsage)
+	}
+
+	src, err := readImportSource(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	report, err := c.svc.ImportSite(ctx, src, uuid.Nil, ImportOptions{DryRun: *dryRun, NoMedia: *noMedia})
+	if err != nil {
+		return fmt.Errorf("cannot import %s: %w", src.Path, err)
+	}
+
+	for _, e := range report.Skipped() {
+		fmt.Fprintf(c.out, "skipped %s (%s): %s\n", e.Source, e.URL, e.Reason)
+	}
+	for _, w := range report.Warnings {
+		fmt.Fprintf(c.out, "warning: %s\n", w)
+	}
+	if report.DryRun {
+		fmt.Fprint(c.out, "Dry run: ")
+	}
+	fmt.Fprintf(c.out, "%d contents, %d sections, %d terms, %d redirects and %d media files imported, %d skipped\n",
+		report.Contents, report.Sections, report.Terms, report.Redirects, report.Media, len(report.Skipped()))
+	fmt.Fprintf(c.out, "Report saved to %s\n", report.File)
+	return nil
+}
+
+func (c *CLI) export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	file := fs.String("o", "", "archive file, hermes-<date>.tar.gz by default")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		*file = "hermes-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+
+	archive, err := c.svc.ExportSite(ctx, f)
+	if err != nil {
+		f.Close()
+		os.Remove(*file)
+		return fmt.Errorf("cannot export site: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "Site exported to %s: %d contents, %d sections, %d layouts, %d redirects and %d media files\n",
+		*file, len(archive.Contents), len(archive.Sections), len(archive.Layouts), len(archive.Redirects), len(archive.Media))
+	return nil
+}
+
+func (c *CLI) restore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	dryRun := fs.Bool("dry-run", false, "report what would be restored without saving anything")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("missing archive file\n%s", cliUsage)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := c.svc.RestoreSite(ctx, f, uuid.Nil, RestoreOptions{DryRun: *dryRun})
+	if err != nil {
+		return fmt.Errorf("cannot restore %s: %w", fs.Arg(0), err)
+	}
+
+	for _, w := range report.Warnings {
+		fmt.Fprintf(c.out, "warning: %s\n", w)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tCREATED\tSKIPPED")
+	for _, kind := range restoreKinds {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", kind, report.Created[kind], report.Skipped[kind])
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+	if report.DryRun {
+		fmt.Fprintln(c.out, "Dry run: nothing was saved")
+	}
+	return nil
+}
+
+func readImportSource(format, p string) (ImportSource, error) {
+	switch format {
+	case ImportHugo:
+		return ReadHugo(p)
+	case ImportJekyll:
+		return ReadJekyll(p)
+	case ImportWordPress:
+		f, err := os.Open(p)
+		if err != nil {
+			return ImportSource{}, err
+		}
+		defer f.Close()
+		return ReadWordPress(f, p)
+	default:
+		return ImportSource{}, fmt.Errorf("unknown import format %s\n%s", format, cliUsage)
+	}
+}
+
+func (c *CLI) liveBuildID(ctx context.Context) (string, error) {
+	builds, err := c.svc.GetBuilds(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range builds {
+		if b.Live {
+			return b.ID().String(), nil
+		}
+	}
+	return "", ErrNoRelease
+}
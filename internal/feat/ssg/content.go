@@ -2,6 +2,7 @@ package ssg
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/adrianpk/hermes/internal/am"
 	"github.com/google/uuid"
@@ -11,13 +12,29 @@ const (
 	contentType = "content"
 )
 
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
 type Content struct {
 	*am.BaseModel
-	UserID    uuid.UUID
-	SectionID uuid.UUID
-	Heading   string `json:"heading"`
-	Body      string `json:"body"`
-	Status    string
+	UserID        uuid.UUID
+	SectionID     uuid.UUID
+	TypeID        uuid.UUID         `json:"type_id"`
+	Fields        map[string]string `json:"fields"`        // Values of the fields of its type, by field name
+	TOCMinDepth   int               `json:"toc_min_depth"` // Shallowest heading level in the table of contents, 0 for the default
+	TOCMaxDepth   int               `json:"toc_max_depth"` // Deepest heading level in the table of contents, 0 for the default
+	AuthorIDs     []uuid.UUID       `json:"author_ids"`    // Profiles credited in the byline in order, nil keeps the saved ones on update
+	Lang          string            `json:"lang"`
+	TranslationID uuid.UUID         `json:"translation_id"`
+	SlugValue     string            `json:"slug"`
+	Heading       string            `json:"heading"`
+	Body          string            `json:"body"`
+	Status        string
+	PublishedAt   time.Time `json:"published_at"`
+	Version       int       `json:"version"` // Bumped on every save, saves based on an older one are rejected
+	PageMeta
 }
 
 func NewContent(heading, body string) Content {
@@ -32,7 +49,50 @@ func (c Content) IsZero() bool {
 	return c.BaseModel.IsZero()
 }
 
+// TranslationKey returns the ID of the translation group this content belongs
+// to. Content that is not a translation of anything is its own group.
+func (c Content) TranslationKey() uuid.UUID {
+	if c.TranslationID != uuid.Nil {
+		return c.TranslationID
+	}
+	return c.ID()
+}
+
+// LangOr returns the content language or def if none was set.
+func (c Content) LangOr(def string) string {
+	if c.Lang == "" {
+		return def
+	}
+	return c.Lang
+}
+
+// TOCDepth returns the range of heading levels listed in the table of
+// contents, falling back to the defaults for the unset ends.
+func (c Content) TOCDepth() (min, max int) {
+	min, max = c.TOCMinDepth, c.TOCMaxDepth
+	if min == 0 {
+		min = defTOCMinDepth
+	}
+	if max == 0 {
+		max = defTOCMaxDepth
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
+
+// IsPublished reports whether the content is live on the generated site.
+func (c Content) IsPublished() bool {
+	return c.Status == StatusPublished
+}
+
+// Slug returns the author editable slug. Content created before slugs were
+// stored falls back to the heading and short ID based one.
 func (r *Content) Slug() string {
+	if r.SlugValue != "" {
+		return r.SlugValue
+	}
 	return am.Normalize(r.Heading) + "-" + r.ShortID()
 }
 
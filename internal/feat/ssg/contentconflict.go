@@ -0,0 +1,195 @@
+package ssg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxDiffCells bounds the work of a line diff, longer texts are shown
+	// whole instead.
+	maxDiffCells = 1 << 20
+)
+
+const (
+	DiffSame    = "same"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+)
+
+// ContentConflict is what the edit form shows when a save is rejected because
+// someone else saved the content meanwhile.
+type ContentConflict struct {
+	ContentID uuid.UUID
+	Version   int    // Saved version, posted back to save over it
+	By        string // Name of who saved it
+	At        time.Time
+	Changes   []FieldChange
+}
+
+// FieldChange is a field of a content whose value differs between the one an
+// editor tried to save and the one saved by someone else meanwhile.
+type FieldChange struct {
+	Field string // Form name of the field
+	Label string
+	Yours string
+	Saved string
+	Diff  []DiffLine // From yours to saved, only for long texts
+}
+
+// DiffLine is a line of a line by line diff.
+type DiffLine struct {
+	Kind string // DiffSame, DiffAdded or DiffRemoved
+	Text string
+}
+
+// contentChanges returns the fields in which yours differs from saved, in form
+// order. Sections and profiles, which may be nil, name the section and the
+// authors instead of their IDs, ct labels the fields of the content type.
+// Authors are only compared if yours has them set.
+func contentChanges(yours, saved Content, sections []Section, profiles []Profile, ct ContentType) []FieldChange {
+	var changes []FieldChange
+	add := func(field, label, y, s string) {
+		if y != s {
+			changes = append(changes, FieldChange{Field: field, Label: label, Yours: y, Saved: s})
+		}
+	}
+	addText := func(field, label, y, s string) {
+		if y != s {
+			changes = append(changes, FieldChange{Field: field, Label: label, Yours: y, Saved: s, Diff: diffLines(y, s)})
+		}
+	}
+
+	add("section_id", "Section", sectionLabel(sections, yours.SectionID), sectionLabel(sections, saved.SectionID))
+	add("lang", "Language", yours.Lang, saved.Lang)
+	add("heading", "Heading", yours.Heading, saved.Heading)
+	add("slug", "Slug", yours.SlugValue, saved.SlugValue)
+	add("status", "Status", yours.Status, saved.Status)
+	if yours.AuthorIDs != nil {
+		add("author_ids", "Authors", profileNames(profiles, yours.AuthorIDs), profileNames(profiles, saved.AuthorIDs))
+	}
+
+	for _, name := range fieldNames(yours.Fields, saved.Fields) {
+		label := name
+		for _, def := range ct.Fields {
+			if def.Name == name {
+				label = def.LabelOr()
+			}
+		}
+		addText(fieldPrefix+name, label, yours.Fields[name], saved.Fields[name])
+	}
+
+	addText("body", "Body", yours.Body, saved.Body)
+	add("toc_min_depth", "Table of contents from level", depthText(yours.TOCMinDepth), depthText(saved.TOCMinDepth))
+	add("toc_max_depth", "Table of contents to level", depthText(yours.TOCMaxDepth), depthText(saved.TOCMaxDepth))
+	add("meta_title", "Meta title", yours.MetaTitle, saved.MetaTitle)
+	add("meta_description", "Meta description", yours.MetaDescription, saved.MetaDescription)
+	add("canonical_url", "Canonical URL", yours.CanonicalURL, saved.CanonicalURL)
+	add("social_image", "Social image", yours.SocialImage, saved.SocialImage)
+	add("noindex", "Hidden from search engines", strconv.FormatBool(yours.NoIndex), strconv.FormatBool(saved.NoIndex))
+	return changes
+}
+
+// fieldNames returns the names of the fields set in either of the values,
+// sorted.
+func fieldNames(a, b map[string]string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range []map[string]string{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sectionLabel(sections []Section, id uuid.UUID) string {
+	for _, s := range sections {
+		if s.ID() == id {
+			return s.Name
+		}
+	}
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func profileNames(profiles []Profile, ids []uuid.UUID) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+		for _, p := range profiles {
+			if p.ID() == id {
+				names[i] = p.Name
+				break
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func depthText(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strconv.Itoa(depth)
+}
+
+// diffLines returns the line by line diff from a to b, nil if they are too
+// long to compare.
+func diffLines(a, b string) []DiffLine {
+	from := strings.Split(strings.ReplaceAll(a, "\r\n", "\n"), "\n")
+	to := strings.Split(strings.ReplaceAll(b, "\r\n", "\n"), "\n")
+	if len(from)*len(to) > maxDiffCells {
+		return nil
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of from[i:]
+	// and to[j:].
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			diff = append(diff, DiffLine{Kind: DiffSame, Text: from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Kind: DiffRemoved, Text: from[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Kind: DiffAdded, Text: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		diff = append(diff, DiffLine{Kind: DiffRemoved, Text: from[i]})
+	}
+	for ; j < len(to); j++ {
+		diff = append(diff, DiffLine{Kind: DiffAdded, Text: to[j]})
+	}
+	return diff
+}
@@ -0,0 +1,164 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestContentChanges(t *testing.T) {
+	jane := newTestProfile("Jane Doe", uuid.Nil)
+	john := newTestProfile("John Roe", uuid.Nil)
+
+	saved := NewContent("Heading", "one\ntwo\nthree")
+	saved.GenCreateValues()
+	saved.SlugValue = "heading"
+	saved.Status = StatusDraft
+	saved.Fields = map[string]string{"summary": "Short"}
+	saved.AuthorIDs = []uuid.UUID{jane.ID()}
+
+	same := saved
+	same.AuthorIDs = nil
+	if got := contentChanges(same, saved, nil, nil, ContentType{}); len(got) != 0 {
+		t.Fatalf("unchanged content: got changes %+v", got)
+	}
+
+	yours := saved
+	yours.Heading = "New heading"
+	yours.Body = "one\r\n2\r\nthree"
+	yours.Fields = map[string]string{"summary": "Longer"}
+	yours.AuthorIDs = []uuid.UUID{jane.ID(), john.ID()}
+	yours.NoIndex = true
+	ct := ContentType{Fields: []FieldDef{{Name: "summary", Label: "Summary", Type: FieldText}}}
+
+	got := contentChanges(yours, saved, nil, []Profile{jane, john}, ct)
+
+	var fields []string
+	for _, c := range got {
+		fields = append(fields, c.Field)
+	}
+	want := []string{"heading", "author_ids", "field.summary", "body", "noindex"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("changed fields: got %v, want %v", fields, want)
+	}
+
+	if got[1].Yours != "Jane Doe, John Roe" || got[1].Saved != "Jane Doe" {
+		t.Errorf("authors: got %q and %q", got[1].Yours, got[1].Saved)
+	}
+	if got[2].Label != "Summary" {
+		t.Errorf("field label: got %q", got[2].Label)
+	}
+
+	wantDiff := []DiffLine{
+		{Kind: DiffSame, Text: "one"},
+		{Kind: DiffRemoved, Text: "2"},
+		{Kind: DiffAdded, Text: "two"},
+		{Kind: DiffSame, Text: "three"},
+	}
+	if !reflect.DeepEqual(got[3].Diff, wantDiff) {
+		t.Errorf("body diff: got %+v, want %+v", got[3].Diff, wantDiff)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{
+			name: "added at the end",
+			a:    "a\nb",
+			b:    "a\nb\nc",
+			want: []DiffLine{{DiffSame, "a"}, {DiffSame, "b"}, {DiffAdded, "c"}},
+		},
+		{
+			name: "removed at the start",
+			a:    "a\nb\nc",
+			b:    "b\nc",
+			want: []DiffLine{{DiffRemoved, "a"}, {DiffSame, "b"}, {DiffSame, "c"}},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc",
+			b:    "b\nc\na",
+			want: []DiffLine{{DiffRemoved, "a"}, {DiffSame, "b"}, {DiffSame, "c"}, {DiffAdded, "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffLines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// versionRepo keeps a single content and saves it as the database does, only
+// over the version it is based on.
+type versionRepo struct {
+	Repo
+	content Content
+}
+
+func (r *versionRepo) GetContent(ctx context.Context, id string) (Content, error) {
+	return r.content, nil
+}
+
+func (r *versionRepo) GetAllContent(ctx context.Context) ([]Content, error) {
+	return []Content{r.content}, nil
+}
+
+func (r *versionRepo) GetContentAuthors(ctx context.Context) ([]ContentAuthor, error) {
+	return nil, nil
+}
+
+func (r *versionRepo) GetSections(ctx context.Context) ([]Section, error) {
+	return nil, nil
+}
+
+func (r *versionRepo) UpdateContent(ctx context.Context, content Content) error {
+	if content.Version != r.content.Version {
+		return ErrContentConflict
+	}
+	content.Version++
+	r.content = content
+	return nil
+}
+
+func TestUpdateContentVersion(t *testing.T) {
+	ctx := context.Background()
+	saved := NewContent("Hello", "Body")
+	saved.GenCreateValues()
+	saved.SlugValue, saved.Version = "hello", 2
+	repo := &versionRepo{content: saved}
+	svc := &BaseService{Service: am.NewService("ssg-service", am.WithCfg(am.NewConfig())), repo: repo}
+
+	for _, version := range []int{0, 1} {
+		edit := saved
+		edit.Body, edit.Version = "Edited", version
+		got, err := svc.UpdateContent(ctx, edit)
+		if !errors.Is(err, ErrContentConflict) {
+			t.Errorf("version %d: expected ErrContentConflict, got %v", version, err)
+		}
+		if got.Body != "Body" || repo.content.Body != "Body" {
+			t.Errorf("version %d: saved content overwritten", version)
+		}
+	}
+
+	edit := saved
+	edit.Body = "Edited"
+	got, err := svc.UpdateContent(ctx, edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 3 || repo.content.Body != "Edited" {
+		t.Errorf("expected version 3 with the edit saved, got %d %q", got.Version, repo.content.Body)
+	}
+}
@@ -7,14 +7,30 @@ import (
 )
 
 type ContentDA struct {
-	ID        uuid.UUID  `db:"id"`
-	ShortID   string     `db:"short_id"`
-	UserID    uuid.UUID  `db:"user_id"`
-	Heading   string     `db:"heading"`
-	Body      string     `db:"body"`
-	Status    string     `db:"status"`
-	CreatedBy *string    `db:"created_by"`
-	UpdatedBy *string    `db:"updated_by"`
-	CreatedAt *time.Time `db:"created_at"`
-	UpdatedAt *time.Time `db:"updated_at"`
+	ID              uuid.UUID  `db:"id"`
+	ShortID         string     `db:"short_id"`
+	SiteID          string     `db:"site_id"`
+	UserID          uuid.UUID  `db:"user_id"`
+	SectionID       string     `db:"section_id"`
+	TypeID          string     `db:"type_id"`
+	Fields          string     `db:"fields"`
+	TOCMinDepth     int        `db:"toc_min_depth"`
+	TOCMaxDepth     int        `db:"toc_max_depth"`
+	MetaTitle       string     `db:"meta_title"`
+	MetaDescription string     `db:"meta_description"`
+	CanonicalURL    string     `db:"canonical_url"`
+	SocialImage     string     `db:"social_image"`
+	NoIndex         bool       `db:"noindex"`
+	Lang            string     `db:"lang"`
+	TranslationID   string     `db:"translation_id"`
+	Slug            string     `db:"slug"`
+	Heading         string     `db:"heading"`
+	Body            string     `db:"body"`
+	Status          string     `db:"status"`
+	PublishedAt     *time.Time `db:"published_at"`
+	Version         int        `db:"version"`
+	CreatedBy       *string    `db:"created_by"`
+	UpdatedBy       *string    `db:"updated_by"`
+	CreatedAt       *time.Time `db:"created_at"`
+	UpdatedAt       *time.Time `db:"updated_at"`
 }
@@ -0,0 +1,55 @@
+package ssg
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// ContentDraft holds the changes a user has not saved yet to a content,
+// autosaved while they edit it so that a closed tab or a crash does not lose
+// them. Users have a draft per content and one for content not created yet.
+// Drafts are kept apart from the content, which only changes when saved.
+type ContentDraft struct {
+	ContentID uuid.UUID // Nil for content not created yet
+	UserID    uuid.UUID
+	Version   int        // Version of the content the draft is based on
+	Values    url.Values // Values of the edit form
+	SavedAt   time.Time
+}
+
+func NewContentDraft(contentID, userID uuid.UUID, version int, form url.Values) ContentDraft {
+	return ContentDraft{
+		ContentID: contentID,
+		UserID:    userID,
+		Version:   version,
+		Values:    draftValues(form),
+		SavedAt:   am.Now(),
+	}
+}
+
+func (d ContentDraft) IsZero() bool {
+	return d.SavedAt.IsZero()
+}
+
+// draftValues returns the values of the edit form worth keeping in a draft,
+// leaving out the ones that only make sense for the request that posted them.
+func draftValues(form url.Values) url.Values {
+	values := url.Values{}
+	for key, vals := range form {
+		switch key {
+		case am.CSRFFieldName, "_method", "overwrite_version":
+			continue
+		}
+		values[key] = vals
+	}
+	return values
+}
+
+// DraftNotice tells an editor that the form holds changes they left unsaved.
+type DraftNotice struct {
+	SavedAt time.Time
+	Stale   bool // The content was saved again after the draft was started
+}
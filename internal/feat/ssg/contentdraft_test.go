@@ -0,0 +1,31 @@
+package ssg
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestNewContentDraft(t *testing.T) {
+	form := url.Values{
+		am.CSRFFieldName:    {"token"},
+		"_method":           {"POST"},
+		"overwrite_version": {"3"},
+		"heading":           {"Hello"},
+		"author_ids":        {"a", "b"},
+	}
+
+	draft := NewContentDraft(uuid.Nil, uuid.New(), 2, form)
+	if draft.IsZero() {
+		t.Fatal("new draft reported as zero")
+	}
+	want := url.Values{"heading": {"Hello"}, "author_ids": {"a", "b"}}
+	if draft.Values.Encode() != want.Encode() {
+		t.Errorf("got values %q, want %q", draft.Values.Encode(), want.Encode())
+	}
+	if !(ContentDraft{}).IsZero() {
+		t.Error("empty draft not reported as zero")
+	}
+}
@@ -0,0 +1,12 @@
+package ssg
+
+import "time"
+
+type ContentDraftDA struct {
+	SiteID    string     `db:"site_id"`
+	ContentID string     `db:"content_id"`
+	UserID    string     `db:"user_id"`
+	Version   int        `db:"version"`
+	Form      string     `db:"form"`
+	SavedAt   *time.Time `db:"saved_at"`
+}
@@ -1,17 +1,76 @@
 package ssg
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
+// fieldPrefix prefixes the form names of content type fields.
+const fieldPrefix = "field."
+
+// ContentForm edits a content. Content with a type also has the fields of the
+// type, posted as field.<name> and kept in Values. Type is set by the handler
+// so that the form can be rendered and validated from the type schema.
 type ContentForm struct {
 	*am.BaseForm
-	ID        string `form:"id"`
-	Heading   string `form:"heading" required:"true"`
-	Body      string `form:"body"`
-	SectionID string `form:"section_id"`
+	ID              string `form:"id"`
+	Heading         string `form:"heading" required:"true"`
+	Slug            string `form:"slug"`
+	Body            string `form:"body"`
+	Status          string `form:"status"`
+	SectionID       string `form:"section_id"`
+	Lang            string `form:"lang"`
+	TranslationID   string `form:"translation_id"`
+	TypeID          string `form:"type_id"`
+	TOCMinDepth     string `form:"toc_min_depth"`
+	TOCMaxDepth     string `form:"toc_max_depth"`
+	MetaTitle       string `form:"meta_title"`
+	MetaDescription string `form:"meta_description"`
+	CanonicalURL    string `form:"canonical_url"`
+	SocialImage     string `form:"social_image"`
+	NoIndex         string `form:"noindex"`
+	Version         string `form:"version"`
+	Values          map[string]string
+	AuthorIDs       []string // Posted as author_ids, one per row in byline order
+	Type            ContentType
+	Conflict        *ContentConflict // Set when a save was rejected, shows what changed
+	Lock            *LockNotice      // Set when someone else is editing the content
+	Draft           *DraftNotice     // Set when the form holds unsaved changes restored from a draft
+}
+
+// FieldInput is a field of the content type along with its value in the form.
+type FieldInput struct {
+	FieldDef
+	Key   string
+	Value string
+}
+
+// Inputs returns the fields of the content type to render.
+func (form ContentForm) Inputs() []FieldInput {
+	inputs := make([]FieldInput, len(form.Type.Fields))
+	for i, def := range form.Type.Fields {
+		inputs[i] = FieldInput{FieldDef: def, Key: fieldPrefix + def.Name, Value: form.Values[def.Name]}
+	}
+	return inputs
+}
+
+// AuthorRows returns the author of each row of the authors field, followed by
+// an empty row to add one more.
+func (form ContentForm) AuthorRows() []string {
+	var rows []string
+	for _, id := range form.AuthorIDs {
+		if id != "" {
+			rows = append(rows, id)
+		}
+	}
+	return append(rows, "")
 }
 
 func NewContentForm(r *http.Request) ContentForm {
@@ -27,13 +86,52 @@ func ContentFormFromRequest(r *http.Request) (cf ContentForm, err error) {
 		return cf, err
 	}
 
+	return contentFormFromValues(r, r.Form), nil
+}
+
+// contentFormFromValues reads a ContentForm from posted values, those of the
+// request or the ones kept in a draft.
+func contentFormFromValues(r *http.Request, form url.Values) ContentForm {
+	// Saving over a conflict posts the version that was saved meanwhile.
+	version := form.Get("overwrite_version")
+	if version == "" {
+		version = form.Get("version")
+	}
+
 	return ContentForm{
-		BaseForm: am.NewBaseForm(r),
-		ID:       r.Form.Get("id"),
-		Heading:  r.Form.Get("heading"),
-		Body:     r.Form.Get("body"),
-		SectionID: r.Form.Get("section_id"),
-	}, nil
+		BaseForm:        am.NewBaseForm(r),
+		ID:              form.Get("id"),
+		Heading:         form.Get("heading"),
+		Slug:            form.Get("slug"),
+		Body:            form.Get("body"),
+		Status:          form.Get("status"),
+		SectionID:       form.Get("section_id"),
+		Lang:            form.Get("lang"),
+		TranslationID:   form.Get("translation_id"),
+		TypeID:          form.Get("type_id"),
+		TOCMinDepth:     strings.TrimSpace(form.Get("toc_min_depth")),
+		TOCMaxDepth:     strings.TrimSpace(form.Get("toc_max_depth")),
+		MetaTitle:       strings.TrimSpace(form.Get("meta_title")),
+		MetaDescription: strings.TrimSpace(form.Get("meta_description")),
+		CanonicalURL:    strings.TrimSpace(form.Get("canonical_url")),
+		SocialImage:     strings.TrimSpace(form.Get("social_image")),
+		NoIndex:         form.Get("noindex"),
+		Version:         version,
+		AuthorIDs:       form["author_ids"],
+		Values:          fieldValues(form),
+	}
+}
+
+// fieldValues returns the posted values of content type fields by name.
+func fieldValues(form url.Values) map[string]string {
+	values := map[string]string{}
+	for key := range form {
+		name, ok := strings.CutPrefix(key, fieldPrefix)
+		if ok {
+			values[name] = strings.TrimSpace(form.Get(key))
+		}
+	}
+	return values
 }
 
 // Validate validates a ContentForm using am validators.
@@ -42,6 +140,11 @@ func (form *ContentForm) Validate() (err error) {
 		am.MinLength("heading", form.Heading, 3),
 		am.MaxLength("heading", form.Heading, 100),
 		am.MinLength("body", form.Body, 1),
+		am.MaxLength("slug", form.Slug, 100),
+		validSlug("slug", form.Slug),
+		validFieldValues(form.Type.Fields, form.Values),
+		validTOCDepth("toc_min_depth", form.TOCMinDepth, "toc_max_depth", form.TOCMaxDepth),
+		validPageMeta(form.MetaTitle, form.MetaDescription, form.CanonicalURL, form.SocialImage),
 	)
 
 	v, err := validate(*form)
@@ -53,3 +156,115 @@ func (form *ContentForm) Validate() (err error) {
 
 	return err
 }
+
+// validSlug checks that a slug, if given, is already in its slugified form so
+// that what the author sees is what ends up in the URL.
+func validSlug(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val != "" && val != Slugify(val) {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: use lowercase letters, digits and dashes only", field))
+		}
+		return v, nil
+	}
+}
+
+// validTOCDepth checks that the heading levels of the table of contents, if
+// given, are between 1 and 6 and in order.
+func validTOCDepth(minField, minVal, maxField, maxVal string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		level := func(field, val string) int {
+			if val == "" {
+				return 0
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 6 {
+				v.AddFieldError(field, val, fmt.Sprintf("%s: must be a heading level from 1 to 6", field))
+				return 0
+			}
+			return n
+		}
+
+		min, max := level(minField, minVal), level(maxField, maxVal)
+		if min != 0 && max != 0 && max < min {
+			v.AddFieldError(maxField, maxVal, fmt.Sprintf("%s: must not be lower than %s", maxField, minField))
+		}
+		return v, nil
+	}
+}
+
+// validFieldValues checks the values of content type fields against their
+// definitions.
+func validFieldValues(defs []FieldDef, values map[string]string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		for _, def := range defs {
+			key := fieldPrefix + def.Name
+			val := values[def.Name]
+			if val == "" {
+				if def.Required && def.Type != FieldBoolean {
+					v.AddFieldError(key, val, fmt.Sprintf("%s: is required", def.LabelOr()))
+				}
+				continue
+			}
+
+			var invalid string
+			switch def.Type {
+			case FieldNumber:
+				if _, err := strconv.ParseFloat(val, 64); err != nil {
+					invalid = "must be a number"
+				}
+			case FieldDate:
+				if _, err := time.Parse(fieldDateFormat, val); err != nil {
+					invalid = "must be a date as YYYY-MM-DD"
+				}
+			case FieldBoolean:
+				if val != "true" {
+					invalid = "must be true or empty"
+				}
+			case FieldMedia:
+				if !isMediaRef(val) {
+					invalid = "must be a site path or an http or https URL"
+				}
+			case FieldReference:
+				if am.ParseUUID(val) == uuid.Nil {
+					invalid = "must be a content"
+				}
+			}
+			if invalid != "" {
+				v.AddFieldError(key, val, fmt.Sprintf("%s: %s", def.LabelOr(), invalid))
+			}
+		}
+		return v, nil
+	}
+}
+
+// validPageMeta checks the search engine and social sharing settings of a
+// content or section.
+func validPageMeta(title, description, canonical, image string) am.Validator {
+	return am.ComposeValidators(
+		am.MaxLength("meta_title", title, 120),
+		am.MaxLength("meta_description", description, 320),
+		validBaseURL("canonical_url", canonical),
+		validMediaRef("social_image", image),
+	)
+}
+
+// validMediaRef checks that a media reference, if given, is a site path or an
+// http or https URL.
+func validMediaRef(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val != "" && !isMediaRef(val) {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be a site path or an http or https URL", field))
+		}
+		return v, nil
+	}
+}
+
+// isMediaRef reports whether val is a site path or an http or https URL.
+func isMediaRef(val string) bool {
+	u, err := url.Parse(val)
+	return err == nil && (strings.HasPrefix(val, "/") || u.Scheme == "http" || u.Scheme == "https")
+}
@@ -0,0 +1,68 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defEditLockTTL = 300 // Seconds
+)
+
+// ContentLock tells other editors that someone is editing a content. Locks are
+// soft, they do not stop anyone from saving, and they expire unless the editor
+// keeps renewing them, which the edit form does each time it autosaves.
+type ContentLock struct {
+	ContentID  uuid.UUID
+	UserID     uuid.UUID
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+func NewContentLock(contentID, userID uuid.UUID, ttl time.Duration) ContentLock {
+	now := am.Now()
+	return ContentLock{
+		ContentID:  contentID,
+		UserID:     userID,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
+
+// IsActive reports whether the lock has not expired at t.
+func (l ContentLock) IsActive(t time.Time) bool {
+	return l.ExpiresAt.After(t)
+}
+
+// HeldByOther reports whether the lock is active at t and held by someone
+// other than userID.
+func (l ContentLock) HeldByOther(userID uuid.UUID, t time.Time) bool {
+	return l.IsActive(t) && l.UserID != userID
+}
+
+// activeLocks returns the locks that have not expired at t by content.
+func activeLocks(locks []ContentLock, t time.Time) map[uuid.UUID]ContentLock {
+	active := make(map[uuid.UUID]ContentLock, len(locks))
+	for _, l := range locks {
+		if l.IsActive(t) {
+			active[l.ContentID] = l
+		}
+	}
+	return active
+}
+
+// LockNotice tells an editor that someone else is editing a content.
+type LockNotice struct {
+	By    string // Name of who holds the lock
+	Since time.Time
+	Until time.Time
+}
+
+// ContentListItem is a content in the content list along with the notice of
+// its lock, if someone is editing it.
+type ContentListItem struct {
+	Content
+	Lock *LockNotice
+}
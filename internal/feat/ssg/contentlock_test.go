@@ -0,0 +1,44 @@
+package ssg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestContentLock(t *testing.T) {
+	editor, other := uuid.New(), uuid.New()
+	lock := NewContentLock(uuid.New(), editor, 5*time.Minute)
+	now := lock.AcquiredAt
+
+	if !lock.IsActive(now.Add(4 * time.Minute)) {
+		t.Error("lock expired before its TTL")
+	}
+	if lock.IsActive(now.Add(5 * time.Minute)) {
+		t.Error("lock still active after its TTL")
+	}
+	if lock.HeldByOther(editor, now) {
+		t.Error("lock reported as held by other to its holder")
+	}
+	if !lock.HeldByOther(other, now) {
+		t.Error("lock not reported as held by other")
+	}
+	if lock.HeldByOther(other, now.Add(time.Hour)) {
+		t.Error("expired lock reported as held")
+	}
+}
+
+func TestActiveLocks(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	live := ContentLock{ContentID: uuid.New(), UserID: uuid.New(), ExpiresAt: now.Add(time.Minute)}
+	expired := ContentLock{ContentID: uuid.New(), UserID: uuid.New(), ExpiresAt: now.Add(-time.Minute)}
+
+	got := activeLocks([]ContentLock{live, expired}, now)
+	if len(got) != 1 {
+		t.Fatalf("got %d active locks, want 1", len(got))
+	}
+	if _, ok := got[live.ContentID]; !ok {
+		t.Error("active lock missing")
+	}
+}
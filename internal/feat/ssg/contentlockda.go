@@ -0,0 +1,11 @@
+package ssg
+
+import "time"
+
+type ContentLockDA struct {
+	SiteID     string     `db:"site_id"`
+	ContentID  string     `db:"content_id"`
+	UserID     string     `db:"user_id"`
+	AcquiredAt *time.Time `db:"acquired_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+}
@@ -0,0 +1,171 @@
+package ssg
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	contentTypeType = "content-type"
+
+	// fieldDateFormat is the format date fields are stored and entered in.
+	fieldDateFormat = "2006-01-02"
+)
+
+// Field types.
+const (
+	FieldText      = "text"
+	FieldMarkdown  = "markdown"
+	FieldNumber    = "number"
+	FieldDate      = "date"
+	FieldBoolean   = "boolean"
+	FieldMedia     = "media"     // Path of a static file or URL
+	FieldReference = "reference" // ID of another content
+	FieldList      = "list"      // One item per line
+)
+
+var fieldTypes = []string{
+	FieldText, FieldMarkdown, FieldNumber, FieldDate, FieldBoolean, FieldMedia, FieldReference, FieldList,
+}
+
+var fieldNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// FieldDef describes a field of a content type.
+type FieldDef struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// LabelOr returns the label of the field, its name if it has none.
+func (f FieldDef) LabelOr() string {
+	if f.Label == "" {
+		return f.Name
+	}
+	return f.Label
+}
+
+// ContentType defines the structured fields content of that type has on top
+// of its heading and body. Content of a type with a layout is rendered with
+// it instead of the layout of its section.
+type ContentType struct {
+	*am.BaseModel
+	Name        string     `json:"name"`
+	SlugValue   string     `json:"slug"`
+	Description string     `json:"description"`
+	Fields      []FieldDef `json:"fields"`
+	LayoutID    uuid.UUID  `json:"layout_id"`
+}
+
+func NewContentType(name, description string) ContentType {
+	return ContentType{
+		BaseModel:   am.NewModel(am.WithType(contentTypeType)),
+		Name:        name,
+		SlugValue:   Slugify(name),
+		Description: description,
+	}
+}
+
+func (t ContentType) IsZero() bool {
+	return t.BaseModel == nil || t.BaseModel.IsZero()
+}
+
+func (t *ContentType) Slug() string {
+	return t.SlugValue
+}
+
+// OptValue and OptLabel make content types selectable.
+func (t ContentType) OptValue() string {
+	return t.ID().String()
+}
+
+func (t ContentType) OptLabel() string {
+	return t.Name
+}
+
+// hasField reports whether the type has a field of the given field type.
+func (t ContentType) hasField(fieldType string) bool {
+	for _, f := range t.Fields {
+		if f.Type == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// Values converts the stored field values of a content to the types layouts
+// work with: markdown is rendered, numbers, dates and booleans are parsed,
+// lists are split and references are resolved to the content they point to.
+// Values that cannot be converted are left out.
+func (t ContentType) Values(fields map[string]string, contents map[uuid.UUID]Content) map[string]any {
+	values := make(map[string]any, len(t.Fields))
+	for _, f := range t.Fields {
+		raw := fields[f.Name]
+		switch f.Type {
+		case FieldMarkdown:
+			html, err := RenderMarkdown(raw)
+			if err != nil {
+				continue
+			}
+			values[f.Name] = html
+		case FieldNumber:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			values[f.Name] = n
+		case FieldDate:
+			d, err := time.Parse(fieldDateFormat, raw)
+			if err != nil {
+				continue
+			}
+			values[f.Name] = d
+		case FieldBoolean:
+			values[f.Name] = raw == "true"
+		case FieldReference:
+			c, ok := contents[am.ParseUUID(raw)]
+			if !ok {
+				continue
+			}
+			values[f.Name] = &c
+		case FieldList:
+			values[f.Name] = splitList(raw)
+		default:
+			values[f.Name] = raw
+		}
+	}
+	return values
+}
+
+// splitList returns the non blank lines of a list field.
+func splitList(raw string) []string {
+	var items []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (t *ContentType) UnmarshalJSON(data []byte) error {
+	type Alias ContentType
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*t = ContentType(*temp)
+	if t.BaseModel == nil {
+		t.BaseModel = am.NewModel(am.WithType(contentTypeType))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package ssg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseFieldDefs(t *testing.T) {
+	text := "venue | text\n\nstarts_at | date | Starts at | required\nspeakers | list | Speakers\n"
+	defs, err := parseFieldDefs(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FieldDef{
+		{Name: "venue", Type: FieldText},
+		{Name: "starts_at", Type: FieldDate, Label: "Starts at", Required: true},
+		{Name: "speakers", Type: FieldList, Label: "Speakers"},
+	}
+	if !reflect.DeepEqual(defs, want) {
+		t.Errorf("defs = %v, want %v", defs, want)
+	}
+
+	again, err := parseFieldDefs(formatFieldDefs(defs))
+	if err != nil || !reflect.DeepEqual(again, want) {
+		t.Errorf("round trip = %v, %v", again, err)
+	}
+
+	for _, bad := range []string{"venue", "Venue | text", "venue | color", "venue | text\nvenue | text", "venue | text | Venue | optional"} {
+		if _, err := parseFieldDefs(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestContentTypeValues(t *testing.T) {
+	ct := NewContentType("Event", "")
+	ct.Fields = []FieldDef{
+		{Name: "seats", Type: FieldNumber},
+		{Name: "starts_at", Type: FieldDate},
+		{Name: "online", Type: FieldBoolean},
+		{Name: "speakers", Type: FieldList},
+		{Name: "talk", Type: FieldReference},
+		{Name: "venue", Type: FieldText},
+	}
+
+	talk := NewContent("Intro talk", "")
+	contents := map[uuid.UUID]Content{talk.ID(): talk}
+
+	values := ct.Values(map[string]string{
+		"seats":     "not a number",
+		"starts_at": "2025-09-01",
+		"online":    "true",
+		"speakers":  "Ana\n\n Bo ",
+		"talk":      talk.ID().String(),
+		"venue":     "Berlin",
+	}, contents)
+
+	if _, ok := values["seats"]; ok {
+		t.Error("invalid number should be left out")
+	}
+	if d, _ := values["starts_at"].(time.Time); !d.Equal(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("starts_at = %v", values["starts_at"])
+	}
+	if values["online"] != true {
+		t.Errorf("online = %v", values["online"])
+	}
+	if !reflect.DeepEqual(values["speakers"], []string{"Ana", "Bo"}) {
+		t.Errorf("speakers = %v", values["speakers"])
+	}
+	if c, _ := values["talk"].(*Content); c == nil || c.Heading != "Intro talk" {
+		t.Errorf("talk = %v", values["talk"])
+	}
+	if values["venue"] != "Berlin" {
+		t.Errorf("venue = %v", values["venue"])
+	}
+}
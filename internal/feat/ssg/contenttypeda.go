@@ -0,0 +1,22 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContentTypeDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
+	Name        string     `db:"name"`
+	Slug        string     `db:"slug"`
+	Description string     `db:"description"`
+	Fields      string     `db:"fields"`
+	LayoutID    string     `db:"layout_id"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
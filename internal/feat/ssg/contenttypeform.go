@@ -0,0 +1,131 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// fieldRequired marks a required field in the schema text.
+const fieldRequired = "required"
+
+// ContentTypeForm edits a content type. Fields are entered one per line as
+// name | type | label | required, where label and required are optional.
+type ContentTypeForm struct {
+	*am.BaseForm
+	ID          string `form:"id"`
+	Name        string `form:"name" required:"true"`
+	Slug        string `form:"slug"`
+	Description string `form:"description"`
+	Fields      string `form:"fields"`
+	LayoutID    string `form:"layout_id"`
+}
+
+func NewContentTypeForm(r *http.Request) ContentTypeForm {
+	return ContentTypeForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func ContentTypeFormFromRequest(r *http.Request) (tf ContentTypeForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return tf, err
+	}
+
+	return ContentTypeForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Name:        strings.TrimSpace(r.Form.Get("name")),
+		Slug:        strings.TrimSpace(r.Form.Get("slug")),
+		Description: strings.TrimSpace(r.Form.Get("description")),
+		Fields:      strings.TrimSpace(r.Form.Get("fields")),
+		LayoutID:    r.Form.Get("layout_id"),
+	}, nil
+}
+
+func (form *ContentTypeForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 64),
+		validSlug("slug", form.Slug),
+		validFieldDefs("fields", form.Fields),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+func validFieldDefs(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		_, err := parseFieldDefs(val)
+		if err != nil {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: %s", field, err))
+		}
+		return v, nil
+	}
+}
+
+// parseFieldDefs reads the fields of a content type, one per line.
+func parseFieldDefs(text string) ([]FieldDef, error) {
+	var defs []FieldDef
+	seen := map[string]bool{}
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parts := strings.Split(line, "|")
+		for j := range parts {
+			parts[j] = strings.TrimSpace(parts[j])
+		}
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("line %d: expected name | type | label | required", i+1)
+		}
+
+		def := FieldDef{Name: parts[0], Type: parts[1]}
+		if len(parts) > 2 {
+			def.Label = parts[2]
+		}
+		if len(parts) > 3 {
+			if parts[3] != fieldRequired {
+				return nil, fmt.Errorf("line %d: unknown flag %s", i+1, parts[3])
+			}
+			def.Required = true
+		}
+
+		switch {
+		case !fieldNameRe.MatchString(def.Name):
+			return nil, fmt.Errorf("line %d: name %q must be lowercase letters, digits and underscores", i+1, def.Name)
+		case seen[def.Name]:
+			return nil, fmt.Errorf("line %d: field %s is defined twice", i+1, def.Name)
+		case !contains(fieldTypes, def.Type):
+			return nil, fmt.Errorf("line %d: type must be one of %s", i+1, strings.Join(fieldTypes, ", "))
+		}
+		seen[def.Name] = true
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// formatFieldDefs is the inverse of parseFieldDefs.
+func formatFieldDefs(defs []FieldDef) string {
+	lines := make([]string, len(defs))
+	for i, def := range defs {
+		parts := []string{def.Name, def.Type}
+		if def.Label != "" || def.Required {
+			parts = append(parts, def.Label)
+		}
+		if def.Required {
+			parts = append(parts, fieldRequired)
+		}
+		lines[i] = strings.Join(parts, " | ")
+	}
+	return strings.Join(lines, "\n")
+}
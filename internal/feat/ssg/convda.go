@@ -1,23 +1,42 @@
 package ssg
 
 import (
+	"encoding/json"
+	"net/url"
+
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
 // Content related
 
 func ToContentDA(content Content) ContentDA {
 	return ContentDA{
-		ID:        content.ID(),
-		UserID:    content.UserID,
-		Heading:   content.Heading,
-		Body:      content.Body,
-		Status:    content.Status,
-		ShortID:   content.ShortID(),
-		CreatedBy: am.UUIDPtr(content.CreatedBy()),
-		UpdatedBy: am.UUIDPtr(content.UpdatedBy()),
-		CreatedAt: am.TimePtr(content.CreatedAt()),
-		UpdatedAt: am.TimePtr(content.UpdatedAt()),
+		ID:              content.ID(),
+		UserID:          content.UserID,
+		SectionID:       content.SectionID.String(),
+		TypeID:          content.TypeID.String(),
+		Fields:          toJSONMap(content.Fields),
+		TOCMinDepth:     content.TOCMinDepth,
+		TOCMaxDepth:     content.TOCMaxDepth,
+		MetaTitle:       content.MetaTitle,
+		MetaDescription: content.MetaDescription,
+		CanonicalURL:    content.CanonicalURL,
+		SocialImage:     content.SocialImage,
+		NoIndex:         content.NoIndex,
+		Lang:            content.Lang,
+		TranslationID:   content.TranslationID.String(),
+		Slug:            content.SlugValue,
+		Heading:         content.Heading,
+		Body:            content.Body,
+		Status:          content.Status,
+		PublishedAt:     am.TimePtr(content.PublishedAt),
+		Version:         content.Version,
+		ShortID:         content.ShortID(),
+		CreatedBy:       am.UUIDPtr(content.CreatedBy()),
+		UpdatedBy:       am.UUIDPtr(content.UpdatedBy()),
+		CreatedAt:       am.TimePtr(content.CreatedAt()),
+		UpdatedAt:       am.TimePtr(content.UpdatedAt()),
 	}
 }
 
@@ -32,10 +51,27 @@ func ToContent(da ContentDA) Content {
 			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
 			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
 		),
-		UserID:  da.UserID,
-		Heading: da.Heading,
-		Body:    da.Body,
-		Status:  da.Status,
+		UserID:        da.UserID,
+		SectionID:     am.ParseUUID(da.SectionID),
+		TypeID:        am.ParseUUID(da.TypeID),
+		Fields:        fromJSONMap(da.Fields),
+		TOCMinDepth:   da.TOCMinDepth,
+		TOCMaxDepth:   da.TOCMaxDepth,
+		Lang:          da.Lang,
+		TranslationID: am.ParseUUID(da.TranslationID),
+		SlugValue:     da.Slug,
+		Heading:       da.Heading,
+		Body:          da.Body,
+		Status:        da.Status,
+		PublishedAt:   am.TimeVal(da.PublishedAt),
+		Version:       da.Version,
+		PageMeta: PageMeta{
+			MetaTitle:       da.MetaTitle,
+			MetaDescription: da.MetaDescription,
+			CanonicalURL:    da.CanonicalURL,
+			SocialImage:     da.SocialImage,
+			NoIndex:         da.NoIndex,
+		},
 	}
 }
 
@@ -51,18 +87,25 @@ func ToContents(das []ContentDA) []Content {
 
 func ToSectionDA(section Section) SectionDA {
 	return SectionDA{
-		ID:          section.ID(),
-		Name:        section.Name,
-		Description: section.Description,
-		Path:        section.Path,
-		LayoutID:    section.LayoutID.String(),
-		ShortID:     section.ShortID(),
-		CreatedBy:   am.UUIDPtr(section.CreatedBy()),
-		UpdatedBy:   am.UUIDPtr(section.UpdatedBy()),
-		CreatedAt:   am.TimePtr(section.CreatedAt()),
-		UpdatedAt:   am.TimePtr(section.UpdatedAt()),
-		Image:       section.Image,
-		Header:      section.Header,
+		ID:              section.ID(),
+		Name:            section.Name,
+		Description:     section.Description,
+		Path:            section.Path,
+		LayoutID:        section.LayoutID.String(),
+		Lang:            section.Lang,
+		Permalink:       section.Permalink,
+		ShortID:         section.ShortID(),
+		CreatedBy:       am.UUIDPtr(section.CreatedBy()),
+		UpdatedBy:       am.UUIDPtr(section.UpdatedBy()),
+		CreatedAt:       am.TimePtr(section.CreatedAt()),
+		UpdatedAt:       am.TimePtr(section.UpdatedAt()),
+		Image:           section.Image,
+		Header:          section.Header,
+		MetaTitle:       section.MetaTitle,
+		MetaDescription: section.MetaDescription,
+		CanonicalURL:    section.CanonicalURL,
+		SocialImage:     section.SocialImage,
+		NoIndex:         section.NoIndex,
 	}
 }
 
@@ -81,8 +124,17 @@ func ToSection(da SectionDA) Section {
 		Description: da.Description,
 		Path:        da.Path,
 		LayoutID:    am.ParseUUID(da.LayoutID),
+		Lang:        da.Lang,
+		Permalink:   da.Permalink,
 		Image:       da.Image,
 		Header:      da.Header,
+		PageMeta: PageMeta{
+			MetaTitle:       da.MetaTitle,
+			MetaDescription: da.MetaDescription,
+			CanonicalURL:    da.CanonicalURL,
+			SocialImage:     da.SocialImage,
+			NoIndex:         da.NoIndex,
+		},
 	}
 }
 
@@ -135,3 +187,975 @@ func ToLayouts(das []LayoutDA) []Layout {
 	return layouts
 }
 
+// Redirect related
+
+func ToRedirectDA(redirect Redirect) RedirectDA {
+	return RedirectDA{
+		ID:         redirect.ID(),
+		ShortID:    redirect.ShortID(),
+		SourcePath: redirect.SourcePath,
+		TargetPath: redirect.TargetPath,
+		StatusCode: redirect.StatusCode,
+		Wildcard:   redirect.Wildcard,
+		Auto:       redirect.Auto,
+		CreatedBy:  am.UUIDPtr(redirect.CreatedBy()),
+		UpdatedBy:  am.UUIDPtr(redirect.UpdatedBy()),
+		CreatedAt:  am.TimePtr(redirect.CreatedAt()),
+		UpdatedAt:  am.TimePtr(redirect.UpdatedAt()),
+	}
+}
+
+func ToRedirect(da RedirectDA) Redirect {
+	return Redirect{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(redirectType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		SourcePath: da.SourcePath,
+		TargetPath: da.TargetPath,
+		StatusCode: da.StatusCode,
+		Wildcard:   da.Wildcard,
+		Auto:       da.Auto,
+	}
+}
+
+func ToRedirects(das []RedirectDA) []Redirect {
+	redirects := make([]Redirect, len(das))
+	for i, da := range das {
+		redirects[i] = ToRedirect(da)
+	}
+	return redirects
+}
+
+// Build related
+
+func ToBuildDA(build Build) BuildDA {
+	return BuildDA{
+		ID:            build.ID(),
+		ShortID:       build.ShortID(),
+		Trigger:       build.Trigger,
+		UserID:        build.UserID.String(),
+		Status:        build.Status,
+		StartedAt:     am.TimePtr(build.StartedAt),
+		FinishedAt:    am.TimePtr(build.FinishedAt),
+		DurationMS:    build.Duration().Milliseconds(),
+		PagesRendered: build.PagesRendered,
+		PagesSkipped:  build.PagesSkipped,
+		PagesDeleted:  build.PagesDeleted,
+		Warnings:      toJSONList(build.Warnings),
+		Errors:        toJSONList(build.Errors),
+		OutputHash:    build.OutputHash,
+		DataHashes:    toJSONMap(build.DataHashes),
+		CreatedBy:     am.UUIDPtr(build.CreatedBy()),
+		UpdatedBy:     am.UUIDPtr(build.UpdatedBy()),
+		CreatedAt:     am.TimePtr(build.CreatedAt()),
+		UpdatedAt:     am.TimePtr(build.UpdatedAt()),
+	}
+}
+
+func ToBuild(da BuildDA) Build {
+	return Build{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(buildType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Trigger:       da.Trigger,
+		UserID:        am.ParseUUID(da.UserID),
+		Status:        da.Status,
+		StartedAt:     am.TimeVal(da.StartedAt),
+		FinishedAt:    am.TimeVal(da.FinishedAt),
+		PagesRendered: da.PagesRendered,
+		PagesSkipped:  da.PagesSkipped,
+		PagesDeleted:  da.PagesDeleted,
+		Warnings:      fromJSONList(da.Warnings),
+		Errors:        fromJSONList(da.Errors),
+		OutputHash:    da.OutputHash,
+		DataHashes:    fromJSONMap(da.DataHashes),
+	}
+}
+
+func ToBuilds(das []BuildDA) []Build {
+	builds := make([]Build, len(das))
+	for i, da := range das {
+		builds[i] = ToBuild(da)
+	}
+	return builds
+}
+
+// toJSONList encodes a list of strings for storage in a text column.
+func toJSONList(list []string) string {
+	if len(list) == 0 {
+		return "[]"
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
+
+func fromJSONList(s string) []string {
+	var list []string
+	_ = json.Unmarshal([]byte(s), &list)
+	return list
+}
+
+// PublishTarget related
+
+// ToPublishTargetDA converts a target for storage. Credentials must already
+// be encrypted into CredentialsEnc.
+func ToPublishTargetDA(target PublishTarget) PublishTargetDA {
+	return PublishTargetDA{
+		ID:             target.ID(),
+		ShortID:        target.ShortID(),
+		Name:           target.Name,
+		Environment:    target.Environment,
+		BaseURL:        target.BaseURL,
+		Kind:           target.Kind,
+		Settings:       toJSONMap(target.Settings),
+		CredentialsEnc: target.CredentialsEnc,
+		CreatedBy:      am.UUIDPtr(target.CreatedBy()),
+		UpdatedBy:      am.UUIDPtr(target.UpdatedBy()),
+		CreatedAt:      am.TimePtr(target.CreatedAt()),
+		UpdatedAt:      am.TimePtr(target.UpdatedAt()),
+	}
+}
+
+func ToPublishTarget(da PublishTargetDA) PublishTarget {
+	return PublishTarget{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(publishTargetType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:           da.Name,
+		Environment:    da.Environment,
+		BaseURL:        da.BaseURL,
+		Kind:           da.Kind,
+		Settings:       fromJSONMap(da.Settings),
+		Credentials:    map[string]string{},
+		CredentialsEnc: da.CredentialsEnc,
+	}
+}
+
+func ToPublishTargets(das []PublishTargetDA) []PublishTarget {
+	targets := make([]PublishTarget, len(das))
+	for i, da := range das {
+		targets[i] = ToPublishTarget(da)
+	}
+	return targets
+}
+
+// Deployment related
+
+func ToDeploymentDA(deployment Deployment) DeploymentDA {
+	return DeploymentDA{
+		ID:           deployment.ID(),
+		ShortID:      deployment.ShortID(),
+		TargetID:     deployment.TargetID.String(),
+		TargetName:   deployment.TargetName,
+		Environment:  deployment.Environment,
+		BuildID:      deployment.BuildID.String(),
+		UserID:       deployment.UserID.String(),
+		PromotedFrom: deployment.PromotedFrom.String(),
+		Status:       deployment.Status,
+		Changes:      deployment.Changes,
+		Ref:          deployment.Ref,
+		Error:        deployment.Error,
+		StartedAt:    am.TimePtr(deployment.StartedAt),
+		FinishedAt:   am.TimePtr(deployment.FinishedAt),
+		CreatedBy:    am.UUIDPtr(deployment.CreatedBy()),
+		UpdatedBy:    am.UUIDPtr(deployment.UpdatedBy()),
+		CreatedAt:    am.TimePtr(deployment.CreatedAt()),
+		UpdatedAt:    am.TimePtr(deployment.UpdatedAt()),
+	}
+}
+
+func ToDeployment(da DeploymentDA) Deployment {
+	return Deployment{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(deploymentType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		TargetID:     am.ParseUUID(da.TargetID),
+		TargetName:   da.TargetName,
+		Environment:  da.Environment,
+		BuildID:      am.ParseUUID(da.BuildID),
+		UserID:       am.ParseUUID(da.UserID),
+		PromotedFrom: am.ParseUUID(da.PromotedFrom),
+		Status:       da.Status,
+		Changes:      da.Changes,
+		Ref:          da.Ref,
+		Error:        da.Error,
+		StartedAt:    am.TimeVal(da.StartedAt),
+		FinishedAt:   am.TimeVal(da.FinishedAt),
+	}
+}
+
+func ToDeployments(das []DeploymentDA) []Deployment {
+	deployments := make([]Deployment, len(das))
+	for i, da := range das {
+		deployments[i] = ToDeployment(da)
+	}
+	return deployments
+}
+
+// toJSONMap encodes a string map for storage in a text column.
+func toJSONMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+func fromJSONMap(s string) map[string]string {
+	m := map[string]string{}
+	_ = json.Unmarshal([]byte(s), &m)
+	return m
+}
+
+// Webhook related
+
+// ToWebhookDA converts a webhook for storage. The secret must already be
+// encrypted into SecretEnc.
+func ToWebhookDA(hook Webhook) WebhookDA {
+	return WebhookDA{
+		ID:        hook.ID(),
+		ShortID:   hook.ShortID(),
+		Name:      hook.Name,
+		URL:       hook.URL,
+		Events:    toJSONList(hook.Events),
+		Active:    hook.Active,
+		SecretEnc: hook.SecretEnc,
+		CreatedBy: am.UUIDPtr(hook.CreatedBy()),
+		UpdatedBy: am.UUIDPtr(hook.UpdatedBy()),
+		CreatedAt: am.TimePtr(hook.CreatedAt()),
+		UpdatedAt: am.TimePtr(hook.UpdatedAt()),
+	}
+}
+
+func ToWebhook(da WebhookDA) Webhook {
+	return Webhook{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(webhookType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:      da.Name,
+		URL:       da.URL,
+		Events:    fromJSONList(da.Events),
+		Active:    da.Active,
+		SecretEnc: da.SecretEnc,
+	}
+}
+
+func ToWebhooks(das []WebhookDA) []Webhook {
+	hooks := make([]Webhook, len(das))
+	for i, da := range das {
+		hooks[i] = ToWebhook(da)
+	}
+	return hooks
+}
+
+// WebhookDelivery related
+
+func ToWebhookDeliveryDA(delivery WebhookDelivery) WebhookDeliveryDA {
+	return WebhookDeliveryDA{
+		ID:          delivery.ID(),
+		ShortID:     delivery.ShortID(),
+		WebhookID:   delivery.WebhookID.String(),
+		WebhookName: delivery.WebhookName,
+		EventID:     delivery.EventID.String(),
+		Event:       delivery.Event,
+		URL:         delivery.URL,
+		Attempt:     delivery.Attempt,
+		StatusCode:  delivery.StatusCode,
+		Error:       delivery.Error,
+		DurationMS:  delivery.DurationMS,
+		Payload:     delivery.Payload,
+		DeliveredAt: am.TimePtr(delivery.DeliveredAt),
+		RetryAt:     am.TimePtr(delivery.RetryAt),
+		CreatedBy:   am.UUIDPtr(delivery.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(delivery.UpdatedBy()),
+		CreatedAt:   am.TimePtr(delivery.CreatedAt()),
+		UpdatedAt:   am.TimePtr(delivery.UpdatedAt()),
+	}
+}
+
+func ToWebhookDelivery(da WebhookDeliveryDA) WebhookDelivery {
+	return WebhookDelivery{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(webhookDeliveryType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		WebhookID:   am.ParseUUID(da.WebhookID),
+		WebhookName: da.WebhookName,
+		EventID:     am.ParseUUID(da.EventID),
+		Event:       da.Event,
+		URL:         da.URL,
+		Attempt:     da.Attempt,
+		StatusCode:  da.StatusCode,
+		Error:       da.Error,
+		DurationMS:  da.DurationMS,
+		Payload:     da.Payload,
+		DeliveredAt: am.TimeVal(da.DeliveredAt),
+		RetryAt:     am.TimeVal(da.RetryAt),
+	}
+}
+
+func ToWebhookDeliveries(das []WebhookDeliveryDA) []WebhookDelivery {
+	deliveries := make([]WebhookDelivery, len(das))
+	for i, da := range das {
+		deliveries[i] = ToWebhookDelivery(da)
+	}
+	return deliveries
+}
+
+// APIToken related
+
+func ToAPITokenDA(token APIToken) APITokenDA {
+	return APITokenDA{
+		ID:         token.ID(),
+		ShortID:    token.ShortID(),
+		Name:       token.Name,
+		Scopes:     toJSONList(token.Scopes),
+		Prefix:     token.Prefix,
+		TokenHash:  token.TokenHash,
+		UserID:     token.UserID.String(),
+		SiteID:     token.SiteID.String(),
+		LastUsedAt: am.TimePtr(token.LastUsedAt),
+		CreatedBy:  am.UUIDPtr(token.CreatedBy()),
+		UpdatedBy:  am.UUIDPtr(token.UpdatedBy()),
+		CreatedAt:  am.TimePtr(token.CreatedAt()),
+		UpdatedAt:  am.TimePtr(token.UpdatedAt()),
+	}
+}
+
+func ToAPIToken(da APITokenDA) APIToken {
+	return APIToken{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(apiTokenType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:       da.Name,
+		Scopes:     fromJSONList(da.Scopes),
+		Prefix:     da.Prefix,
+		TokenHash:  da.TokenHash,
+		UserID:     am.ParseUUID(da.UserID),
+		SiteID:     am.ParseUUID(da.SiteID),
+		LastUsedAt: am.TimeVal(da.LastUsedAt),
+	}
+}
+
+func ToAPITokens(das []APITokenDA) []APIToken {
+	tokens := make([]APIToken, len(das))
+	for i, da := range das {
+		tokens[i] = ToAPIToken(da)
+	}
+	return tokens
+}
+
+// IdempotencyKey related
+
+func ToIdempotencyKeyDA(key IdempotencyKey) IdempotencyKeyDA {
+	return IdempotencyKeyDA{
+		TokenID:     key.TokenID.String(),
+		Key:         key.Key,
+		RequestHash: key.RequestHash,
+		StatusCode:  key.StatusCode,
+		Body:        key.Body,
+		CreatedAt:   am.TimePtr(key.CreatedAt),
+	}
+}
+
+func ToIdempotencyKey(da IdempotencyKeyDA) IdempotencyKey {
+	return IdempotencyKey{
+		TokenID:     am.ParseUUID(da.TokenID),
+		Key:         da.Key,
+		RequestHash: da.RequestHash,
+		StatusCode:  da.StatusCode,
+		Body:        da.Body,
+		CreatedAt:   am.TimeVal(da.CreatedAt),
+	}
+}
+
+// Term related
+
+func ToTermDA(term Term) TermDA {
+	return TermDA{
+		ID:        term.ID(),
+		ShortID:   term.ShortID(),
+		Kind:      term.Kind,
+		Name:      term.Name,
+		Slug:      term.SlugValue,
+		CreatedBy: am.UUIDPtr(term.CreatedBy()),
+		UpdatedBy: am.UUIDPtr(term.UpdatedBy()),
+		CreatedAt: am.TimePtr(term.CreatedAt()),
+		UpdatedAt: am.TimePtr(term.UpdatedAt()),
+	}
+}
+
+func ToTerm(da TermDA) Term {
+	return Term{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(termType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Kind:      da.Kind,
+		Name:      da.Name,
+		SlugValue: da.Slug,
+	}
+}
+
+func ToTerms(das []TermDA) []Term {
+	terms := make([]Term, len(das))
+	for i, da := range das {
+		terms[i] = ToTerm(da)
+	}
+	return terms
+}
+
+// Site related
+
+func ToSiteDA(site Site) SiteDA {
+	return SiteDA{
+		ID:          site.ID(),
+		ShortID:     site.ShortID(),
+		Name:        site.Name,
+		Slug:        site.SlugValue,
+		Description: site.Description,
+		OrgID:       site.OrgID.String(),
+		TeamID:      site.TeamID.String(),
+		CreatedBy:   am.UUIDPtr(site.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(site.UpdatedBy()),
+		CreatedAt:   am.TimePtr(site.CreatedAt()),
+		UpdatedAt:   am.TimePtr(site.UpdatedAt()),
+	}
+}
+
+func ToSite(da SiteDA) Site {
+	return Site{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(siteType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:        da.Name,
+		SlugValue:   da.Slug,
+		Description: da.Description,
+		OrgID:       am.ParseUUID(da.OrgID),
+		TeamID:      am.ParseUUID(da.TeamID),
+	}
+}
+
+func ToSites(das []SiteDA) []Site {
+	sites := make([]Site, len(das))
+	for i, da := range das {
+		sites[i] = ToSite(da)
+	}
+	return sites
+}
+
+// SiteSettings related
+
+func ToSiteSettingsDA(settings SiteSettings) SiteSettingsDA {
+	return SiteSettingsDA{
+		ID:          settings.ID(),
+		ShortID:     settings.ShortID(),
+		Title:       settings.Title,
+		BaseURL:     settings.BaseURL,
+		DefaultLang: settings.DefaultLang,
+		AuthorName:  settings.AuthorName,
+		AuthorEmail: settings.AuthorEmail,
+		AuthorURL:   settings.AuthorURL,
+		SocialLinks: toJSONLinks(settings.SocialLinks),
+		Analytics:   settings.Analytics,
+		DateFormat:  settings.DateFormat,
+		Description: settings.Description,
+		SocialImage: settings.SocialImage,
+		Logo:        settings.Logo,
+		CreatedBy:   am.UUIDPtr(settings.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(settings.UpdatedBy()),
+		CreatedAt:   am.TimePtr(settings.CreatedAt()),
+		UpdatedAt:   am.TimePtr(settings.UpdatedAt()),
+	}
+}
+
+func ToSiteSettings(da SiteSettingsDA) SiteSettings {
+	return SiteSettings{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(siteSettingsType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Title:       da.Title,
+		BaseURL:     da.BaseURL,
+		DefaultLang: da.DefaultLang,
+		AuthorName:  da.AuthorName,
+		AuthorEmail: da.AuthorEmail,
+		AuthorURL:   da.AuthorURL,
+		SocialLinks: fromJSONLinks(da.SocialLinks),
+		Analytics:   da.Analytics,
+		DateFormat:  da.DateFormat,
+		Description: da.Description,
+		SocialImage: da.SocialImage,
+		Logo:        da.Logo,
+	}
+}
+
+func toJSONLinks(links []SocialLink) string {
+	if len(links) == 0 {
+		return "[]"
+	}
+	b, err := json.Marshal(links)
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
+
+func fromJSONLinks(s string) []SocialLink {
+	var links []SocialLink
+	_ = json.Unmarshal([]byte(s), &links)
+	return links
+}
+
+// ContentType related
+
+func ToContentTypeDA(ct ContentType) ContentTypeDA {
+	return ContentTypeDA{
+		ID:          ct.ID(),
+		ShortID:     ct.ShortID(),
+		Name:        ct.Name,
+		Slug:        ct.SlugValue,
+		Description: ct.Description,
+		Fields:      toJSONFields(ct.Fields),
+		LayoutID:    ct.LayoutID.String(),
+		CreatedBy:   am.UUIDPtr(ct.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(ct.UpdatedBy()),
+		CreatedAt:   am.TimePtr(ct.CreatedAt()),
+		UpdatedAt:   am.TimePtr(ct.UpdatedAt()),
+	}
+}
+
+func ToContentType(da ContentTypeDA) ContentType {
+	return ContentType{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(contentTypeType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:        da.Name,
+		SlugValue:   da.Slug,
+		Description: da.Description,
+		Fields:      fromJSONFields(da.Fields),
+		LayoutID:    am.ParseUUID(da.LayoutID),
+	}
+}
+
+func ToContentTypes(das []ContentTypeDA) []ContentType {
+	types := make([]ContentType, len(das))
+	for i, da := range das {
+		types[i] = ToContentType(da)
+	}
+	return types
+}
+
+func toJSONFields(fields []FieldDef) string {
+	if len(fields) == 0 {
+		return "[]"
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
+
+func fromJSONFields(s string) []FieldDef {
+	var fields []FieldDef
+	_ = json.Unmarshal([]byte(s), &fields)
+	return fields
+}
+
+// DataFile related
+
+func ToDataFileDA(file DataFile) DataFileDA {
+	return DataFileDA{
+		ID:        file.ID(),
+		ShortID:   file.ShortID(),
+		Name:      file.Name,
+		Format:    file.Format,
+		Content:   file.Content,
+		Schema:    toJSONFields(file.Schema),
+		Source:    file.Source,
+		Path:      file.Path,
+		CreatedBy: am.UUIDPtr(file.CreatedBy()),
+		UpdatedBy: am.UUIDPtr(file.UpdatedBy()),
+		CreatedAt: am.TimePtr(file.CreatedAt()),
+		UpdatedAt: am.TimePtr(file.UpdatedAt()),
+	}
+}
+
+func ToDataFile(da DataFileDA) DataFile {
+	return DataFile{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(dataFileType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:    da.Name,
+		Format:  da.Format,
+		Content: da.Content,
+		Schema:  fromJSONFields(da.Schema),
+		Source:  da.Source,
+		Path:    da.Path,
+	}
+}
+
+func ToDataFiles(das []DataFileDA) []DataFile {
+	files := make([]DataFile, len(das))
+	for i, da := range das {
+		files[i] = ToDataFile(da)
+	}
+	return files
+}
+
+// Menu related
+
+func ToMenuDA(menu Menu) MenuDA {
+	return MenuDA{
+		ID:          menu.ID(),
+		ShortID:     menu.ShortID(),
+		Name:        menu.Name,
+		Description: menu.Description,
+		CreatedBy:   am.UUIDPtr(menu.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(menu.UpdatedBy()),
+		CreatedAt:   am.TimePtr(menu.CreatedAt()),
+		UpdatedAt:   am.TimePtr(menu.UpdatedAt()),
+	}
+}
+
+func ToMenu(da MenuDA) Menu {
+	return Menu{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(menuType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:        da.Name,
+		Description: da.Description,
+	}
+}
+
+func ToMenus(das []MenuDA) []Menu {
+	menus := make([]Menu, len(das))
+	for i, da := range das {
+		menus[i] = ToMenu(da)
+	}
+	return menus
+}
+
+func ToMenuItemDA(item MenuItem) MenuItemDA {
+	return MenuItemDA{
+		ID:        item.ID(),
+		ShortID:   item.ShortID(),
+		MenuID:    item.MenuID.String(),
+		ParentID:  item.ParentID.String(),
+		Label:     item.Label,
+		Kind:      item.Kind,
+		TargetID:  item.TargetID.String(),
+		URL:       item.URL,
+		Position:  item.Position,
+		CreatedBy: am.UUIDPtr(item.CreatedBy()),
+		UpdatedBy: am.UUIDPtr(item.UpdatedBy()),
+		CreatedAt: am.TimePtr(item.CreatedAt()),
+		UpdatedAt: am.TimePtr(item.UpdatedAt()),
+	}
+}
+
+func ToMenuItem(da MenuItemDA) MenuItem {
+	return MenuItem{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(menuItemType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		MenuID:   am.ParseUUID(da.MenuID),
+		ParentID: am.ParseUUID(da.ParentID),
+		Label:    da.Label,
+		Kind:     da.Kind,
+		TargetID: am.ParseUUID(da.TargetID),
+		URL:      da.URL,
+		Position: da.Position,
+	}
+}
+
+func ToMenuItems(das []MenuItemDA) []MenuItem {
+	items := make([]MenuItem, len(das))
+	for i, da := range das {
+		items[i] = ToMenuItem(da)
+	}
+	return items
+}
+
+func ToContentTerms(das []ContentTermDA) []ContentTerm {
+	links := make([]ContentTerm, len(das))
+	for i, da := range das {
+		links[i] = ContentTerm{
+			ContentID: am.ParseUUID(da.ContentID),
+			TermID:    am.ParseUUID(da.TermID),
+		}
+	}
+	return links
+}
+
+// Series related
+
+func ToSeriesDA(series Series) SeriesDA {
+	return SeriesDA{
+		ID:          series.ID(),
+		ShortID:     series.ShortID(),
+		Name:        series.Name,
+		Slug:        series.SlugValue,
+		Description: series.Description,
+		CreatedBy:   am.UUIDPtr(series.CreatedBy()),
+		UpdatedBy:   am.UUIDPtr(series.UpdatedBy()),
+		CreatedAt:   am.TimePtr(series.CreatedAt()),
+		UpdatedAt:   am.TimePtr(series.UpdatedAt()),
+	}
+}
+
+func ToSeries(da SeriesDA) Series {
+	return Series{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(seriesType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		Name:        da.Name,
+		SlugValue:   da.Slug,
+		Description: da.Description,
+	}
+}
+
+func ToSeriesList(das []SeriesDA) []Series {
+	series := make([]Series, len(das))
+	for i, da := range das {
+		series[i] = ToSeries(da)
+	}
+	return series
+}
+
+func ToSeriesPartDA(part SeriesPart) SeriesPartDA {
+	return SeriesPartDA{
+		SeriesID:  part.SeriesID.String(),
+		ContentID: part.ContentID.String(),
+		Position:  part.Position,
+	}
+}
+
+func ToSeriesParts(das []SeriesPartDA) []SeriesPart {
+	parts := make([]SeriesPart, len(das))
+	for i, da := range das {
+		parts[i] = SeriesPart{
+			SeriesID:  am.ParseUUID(da.SeriesID),
+			ContentID: am.ParseUUID(da.ContentID),
+			Position:  da.Position,
+		}
+	}
+	return parts
+}
+
+// Profile related
+
+func ToProfileDA(profile Profile) ProfileDA {
+	da := ProfileDA{
+		ID:        profile.ID(),
+		ShortID:   profile.ShortID(),
+		Name:      profile.Name,
+		Slug:      profile.SlugValue,
+		Bio:       profile.Bio,
+		Avatar:    profile.Avatar,
+		Links:     toJSONLinks(profile.Links),
+		CreatedBy: am.UUIDPtr(profile.CreatedBy()),
+		UpdatedBy: am.UUIDPtr(profile.UpdatedBy()),
+		CreatedAt: am.TimePtr(profile.CreatedAt()),
+		UpdatedAt: am.TimePtr(profile.UpdatedAt()),
+	}
+	if profile.UserID != uuid.Nil {
+		da.UserID = profile.UserID.String()
+	}
+	return da
+}
+
+func ToProfile(da ProfileDA) Profile {
+	return Profile{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID),
+			am.WithType(profileType),
+			am.WithCreatedBy(am.UUIDVal(da.CreatedBy)),
+			am.WithUpdatedBy(am.UUIDVal(da.UpdatedBy)),
+			am.WithCreatedAt(am.TimeVal(da.CreatedAt)),
+			am.WithUpdatedAt(am.TimeVal(da.UpdatedAt)),
+		),
+		UserID:    am.ParseUUID(da.UserID),
+		Name:      da.Name,
+		SlugValue: da.Slug,
+		Bio:       da.Bio,
+		Avatar:    da.Avatar,
+		Links:     fromJSONLinks(da.Links),
+	}
+}
+
+func ToProfiles(das []ProfileDA) []Profile {
+	profiles := make([]Profile, len(das))
+	for i, da := range das {
+		profiles[i] = ToProfile(da)
+	}
+	return profiles
+}
+
+func ToContentAuthorDA(link ContentAuthor) ContentAuthorDA {
+	return ContentAuthorDA{
+		ContentID: link.ContentID.String(),
+		ProfileID: link.ProfileID.String(),
+		Position:  link.Position,
+	}
+}
+
+func ToContentAuthors(das []ContentAuthorDA) []ContentAuthor {
+	links := make([]ContentAuthor, len(das))
+	for i, da := range das {
+		links[i] = ContentAuthor{
+			ContentID: am.ParseUUID(da.ContentID),
+			ProfileID: am.ParseUUID(da.ProfileID),
+			Position:  da.Position,
+		}
+	}
+	return links
+}
+
+// ContentLock related
+
+func ToContentLockDA(lock ContentLock) ContentLockDA {
+	return ContentLockDA{
+		ContentID:  lock.ContentID.String(),
+		UserID:     lock.UserID.String(),
+		AcquiredAt: am.TimePtr(lock.AcquiredAt),
+		ExpiresAt:  am.TimePtr(lock.ExpiresAt),
+	}
+}
+
+func ToContentLock(da ContentLockDA) ContentLock {
+	return ContentLock{
+		ContentID:  am.ParseUUID(da.ContentID),
+		UserID:     am.ParseUUID(da.UserID),
+		AcquiredAt: am.TimeVal(da.AcquiredAt),
+		ExpiresAt:  am.TimeVal(da.ExpiresAt),
+	}
+}
+
+func ToContentLocks(das []ContentLockDA) []ContentLock {
+	locks := make([]ContentLock, len(das))
+	for i, da := range das {
+		locks[i] = ToContentLock(da)
+	}
+	return locks
+}
+
+// ContentDraft related
+
+func ToContentDraftDA(draft ContentDraft) ContentDraftDA {
+	return ContentDraftDA{
+		ContentID: draft.ContentID.String(),
+		UserID:    draft.UserID.String(),
+		Version:   draft.Version,
+		Form:      draft.Values.Encode(),
+		SavedAt:   am.TimePtr(draft.SavedAt),
+	}
+}
+
+// ToContentDraft drops form values that cannot be decoded, a draft is only a
+// best effort copy.
+func ToContentDraft(da ContentDraftDA) ContentDraft {
+	values, _ := url.ParseQuery(da.Form)
+	return ContentDraft{
+		ContentID: am.ParseUUID(da.ContentID),
+		UserID:    am.ParseUUID(da.UserID),
+		Version:   da.Version,
+		Values:    values,
+		SavedAt:   am.TimeVal(da.SavedAt),
+	}
+}
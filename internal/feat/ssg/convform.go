@@ -2,40 +2,102 @@ package ssg
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
 // Content related
 
+// ToContentForm expects the type of the content, if any, to be set on the
+// form afterwards.
 func ToContentForm(r *http.Request, content Content) ContentForm {
-	return ContentForm{
-		BaseForm:  am.NewBaseForm(r),
-		ID:        content.ID().String(),
-		Heading:   content.Heading,
-		Body:      content.Body,
-		SectionID: content.SectionID.String(),
+	form := ContentForm{
+		BaseForm:      am.NewBaseForm(r),
+		ID:            content.ID().String(),
+		Heading:       content.Heading,
+		Slug:          content.SlugValue,
+		Body:          content.Body,
+		Status:        content.Status,
+		SectionID:     content.SectionID.String(),
+		Lang:          content.Lang,
+		TranslationID: content.TranslationID.String(),
+		Version:       strconv.Itoa(content.Version),
+		Values:        content.Fields,
 	}
+	if content.TypeID != uuid.Nil {
+		form.TypeID = content.TypeID.String()
+	}
+	form.MetaTitle = content.MetaTitle
+	form.MetaDescription = content.MetaDescription
+	form.CanonicalURL = content.CanonicalURL
+	form.SocialImage = content.SocialImage
+	if content.NoIndex {
+		form.NoIndex = "true"
+	}
+	for _, id := range content.AuthorIDs {
+		form.AuthorIDs = append(form.AuthorIDs, id.String())
+	}
+	if content.TOCMinDepth != 0 {
+		form.TOCMinDepth = strconv.Itoa(content.TOCMinDepth)
+	}
+	if content.TOCMaxDepth != 0 {
+		form.TOCMaxDepth = strconv.Itoa(content.TOCMaxDepth)
+	}
+	return form
 }
 
+// ToContentFromForm keeps only the values of the fields of the form type.
 func ToContentFromForm(form ContentForm) Content {
-	return Content{
-		BaseModel: am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(contentType)),
-		Heading:   form.Heading,
-		Body:      form.Body,
-		SectionID: am.ParseUUID(form.SectionID),
+	content := Content{
+		BaseModel:     am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(contentType)),
+		Heading:       form.Heading,
+		SlugValue:     form.Slug,
+		Body:          form.Body,
+		Status:        form.Status,
+		SectionID:     am.ParseUUID(form.SectionID),
+		Lang:          form.Lang,
+		TranslationID: am.ParseUUID(form.TranslationID),
+		TypeID:        am.ParseUUID(form.TypeID),
+	}
+	content.PageMeta = toPageMeta(form.MetaTitle, form.MetaDescription, form.CanonicalURL, form.SocialImage, form.NoIndex)
+	content.TOCMinDepth, _ = strconv.Atoi(form.TOCMinDepth)
+	content.TOCMaxDepth, _ = strconv.Atoi(form.TOCMaxDepth)
+	content.Version, _ = strconv.Atoi(form.Version)
+	// Never nil, the form always posts the full list of authors.
+	content.AuthorIDs = []uuid.UUID{}
+	for _, id := range form.AuthorIDs {
+		if authorID := am.ParseUUID(id); authorID != uuid.Nil {
+			content.AuthorIDs = append(content.AuthorIDs, authorID)
+		}
+	}
+	for _, def := range form.Type.Fields {
+		if content.Fields == nil {
+			content.Fields = map[string]string{}
+		}
+		content.Fields[def.Name] = form.Values[def.Name]
 	}
+	return content
 }
 
 // Section related
 func ToSectionForm(section Section) SectionForm {
 	return SectionForm{
-		Name:        section.Name,
-		Description: section.Description,
-		Path:        section.Path,
-		LayoutID:    section.LayoutID.String(),
-		Image:       section.Image,
-		Header:      section.Header,
+		Name:            section.Name,
+		Description:     section.Description,
+		Path:            section.Path,
+		LayoutID:        section.LayoutID.String(),
+		Lang:            section.Lang,
+		Permalink:       section.Permalink,
+		Image:           section.Image,
+		Header:          section.Header,
+		MetaTitle:       section.MetaTitle,
+		MetaDescription: section.MetaDescription,
+		CanonicalURL:    section.CanonicalURL,
+		SocialImage:     section.SocialImage,
+		NoIndex:         strconv.FormatBool(section.NoIndex),
 	}
 }
 
@@ -46,8 +108,21 @@ func ToSectionFromForm(form SectionForm) Section {
 		Description: form.Description,
 		Path:        form.Path,
 		LayoutID:    am.ParseUUID(form.LayoutID),
+		Lang:        form.Lang,
+		Permalink:   form.Permalink,
 		Image:       form.Image,
 		Header:      form.Header,
+		PageMeta:    toPageMeta(form.MetaTitle, form.MetaDescription, form.CanonicalURL, form.SocialImage, form.NoIndex),
+	}
+}
+
+func toPageMeta(title, description, canonical, image, noindex string) PageMeta {
+	return PageMeta{
+		MetaTitle:       title,
+		MetaDescription: description,
+		CanonicalURL:    canonical,
+		SocialImage:     image,
+		NoIndex:         noindex == "true",
 	}
 }
 
@@ -68,3 +143,355 @@ func ToLayoutFromForm(form LayoutForm) Layout {
 		Code:        form.Code,
 	}
 }
+
+// Redirect related
+func ToRedirectForm(r *http.Request, redirect Redirect) RedirectForm {
+	form := RedirectForm{
+		BaseForm:   am.NewBaseForm(r),
+		ID:         redirect.ID().String(),
+		SourcePath: redirect.SourcePath,
+		TargetPath: redirect.TargetPath,
+		StatusCode: strconv.Itoa(redirect.StatusCode),
+	}
+	if redirect.Wildcard {
+		form.Wildcard = "on"
+	}
+	return form
+}
+
+func ToRedirectFromForm(form RedirectForm) Redirect {
+	code, _ := strconv.Atoi(form.StatusCode)
+	return Redirect{
+		BaseModel:  am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(redirectType)),
+		SourcePath: form.SourcePath,
+		TargetPath: form.TargetPath,
+		StatusCode: code,
+		Wildcard:   form.IsWildcard(),
+	}
+}
+
+// PublishTarget related
+func ToPublishTargetForm(r *http.Request, target PublishTarget) PublishTargetForm {
+	return PublishTargetForm{
+		BaseForm:     am.NewBaseForm(r),
+		ID:           target.ID().String(),
+		Name:         target.Name,
+		Environment:  target.Environment,
+		BaseURL:      target.BaseURL,
+		Kind:         target.Kind,
+		Repo:         target.Setting(SettingRepo),
+		Branch:       target.Setting(SettingBranch),
+		Dir:          target.Setting(SettingDir),
+		Addr:         target.Setting(SettingAddr),
+		User:         target.Setting(SettingUser),
+		KeyFile:      target.Setting(SettingKeyFile),
+		KnownHosts:   target.Setting(SettingKnownHosts),
+		Endpoint:     target.Setting(SettingEndpoint),
+		Region:       target.Setting(SettingRegion),
+		Bucket:       target.Setting(SettingBucket),
+		Prefix:       target.Setting(SettingPrefix),
+		HasPassword:  target.HasCredential(CredPassword),
+		HasAccessKey: target.HasCredential(CredAccessKey),
+		HasSecretKey: target.HasCredential(CredSecretKey),
+	}
+}
+
+// ToPublishTargetFromForm keeps only the settings and credentials of the
+// selected kind.
+func ToPublishTargetFromForm(form PublishTargetForm) PublishTarget {
+	target := NewPublishTarget(form.Name, form.Environment, form.Kind)
+	target.BaseModel = am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(publishTargetType))
+	target.BaseURL = form.BaseURL
+
+	set := func(m map[string]string, name, val string) {
+		if val != "" {
+			m[name] = val
+		}
+	}
+
+	switch form.Kind {
+	case PublishTargetGit:
+		set(target.Settings, SettingRepo, form.Repo)
+		set(target.Settings, SettingBranch, form.Branch)
+	case PublishTargetLocal:
+		set(target.Settings, SettingDir, form.Dir)
+	case PublishTargetSFTP:
+		set(target.Settings, SettingAddr, form.Addr)
+		set(target.Settings, SettingUser, form.User)
+		set(target.Settings, SettingKeyFile, form.KeyFile)
+		set(target.Settings, SettingKnownHosts, form.KnownHosts)
+		set(target.Settings, SettingDir, form.Dir)
+		set(target.Credentials, CredPassword, form.Password)
+	case PublishTargetS3:
+		set(target.Settings, SettingEndpoint, form.Endpoint)
+		set(target.Settings, SettingRegion, form.Region)
+		set(target.Settings, SettingBucket, form.Bucket)
+		set(target.Settings, SettingPrefix, form.Prefix)
+		set(target.Credentials, CredAccessKey, form.AccessKey)
+		set(target.Credentials, CredSecretKey, form.SecretKey)
+	}
+	return target
+}
+
+// Webhook related
+func ToWebhookForm(r *http.Request, hook Webhook) WebhookForm {
+	form := WebhookForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       hook.ID().String(),
+		Name:     hook.Name,
+		URL:      hook.URL,
+		Events:   hook.Events,
+		Secret:   hook.Secret,
+	}
+	if hook.Active {
+		form.Active = "on"
+	}
+	return form
+}
+
+func ToWebhookFromForm(form WebhookForm) Webhook {
+	hook := NewWebhook(form.Name, form.URL, form.Events...)
+	hook.BaseModel = am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(webhookType))
+	hook.Active = form.IsActive()
+	hook.Secret = form.Secret
+	return hook
+}
+
+// APIToken related
+
+func ToAPITokenFromForm(form APITokenForm, userID uuid.UUID) APIToken {
+	return NewAPIToken(form.Name, userID, form.Scopes...)
+}
+
+// Site related
+func ToSiteForm(r *http.Request, site Site) SiteForm {
+	return SiteForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          site.ID().String(),
+		Name:        site.Name,
+		Slug:        site.SlugValue,
+		Description: site.Description,
+		Owner:       site.OwnerKey(),
+	}
+}
+
+func ToSiteFromForm(form SiteForm) Site {
+	site := Site{
+		BaseModel:   am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(siteType)),
+		Name:        form.Name,
+		SlugValue:   form.Slug,
+		Description: form.Description,
+	}
+	kind, id, _ := strings.Cut(form.Owner, ":")
+	switch kind {
+	case ownerOrg:
+		site.OrgID = am.ParseUUID(id)
+	case ownerTeam:
+		site.TeamID = am.ParseUUID(id)
+	}
+	return site
+}
+
+// SiteSettings related
+func ToSiteSettingsForm(r *http.Request, settings SiteSettings, langs []string) SiteSettingsForm {
+	form := SiteSettingsForm{
+		BaseForm:    am.NewBaseForm(r),
+		Title:       settings.Title,
+		BaseURL:     settings.BaseURL,
+		DefaultLang: settings.DefaultLang,
+		AuthorName:  settings.AuthorName,
+		AuthorEmail: settings.AuthorEmail,
+		AuthorURL:   settings.AuthorURL,
+		SocialLinks: formatSocialLinks(settings.SocialLinks),
+		Analytics:   settings.Analytics,
+		DateFormat:  settings.DateFormat,
+		Description: settings.Description,
+		SocialImage: settings.SocialImage,
+		Logo:        settings.Logo,
+		Langs:       langs,
+	}
+	if !settings.IsZero() {
+		form.ID = settings.ID().String()
+	}
+	return form
+}
+
+// ToSiteSettingsFromForm expects a validated form, invalid social links are
+// dropped.
+func ToSiteSettingsFromForm(form SiteSettingsForm) SiteSettings {
+	links, _ := parseSocialLinks(form.SocialLinks)
+	return SiteSettings{
+		BaseModel:   am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(siteSettingsType)),
+		Title:       form.Title,
+		BaseURL:     form.BaseURL,
+		DefaultLang: form.DefaultLang,
+		AuthorName:  form.AuthorName,
+		AuthorEmail: form.AuthorEmail,
+		AuthorURL:   form.AuthorURL,
+		SocialLinks: links,
+		Analytics:   form.Analytics,
+		DateFormat:  form.DateFormat,
+		Description: form.Description,
+		SocialImage: form.SocialImage,
+		Logo:        form.Logo,
+	}
+}
+
+// ContentType related
+func ToContentTypeForm(r *http.Request, ct ContentType) ContentTypeForm {
+	form := ContentTypeForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          ct.ID().String(),
+		Name:        ct.Name,
+		Slug:        ct.SlugValue,
+		Description: ct.Description,
+		Fields:      formatFieldDefs(ct.Fields),
+	}
+	if ct.LayoutID != uuid.Nil {
+		form.LayoutID = ct.LayoutID.String()
+	}
+	return form
+}
+
+// ToContentTypeFromForm expects a validated form, invalid fields are dropped.
+func ToContentTypeFromForm(form ContentTypeForm) ContentType {
+	fields, _ := parseFieldDefs(form.Fields)
+	return ContentType{
+		BaseModel:   am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(contentTypeType)),
+		Name:        form.Name,
+		SlugValue:   form.Slug,
+		Description: form.Description,
+		Fields:      fields,
+		LayoutID:    am.ParseUUID(form.LayoutID),
+	}
+}
+
+// DataFile related
+func ToDataFileForm(r *http.Request, file DataFile) DataFileForm {
+	return DataFileForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       file.ID().String(),
+		Name:     file.Name,
+		Format:   file.Format,
+		Content:  file.Content,
+		Schema:   formatFieldDefs(file.Schema),
+	}
+}
+
+// ToDataFileFromForm expects a validated form, invalid fields are dropped.
+func ToDataFileFromForm(form DataFileForm) DataFile {
+	schema, _ := parseFieldDefs(form.Schema)
+	return DataFile{
+		BaseModel: am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(dataFileType)),
+		Name:      form.Name,
+		Format:    form.Format,
+		Content:   form.Content,
+		Schema:    schema,
+		Source:    DataSourceDB,
+	}
+}
+
+// Menu related
+func ToMenuForm(r *http.Request, menu Menu) MenuForm {
+	return MenuForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          menu.ID().String(),
+		Name:        menu.Name,
+		Description: menu.Description,
+	}
+}
+
+func ToMenuFromForm(form MenuForm) Menu {
+	return Menu{
+		BaseModel:   am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(menuType)),
+		Name:        form.Name,
+		Description: form.Description,
+	}
+}
+
+func ToMenuItemForm(r *http.Request, item MenuItem) MenuItemForm {
+	form := MenuItemForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       item.ID().String(),
+		MenuID:   item.MenuID.String(),
+		Label:    item.Label,
+		Kind:     item.Kind,
+		URL:      item.URL,
+	}
+	if item.ParentID != uuid.Nil {
+		form.ParentID = item.ParentID.String()
+	}
+	switch item.Kind {
+	case MenuItemSection:
+		form.SectionID = item.TargetID.String()
+	case MenuItemContent:
+		form.ContentID = item.TargetID.String()
+	}
+	return form
+}
+
+// ToMenuItemFromForm keeps only the target of the selected kind.
+func ToMenuItemFromForm(form MenuItemForm) MenuItem {
+	item := NewMenuItem(am.ParseUUID(form.MenuID), form.Label, form.Kind)
+	item.BaseModel = am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(menuItemType))
+	item.ParentID = am.ParseUUID(form.ParentID)
+	switch form.Kind {
+	case MenuItemSection:
+		item.TargetID = am.ParseUUID(form.SectionID)
+	case MenuItemContent:
+		item.TargetID = am.ParseUUID(form.ContentID)
+	case MenuItemURL:
+		item.URL = form.URL
+	}
+	return item
+}
+
+// Series related
+func ToSeriesForm(r *http.Request, series Series) SeriesForm {
+	return SeriesForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          series.ID().String(),
+		Name:        series.Name,
+		Slug:        series.SlugValue,
+		Description: series.Description,
+	}
+}
+
+func ToSeriesFromForm(form SeriesForm) Series {
+	return Series{
+		BaseModel:   am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(seriesType)),
+		Name:        form.Name,
+		SlugValue:   form.Slug,
+		Description: form.Description,
+	}
+}
+
+// Profile related
+func ToProfileForm(r *http.Request, profile Profile) ProfileForm {
+	form := ProfileForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       profile.ID().String(),
+		Name:     profile.Name,
+		Slug:     profile.SlugValue,
+		Bio:      profile.Bio,
+		Avatar:   profile.Avatar,
+		Links:    formatSocialLinks(profile.Links),
+	}
+	if profile.UserID != uuid.Nil {
+		form.UserID = profile.UserID.String()
+	}
+	return form
+}
+
+func ToProfileFromForm(form ProfileForm) Profile {
+	links, _ := parseSocialLinks(form.Links)
+	return Profile{
+		BaseModel: am.NewModel(am.WithID(am.ParseUUID(form.ID)), am.WithType(profileType)),
+		UserID:    am.ParseUUID(form.UserID),
+		Name:      form.Name,
+		SlugValue: form.Slug,
+		Bio:       form.Bio,
+		Avatar:    form.Avatar,
+		Links:     links,
+	}
+}
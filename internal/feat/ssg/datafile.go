@@ -0,0 +1,509 @@
+package ssg
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dataFileType = "data-file"
+	defDataDir   = "data"
+)
+
+// Data file formats.
+const (
+	DataJSON = "json"
+	DataYAML = "yaml"
+	DataCSV  = "csv" // First row holds the column names
+	DataTOML = "toml"
+)
+
+var dataFormats = []string{DataJSON, DataYAML, DataCSV, DataTOML}
+
+// Data file sources.
+const (
+	DataSourceDB   = "db"
+	DataSourceDisk = "disk" // Replaced on each sync from the data dir
+)
+
+// DataFile holds structured data layouts render as .Site.Data.<name>, such as
+// a team roster or a pricing table. An optional schema, written as the fields
+// of a content type, is checked against each record of the data.
+type DataFile struct {
+	*am.BaseModel
+	Name    string     `json:"name"`
+	Format  string     `json:"format"`
+	Content string     `json:"content"`
+	Schema  []FieldDef `json:"schema"`
+	Source  string     `json:"source"`
+	Path    string     `json:"path"` // File the data was synced from, relative to the data dir
+}
+
+func NewDataFile(name, format, content string) DataFile {
+	return DataFile{
+		BaseModel: am.NewModel(am.WithType(dataFileType)),
+		Name:      name,
+		Format:    format,
+		Content:   content,
+		Source:    DataSourceDB,
+	}
+}
+
+func (d DataFile) IsZero() bool {
+	return d.BaseModel == nil || d.BaseModel.IsZero()
+}
+
+func (d *DataFile) Slug() string {
+	return d.Name
+}
+
+// IsSynced reports whether the data comes from the data dir.
+func (d DataFile) IsSynced() bool {
+	return d.Source == DataSourceDisk
+}
+
+// Hash identifies the data as rendered, so that builds can tell whether it
+// changed since they ran.
+func (d DataFile) Hash() string {
+	sum := sha256.Sum256([]byte(d.Format + "\n" + toJSONFields(d.Schema) + "\n" + d.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Data parses the content and checks it against the schema. Values are
+// converted as for content type fields: markdown is rendered and dates are
+// parsed. CSV cells are also converted to the number, boolean or list the
+// schema expects.
+func (d DataFile) Data() (any, error) {
+	data, err := parseData(d.Format, d.Content)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.Schema) == 0 {
+		return data, nil
+	}
+	return applySchema(data, d.Schema, d.Format == DataCSV)
+}
+
+// DataFileStatus tells how a data file is used by the site.
+type DataFileStatus struct {
+	DataFile
+	Layouts []string // Names of the layouts that use the data
+	Stale   bool     // True if used and changed since the last successful build
+}
+
+// DataSyncReport lists what a sync from the data dir did, by data file name.
+type DataSyncReport struct {
+	Created []string
+	Updated []string
+	Deleted []string
+	Errors  []string
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (d *DataFile) UnmarshalJSON(data []byte) error {
+	type Alias DataFile
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*d = DataFile(*temp)
+	if d.BaseModel == nil {
+		d.BaseModel = am.NewModel(am.WithType(dataFileType))
+	}
+	return nil
+}
+
+func parseData(format, content string) (any, error) {
+	switch format {
+	case DataJSON:
+		var data any
+		err := json.Unmarshal([]byte(content), &data)
+		return data, err
+	case DataYAML:
+		var data any
+		err := yaml.Unmarshal([]byte(content), &data)
+		return data, err
+	case DataCSV:
+		return parseCSV(content)
+	case DataTOML:
+		fm, err := parseTOML(content)
+		return map[string]any(fm), err
+	}
+	return nil, fmt.Errorf("unknown format %s", format)
+}
+
+// parseCSV returns a record per row, keyed by the names in the first row.
+func parseCSV(content string) ([]any, error) {
+	rows, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []any{}, nil
+	}
+
+	header := rows[0]
+	records := make([]any, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		rec := make(map[string]any, len(header))
+		for i, name := range header {
+			rec[strings.TrimSpace(name)] = row[i]
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// applySchema checks that data is a record or a list of records matching
+// the schema and returns it with its values converted.
+func applySchema(data any, schema []FieldDef, fromText bool) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		return applyRecord(v, schema, fromText)
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			rec, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("item %d: expected a record", i+1)
+			}
+			out, err := applyRecord(rec, schema, fromText)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			list[i] = out
+		}
+		return list, nil
+	}
+	return nil, errors.New("expected a record or a list of records")
+}
+
+// applyRecord converts the fields of a record the schema describes. Other
+// fields are kept as they are.
+func applyRecord(rec map[string]any, schema []FieldDef, fromText bool) (map[string]any, error) {
+	out := make(map[string]any, len(rec))
+	for k, v := range rec {
+		out[k] = v
+	}
+
+	for _, f := range schema {
+		v, ok := rec[f.Name]
+		if !ok || v == nil || v == "" {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Name)
+			}
+			continue
+		}
+
+		val, err := dataValue(f, v, fromText)
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", f.Name, err)
+		}
+		out[f.Name] = val
+	}
+	return out, nil
+}
+
+func dataValue(f FieldDef, v any, fromText bool) (any, error) {
+	s, isString := v.(string)
+
+	switch f.Type {
+	case FieldNumber:
+		switch n := v.(type) {
+		case int, int64, uint64, float64:
+			return n, nil
+		}
+		if isString && fromText {
+			if n, err := strconv.ParseFloat(s, 64); err == nil {
+				return n, nil
+			}
+		}
+		return nil, errors.New("must be a number")
+	case FieldBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		if isString && fromText {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b, nil
+			}
+		}
+		return nil, errors.New("must be true or false")
+	case FieldDate:
+		if t, ok := v.(time.Time); ok {
+			return t, nil
+		}
+		if isString {
+			if t, err := time.Parse(fieldDateFormat, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("must be a date as %s", fieldDateFormat)
+	case FieldList:
+		if list, ok := v.([]any); ok {
+			return list, nil
+		}
+		if isString && fromText {
+			return splitList(s), nil
+		}
+		return nil, errors.New("must be a list")
+	}
+
+	if !isString {
+		return nil, errors.New("must be text")
+	}
+
+	switch f.Type {
+	case FieldMarkdown:
+		return RenderMarkdown(s)
+	case FieldReference:
+		if _, err := uuid.Parse(s); err != nil {
+			return nil, errors.New("must be a content ID")
+		}
+	case FieldMedia:
+		if !isMediaRef(s) {
+			return nil, errors.New("must be a site path or an http or https URL")
+		}
+	}
+	return s, nil
+}
+
+// dataFormatFor returns the format of a data file by its extension.
+func dataFormatFor(path string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return DataJSON, true
+	case ".yaml", ".yml":
+		return DataYAML, true
+	case ".csv":
+		return DataCSV, true
+	case ".toml":
+		return DataTOML, true
+	}
+	return "", false
+}
+
+// readDataDir returns the data files found in dir, named after their path
+// without extension, as in team for team.yaml and people_team_members for
+// people/team-members.csv. Files of other formats are
+// ignored. A missing dir holds no data.
+func readDataDir(dir string) ([]DataFile, error) {
+	var files []DataFile
+	err := filepath.WalkDir(dir, func(path string, e os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		format, ok := dataFormatFor(path)
+		if e.IsDir() || !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		name := strings.NewReplacer("/", "_", "-", "_").Replace(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		f := NewDataFile(name, format, string(b))
+		f.Source = DataSourceDisk
+		f.Path = rel
+		files = append(files, f)
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// dataRefs returns the names of the data files a layout uses, as in
+// .Site.Data.team or index .Site.Data "team". all is true if the layout uses
+// .Site.Data in any other way, for instance ranging over it, and so depends
+// on every data file. Data reached through variables bound to .Site is not
+// seen.
+func dataRefs(code string) (names []string, all bool, err error) {
+	tmpl, err := template.New("layout").Parse(code)
+	if err != nil {
+		return nil, false, err
+	}
+
+	seen := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		walkDataRefs(t.Tree.Root, seen, &all)
+	}
+
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, all, nil
+}
+
+func walkDataRefs(node parse.Node, seen map[string]bool, all *bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkDataRefs(c, seen, all)
+		}
+	case *parse.ActionNode:
+		walkDataRefs(n.Pipe, seen, all)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkDataRefs(cmd, seen, all)
+		}
+	case *parse.CommandNode:
+		for i, arg := range n.Args {
+			if !isSiteData(arg) {
+				walkDataRefs(arg, seen, all)
+				continue
+			}
+			// .Site.Data alone is fine as the first argument of index.
+			if i == 1 && len(n.Args) > 2 && isIdent(n.Args[0], "index") {
+				if s, ok := n.Args[2].(*parse.StringNode); ok {
+					seen[s.Text] = true
+					continue
+				}
+			}
+			*all = true
+		}
+	case *parse.FieldNode:
+		addDataRef(n.Ident, seen)
+	case *parse.VariableNode:
+		if len(n.Ident) > 0 && n.Ident[0] == "$" {
+			addDataRef(n.Ident[1:], seen)
+		}
+	case *parse.ChainNode:
+		walkDataRefs(n.Node, seen, all)
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, seen, all)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, seen, all)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, seen, all)
+	case *parse.TemplateNode:
+		walkDataRefs(n.Pipe, seen, all)
+	}
+}
+
+func walkBranch(n *parse.BranchNode, seen map[string]bool, all *bool) {
+	walkDataRefs(n.Pipe, seen, all)
+	walkDataRefs(n.List, seen, all)
+	walkDataRefs(n.ElseList, seen, all)
+}
+
+// addDataRef records the data file of a field chain such as Site.Data.team.
+func addDataRef(ident []string, seen map[string]bool) {
+	if len(ident) > 2 && ident[0] == "Site" && ident[1] == "Data" {
+		seen[ident[2]] = true
+	}
+}
+
+// isSiteData reports whether the node is .Site.Data or $.Site.Data itself.
+func isSiteData(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		return len(n.Ident) == 2 && n.Ident[0] == "Site" && n.Ident[1] == "Data"
+	case *parse.VariableNode:
+		return len(n.Ident) == 3 && n.Ident[0] == "$" && n.Ident[1] == "Site" && n.Ident[2] == "Data"
+	}
+	return false
+}
+
+func isIdent(node parse.Node, name string) bool {
+	id, ok := node.(*parse.IdentifierNode)
+	return ok && id.Ident == name
+}
+
+// siteData returns the data of every file by name, as layouts get it.
+func siteData(files []DataFile) (map[string]any, error) {
+	data := make(map[string]any, len(files))
+	for _, f := range files {
+		d, err := f.Data()
+		if err != nil {
+			return nil, fmt.Errorf("invalid data file %s: %w", f.Name, err)
+		}
+		data[f.Name] = d
+	}
+	return data, nil
+}
+
+// usedData returns the hash of each data file the layouts use, so that a
+// build records what it depends on, along with warnings for the data files
+// they refer to that do not exist.
+func usedData(layouts []Layout, files []DataFile) (hashes map[string]string, warnings []string) {
+	byName := make(map[string]DataFile, len(files))
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+
+	hashes = make(map[string]string)
+	deps := dataDeps(layouts, files)
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f, ok := byName[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("missing data file %s used by %s", name, strings.Join(deps[name], ", ")))
+			continue
+		}
+		hashes[name] = f.Hash()
+	}
+	return hashes, warnings
+}
+
+// dataDeps maps the name of each data file to the names of the layouts that
+// use it. Layouts that use every data file are listed under each of them.
+// Layouts that do not parse are skipped, they fail the build anyway.
+func dataDeps(layouts []Layout, files []DataFile) map[string][]string {
+	deps := make(map[string][]string)
+	for _, l := range layouts {
+		names, all, err := dataRefs(l.Code)
+		if err != nil {
+			continue
+		}
+		if all {
+			names = names[:0]
+			for _, f := range files {
+				names = append(names, f.Name)
+			}
+		}
+		for _, name := range names {
+			deps[name] = append(deps[name], l.Name)
+		}
+	}
+	return deps
+}
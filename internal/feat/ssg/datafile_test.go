@@ -0,0 +1,125 @@
+package ssg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDataFileData(t *testing.T) {
+	schema := []FieldDef{
+		{Name: "name", Type: FieldText, Required: true},
+		{Name: "price", Type: FieldNumber},
+		{Name: "active", Type: FieldBoolean},
+		{Name: "since", Type: FieldDate},
+	}
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format  string
+		content string
+	}{
+		{DataJSON, `[{"name": "Basic", "price": 5, "active": true, "since": "2024-03-01"}]`},
+		{DataYAML, "- name: Basic\n  price: 5\n  active: true\n  since: \"2024-03-01\"\n"},
+		{DataCSV, "name,price,active,since\nBasic,5,true,2024-03-01\n"},
+		{DataTOML, "[[plans]]\nname = \"Basic\"\nprice = 5\nactive = true\nsince = 2024-03-01\n"},
+	}
+
+	for _, tt := range tests {
+		file := NewDataFile("plans", tt.format, tt.content)
+		file.Schema = schema
+		if tt.format == DataTOML {
+			// TOML has no top-level arrays, the list lives under a key.
+			file.Schema = nil
+		}
+
+		data, err := file.Data()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if tt.format == DataTOML {
+			data, err = applySchema(data.(map[string]any)["plans"], schema, false)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.format, err)
+			}
+		}
+
+		list, ok := data.([]any)
+		if !ok || len(list) != 1 {
+			t.Fatalf("%s: data = %#v", tt.format, data)
+		}
+		rec := list[0].(map[string]any)
+		if rec["name"] != "Basic" || rec["active"] != true || rec["since"] != since {
+			t.Errorf("%s: record = %#v", tt.format, rec)
+		}
+		switch n := rec["price"].(type) {
+		case int, int64, float64:
+		default:
+			t.Errorf("%s: price = %#v", tt.format, n)
+		}
+	}
+}
+
+func TestDataFileSchemaErrors(t *testing.T) {
+	schema := []FieldDef{{Name: "name", Type: FieldText, Required: true}, {Name: "price", Type: FieldNumber}}
+
+	for _, content := range []string{
+		`[{"price": 5}]`,
+		`[{"name": "Basic", "price": "five"}]`,
+		`["Basic"]`,
+		`"Basic"`,
+	} {
+		file := NewDataFile("plans", DataJSON, content)
+		file.Schema = schema
+		if _, err := file.Data(); err == nil {
+			t.Errorf("expected an error for %s", content)
+		}
+	}
+
+	// Only CSV cells, which are always text, are converted.
+	file := NewDataFile("plans", DataJSON, `{"name": "Basic", "price": "5"}`)
+	file.Schema = schema
+	if _, err := file.Data(); err == nil {
+		t.Error("expected an error for a JSON number written as text")
+	}
+}
+
+func TestDataRefs(t *testing.T) {
+	code := `{{ define "layout" }}
+{{ range .Site.Data.team }}{{ .name }}{{ end }}
+{{ with index .Site.Data "pricing" }}{{ .basic }}{{ end }}
+{{ range $.Site.Data.links }}{{ end }}
+{{ if .Content.Heading }}{{ .Site.Data.team }}{{ end }}
+{{ end }}`
+
+	names, all, err := dataRefs(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"links", "pricing", "team"}; !reflect.DeepEqual(names, want) || all {
+		t.Errorf("dataRefs = %v, %v, want %v, false", names, all, want)
+	}
+
+	_, all, err = dataRefs(`{{ range $k, $v := .Site.Data }}{{ $k }}{{ end }}`)
+	if err != nil || !all {
+		t.Errorf("ranging over .Site.Data should use all data files, got %v, %v", all, err)
+	}
+}
+
+func TestUsedData(t *testing.T) {
+	team := NewDataFile("team", DataJSON, `[]`)
+	layouts := []Layout{
+		Newlayout("Page", "", `{{ define "layout" }}{{ .Site.Data.team }}{{ .Site.Data.prices }}{{ end }}`, uuid.Nil),
+		Newlayout("Plain", "", `{{ define "layout" }}{{ .HTML }}{{ end }}`, uuid.Nil),
+	}
+
+	hashes, warnings := usedData(layouts, []DataFile{team, NewDataFile("unused", DataJSON, `{}`)})
+	if want := map[string]string{"team": team.Hash()}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("hashes = %v, want %v", hashes, want)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want one for the missing prices data", warnings)
+	}
+}
@@ -0,0 +1,23 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataFileDA struct {
+	ID        uuid.UUID  `db:"id"`
+	ShortID   string     `db:"short_id"`
+	SiteID    string     `db:"site_id"`
+	Name      string     `db:"name"`
+	Format    string     `db:"format"`
+	Content   string     `db:"content"`
+	Schema    string     `db:"schema"`
+	Source    string     `db:"source"`
+	Path      string     `db:"path"`
+	CreatedBy *string    `db:"created_by"`
+	UpdatedBy *string    `db:"updated_by"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,87 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// DataFileForm edits a data file. The schema is written as the fields of a
+// content type, one per line.
+type DataFileForm struct {
+	*am.BaseForm
+	ID      string `form:"id"`
+	Name    string `form:"name" required:"true"`
+	Format  string `form:"format" required:"true"`
+	Content string `form:"content"`
+	Schema  string `form:"schema"`
+}
+
+func NewDataFileForm(r *http.Request) DataFileForm {
+	return DataFileForm{
+		BaseForm: am.NewBaseForm(r),
+		Format:   DataJSON,
+	}
+}
+
+func DataFileFormFromRequest(r *http.Request) (df DataFileForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return df, err
+	}
+
+	return DataFileForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       r.Form.Get("id"),
+		Name:     strings.TrimSpace(r.Form.Get("name")),
+		Format:   r.Form.Get("format"),
+		Content:  strings.ReplaceAll(r.Form.Get("content"), "\r\n", "\n"),
+		Schema:   strings.TrimSpace(r.Form.Get("schema")),
+	}, nil
+}
+
+func (form *DataFileForm) Validate() error {
+	validate := am.ComposeValidators(
+		validDataName("name", form.Name),
+		validOption("format", form.Format, dataFormats),
+		validFieldDefs("schema", form.Schema),
+		validData("content", form.Format, form.Content, form.Schema),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+func validDataName(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if !fieldNameRe.MatchString(val) {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be lowercase letters, digits and underscores, starting with a letter", field))
+		}
+		return v, nil
+	}
+}
+
+// validData checks that the content parses in its format and matches the
+// schema. Format and schema errors are reported on their own fields.
+func validData(field, format, content, schema string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		defs, err := parseFieldDefs(schema)
+		if err != nil || !contains(dataFormats, format) {
+			return v, nil
+		}
+
+		file := DataFile{Format: format, Content: content, Schema: defs}
+		_, err = file.Data()
+		if err != nil {
+			v.AddFieldError(field, "", fmt.Sprintf("%s: %s", field, err))
+		}
+		return v, nil
+	}
+}
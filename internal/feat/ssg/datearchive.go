@@ -0,0 +1,125 @@
+package ssg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defArchivePageSize = 10
+)
+
+// YearArchive is a year of the date archive of a section, as layouts see it
+// in .Dates.
+type YearArchive struct {
+	Year   int
+	Count  int // Published pages in the year
+	URL    string
+	Months []MonthArchive // Newest first, months without pages are left out
+}
+
+// MonthArchive is a month of the date archive of a section.
+type MonthArchive struct {
+	Year  int
+	Month time.Month // Renders as the English month name, {{ printf "%02d" .Month }} gives the number
+	Count int        // Published pages in the month
+	URL   string
+}
+
+// dateArchive summarizes entries by year and month, newest first. entries
+// must be sorted newest first and base is the URL of their section.
+func dateArchive(entries []ListEntry, base string) []YearArchive {
+	var years []YearArchive
+	for _, e := range entries {
+		t := e.Content.PublishedAt.UTC()
+		if len(years) == 0 || years[len(years)-1].Year != t.Year() {
+			years = append(years, YearArchive{Year: t.Year(), URL: YearURL(base, t.Year())})
+		}
+		y := &years[len(years)-1]
+		y.Count++
+
+		if len(y.Months) == 0 || y.Months[len(y.Months)-1].Month != t.Month() {
+			y.Months = append(y.Months, MonthArchive{Year: t.Year(), Month: t.Month(), URL: MonthURL(base, t.Year(), t.Month())})
+		}
+		y.Months[len(y.Months)-1].Count++
+	}
+	return years
+}
+
+// entriesIn returns the entries published in a year, or in a month of it if
+// month is not zero.
+func entriesIn(entries []ListEntry, year int, month time.Month) []ListEntry {
+	var result []ListEntry
+	for _, e := range entries {
+		t := e.Content.PublishedAt.UTC()
+		if t.Year() == year && (month == 0 || t.Month() == month) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// YearURL returns the URL of the archive of a year of the section at base.
+func YearURL(base string, year int) string {
+	return fmt.Sprintf("%s%04d/", base, year)
+}
+
+// MonthURL returns the URL of the archive of a month of the section at base.
+func MonthURL(base string, year int, month time.Month) string {
+	return fmt.Sprintf("%s%04d/%02d/", base, year, month)
+}
+
+// archiveTitle returns the title of a page of a date archive, prefixed with the
+// name of its section, if any, and followed by the page number after the first.
+func archiveTitle(section, title string, page int) string {
+	if section != "" {
+		title = section + ", " + title
+	}
+	if page > 1 {
+		title = fmt.Sprintf("%s, page %d", title, page)
+	}
+	return title
+}
+
+// listingPage is a page of a paginated listing and the URL it is served at.
+type listingPage struct {
+	url     string
+	listing Listing
+}
+
+// paginate splits a listing into pages of size entries. The first page is
+// served at url and the next ones at url/page/2/ and so on. A listing without
+// entries still gets its first page.
+func paginate(l Listing, url string, size int) []listingPage {
+	if size <= 0 {
+		size = defArchivePageSize
+	}
+
+	total := (len(l.Entries) + size - 1) / size
+	if total == 0 {
+		total = 1
+	}
+
+	pages := make([]listingPage, total)
+	for i := range pages {
+		p := l
+		p.Page, p.Pages = i+1, total
+		p.Entries = l.Entries[min(i*size, len(l.Entries)):min((i+1)*size, len(l.Entries))]
+		if i > 0 {
+			p.PrevURL = listingPageURL(url, i)
+		}
+		if i < total-1 {
+			p.NextURL = listingPageURL(url, i+2)
+		}
+		pages[i] = listingPage{url: listingPageURL(url, i+1), listing: p}
+	}
+	return pages
+}
+
+// listingPageURL returns the URL of page n of the listing at url.
+func listingPageURL(url string, n int) string {
+	if n <= 1 {
+		return url
+	}
+	return fmt.Sprintf("%spage/%d/", url, n)
+}
@@ -0,0 +1,111 @@
+package ssg
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func datedEntries(dates ...string) []ListEntry {
+	entries := make([]ListEntry, len(dates))
+	for i, d := range dates {
+		c := NewContent(d, "")
+		c.PublishedAt, _ = time.Parse(time.DateOnly, d)
+		entries[i] = ListEntry{Content: c, URL: "/blog/" + d + "/"}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+func TestDateArchive(t *testing.T) {
+	entries := datedEntries("2025-01-10", "2026-10-02", "2026-03-15", "2026-10-20", "2025-01-01")
+
+	years := dateArchive(entries, "/blog/")
+	if len(years) != 2 {
+		t.Fatalf("got %d years: %+v", len(years), years)
+	}
+
+	y := years[0]
+	if y.Year != 2026 || y.Count != 3 || y.URL != "/blog/2026/" || len(y.Months) != 2 {
+		t.Errorf("years[0] = %+v", y)
+	}
+	if m := y.Months[0]; m.Month != time.October || m.Count != 2 || m.URL != "/blog/2026/10/" {
+		t.Errorf("2026 months[0] = %+v", m)
+	}
+	if m := y.Months[1]; m.Month != time.March || m.Count != 1 || m.URL != "/blog/2026/03/" {
+		t.Errorf("2026 months[1] = %+v", m)
+	}
+
+	if y := years[1]; y.Year != 2025 || y.Count != 2 || len(y.Months) != 1 || y.Months[0].Count != 2 {
+		t.Errorf("years[1] = %+v", y)
+	}
+
+	if got := entriesIn(entries, 2026, 0); len(got) != 3 {
+		t.Errorf("entries in 2026 = %d", len(got))
+	}
+	if got := entriesIn(entries, 2026, time.October); len(got) != 2 || got[0].Content.Heading != "2026-10-20" {
+		t.Errorf("entries in October 2026 = %+v", got)
+	}
+}
+
+func TestDateArchiveRoot(t *testing.T) {
+	years := dateArchive(datedEntries("2026-10-02"), "/es/")
+	if years[0].URL != "/es/2026/" || years[0].Months[0].URL != "/es/2026/10/" {
+		t.Errorf("years = %+v", years)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	var dates []string
+	for i := 1; i <= 7; i++ {
+		dates = append(dates, fmt.Sprintf("2026-10-%02d", i))
+	}
+	listing := Listing{Title: "October 2026", Entries: datedEntries(dates...)}
+
+	pages := paginate(listing, "/blog/2026/10/", 3)
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages", len(pages))
+	}
+
+	want := []struct {
+		url, prev, next string
+		entries         int
+	}{
+		{"/blog/2026/10/", "", "/blog/2026/10/page/2/", 3},
+		{"/blog/2026/10/page/2/", "/blog/2026/10/", "/blog/2026/10/page/3/", 3},
+		{"/blog/2026/10/page/3/", "/blog/2026/10/page/2/", "", 1},
+	}
+	for i, w := range want {
+		p := pages[i]
+		if p.url != w.url || p.listing.PrevURL != w.prev || p.listing.NextURL != w.next || len(p.listing.Entries) != w.entries {
+			t.Errorf("pages[%d] = %s %+v", i, p.url, p.listing)
+		}
+		if p.listing.Page != i+1 || p.listing.Pages != 3 || p.listing.Title != "October 2026" {
+			t.Errorf("pages[%d] numbering = %d of %d", i, p.listing.Page, p.listing.Pages)
+		}
+	}
+	if pages[0].listing.Entries[0].Content.Heading != "2026-10-07" || pages[2].listing.Entries[0].Content.Heading != "2026-10-01" {
+		t.Error("entries not split newest first")
+	}
+
+	if pages := paginate(Listing{}, "/blog/2026/", 3); len(pages) != 1 || pages[0].listing.Pages != 1 {
+		t.Errorf("empty listing pages = %+v", pages)
+	}
+}
+
+func TestArchiveTitle(t *testing.T) {
+	tests := []struct {
+		section, title string
+		page           int
+		want           string
+	}{
+		{"Blog", "2026", 1, "Blog, 2026"},
+		{"Blog", "October 2026", 2, "Blog, October 2026, page 2"},
+		{"", "2026", 3, "2026, page 3"},
+	}
+	for _, tt := range tests {
+		if got := archiveTitle(tt.section, tt.title, tt.page); got != tt.want {
+			t.Errorf("archiveTitle(%q, %q, %d) = %q, want %q", tt.section, tt.title, tt.page, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ssg
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	deploymentType = "deployment"
+)
+
+const (
+	DeploymentRunning   = "running"
+	DeploymentSucceeded = "succeeded"
+	DeploymentFailed    = "failed"
+)
+
+// Deployment records the publishing of a build to a target. The target name
+// and environment are kept as they were at the time.
+type Deployment struct {
+	*am.BaseModel
+	TargetID     uuid.UUID `json:"target_id"`
+	TargetName   string    `json:"target_name"`
+	Environment  string    `json:"environment"`
+	BuildID      uuid.UUID `json:"build_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	PromotedFrom uuid.UUID `json:"promoted_from"` // Staging deployment a promotion was made from
+	Status       string    `json:"status"`
+	Changes      int       `json:"changes"`
+	Ref          string    `json:"ref"`
+	Error        string    `json:"error"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+func NewDeployment(target PublishTarget, buildID, userID uuid.UUID) Deployment {
+	return Deployment{
+		BaseModel:   am.NewModel(am.WithType(deploymentType)),
+		TargetID:    target.ID(),
+		TargetName:  target.Name,
+		Environment: target.Environment,
+		BuildID:     buildID,
+		UserID:      userID,
+		Status:      DeploymentRunning,
+		StartedAt:   am.Now(),
+	}
+}
+
+func (d Deployment) IsZero() bool {
+	return d.BaseModel == nil || d.BaseModel.IsZero()
+}
+
+func (d Deployment) IsPromotion() bool {
+	return d.PromotedFrom != uuid.Nil
+}
+
+// Finish records the result of the publish.
+func (d *Deployment) Finish(result PublishResult, err error) {
+	d.FinishedAt = am.Now()
+	d.Changes = len(result.Changes)
+	d.Ref = result.Ref
+	d.Status = DeploymentSucceeded
+	if err != nil {
+		d.Status = DeploymentFailed
+		d.Error = err.Error()
+	}
+}
+
+func (d *Deployment) Slug() string {
+	return d.StartedAt.Format("20060102-150405") + "-" + d.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (d *Deployment) UnmarshalJSON(data []byte) error {
+	type Alias Deployment
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*d = Deployment(*temp)
+	if d.BaseModel == nil {
+		d.BaseModel = am.NewModel(am.WithType(deploymentType))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeploymentDA struct {
+	ID           uuid.UUID  `db:"id"`
+	ShortID      string     `db:"short_id"`
+	SiteID       string     `db:"site_id"`
+	TargetID     string     `db:"target_id"`
+	TargetName   string     `db:"target_name"`
+	Environment  string     `db:"environment"`
+	BuildID      string     `db:"build_id"`
+	UserID       string     `db:"user_id"`
+	PromotedFrom string     `db:"promoted_from"`
+	Status       string     `db:"status"`
+	Changes      int        `db:"changes"`
+	Ref          string     `db:"ref"`
+	Error        string     `db:"error"`
+	StartedAt    *time.Time `db:"started_at"`
+	FinishedAt   *time.Time `db:"finished_at"`
+	CreatedBy    *string    `db:"created_by"`
+	UpdatedBy    *string    `db:"updated_by"`
+	CreatedAt    *time.Time `db:"created_at"`
+	UpdatedAt    *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,316 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defWebhookAttempts = 5
+	webhookBackoff     = time.Second
+	webhookTimeout     = 10 * time.Second
+	webhookUserAgent   = "Hermes-Webhook/1"
+
+	// Receivers verify a payload by computing the HMAC-SHA256 of the request
+	// body with the webhook secret and comparing it with the signature header.
+	SignatureHeader = "X-Hermes-Signature"
+	EventHeader     = "X-Hermes-Event"
+	DeliveryHeader  = "X-Hermes-Delivery"
+)
+
+// Event is something that happened webhooks can subscribe to.
+type Event struct {
+	ID         uuid.UUID      `json:"id"`
+	Type       string         `json:"event"`
+	Site       string         `json:"site,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data"`
+}
+
+func NewEvent(eventType string, data map[string]any) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: am.Now(),
+		Data:       data,
+	}
+}
+
+// Dispatcher posts events to the webhooks subscribed to them. Deliveries run
+// in the background and are retried with exponential backoff while the
+// receiver is unreachable or answers with a server error. Every attempt is
+// recorded in the delivery log, along with when the next one is due, so that
+// retries pending when the process stops are resumed when it starts again.
+// Deliveries are concurrent, receivers should order events by occurred_at
+// rather than by arrival.
+type Dispatcher struct {
+	am.Core
+	repo    Repo
+	client  HTTPClient
+	backoff time.Duration
+	wg      sync.WaitGroup
+}
+
+func NewDispatcher(repo Repo, opts ...am.Option) *Dispatcher {
+	return &Dispatcher{
+		Core:    am.NewCore("ssg-dispatcher", opts...),
+		repo:    repo,
+		client:  &http.Client{Timeout: webhookTimeout},
+		backoff: webhookBackoff,
+	}
+}
+
+// SetClient sets the client used to post events.
+func (d *Dispatcher) SetClient(client HTTPClient) {
+	d.client = client
+}
+
+// Dispatch queues the delivery of an event of the site of the context to
+// every active webhook of the site subscribed to it. A nil dispatcher discards
+// events.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) {
+	if d == nil {
+		return
+	}
+
+	site := SiteFrom(ctx)
+	event.Site = site.SlugValue
+	// Deliveries outlive the request the event was raised in.
+	ctx = WithSite(context.Background(), site)
+
+	hooks, err := d.repo.GetWebhooks(ctx)
+	if err != nil {
+		d.Log().Errorf("Cannot get webhooks for %s: %v", event.Type, err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if hook.Subscribes(event.Type) {
+			d.send(ctx, hook, event)
+		}
+	}
+}
+
+// Redeliver queues the delivery of an event to a single webhook of the site of
+// the context whether it is subscribed to it or not.
+func (d *Dispatcher) Redeliver(ctx context.Context, hook Webhook, event Event) {
+	if d == nil {
+		return
+	}
+	d.send(WithSite(context.Background(), SiteFrom(ctx)), hook, event)
+}
+
+func (d *Dispatcher) send(ctx context.Context, hook Webhook, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.Log().Errorf("Cannot encode %s event: %v", event.Type, err)
+		return
+	}
+
+	secret, err := d.secret(hook)
+	if err != nil {
+		d.Log().Errorf("Cannot decrypt secret of webhook %s: %v", hook.Name, err)
+		return
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.deliver(ctx, hook, secret, event, payload, WebhookDelivery{})
+	}()
+}
+
+// Start resumes the retries left pending when the process last stopped, those
+// of every site.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	pending, err := d.repo.GetPendingWebhookDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get pending webhook deliveries: %w", err)
+	}
+
+	sites, err := d.repo.GetSites(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get sites: %w", err)
+	}
+
+	hooks := make(map[uuid.UUID]siteWebhook)
+	for _, site := range sites {
+		siteCtx := WithSite(context.Background(), site)
+		siteHooks, err := d.repo.GetWebhooks(siteCtx)
+		if err != nil {
+			return fmt.Errorf("cannot get webhooks of %s: %w", site.SlugValue, err)
+		}
+		for _, hook := range siteHooks {
+			hooks[hook.ID()] = siteWebhook{ctx: siteCtx, hook: hook}
+		}
+	}
+
+	for _, prev := range pending {
+		d.resume(hooks, prev)
+	}
+	return nil
+}
+
+// siteWebhook is a webhook along with a context scoped to its site.
+type siteWebhook struct {
+	ctx  context.Context
+	hook Webhook
+}
+
+// resume schedules the next attempt of a pending delivery. Deliveries to
+// webhooks since deleted or deactivated are given up.
+func (d *Dispatcher) resume(hooks map[uuid.UUID]siteWebhook, prev WebhookDelivery) {
+	ctx, hook := context.Background(), Webhook{}
+	if h, ok := hooks[prev.WebhookID]; ok {
+		ctx, hook = h.ctx, h.hook
+	}
+	if hook.IsZero() || !hook.Active {
+		d.Log().Infof("Giving up delivering %s %s to inactive webhook %s", prev.Event, prev.EventID, prev.WebhookName)
+		d.clearRetry(ctx, prev)
+		return
+	}
+
+	var event Event
+	err := json.Unmarshal([]byte(prev.Payload), &event)
+	if err != nil {
+		d.Log().Errorf("Cannot decode pending delivery %s: %v", prev.ID(), err)
+		d.clearRetry(ctx, prev)
+		return
+	}
+
+	secret, err := d.secret(hook)
+	if err != nil {
+		d.Log().Errorf("Cannot decrypt secret of webhook %s: %v", hook.Name, err)
+		return
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		time.Sleep(time.Until(prev.RetryAt))
+		d.deliver(ctx, hook, secret, event, []byte(prev.Payload), prev)
+	}()
+}
+
+// Stop waits for pending deliveries, including their retries, to finish.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver posts the payload until the receiver accepts it or attempts run
+// out, going on from prev, the last attempt made if any. Each attempt is
+// recorded before the previous one stops being pending, so a retry is never
+// lost, at worst made twice.
+func (d *Dispatcher) deliver(ctx context.Context, hook Webhook, secret []byte, event Event, payload []byte, prev WebhookDelivery) {
+	attempts := int(d.Cfg().IntVal(key.SSGWebhookAttempts, defWebhookAttempts))
+
+	for attempt := prev.Attempt + 1; attempt <= attempts; attempt++ {
+		delivery := d.post(ctx, hook, secret, event, payload, attempt)
+		if !delivery.Succeeded() && retryable(delivery) && attempt < attempts {
+			delivery.RetryAt = am.Now().Add(d.backoff << (attempt - 1))
+		}
+
+		err := d.repo.CreateWebhookDelivery(ctx, delivery)
+		if err != nil {
+			d.Log().Errorf("Cannot record delivery of %s to %s: %v", event.Type, hook.Name, err)
+		}
+		if prev.Pending() {
+			d.clearRetry(ctx, prev)
+		}
+
+		if !delivery.Pending() {
+			if !delivery.Succeeded() && retryable(delivery) {
+				d.Log().Errorf("Giving up delivering %s %s to %s after %d attempts", event.Type, event.ID, hook.Name, attempts)
+			}
+			return
+		}
+
+		time.Sleep(time.Until(delivery.RetryAt))
+		prev = delivery
+	}
+}
+
+func (d *Dispatcher) clearRetry(ctx context.Context, delivery WebhookDelivery) {
+	err := d.repo.ClearWebhookDeliveryRetry(ctx, delivery.ID())
+	if err != nil {
+		d.Log().Errorf("Cannot clear retry of delivery %s: %v", delivery.ID(), err)
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, hook Webhook, secret []byte, event Event, payload []byte, attempt int) WebhookDelivery {
+	delivery := NewWebhookDelivery(hook, event, attempt)
+	delivery.Payload = string(payload)
+	delivery.GenCreateValues()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID.String())
+	req.Header.Set(SignatureHeader, signPayload(secret, payload))
+
+	start := time.Now()
+	res, err := d.client.Do(req)
+	delivery.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	delivery.StatusCode = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		delivery.Error = res.Status
+	}
+	return delivery
+}
+
+func (d *Dispatcher) secret(hook Webhook) ([]byte, error) {
+	if len(hook.SecretEnc) == 0 {
+		return nil, nil
+	}
+	return unseal(d.Cfg(), hook.SecretEnc)
+}
+
+// retryable reports whether a failed delivery may succeed later. Client errors
+// other than rate limiting are not retried.
+func retryable(delivery WebhookDelivery) bool {
+	return delivery.StatusCode == 0 ||
+		delivery.StatusCode == http.StatusTooManyRequests ||
+		delivery.StatusCode >= 500
+}
+
+// signPayload returns the signature header value for a payload.
+func signPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
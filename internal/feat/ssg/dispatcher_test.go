@@ -0,0 +1,241 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// deliveryRepo keeps webhooks, by site slug, and deliveries in memory along
+// with the site each delivery was recorded on.
+type deliveryRepo struct {
+	Repo
+	sites      []Site
+	hooks      map[string][]Webhook
+	mu         sync.Mutex
+	deliveries []WebhookDelivery
+	recordedOn []string
+}
+
+func (r *deliveryRepo) GetSites(ctx context.Context) ([]Site, error) {
+	return append([]Site{DefaultSite()}, r.sites...), nil
+}
+
+func (r *deliveryRepo) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	return r.hooks[SiteFrom(ctx).SlugValue], nil
+}
+
+func (r *deliveryRepo) CreateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	r.recordedOn = append(r.recordedOn, SiteFrom(ctx).SlugValue)
+	return nil
+}
+
+func (r *deliveryRepo) GetPendingWebhookDeliveries(ctx context.Context) ([]WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pending []WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Pending() {
+			pending = append(pending, d)
+		}
+	}
+	return pending, nil
+}
+
+func (r *deliveryRepo) ClearWebhookDeliveryRetry(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, d := range r.deliveries {
+		if d.ID() == id {
+			r.deliveries[i].RetryAt = time.Time{}
+		}
+	}
+	return nil
+}
+
+func TestDispatcherRetriesAndSigns(t *testing.T) {
+	secret := []byte("s3cr3t")
+	var calls int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), signPayload(secret, body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if r.Header.Get(EventHeader) != EventBuildFailed {
+			t.Errorf("event header = %q", r.Header.Get(EventHeader))
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{am.Key.SecEncryptionKey: "0123456789abcdef0123456789abcdef"})
+	secretEnc, err := seal(cfg, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := NewWebhook("ci", srv.URL, EventBuildFailed)
+	hook.GenCreateValues()
+	hook.SecretEnc = secretEnc
+	ignored := NewWebhook("chat", srv.URL, EventContentPublished)
+	ignored.GenCreateValues()
+
+	repo := &deliveryRepo{hooks: map[string][]Webhook{DefaultSiteSlug: {hook, ignored}}}
+	d := NewDispatcher(repo, am.WithCfg(cfg), am.WithLog(am.NewLogger("error")))
+	d.backoff = time.Millisecond
+
+	d.Dispatch(context.Background(), NewEvent(EventBuildFailed, map[string]any{"id": "b1"}))
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("deliveries = %d, want 3", len(repo.deliveries))
+	}
+	for i, delivery := range repo.deliveries {
+		if delivery.Attempt != i+1 || delivery.WebhookID != hook.ID() {
+			t.Errorf("delivery %d = attempt %d to %s", i, delivery.Attempt, delivery.WebhookName)
+		}
+		if delivery.Pending() {
+			t.Errorf("delivery %d still pending after the retries finished", i)
+		}
+	}
+	if last := repo.deliveries[2]; !last.Succeeded() {
+		t.Errorf("last delivery failed: %d %s", last.StatusCode, last.Error)
+	}
+}
+
+func TestDispatcherResumesPendingDeliveries(t *testing.T) {
+	var attempts []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, r.Header.Get(DeliveryHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hook := NewWebhook("ci", srv.URL, EventBuildFailed)
+	hook.GenCreateValues()
+	gone := NewWebhook("gone", srv.URL, EventBuildFailed)
+	gone.GenCreateValues()
+
+	event := NewEvent(EventBuildFailed, map[string]any{"id": "b1"})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := func(h Webhook) WebhookDelivery {
+		d := NewWebhookDelivery(h, event, 2)
+		d.GenCreateValues()
+		d.Payload, d.StatusCode, d.Error = string(payload), http.StatusBadGateway, "502 Bad Gateway"
+		d.RetryAt = am.Now()
+		return d
+	}
+
+	// Left pending by a previous run on another site, the second webhook was
+	// deleted since.
+	docs := NewSite("Docs", "")
+	docs.GenCreateValues()
+	repo := &deliveryRepo{
+		sites:      []Site{docs},
+		hooks:      map[string][]Webhook{docs.SlugValue: {hook}},
+		deliveries: []WebhookDelivery{pending(hook), pending(gone)},
+		recordedOn: []string{docs.SlugValue, docs.SlugValue},
+	}
+	d := NewDispatcher(repo, am.WithCfg(am.NewConfig()), am.WithLog(am.NewLogger("error")))
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(attempts) != 1 || attempts[0] != event.ID.String() {
+		t.Fatalf("attempts = %v, want one for %s", attempts, event.ID)
+	}
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("deliveries = %d, want 3", len(repo.deliveries))
+	}
+	if last := repo.deliveries[2]; last.Attempt != 3 || !last.Succeeded() {
+		t.Errorf("resumed delivery = attempt %d, succeeded %t", last.Attempt, last.Succeeded())
+	}
+	if repo.recordedOn[2] != docs.SlugValue {
+		t.Errorf("resumed delivery recorded on %q, want %q", repo.recordedOn[2], docs.SlugValue)
+	}
+	for i, delivery := range repo.deliveries {
+		if delivery.Pending() {
+			t.Errorf("delivery %d still pending", i)
+		}
+	}
+}
+
+func TestDispatcherOnlyUsesWebhooksOfTheSite(t *testing.T) {
+	var sites []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error(err)
+		}
+		sites = append(sites, event.Site)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	docs := NewSite("Docs", "")
+	docs.GenCreateValues()
+	hooks := map[string][]Webhook{}
+	for _, slug := range []string{DefaultSiteSlug, docs.SlugValue} {
+		hook := NewWebhook(slug, srv.URL, EventBuildFailed)
+		hook.GenCreateValues()
+		hooks[slug] = []Webhook{hook}
+	}
+
+	repo := &deliveryRepo{sites: []Site{docs}, hooks: hooks}
+	d := NewDispatcher(repo, am.WithCfg(am.NewConfig()), am.WithLog(am.NewLogger("error")))
+
+	d.Dispatch(WithSite(context.Background(), docs), NewEvent(EventBuildFailed, map[string]any{"id": "b1"}))
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sites) != 1 || sites[0] != docs.SlugValue {
+		t.Fatalf("events delivered for sites %v, want one for %s", sites, docs.SlugValue)
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].WebhookID != hooks[docs.SlugValue][0].ID() || repo.recordedOn[0] != docs.SlugValue {
+		t.Errorf("deliveries = %d, recorded on %v", len(repo.deliveries), repo.recordedOn)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := map[int]bool{0: true, 429: true, 500: true, 503: true, 400: false, 404: false, 410: false}
+	for code, want := range tests {
+		if got := retryable(WebhookDelivery{StatusCode: code}); got != want {
+			t.Errorf("retryable(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package ssg
+
+import "errors"
+
+var (
+	ErrInvalidPermalink     = errors.New("invalid permalink")
+	ErrDuplicateURL         = errors.New("url already in use")
+	ErrDuplicateRedirect    = errors.New("redirect source already in use")
+	ErrPublishNotConfigured = errors.New("publishing is not configured")
+	ErrInvalidGitTarget     = errors.New("invalid git repository or branch")
+	ErrTargetNotEmpty       = errors.New("target directory has files not published by Hermes")
+	ErrNotProduction        = errors.New("target is not a production target")
+	ErrNotStaged            = errors.New("build has not been published to staging")
+	ErrInvalidAPIToken      = errors.New("invalid API token")
+	ErrIdempotencyKeyExists = errors.New("idempotency key already used")
+	ErrDuplicateSite        = errors.New("site slug already in use")
+	ErrDuplicateContentType = errors.New("content type slug already in use")
+	ErrDuplicateDataFile    = errors.New("data file name already in use")
+	ErrDuplicateMenu        = errors.New("menu name already in use")
+	ErrInvalidMenuOrder     = errors.New("invalid menu order")
+	ErrDuplicateSeries      = errors.New("series slug already in use")
+	ErrContentInSeries      = errors.New("content already in a series")
+	ErrInvalidSeriesOrder   = errors.New("invalid series order")
+	ErrDuplicateProfile     = errors.New("profile slug already in use")
+	ErrUserHasProfile       = errors.New("user already has a profile")
+	ErrContentConflict      = errors.New("content was changed by someone else")
+	ErrNoPreview            = errors.New("content is not published in its language")
+)
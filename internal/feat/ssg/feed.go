@@ -0,0 +1,130 @@
+package ssg
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	feedFile = "feed.xml"
+	feedSize = 20
+	atomNS   = "http://www.w3.org/2005/Atom"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	NS      string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  *atomPerson `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Published string       `xml:"published,omitempty"`
+	Updated   string       `xml:"updated"`
+	Links     []atomLink   `xml:"link"`
+	Authors   []atomPerson `xml:"author"`
+	Summary   *atomText    `xml:"summary,omitempty"`
+	Content   *atomText    `xml:"content,omitempty"`
+}
+
+// renderFeed returns the Atom feed of the newest entries of a listing
+// published at pageURL. author is set for the feed of an author, entries of
+// other feeds credit their own authors.
+func renderFeed(site SiteSettings, l Listing, pageURL string, author *PageAuthor) ([]byte, error) {
+	entries := l.Entries
+	if len(entries) > feedSize {
+		entries = entries[:feedSize]
+	}
+
+	feed := atomFeed{
+		NS:    atomNS,
+		Title: l.Title,
+		ID:    site.AbsURL(pageURL),
+		Links: []atomLink{
+			{Href: site.AbsURL(pageURL), Rel: "alternate", Type: "text/html"},
+			{Href: site.AbsURL(l.FeedURL), Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	if site.Title != "" {
+		feed.Title += " | " + site.Title
+	}
+	if author != nil {
+		feed.Author = &atomPerson{Name: author.Name, URI: site.AbsURL(author.URL)}
+	}
+
+	// The feed changes only when its entries do so that builds stay
+	// reproducible.
+	var updated time.Time
+	for _, e := range entries {
+		entry, err := feedEntry(site, e)
+		if err != nil {
+			return nil, err
+		}
+		if entryTime(e.Content).After(updated) {
+			updated = entryTime(e.Content)
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func feedEntry(site SiteSettings, e ListEntry) (atomEntry, error) {
+	body, err := RenderMarkdown(e.Content.Body)
+	if err != nil {
+		return atomEntry{}, err
+	}
+
+	entry := atomEntry{
+		Title:   e.Content.Heading,
+		ID:      "urn:uuid:" + e.Content.ID().String(),
+		Updated: entryTime(e.Content).UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: site.AbsURL(e.URL), Rel: "alternate", Type: "text/html"}},
+		Content: &atomText{Type: "html", Body: string(body)},
+	}
+	if !e.Content.PublishedAt.IsZero() {
+		entry.Published = e.Content.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	if e.Content.MetaDescription != "" {
+		entry.Summary = &atomText{Type: "text", Body: e.Content.MetaDescription}
+	}
+	for _, a := range e.Authors {
+		entry.Authors = append(entry.Authors, atomPerson{Name: a.Name, URI: site.AbsURL(a.URL)})
+	}
+	return entry, nil
+}
+
+// entryTime returns when a content last changed, as feeds report it.
+func entryTime(c Content) time.Time {
+	if c.UpdatedAt().After(c.PublishedAt) {
+		return c.UpdatedAt()
+	}
+	return c.PublishedAt
+}
@@ -0,0 +1,95 @@
+package ssg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderFeed(t *testing.T) {
+	site := NewSiteSettings("Notes", "en")
+	site.BaseURL = "https://example.com/"
+	author := PageAuthor{Name: "Jane Doe", URL: "/authors/jane-doe/"}
+
+	base := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	var entries []ListEntry
+	for i := 0; i < feedSize+5; i++ {
+		c := NewContent(fmt.Sprintf("Post %d", i), "Some *text* & more")
+		c.GenCreateValues()
+		c.PublishedAt = base.Add(-time.Duration(i) * time.Hour)
+		entries = append(entries, ListEntry{Content: c, URL: fmt.Sprintf("/blog/post-%d/", i), Authors: []PageAuthor{author}})
+	}
+	entries[0].Content.MetaDescription = "First post"
+
+	listing := Listing{Title: "Jane Doe", Entries: entries, FeedURL: "/authors/jane-doe/feed.xml"}
+	out, err := renderFeed(site, listing, "/authors/jane-doe/", &author)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed struct {
+		Title   string `xml:"title"`
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title   string `xml:"title"`
+			Summary string `xml:"summary"`
+			Content string `xml:"content"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Author struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(out, &feed); err != nil {
+		t.Fatalf("invalid feed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), `xmlns="http://www.w3.org/2005/Atom"`) {
+		t.Errorf("missing Atom namespace\n%s", out)
+	}
+
+	if feed.Title != "Jane Doe | Notes" || feed.ID != "https://example.com/authors/jane-doe/" {
+		t.Errorf("Title = %q, ID = %q", feed.Title, feed.ID)
+	}
+	if len(feed.Entries) != feedSize {
+		t.Fatalf("got %d entries, want %d", len(feed.Entries), feedSize)
+	}
+
+	first := feed.Entries[0]
+	if first.Link.Href != "https://example.com/blog/post-0/" || first.Author.Name != "Jane Doe" || first.Summary != "First post" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if !strings.Contains(first.Content, "<em>text</em> &amp; more") {
+		t.Errorf("Content = %q", first.Content)
+	}
+	if updated, err := time.Parse(time.RFC3339, feed.Updated); err != nil || updated.Before(base) {
+		t.Errorf("Updated = %q, want the time of the newest entry", feed.Updated)
+	}
+}
+
+func TestListingHTML(t *testing.T) {
+	site := NewSiteSettings("Notes", "en")
+	site.DateFormat = "2006-01-02"
+
+	older := NewContent("Older", "")
+	older.PublishedAt = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	newer := NewContent("<Newer>", "")
+	newer.PublishedAt = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := []ListEntry{{Content: older, URL: "/older/"}, {Content: newer, URL: "/newer/"}}
+	sortEntries(entries)
+
+	html := string(listingHTML(site, Listing{Title: "Jane", Entries: entries, FeedURL: "/authors/jane/feed.xml"}))
+	newerAt := strings.Index(html, `<a href="/newer/">&lt;Newer&gt;</a> <time datetime="2025-07-01">2025-07-01</time>`)
+	olderAt := strings.Index(html, `<a href="/older/">Older</a>`)
+	if newerAt < 0 || olderAt < 0 || newerAt > olderAt {
+		t.Errorf("unexpected listing\n%s", html)
+	}
+	if !strings.Contains(html, `href="/authors/jane/feed.xml"`) {
+		t.Errorf("missing feed link\n%s", html)
+	}
+}
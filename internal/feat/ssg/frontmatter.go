@@ -0,0 +1,213 @@
+package ssg
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatter holds the metadata block at the top of a Hugo or Jekyll
+// content file.
+type frontMatter map[string]any
+
+var fmDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// splitFrontMatter separates the front matter of a content file from its
+// body. YAML front matter is delimited by "---" lines and TOML front matter
+// by "+++" lines. Files without front matter return a nil map.
+func splitFrontMatter(src []byte) (frontMatter, string, error) {
+	src = bytes.TrimPrefix(src, []byte("\ufeff"))
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+
+	var delim string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delim = "+++"
+	default:
+		return nil, text, nil
+	}
+
+	rest := text[len(delim)+1:]
+	var head, body string
+	if strings.HasPrefix(rest, delim+"\n") || rest == delim {
+		body = strings.TrimPrefix(strings.TrimPrefix(rest, delim), "\n")
+	} else {
+		end := strings.Index(rest, "\n"+delim+"\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n"+delim) {
+				return nil, text, fmt.Errorf("unterminated front matter")
+			}
+			end = len(rest) - len(delim) - 1
+			head = rest[:end]
+		} else {
+			head = rest[:end]
+			body = rest[end+len(delim)+2:]
+		}
+	}
+
+	fm := frontMatter{}
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal([]byte(head), &fm)
+	} else {
+		fm, err = parseTOML(head)
+	}
+	if err != nil {
+		return nil, text, fmt.Errorf("invalid front matter: %w", err)
+	}
+	return fm, body, nil
+}
+
+// String returns the value of the first key set, as text.
+func (fm frontMatter) String(keys ...string) string {
+	for _, k := range keys {
+		switch v := fm[k].(type) {
+		case nil:
+			continue
+		case string:
+			return strings.TrimSpace(v)
+		case time.Time:
+			return v.Format(time.RFC3339)
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// Strings returns the value of the first key set as a list. A single string
+// is split on whitespace, as Jekyll does with categories and tags.
+func (fm frontMatter) Strings(keys ...string) []string {
+	for _, k := range keys {
+		switch v := fm[k].(type) {
+		case nil:
+			continue
+		case string:
+			return strings.Fields(v)
+		case []any:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+					list = append(list, s)
+				}
+			}
+			return list
+		default:
+			return []string{fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
+
+// Bool returns the value of the key as a boolean, or def if it is not set.
+func (fm frontMatter) Bool(key string, def bool) bool {
+	switch v := fm[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Time returns the value of the first key set that holds a date.
+func (fm frontMatter) Time(keys ...string) time.Time {
+	for _, k := range keys {
+		switch v := fm[k].(type) {
+		case time.Time:
+			return v
+		case string:
+			if t := parseDate(v); !t.IsZero() {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Map returns the value of the key as a nested front matter.
+func (fm frontMatter) Map(key string) frontMatter {
+	switch v := fm[key].(type) {
+	case map[string]any:
+		return frontMatter(v)
+	case frontMatter:
+		return v
+	}
+	return nil
+}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range fmDateLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseTOML decodes TOML front matter, site configuration and data files.
+// Values are returned as YAML would return them, so that both formats read
+// the same.
+func parseTOML(src string) (frontMatter, error) {
+	fm := frontMatter{}
+	_, err := toml.Decode(src, (*map[string]any)(&fm))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range fm {
+		fm[k] = tomlValue(v)
+	}
+	return fm, nil
+}
+
+// tomlValue turns integers into ints, arrays of tables into lists and local
+// dates and times into UTC ones.
+func tomlValue(v any) any {
+	switch v := v.(type) {
+	case int64:
+		return int(v)
+	case time.Time:
+		// Values without an offset come in zones named after their kind.
+		if name, _ := v.Zone(); strings.HasSuffix(name, "-local") {
+			return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC)
+		}
+		return v
+	case []map[string]any:
+		list := make([]any, len(v))
+		for i, table := range v {
+			list[i] = tomlValue(table)
+		}
+		return list
+	case []any:
+		for i, item := range v {
+			v[i] = tomlValue(item)
+		}
+		return v
+	case map[string]any:
+		for k, item := range v {
+			v[k] = tomlValue(item)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,954 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defOutputDir       = "output"
+	defReportsDir      = "reports"
+	defStaticDir       = "static"
+	fallbackLayoutPath = "assets/template/layout/layout.tmpl"
+	indexFile          = "index.html"
+	linkReportFile     = "links.json"
+	linkCheckTimeout   = 10 * time.Second
+)
+
+var defineRe = regexp.MustCompile(`{{-?\s*define\s+"([^"]+)"`)
+
+// pageDefs provides the blocks every layout can rely on. They take precedence
+// over the placeholders of the layout blocks but not over the blocks the layout
+// code defines itself.
+const pageDefs = `
+{{ define "page" }}{{ template "layout" . }}{{ end }}
+{{ define "title" }}{{ .SEO.Title }}{{ end }}
+{{ define "head" }}{{ .SEO.Meta }}{{ range .Alternates }}
+<link rel="alternate" hreflang="{{ .Lang }}" href="{{ .URL }}">{{ end }}{{ with .Listing }}{{ if .FeedURL }}
+<link rel="alternate" type="application/atom+xml" title="{{ .Title }}" href="{{ .FeedURL }}">{{ end }}{{ end }}
+{{ .SEO.JSONLD }}
+{{ .Site.AnalyticsHTML }}
+{{ end }}
+{{ define "header" }}{{ end }}
+{{ define "flash" }}{{ end }}
+{{ define "content" }}<article lang="{{ .Content.LangOr .Lang }}">{{ .HTML }}</article>{{ end }}
+{{ define "submenu" }}{{ end }}
+`
+
+// PageData is the value layouts are executed with.
+type PageData struct {
+	Lang       string
+	IsFallback bool // True if Content is the default language version shown in place of a missing translation
+	URL        string
+	Site       SiteSettings
+	Content    Content
+	Section    Section
+	Type       ContentType    // Zero for content without type
+	Fields     map[string]any // Values of the fields of the content type, by field name
+	HTML       template.HTML
+	TOC        TOC           // Table of contents of the body
+	SEO        SEO           // Meta tags and structured data
+	Related    []Related     // Pages on similar topics, most related first
+	Series     *SeriesNav    // Nil for content not in a series
+	Authors    []PageAuthor  // Authors of the content, in byline order
+	Author     *PageAuthor   // Nil but on the archive page of an author
+	Listing    *Listing      // Nil but on archive pages, whose Content only holds the title
+	Dates      []YearArchive // Date archive of the section in the language of the page, newest first
+	Alternates []Alternate
+}
+
+type page struct {
+	PageData
+	layoutID uuid.UUID
+}
+
+// Generator renders content into a static site tree.
+type Generator struct {
+	am.Core
+	assetsFS   embed.FS
+	repo       Repo
+	linkClient HTTPClient
+	mu         sync.Mutex
+	siteMu     map[uuid.UUID]*sync.Mutex
+}
+
+func NewGenerator(assetsFS embed.FS, repo Repo, opts ...am.Option) *Generator {
+	return &Generator{
+		Core:       am.NewCore("ssg-generator", opts...),
+		assetsFS:   assetsFS,
+		repo:       repo,
+		linkClient: &http.Client{Timeout: linkCheckTimeout},
+	}
+}
+
+// SetLinkClient sets the client used to check external links.
+func (g *Generator) SetLinkClient(client HTTPClient) {
+	g.linkClient = client
+}
+
+// OutputDir returns the path the live site is served from. It is a symlink
+// to the release of the last activated build.
+func (g *Generator) OutputDir(ctx context.Context) string {
+	return siteDir(ctx, g.Cfg().StrValOrDef(am.Key.SSGOutputDir, defOutputDir))
+}
+
+// ReportsDir returns the directory build reports are written to. It is kept
+// out of the output directory so reports are never published.
+func (g *Generator) ReportsDir(ctx context.Context) string {
+	return siteDir(ctx, g.Cfg().StrValOrDef(am.Key.SSGReportsDir, defReportsDir))
+}
+
+// StaticDir returns the directory of files served as they are, such as
+// images and downloads. Its tree is copied to the site root on each build.
+func (g *Generator) StaticDir(ctx context.Context) string {
+	return siteDir(ctx, g.Cfg().StrValOrDef(am.Key.SSGStaticDir, defStaticDir))
+}
+
+// DataDir returns the directory data files are synced from.
+func (g *Generator) DataDir(ctx context.Context) string {
+	return siteDir(ctx, g.Cfg().StrValOrDef(am.Key.SSGDataDir, defDataDir))
+}
+
+// siteDir returns the variant of a configured directory for the site of the
+// context. The default site uses the directory as configured so existing
+// installs keep their paths, other sites get their slug appended, as in
+// output-blog.
+func siteDir(ctx context.Context, dir string) string {
+	site := SiteFrom(ctx)
+	if site.IsDefault() {
+		return dir
+	}
+	return filepath.Clean(dir) + "-" + site.SlugValue
+}
+
+// lock serializes the runs that change the output of the site of the
+// context. Sites do not share directories so they are built independently.
+func (g *Generator) lock(ctx context.Context) func() {
+	id := SiteIDFrom(ctx)
+
+	g.mu.Lock()
+	if g.siteMu == nil {
+		g.siteMu = map[uuid.UUID]*sync.Mutex{}
+	}
+	mu, ok := g.siteMu[id]
+	if !ok {
+		mu = &sync.Mutex{}
+		g.siteMu[id] = mu
+	}
+	g.mu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// Generate renders every content into a new release for a build, one tree per
+// configured language, and makes it live once complete. A failed build leaves
+// the live site untouched. Runs of the same site are serialized because they
+// share the output directory.
+func (g *Generator) Generate(ctx context.Context, buildID uuid.UUID) (BuildStats, error) {
+	defer g.lock(ctx)()
+
+	dir := g.releaseDir(ctx, buildID)
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return BuildStats{}, fmt.Errorf("cannot clean release dir: %w", err)
+	}
+
+	stats, err := g.generate(ctx, dir)
+	if err != nil {
+		rmErr := os.RemoveAll(dir)
+		if rmErr != nil {
+			g.Log().Errorf("Cannot remove failed release %s: %v", dir, rmErr)
+		}
+		return stats, err
+	}
+
+	err = g.activate(ctx, buildID)
+	if err != nil {
+		return stats, fmt.Errorf("cannot activate release: %w", err)
+	}
+
+	keep := int(g.Cfg().IntVal(am.Key.SSGReleasesKeep, defReleases))
+	err = pruneReleases(g.ReleasesDir(ctx), keep, buildID)
+	if err != nil {
+		stats.Warnings = append(stats.Warnings, fmt.Sprintf("cannot prune releases: %s", err))
+	}
+
+	g.Log().Infof("Site released from %s", dir)
+	return stats, nil
+}
+
+// siteInput is what a site is generated from.
+type siteInput struct {
+	contents  []Content
+	sections  []Section
+	layouts   []Layout
+	redirects []Redirect
+	types     []ContentType
+	settings  SiteSettings
+	files     []DataFile
+	menus     []Menu
+	items     []MenuItem
+	series    []Series
+	parts     []SeriesPart
+	terms     []ContentTerm
+	profiles  []Profile
+	credits   []ContentAuthor
+}
+
+// load reads what the site is generated from.
+func (g *Generator) load(ctx context.Context) (in siteInput, err error) {
+	in.contents, err = g.repo.GetAllContent(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get contents: %w", err)
+	}
+
+	in.sections, err = g.repo.GetSections(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get sections: %w", err)
+	}
+
+	in.layouts, err = g.repo.GetAllLayouts(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get layouts: %w", err)
+	}
+
+	in.redirects, err = g.repo.GetAllRedirects(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get redirects: %w", err)
+	}
+
+	in.types, err = g.repo.GetContentTypes(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get content types: %w", err)
+	}
+
+	in.settings, err = loadSiteSettings(ctx, g.repo, g.Cfg())
+	if err != nil {
+		return in, fmt.Errorf("cannot get site settings: %w", err)
+	}
+
+	in.files, err = g.repo.GetDataFiles(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get data files: %w", err)
+	}
+
+	in.settings.Data, err = siteData(in.files)
+	if err != nil {
+		return in, err
+	}
+
+	in.menus, err = g.repo.GetMenus(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get menus: %w", err)
+	}
+
+	in.items, err = g.repo.GetMenuItems(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get menu items: %w", err)
+	}
+
+	in.series, err = g.repo.GetAllSeries(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get series: %w", err)
+	}
+
+	in.parts, err = g.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get series parts: %w", err)
+	}
+
+	in.terms, err = g.repo.GetAllContentTerms(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get content terms: %w", err)
+	}
+
+	in.profiles, err = g.repo.GetProfiles(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get profiles: %w", err)
+	}
+
+	in.credits, err = g.repo.GetContentAuthors(ctx)
+	if err != nil {
+		return in, fmt.Errorf("cannot get content authors: %w", err)
+	}
+
+	return in, nil
+}
+
+// generate renders the site into dir.
+func (g *Generator) generate(ctx context.Context, dir string) (BuildStats, error) {
+	var stats BuildStats
+
+	in, err := g.load(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.DataHashes, stats.Warnings = usedData(in.layouts, in.files)
+
+	previous, err := listFiles(g.OutputDir(ctx))
+	if err != nil {
+		return stats, fmt.Errorf("cannot read output dir: %w", err)
+	}
+
+	// Static files are copied first so that rendered pages win on conflicts.
+	err = copyStatic(g.StaticDir(ctx), dir)
+	if err != nil {
+		return stats, fmt.Errorf("cannot copy static files: %w", err)
+	}
+
+	r := newRenderer(g.assetsFS, in.layouts)
+	pages, archives, skipped := g.site(in)
+	for _, p := range archives {
+		if p.Listing.FeedURL == "" {
+			continue
+		}
+
+		feed, err := renderFeed(in.settings, *p.Listing, p.URL, p.Author)
+		if err != nil {
+			return stats, fmt.Errorf("cannot render feed of %s: %w", p.URL, err)
+		}
+
+		err = writePage(dir, p.Listing.FeedURL, feed)
+		if err != nil {
+			return stats, fmt.Errorf("cannot write %s: %w", p.Listing.FeedURL, err)
+		}
+	}
+	pages = append(pages, archives...)
+
+	for _, p := range pages {
+		out, err := r.render(p)
+		if err != nil {
+			return stats, fmt.Errorf("cannot render %s: %w", p.URL, err)
+		}
+
+		err = writePage(dir, p.URL, out)
+		if err != nil {
+			return stats, fmt.Errorf("cannot write %s: %w", p.URL, err)
+		}
+		stats.PagesRendered++
+	}
+	stats.PagesSkipped = skipped
+
+	err = writeRedirects(dir, in.redirects)
+	if err != nil {
+		return stats, fmt.Errorf("cannot write redirects: %w", err)
+	}
+
+	current, err := listFiles(dir)
+	if err != nil {
+		return stats, fmt.Errorf("cannot read release dir: %w", err)
+	}
+	stats.PagesDeleted = countDeletedPages(previous, current)
+	stats.OutputHash = treeHash(current)
+
+	report, err := g.checkLinks(ctx, dir, pages, in.redirects)
+	if err != nil {
+		return stats, fmt.Errorf("cannot check links: %w", err)
+	}
+	for _, issue := range report.Issues {
+		stats.Warnings = append(stats.Warnings, fmt.Sprintf("%s in %s: %s (%s)", issue.Kind, issue.Page, issue.Ref, issue.Detail))
+	}
+
+	g.Log().Infof("Site generated in %s, %d link issues found", dir, len(report.Issues))
+	return stats, nil
+}
+
+// site plans the pages of the site along with everything layouts see on
+// them: a page per content and language, then the archive pages.
+func (g *Generator) site(in siteInput) (pages, archives []page, skipped int) {
+	settings := in.settings
+	pages, skipped = g.plan(in.contents, in.sections, in.types)
+	byLang := g.menus(in.menus, in.items, in.contents, in.sections, pages)
+	navs := g.series(in.series, in.parts, in.contents, pages)
+	related := relatedPages(pages, in.terms, relatedWeights(g.Cfg()))
+	def := DefaultLang(g.Cfg())
+	for i, p := range pages {
+		pages[i].Site = settings
+		pages[i].Site.Menus = activeMenus(byLang[p.Lang], p.URL)
+		pages[i].SEO = pageSEO(settings, p.Section, p.Content, p.Lang, def, p.URL)
+		pages[i].Related = related[i]
+		pages[i].Series = navs[p.URL]
+		var names []string
+		for _, a := range authorsOf(p.Content, in.profiles, in.credits) {
+			author := pageAuthor(settings, a, p.Lang, def)
+			pages[i].Authors = append(pages[i].Authors, author)
+			names = append(names, author.Name)
+		}
+		if len(names) > 0 {
+			pages[i].SEO.Article.Authors = names
+		}
+	}
+
+	dated, dates := g.datePages(settings, in.sections, pages)
+	for i, p := range pages {
+		pages[i].Dates = dates[sectionLang{p.Section.ID(), p.Lang}]
+	}
+
+	archives = append(g.authorPages(settings, in.profiles, in.sections, pages), dated...)
+	for i, p := range archives {
+		archives[i].Site = settings
+		archives[i].Site.Menus = activeMenus(byLang[p.Lang], p.URL)
+	}
+	return pages, archives, skipped
+}
+
+// checkLinks validates the generated output and saves the report. External
+// links are only checked when enabled because they slow builds down and
+// depend on third party availability.
+func (g *Generator) checkLinks(ctx context.Context, dir string, pages []page, redirects []Redirect) (LinkReport, error) {
+	origins := make(map[string]uuid.UUID, len(pages))
+	for _, p := range pages {
+		origins[p.URL] = p.Content.ID()
+	}
+
+	var client HTTPClient
+	if g.Cfg().BoolVal(am.Key.SSGCheckExternal, false) {
+		client = g.linkClient
+	}
+
+	report, err := NewLinkChecker(client).Check(ctx, dir, origins, redirects)
+	if err != nil {
+		return report, err
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return report, err
+	}
+
+	err = os.MkdirAll(g.ReportsDir(ctx), 0o755)
+	if err != nil {
+		return report, err
+	}
+
+	return report, os.WriteFile(filepath.Join(g.ReportsDir(ctx), linkReportFile), b, 0o644)
+}
+
+// LinkReport returns the report of the last link check. A zero report is
+// returned if the site was never generated.
+func (g *Generator) LinkReport(ctx context.Context) (LinkReport, error) {
+	var report LinkReport
+	b, err := os.ReadFile(filepath.Join(g.ReportsDir(ctx), linkReportFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+	return report, json.Unmarshal(b, &report)
+}
+
+// plan decides which pages are generated for each language, applying the
+// fallback policy for missing translations and linking language versions. It
+// also returns how many pages were left out by that policy. Content not
+// published is left out altogether, so nothing on the site links to it.
+func (g *Generator) plan(contents []Content, sections []Section, types []ContentType) (pages []page, skipped int) {
+	contents = publishedContents(contents)
+	langs := Languages(g.Cfg())
+	def := DefaultLang(g.Cfg())
+	fallback := LangFallback(g.Cfg())
+
+	sectionsByID := make(map[uuid.UUID]Section, len(sections))
+	var root Section
+	for _, s := range sections {
+		sectionsByID[s.ID()] = s
+		if s.Path == "/" {
+			root = s
+		}
+	}
+
+	typesByID := make(map[uuid.UUID]ContentType, len(types))
+	for _, t := range types {
+		typesByID[t.ID()] = t
+	}
+
+	contentsByID := make(map[uuid.UUID]Content, len(contents))
+	for _, c := range contents {
+		contentsByID[c.ID()] = c
+	}
+
+	byGroup := make(map[uuid.UUID]map[string]Content)
+	var order []uuid.UUID
+	for _, c := range contents {
+		key := c.TranslationKey()
+		if _, ok := byGroup[key]; !ok {
+			byGroup[key] = make(map[string]Content)
+			order = append(order, key)
+		}
+		byGroup[key][c.LangOr(def)] = c
+	}
+
+	for _, key := range order {
+		versions := byGroup[key]
+
+		var group []page
+		for _, lang := range langs {
+			c, ok := versions[lang]
+			isFallback := false
+			if !ok {
+				c, ok = versions[def]
+				if !ok || fallback != LangFallbackDefault {
+					skipped++
+					continue
+				}
+				isFallback = true
+			}
+
+			section, ok := sectionsByID[c.SectionID]
+			if !ok {
+				section = root
+			}
+			if !section.ServesLang(lang) {
+				skipped++
+				continue
+			}
+
+			p := page{
+				PageData: PageData{
+					Lang:       lang,
+					IsFallback: isFallback,
+					URL:        ContentURL(lang, def, section, c),
+					Content:    c,
+					Section:    section,
+				},
+				layoutID: section.LayoutID,
+			}
+			if t, ok := typesByID[c.TypeID]; ok {
+				p.Type = t
+				p.Fields = t.Values(c.Fields, contentsByID)
+				if t.LayoutID != uuid.Nil {
+					p.layoutID = t.LayoutID
+				}
+			}
+			group = append(group, p)
+		}
+
+		alternates := alternatesFor(group, def)
+		for i := range group {
+			group[i].Alternates = alternates
+		}
+		pages = append(pages, group...)
+	}
+
+	return pages, skipped
+}
+
+func publishedContents(contents []Content) []Content {
+	published := make([]Content, 0, len(contents))
+	for _, c := range contents {
+		if c.IsPublished() {
+			published = append(published, c)
+		}
+	}
+	return published
+}
+
+// menus resolves the menus of the site for every language, by language.
+// Content items link to the page of the content in that language, or to the
+// default language page if it is not published there. Section items are left
+// out of the languages their section does not serve.
+func (g *Generator) menus(menus []Menu, items []MenuItem, contents []Content, sections []Section, pages []page) map[string]map[string][]MenuEntry {
+	def := DefaultLang(g.Cfg())
+
+	sectionsByID := make(map[uuid.UUID]Section, len(sections))
+	for _, s := range sections {
+		sectionsByID[s.ID()] = s
+	}
+
+	keys := make(map[uuid.UUID]uuid.UUID, len(contents))
+	for _, c := range contents {
+		keys[c.ID()] = c.TranslationKey()
+	}
+
+	type pageKey struct {
+		key  uuid.UUID
+		lang string
+	}
+	pagesByKey := make(map[pageKey]page, len(pages))
+	for _, p := range pages {
+		pagesByKey[pageKey{p.Content.TranslationKey(), p.Lang}] = p
+	}
+
+	result := make(map[string]map[string][]MenuEntry)
+	for _, lang := range Languages(g.Cfg()) {
+		link := func(it MenuItem) (MenuEntry, bool) {
+			e := MenuEntry{Label: it.Label}
+			switch it.Kind {
+			case MenuItemSection:
+				s, ok := sectionsByID[it.TargetID]
+				if !ok || !s.ServesLang(lang) {
+					return e, false
+				}
+				e.URL = SectionURL(lang, def, s)
+				if e.URL != LangPrefix(lang, def)+"/" {
+					e.within = e.URL
+				}
+				if e.Label == "" {
+					e.Label = s.Name
+				}
+			case MenuItemContent:
+				key, ok := keys[it.TargetID]
+				if !ok {
+					return e, false
+				}
+				p, ok := pagesByKey[pageKey{key, lang}]
+				if !ok {
+					p, ok = pagesByKey[pageKey{key, def}]
+				}
+				if !ok {
+					return e, false
+				}
+				e.URL = p.URL
+				if e.Label == "" {
+					e.Label = p.Content.Heading
+				}
+			case MenuItemURL:
+				e.URL = it.URL
+				e.External = !strings.HasPrefix(it.URL, "/")
+			default:
+				return e, false
+			}
+			return e, true
+		}
+		result[lang] = siteMenus(menus, items, link)
+	}
+	return result
+}
+
+// series resolves the series navigation of the pages in a series, by page
+// URL. Parts link to the page of their content in the language of the page
+// and are left out of the languages they are not published in.
+func (g *Generator) series(series []Series, parts []SeriesPart, contents []Content, pages []page) map[string]*SeriesNav {
+	keys := make(map[uuid.UUID]uuid.UUID, len(contents))
+	for _, c := range contents {
+		keys[c.ID()] = c.TranslationKey()
+	}
+
+	type pageKey struct {
+		key  uuid.UUID
+		lang string
+	}
+	pagesByKey := make(map[pageKey]page, len(pages))
+	for _, p := range pages {
+		pagesByKey[pageKey{p.Content.TranslationKey(), p.Lang}] = p
+	}
+
+	result := make(map[string]*SeriesNav)
+	for _, lang := range Languages(g.Cfg()) {
+		for _, s := range series {
+			var links []SeriesLink
+			for _, id := range seriesOrder(parts, s.ID()) {
+				key, ok := keys[id]
+				if !ok {
+					continue
+				}
+				p, ok := pagesByKey[pageKey{key, lang}]
+				if !ok {
+					continue
+				}
+				links = append(links, SeriesLink{Heading: p.Content.Heading, URL: p.URL})
+			}
+
+			for url, nav := range seriesNavs(s, links) {
+				result[url] = nav
+			}
+		}
+	}
+	return result
+}
+
+// authorPages returns the archive page of every author in each language they
+// have pages in. Archives list the pages of the author newest first
+// and are rendered with the layout of the root section.
+func (g *Generator) authorPages(site SiteSettings, profiles []Profile, sections []Section, pages []page) []page {
+	def := DefaultLang(g.Cfg())
+	root := sectionOf(sections, uuid.Nil)
+
+	var result []page
+	for _, lang := range Languages(g.Cfg()) {
+		for _, prof := range profiles {
+			found := false
+			var entries []ListEntry
+			for _, p := range pages {
+				if p.Lang != lang || !credited(p.Authors, prof.SlugValue) {
+					continue
+				}
+				found = true
+				entries = append(entries, ListEntry{Content: p.Content, URL: p.URL, Authors: p.Authors})
+			}
+			if !found {
+				continue
+			}
+			sortEntries(entries)
+
+			author := pageAuthor(site, prof, lang, def)
+			content := NewContent(author.Name, "")
+			content.Lang = lang
+			result = append(result, page{
+				PageData: PageData{
+					Lang:    lang,
+					URL:     author.URL,
+					Content: content,
+					Section: root,
+					SEO:     listingSEO(site, author.Name, author.Bio, author.Avatar, lang, def, author.URL),
+					Author:  &author,
+					Listing: &Listing{
+						Title:       author.Name,
+						Description: author.Bio,
+						Entries:     entries,
+						FeedURL:     author.FeedURL,
+						Page:        1,
+						Pages:       1,
+					},
+				},
+				layoutID: root.LayoutID,
+			})
+		}
+	}
+	return result
+}
+
+// sectionLang identifies the pages of a section in a language.
+type sectionLang struct {
+	section uuid.UUID
+	lang    string
+}
+
+// datePages returns the year and month archive pages of every section in each
+// language it has published pages in, paginated and rendered with the layout
+// of the section. It also returns the date archive summary of each section by
+// language. Archives never replace a content page published at their URL.
+func (g *Generator) datePages(site SiteSettings, sections []Section, pages []page) ([]page, map[sectionLang][]YearArchive) {
+	def := DefaultLang(g.Cfg())
+	size := int(g.Cfg().IntVal(am.Key.SSGArchivePageSize, defArchivePageSize))
+
+	taken := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		taken[p.URL] = true
+	}
+
+	var result []page
+	summaries := make(map[sectionLang][]YearArchive)
+	for _, lang := range Languages(g.Cfg()) {
+		for _, s := range sections {
+			var entries []ListEntry
+			for _, p := range pages {
+				if p.Lang != lang || p.Section.ID() != s.ID() || p.Content.PublishedAt.IsZero() {
+					continue
+				}
+				entries = append(entries, ListEntry{Content: p.Content, URL: p.URL, Authors: p.Authors})
+			}
+			if len(entries) == 0 {
+				continue
+			}
+			sortEntries(entries)
+
+			base := SectionURL(lang, def, s)
+			years := dateArchive(entries, base)
+			summaries[sectionLang{s.ID(), lang}] = years
+
+			var trail []Breadcrumb
+			if s.Path != "" && s.Path != "/" {
+				trail = append(trail, Breadcrumb{Name: s.Name, URL: base})
+			}
+
+			add := func(title, url string, entries []ListEntry, trail []Breadcrumb) {
+				for _, lp := range paginate(Listing{Title: title, Entries: entries}, url, size) {
+					if taken[lp.url] {
+						continue
+					}
+
+					name := ""
+					if len(trail) > 0 {
+						name = s.Name
+					}
+					seo := listingSEO(site, archiveTitle(name, title, lp.listing.Page), s.MetaDescription, s.SocialImage, lang, def, lp.url, trail...)
+					seo.NoIndex = s.NoIndex
+
+					content := NewContent(title, "")
+					content.Lang = lang
+					listing := lp.listing
+					result = append(result, page{
+						PageData: PageData{
+							Lang:    lang,
+							URL:     lp.url,
+							Content: content,
+							Section: s,
+							SEO:     seo,
+							Listing: &listing,
+							Dates:   years,
+						},
+						layoutID: s.LayoutID,
+					})
+				}
+			}
+
+			for _, y := range years {
+				year := strconv.Itoa(y.Year)
+				add(year, y.URL, entriesIn(entries, y.Year, 0), trail)
+				for _, m := range y.Months {
+					monthTrail := append(trail[:len(trail):len(trail)], Breadcrumb{Name: year, URL: y.URL})
+					add(m.Month.String()+" "+year, m.URL, entriesIn(entries, m.Year, m.Month), monthTrail)
+				}
+			}
+		}
+	}
+	return result, summaries
+}
+
+func credited(authors []PageAuthor, slug string) bool {
+	for _, a := range authors {
+		if a.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// alternatesFor returns the hreflang alternates of a group of pages. Fallback
+// pages are left out because they do not hold a real translation.
+func alternatesFor(group []page, def string) []Alternate {
+	var alternates []Alternate
+	for _, p := range group {
+		if p.IsFallback {
+			continue
+		}
+		alternates = append(alternates, Alternate{Lang: p.Lang, URL: p.URL})
+		if p.Lang == def {
+			alternates = append(alternates, Alternate{Lang: "x-default", URL: p.URL})
+		}
+	}
+	if len(alternates) < 2 {
+		return nil
+	}
+	return alternates
+}
+
+// writePage writes a page under dir. URLs ending in a slash are written as
+// index files so that they can be served as directories.
+func writePage(dir, url string, content []byte) error {
+	file := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(url, "/")))
+	if strings.HasSuffix(url, "/") {
+		file = filepath.Join(file, indexFile)
+	}
+	err := os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0o644)
+}
+
+// renderer executes layouts, parsing each of them only once per generation.
+type renderer struct {
+	assetsFS  embed.FS
+	layouts   map[uuid.UUID]Layout
+	templates map[uuid.UUID]*template.Template
+}
+
+func newRenderer(assetsFS embed.FS, layouts []Layout) *renderer {
+	byID := make(map[uuid.UUID]Layout, len(layouts))
+	for _, l := range layouts {
+		byID[l.ID()] = l
+	}
+	return &renderer{
+		assetsFS:  assetsFS,
+		layouts:   byID,
+		templates: make(map[uuid.UUID]*template.Template),
+	}
+}
+
+func (r *renderer) render(p page) ([]byte, error) {
+	tmpl, err := r.template(p.layoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Listing != nil {
+		p.HTML = listingHTML(p.Site, *p.Listing)
+	} else {
+		min, max := p.Content.TOCDepth()
+		p.HTML, p.TOC, err = RenderMarkdownTOC(p.Content.Body, min, max)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "page", p.PageData)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// definedBlocks returns the names of the templates the layout code defines
+// with define. Those defined with block are placeholders and not included.
+func definedBlocks(code string) map[string]bool {
+	names := map[string]bool{}
+	for _, m := range defineRe.FindAllStringSubmatch(code, -1) {
+		names[m[1]] = true
+	}
+	return names
+}
+
+// template returns the parsed layout, falling back to the embedded one when
+// the layout is not in the database or has no code.
+func (r *renderer) template(layoutID uuid.UUID) (*template.Template, error) {
+	if tmpl, ok := r.templates[layoutID]; ok {
+		return tmpl, nil
+	}
+
+	code := ""
+	if layout, ok := r.layouts[layoutID]; ok {
+		code = layout.Code
+	}
+
+	if strings.TrimSpace(code) == "" {
+		b, err := r.assetsFS.ReadFile(fallbackLayoutPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read fallback layout: %w", err)
+		}
+		code = string(b)
+	}
+
+	tmpl, err := template.New("layout-code").Parse(code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse layout: %w", err)
+	}
+
+	defs, err := template.New("page-defs").Parse(pageDefs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse page blocks: %w", err)
+	}
+
+	own := definedBlocks(code)
+	for _, def := range defs.Templates() {
+		if def == defs || own[def.Name()] {
+			continue
+		}
+		if _, err := tmpl.AddParseTree(def.Name(), def.Tree); err != nil {
+			return nil, fmt.Errorf("cannot add page block %s: %w", def.Name(), err)
+		}
+	}
+
+	r.templates[layoutID] = tmpl
+	return tmpl, nil
+}
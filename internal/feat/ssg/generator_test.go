@@ -0,0 +1,178 @@
+package ssg
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// buildRepo holds what a build reads. Site settings and what the test does
+// not need are left unset.
+type buildRepo struct {
+	Repo
+	layouts  []Layout
+	sections []Section
+	contents []Content
+	links    []ContentTerm
+	series   []Series
+	parts    []SeriesPart
+}
+
+func (r *buildRepo) GetAllLayouts(ctx context.Context) ([]Layout, error) {
+	return r.layouts, nil
+}
+
+func (r *buildRepo) GetSections(ctx context.Context) ([]Section, error) {
+	return r.sections, nil
+}
+
+func (r *buildRepo) GetAllContent(ctx context.Context) ([]Content, error) {
+	return r.contents, nil
+}
+
+func (r *buildRepo) GetAllContentTerms(ctx context.Context) ([]ContentTerm, error) {
+	return r.links, nil
+}
+
+func (r *buildRepo) GetAllSeries(ctx context.Context) ([]Series, error) {
+	return r.series, nil
+}
+
+func (r *buildRepo) GetSeriesParts(ctx context.Context) ([]SeriesPart, error) {
+	return r.parts, nil
+}
+
+func (r *buildRepo) GetSiteSettings(ctx context.Context) (SiteSettings, error) {
+	return SiteSettings{}, sql.ErrNoRows
+}
+
+func (r *buildRepo) GetAllRedirects(ctx context.Context) ([]Redirect, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetContentTypes(ctx context.Context) ([]ContentType, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetDataFiles(ctx context.Context) ([]DataFile, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetMenus(ctx context.Context) ([]Menu, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetMenuItems(ctx context.Context) ([]MenuItem, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetProfiles(ctx context.Context) ([]Profile, error) {
+	return nil, nil
+}
+
+func (r *buildRepo) GetContentAuthors(ctx context.Context) ([]ContentAuthor, error) {
+	return nil, nil
+}
+
+func TestGenerateSkipsDrafts(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.SSGOutputDir:  filepath.Join(root, "output"),
+		am.Key.SSGReportsDir: filepath.Join(root, "reports"),
+		am.Key.SSGStaticDir:  filepath.Join(root, "static"),
+	})
+
+	repo := &buildRepo{}
+	layout := Layout{BaseModel: am.NewModel(am.WithType(layoutType)), Name: "main",
+		Code: `{{ define "layout" }}{{ .HTML }}{{ range .Related }}<a href="{{ .URL }}">{{ .Content.Heading }}</a>{{ end }}` +
+			`{{ with .Series }}{{ range .Parts }}<a href="{{ .URL }}">{{ .Heading }}</a>{{ end }}{{ end }}{{ end }}`}
+	layout.GenCreateValues()
+	blog := NewSection("Blog", "", "/blog", layout.ID())
+	blog.GenCreateValues()
+	tag := NewTerm(TermTag, "Go")
+	tag.GenCreateValues()
+	series := NewSeries("Intro", "")
+	series.GenCreateValues()
+
+	var contents []Content
+	for i, status := range []string{StatusPublished, StatusDraft} {
+		c := NewContent([]string{"Published post", "Draft post"}[i], "Body")
+		c.GenCreateValues()
+		c.TranslationID = c.ID()
+		c.SectionID, c.Status = blog.ID(), status
+		c.SlugValue = Slugify(c.Heading)
+		contents = append(contents, c)
+		repo.links = append(repo.links, ContentTerm{ContentID: c.ID(), TermID: tag.ID()})
+		repo.parts = append(repo.parts, SeriesPart{SeriesID: series.ID(), ContentID: c.ID(), Position: i})
+	}
+	repo.layouts, repo.sections, repo.contents = []Layout{layout}, []Section{blog}, contents
+	repo.series = []Series{series}
+
+	g := NewGenerator(embed.FS{}, repo, am.WithCfg(cfg), am.WithLog(am.NewLogger("error")))
+	dir := filepath.Join(root, "release")
+	if _, err := g.generate(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "blog", "draft-post", "index.html")); !os.IsNotExist(err) {
+		t.Errorf("draft page generated, stat error = %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "blog", "published-post", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "draft-post") {
+		t.Errorf("published page links to the draft:\n%s", b)
+	}
+}
+
+func TestLayoutBlocksOverridePageDefs(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.SSGOutputDir:  filepath.Join(root, "output"),
+		am.Key.SSGReportsDir: filepath.Join(root, "reports"),
+		am.Key.SSGStaticDir:  filepath.Join(root, "static"),
+	})
+
+	layout := Layout{BaseModel: am.NewModel(am.WithType(layoutType)), Name: "main",
+		Code: `{{ define "layout" }}<title>{{ template "title" . }}</title>` +
+			`<main>{{ block "content" . }}Content{{ end }}</main>{{ end }}` +
+			`{{ define "title" }}Custom {{ .Content.Heading }}{{ end }}`}
+	layout.GenCreateValues()
+	blog := NewSection("Blog", "", "/blog", layout.ID())
+	blog.GenCreateValues()
+	c := NewContent("Hello", "Body")
+	c.GenCreateValues()
+	c.TranslationID = c.ID()
+	c.SectionID, c.Status = blog.ID(), StatusPublished
+	c.SlugValue = Slugify(c.Heading)
+	repo := &buildRepo{layouts: []Layout{layout}, sections: []Section{blog}, contents: []Content{c}}
+
+	g := NewGenerator(embed.FS{}, repo, am.WithCfg(cfg), am.WithLog(am.NewLogger("error")))
+	dir := filepath.Join(root, "release")
+	if _, err := g.generate(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "blog", "hello", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(b)
+	if !strings.Contains(page, "<title>Custom Hello</title>") {
+		t.Errorf("layout title not used:\n%s", page)
+	}
+	if !strings.Contains(page, "<main><article") {
+		t.Errorf("content placeholder not replaced by the default block:\n%s", page)
+	}
+}
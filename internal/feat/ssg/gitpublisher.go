@@ -0,0 +1,220 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defPublishBranch = "gh-pages"
+	defAuthorName    = "Hermes"
+	defAuthorEmail   = "hermes@localhost"
+	noJekyllFile     = ".nojekyll"
+)
+
+// GitPublisher commits a generated site to a branch of a git repository, as
+// expected by hosts such as GitHub Pages. The repository can be a local path
+// or any remote URL git understands.
+type GitPublisher struct {
+	repo   string
+	branch string
+}
+
+func NewGitPublisher(repo, branch string) *GitPublisher {
+	if branch == "" {
+		branch = defPublishBranch
+	}
+	return &GitPublisher{repo: repo, branch: branch}
+}
+
+// Publish replaces the content of the branch with the files under dir and
+// pushes it as a single commit referencing the build. Nothing is committed on
+// dry runs or when the branch already holds the same files.
+func (p *GitPublisher) Publish(ctx context.Context, dir string, build Build, author Author, dryRun bool) (PublishResult, error) {
+	result := PublishResult{DryRun: dryRun}
+
+	err := checkGitTarget(ctx, p.repo, p.branch)
+	if err != nil {
+		return result, err
+	}
+
+	work, err := os.MkdirTemp("", "hermes-publish-")
+	if err != nil {
+		return result, err
+	}
+	defer os.RemoveAll(work)
+
+	err = p.checkout(ctx, work)
+	if err != nil {
+		return result, err
+	}
+
+	err = clearWorkTree(work)
+	if err != nil {
+		return result, fmt.Errorf("cannot clear work tree: %w", err)
+	}
+
+	err = copyTree(dir, work)
+	if err != nil {
+		return result, fmt.Errorf("cannot copy output: %w", err)
+	}
+
+	// Keep GitHub Pages from running Jekyll, which drops files such as _redirects.
+	err = os.WriteFile(filepath.Join(work, noJekyllFile), nil, 0o644)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = p.git(ctx, work, "add", "--all")
+	if err != nil {
+		return result, err
+	}
+
+	status, err := p.git(ctx, work, "diff", "--cached", "--name-status", "--no-renames")
+	if err != nil {
+		return result, err
+	}
+	for _, line := range strings.Split(status, "\n") {
+		op, name, ok := strings.Cut(line, "\t")
+		if ok {
+			result.Changes = append(result.Changes, Change{Op: op, Path: name})
+		}
+	}
+
+	if dryRun || !result.HasChanges() {
+		return result, nil
+	}
+
+	if author.Name == "" {
+		author.Name = defAuthorName
+	}
+	if author.Email == "" {
+		author.Email = defAuthorEmail
+	}
+
+	_, err = p.git(ctx, work,
+		"-c", "user.name="+author.Name,
+		"-c", "user.email="+author.Email,
+		"-c", "commit.gpgsign=false",
+		"commit", "--quiet", "--message", commitMessage(build))
+	if err != nil {
+		return result, err
+	}
+
+	result.Ref, err = p.git(ctx, work, "rev-parse", "HEAD")
+	if err != nil {
+		return result, err
+	}
+
+	_, err = p.git(ctx, work, "push", "--quiet", "origin", "HEAD:refs/heads/"+p.branch)
+	return result, err
+}
+
+// checkout prepares a work tree of the branch in work, starting an orphan
+// branch if it does not exist yet.
+func (p *GitPublisher) checkout(ctx context.Context, work string) error {
+	heads, err := p.git(ctx, "", "ls-remote", "--heads", "--", p.repo, p.branch)
+	if err != nil {
+		return err
+	}
+
+	if heads != "" {
+		_, err = p.git(ctx, "", "clone", "--quiet", "--single-branch", "--branch", p.branch, "--", p.repo, work)
+		return err
+	}
+
+	_, err = p.git(ctx, work, "init", "--quiet")
+	if err != nil {
+		return err
+	}
+	_, err = p.git(ctx, work, "remote", "add", "--", "origin", p.repo)
+	if err != nil {
+		return err
+	}
+	_, err = p.git(ctx, work, "checkout", "--quiet", "--orphan", p.branch)
+	return err
+}
+
+// checkGitTarget rejects a repository or branch that git would take for an
+// option, which would let target settings run commands, and branch names git
+// does not accept.
+func checkGitTarget(ctx context.Context, repo, branch string) error {
+	if !validGitRepo(repo) {
+		return fmt.Errorf("%w: repository %q", ErrInvalidGitTarget, repo)
+	}
+	if !validGitBranch(ctx, branch) {
+		return fmt.Errorf("%w: branch %q", ErrInvalidGitTarget, branch)
+	}
+	return nil
+}
+
+func validGitRepo(repo string) bool {
+	return repo != "" && !strings.HasPrefix(repo, "-")
+}
+
+// validGitBranch reports whether git accepts name as a branch name as is.
+// Shorthands such as @{-1} are expanded by git, so they do not come back
+// unchanged.
+func validGitBranch(ctx context.Context, name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	out, err := exec.CommandContext(ctx, "git", "check-ref-format", "--branch", name).Output()
+	return err == nil && strings.TrimSpace(string(out)) == name
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func (p *GitPublisher) git(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", gitCommand(args), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitCommand returns the subcommand of a git invocation for error messages.
+func gitCommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+func commitMessage(build Build) string {
+	return fmt.Sprintf("Publish build %s\n\nGenerated at %s by a %s build.\n\nBuild-ID: %s\n",
+		build.ShortID(), build.StartedAt.Format("2006-01-02 15:04:05"), build.Trigger, build.ID())
+}
+
+// clearWorkTree removes everything in a work tree but the git metadata.
+func clearWorkTree(work string) error {
+	entries, err := os.ReadDir(work)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == ".git" {
+			continue
+		}
+		err = os.RemoveAll(filepath.Join(work, e.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
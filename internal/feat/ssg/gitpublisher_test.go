@@ -0,0 +1,133 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGitPublisherPublish(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "site.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("cannot create bare repo: %v: %s", err, out)
+	}
+
+	output := filepath.Join(root, "output")
+	if err := writePage(output, "/", []byte("home")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePage(output, "/about/", []byte("about")); err != nil {
+		t.Fatal(err)
+	}
+
+	build := NewBuild(BuildTriggerManual, uuid.Nil)
+	build.GenCreateValues()
+	author := Author{Name: "Jane Doe", Email: "jane@example.com"}
+	p := NewGitPublisher(remote, "")
+
+	dry, err := p.Publish(ctx, output, build, author, true)
+	if err != nil {
+		t.Fatalf("Publish() dry run error = %v", err)
+	}
+	if len(dry.Changes) != 3 || dry.Ref != "" {
+		t.Errorf("Publish() dry run = %+v, want 3 changes and no commit", dry)
+	}
+	if heads := gitOutput(t, remote, "branch", "--list"); heads != "" {
+		t.Fatalf("dry run pushed branches %q", heads)
+	}
+
+	res, err := p.Publish(ctx, output, build, author, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if res.Ref == "" {
+		t.Fatal("Publish() did not commit")
+	}
+
+	log := gitOutput(t, remote, "log", defPublishBranch, "--format=%an <%ae>%n%B")
+	if !strings.Contains(log, "Jane Doe <jane@example.com>") {
+		t.Errorf("commit author not set, log:\n%s", log)
+	}
+	if !strings.Contains(log, "Build-ID: "+build.ID().String()) {
+		t.Errorf("commit does not reference build, log:\n%s", log)
+	}
+
+	// Removed pages are removed from the branch, unchanged output is not committed.
+	if err := os.RemoveAll(filepath.Join(output, "about")); err != nil {
+		t.Fatal(err)
+	}
+	res, err = p.Publish(ctx, output, build, author, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(res.Changes) != 1 || res.Changes[0] != (Change{Op: ChangeDeleted, Path: "about/index.html"}) {
+		t.Errorf("Publish() changes = %v, want about page deleted", res.Changes)
+	}
+
+	res, err = p.Publish(ctx, output, build, author, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if res.HasChanges() || res.Ref != "" {
+		t.Errorf("Publish() of unchanged output = %+v, want no commit", res)
+	}
+
+	files := gitOutput(t, remote, "ls-tree", "-r", "--name-only", defPublishBranch)
+	if files != ".nojekyll\nindex.html" {
+		t.Errorf("published files = %q", files)
+	}
+}
+
+func TestGitPublisherRejectsOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "site.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("cannot create bare repo: %v: %s", err, out)
+	}
+	marker := filepath.Join(root, "pwned")
+	build := NewBuild(BuildTriggerManual, uuid.Nil)
+	build.GenCreateValues()
+
+	for _, p := range []*GitPublisher{
+		NewGitPublisher("--upload-pack=touch "+marker, ""),
+		NewGitPublisher(remote, "--upload-pack=touch "+marker),
+		NewGitPublisher(remote, "a..b"),
+		NewGitPublisher(remote, "@{-1}"),
+	} {
+		_, err := p.Publish(ctx, root, build, Author{}, true)
+		if !errors.Is(err, ErrInvalidGitTarget) {
+			t.Errorf("Publish() to %q %q error = %v, want ErrInvalidGitTarget", p.repo, p.branch, err)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("option in target settings ran a command, stat error = %v", err)
+	}
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,59 @@
+package ssg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	// IdempotencyHeader carries the key clients set to retry a request
+	// without repeating its effects.
+	IdempotencyHeader = "Idempotency-Key"
+
+	idempotencyKeyMaxLen = 255
+	idempotencyTTL       = 24 * time.Hour
+)
+
+// IdempotencyKey records the response to a request made with an idempotency
+// key so that retries of the same request get it back instead of running it
+// again. Keys are scoped to the API token and expire after a day.
+type IdempotencyKey struct {
+	TokenID     uuid.UUID
+	Key         string
+	RequestHash string
+	StatusCode  int // Zero while the request is still being processed
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func NewIdempotencyKey(tokenID uuid.UUID, key, requestHash string) IdempotencyKey {
+	return IdempotencyKey{
+		TokenID:     tokenID,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   am.Now(),
+	}
+}
+
+// IsPending reports whether the request that reserved the key has not
+// finished yet.
+func (k IdempotencyKey) IsPending() bool {
+	return k.StatusCode == 0
+}
+
+// Matches reports whether a request is the same one the key was used for.
+func (k IdempotencyKey) Matches(requestHash string) bool {
+	return k.RequestHash == requestHash
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
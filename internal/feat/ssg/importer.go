@@ -0,0 +1,514 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// Import formats.
+const (
+	ImportHugo      = "hugo"
+	ImportJekyll    = "jekyll"
+	ImportWordPress = "wordpress"
+)
+
+// Outcome of importing an item.
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+)
+
+const (
+	postsSectionPath      = "/posts"
+	mediaDownloadTimeout  = 30 * time.Second
+	importReportTimestamp = "20060102-150405"
+)
+
+// ImportOptions tune how an import is applied.
+type ImportOptions struct {
+	DryRun  bool // Report what would be imported without saving anything
+	NoMedia bool // Do not copy or download media files
+}
+
+// ImportSource is what a reader found in the files of another site
+// generator or CMS, ready to be mapped into Hermes.
+type ImportSource struct {
+	Format   string
+	Path     string
+	Items    []ImportItem
+	Media    []ImportMedia
+	Warnings []string
+}
+
+func (src *ImportSource) warn(format string, args ...any) {
+	src.Warnings = append(src.Warnings, fmt.Sprintf(format, args...))
+}
+
+// ImportItem is a post or page of the imported site.
+type ImportItem struct {
+	Source         string // File or export entry the item was read from
+	Title          string
+	Slug           string
+	Body           string
+	Lang           string
+	Draft          bool
+	Date           time.Time
+	SectionPath    string // Path of the section the item is imported into, "/" for pages
+	SectionName    string
+	Categories     []string
+	Tags           []string
+	URLs           []string // URLs the item was served at, redirected to the new one
+	TranslationKey string   // Items sharing a key are translations of each other
+}
+
+// ImportMedia is a file of the imported site copied, or downloaded, into the
+// static dir so that it keeps being served at its original path.
+type ImportMedia struct {
+	Source string // Local file or URL
+	Path   string // Path under the static dir, which is also the URL path
+}
+
+// ImportReport describes what an import did, or would do in a dry run.
+type ImportReport struct {
+	Format     string        `json:"format"`
+	Source     string        `json:"source"`
+	DryRun     bool          `json:"dry_run"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Sections   int           `json:"sections"`
+	Contents   int           `json:"contents"`
+	Terms      int           `json:"terms"`
+	Redirects  int           `json:"redirects"`
+	Media      int           `json:"media"`
+	Entries    []ImportEntry `json:"entries"`
+	Warnings   []string      `json:"warnings"`
+	File       string        `json:"-"` // Where the report was saved
+}
+
+// ImportEntry is the outcome of importing an item.
+type ImportEntry struct {
+	Source  string   `json:"source"`
+	Title   string   `json:"title"`
+	Status  string   `json:"status"`
+	URL     string   `json:"url,omitempty"`
+	OldURLs []string `json:"old_urls,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+func (r ImportReport) Skipped() []ImportEntry {
+	var skipped []ImportEntry
+	for _, e := range r.Entries {
+		if e.Status == ImportSkipped {
+			skipped = append(skipped, e)
+		}
+	}
+	return skipped
+}
+
+func (r *ImportReport) warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// siteImport maps an import source into sections, contents, terms and
+// redirects. It keeps track of what already exists so that nothing is
+// created twice and no URL ends up served by two contents.
+type siteImport struct {
+	svc      *BaseService
+	userID   uuid.UUID
+	opts     ImportOptions
+	report   *ImportReport
+	client   HTTPClient
+	layoutID uuid.UUID
+	sections []Section
+	urls     map[string]bool
+	sources  map[string]bool
+	terms    map[string]Term
+	groups   map[string]uuid.UUID
+	events   []Event
+}
+
+func newSiteImport(ctx context.Context, svc *BaseService, userID uuid.UUID, opts ImportOptions, report *ImportReport) (*siteImport, error) {
+	imp := &siteImport{
+		svc:     svc,
+		userID:  userID,
+		opts:    opts,
+		report:  report,
+		client:  &http.Client{Timeout: mediaDownloadTimeout},
+		urls:    map[string]bool{},
+		sources: map[string]bool{},
+		terms:   map[string]Term{},
+		groups:  map[string]uuid.UUID{},
+	}
+
+	var err error
+	imp.sections, err = svc.repo.GetSections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections: %w", err)
+	}
+
+	layouts, err := svc.repo.GetAllLayouts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layouts: %w", err)
+	}
+	if len(layouts) > 0 {
+		imp.layoutID = layouts[0].ID()
+	}
+	if root := sectionByPath(imp.sections, "/"); root != nil && root.LayoutID != uuid.Nil {
+		imp.layoutID = root.LayoutID
+	}
+
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents: %w", err)
+	}
+	for _, c := range contents {
+		imp.urls[svc.contentURL(c, imp.sections)] = true
+	}
+
+	redirects, err := svc.repo.GetAllRedirects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get redirects: %w", err)
+	}
+	for _, r := range redirects {
+		imp.sources[r.SourcePath] = true
+	}
+
+	terms, err := svc.repo.GetTerms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get terms: %w", err)
+	}
+	for _, t := range terms {
+		imp.terms[t.Kind+"/"+t.SlugValue] = t
+	}
+
+	return imp, nil
+}
+
+func (imp *siteImport) run(ctx context.Context, src ImportSource) error {
+	for _, item := range src.Items {
+		entry, err := imp.importItem(ctx, item)
+		if err != nil {
+			return fmt.Errorf("cannot import %s: %w", item.Source, err)
+		}
+		imp.report.Entries = append(imp.report.Entries, entry)
+	}
+
+	if imp.opts.NoMedia {
+		if len(src.Media) > 0 {
+			imp.report.warn("%d media files were not imported", len(src.Media))
+		}
+		return nil
+	}
+	for _, m := range src.Media {
+		err := imp.importMedia(ctx, m)
+		if err != nil {
+			imp.report.warn("Cannot import %s: %v", m.Source, err)
+			continue
+		}
+	}
+	return nil
+}
+
+func (imp *siteImport) importItem(ctx context.Context, item ImportItem) (ImportEntry, error) {
+	entry := ImportEntry{Source: item.Source, Title: item.Title, OldURLs: item.URLs}
+
+	section, err := imp.section(ctx, item)
+	if err != nil {
+		return entry, err
+	}
+
+	content := NewContent(item.Title, item.Body)
+	content.GenCreateValues(imp.userID)
+	content.UserID = imp.userID
+	content.SectionID = section.ID()
+	content.SlugValue = Slugify(item.Slug)
+	if content.SlugValue == "" {
+		content.SlugValue = Slugify(item.Title)
+	}
+	content.Status = StatusDraft
+	if !item.Draft {
+		content.Status = StatusPublished
+		content.PublishedAt = item.Date
+		if content.PublishedAt.IsZero() {
+			content.PublishedAt = am.Now()
+		}
+	}
+
+	lang := strings.ToLower(item.Lang)
+	if lang != "" && !contains(Languages(imp.svc.Cfg()), lang) {
+		imp.report.warn("%s is in %s, which is not configured, it was imported in the default language", item.Source, lang)
+		lang = ""
+	}
+	content.Lang = lang
+
+	content.TranslationID = content.ID()
+	if id, ok := imp.groups[item.TranslationKey]; ok {
+		content.TranslationID = id
+	}
+
+	entry.URL = imp.svc.contentURL(content, imp.sections)
+	if imp.urls[entry.URL] {
+		entry.Status = ImportSkipped
+		entry.Reason = ErrDuplicateURL.Error()
+		return entry, nil
+	}
+
+	if !imp.opts.DryRun {
+		events, err := imp.svc.createContent(ctx, content)
+		if err != nil {
+			return entry, err
+		}
+		imp.events = append(imp.events, events...)
+	}
+	imp.urls[entry.URL] = true
+	if _, ok := imp.groups[item.TranslationKey]; !ok {
+		imp.groups[item.TranslationKey] = content.ID()
+	}
+	imp.report.Contents++
+	entry.Status = ImportCreated
+
+	for _, name := range item.Categories {
+		err = imp.classify(ctx, content, TermCategory, name)
+		if err != nil {
+			return entry, err
+		}
+	}
+	for _, name := range item.Tags {
+		err = imp.classify(ctx, content, TermTag, name)
+		if err != nil {
+			return entry, err
+		}
+	}
+
+	// Drafts were not public so there is nothing to redirect.
+	if content.IsPublished() {
+		for _, old := range item.URLs {
+			err = imp.redirect(ctx, old, entry.URL)
+			if err != nil {
+				return entry, err
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+// section returns the section an item goes to, creating it if needed.
+func (imp *siteImport) section(ctx context.Context, item ImportItem) (Section, error) {
+	if s := sectionByPath(imp.sections, item.SectionPath); s != nil {
+		return *s, nil
+	}
+
+	section := NewSection(item.SectionName, "", item.SectionPath, imp.layoutID)
+	section.GenCreateValues(imp.userID)
+	if !imp.opts.DryRun {
+		err := imp.svc.CreateSection(ctx, section)
+		if err != nil {
+			return section, err
+		}
+	}
+	imp.sections = append(imp.sections, section)
+	imp.report.Sections++
+	return section, nil
+}
+
+// classify links content to a term, creating the term if needed.
+func (imp *siteImport) classify(ctx context.Context, content Content, kind, name string) error {
+	term := NewTerm(kind, name)
+	if term.SlugValue == "" {
+		return nil
+	}
+
+	if existing, ok := imp.terms[kind+"/"+term.SlugValue]; ok {
+		term = existing
+	} else {
+		term.GenCreateValues(imp.userID)
+		if !imp.opts.DryRun {
+			err := imp.svc.repo.CreateTerm(ctx, term)
+			if err != nil {
+				return err
+			}
+		}
+		imp.terms[kind+"/"+term.SlugValue] = term
+		imp.report.Terms++
+	}
+
+	if imp.opts.DryRun {
+		return nil
+	}
+	return imp.svc.repo.AddContentTerm(ctx, content.ID(), term.ID())
+}
+
+// redirect records a permanent redirect from an original URL, unless it is
+// the new URL, already redirected or served by another content.
+func (imp *siteImport) redirect(ctx context.Context, oldURL, newURL string) error {
+	oldURL = urlPath(oldURL)
+	if oldURL == "" || oldURL == newURL || imp.sources[oldURL] || imp.urls[oldURL] {
+		return nil
+	}
+
+	redirect := NewRedirect(oldURL, newURL, http.StatusMovedPermanently)
+	redirect.GenCreateValues(imp.userID)
+	if !imp.opts.DryRun {
+		err := imp.svc.CreateRedirect(ctx, redirect)
+		if err != nil {
+			return err
+		}
+	}
+	imp.sources[oldURL] = true
+	imp.report.Redirects++
+	return nil
+}
+
+// importMedia copies a local file, or downloads a remote one, into the static
+// dir. Existing files are left alone.
+func (imp *siteImport) importMedia(ctx context.Context, m ImportMedia) error {
+	rel := strings.TrimPrefix(path.Clean("/"+m.Path), "/")
+	if rel == "" {
+		return fmt.Errorf("invalid path %s", m.Path)
+	}
+	dst := filepath.Join(imp.svc.gen.StaticDir(ctx), filepath.FromSlash(rel))
+	if _, err := os.Stat(dst); err == nil {
+		imp.report.warn("%s already exists, it was not replaced", dst)
+		return nil
+	}
+
+	if imp.opts.DryRun {
+		imp.report.Media++
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(m.Source, "http://") && !strings.HasPrefix(m.Source, "https://") {
+		err = copyFile(m.Source, dst)
+	} else {
+		err = imp.download(ctx, m.Source, dst)
+	}
+	if err != nil {
+		return err
+	}
+	imp.report.Media++
+	return nil
+}
+
+func (imp *siteImport) download(ctx context.Context, src, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return err
+	}
+	res, err := imp.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed: %s", res.Status)
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, res.Body)
+	if err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// saveImportReport writes the report as JSON under the reports dir.
+func saveImportReport(dir string, report *ImportReport) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Imports started within the same second get numbered reports.
+	name := "import-" + report.StartedAt.Format(importReportTimestamp)
+	for i := 2; ; i++ {
+		report.File = filepath.Join(dir, name+".json")
+		f, err := os.OpenFile(report.File, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("import-%s-%d", report.StartedAt.Format(importReportTimestamp), i)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(b)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+func sectionByPath(sections []Section, p string) *Section {
+	for i := range sections {
+		if sections[i].Path == p {
+			return &sections[i]
+		}
+	}
+	return nil
+}
+
+// urlPath returns the path of an absolute or relative URL, dropping the host,
+// query and fragment. An empty string is returned for URLs that only differ
+// by their query, such as WordPress ?p=ID links, because static hosts cannot
+// redirect them.
+func urlPath(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Path == "" || (u.RawQuery != "" && (u.Path == "/" || u.Path == "")) {
+		return ""
+	}
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// sectionName turns a section path into a readable name.
+func sectionName(sectionPath string) string {
+	name := strings.ReplaceAll(path.Base(sectionPath), "-", " ")
+	if name == "" || name == "/" || name == "." {
+		return "root"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// shortcodeMarkers are template constructs of other generators that Hermes
+// does not understand and leaves as they are.
+var shortcodeMarkers = []string{"{{<", "{{%", "{%"}
+
+func hasShortcodes(body string) bool {
+	for _, m := range shortcodeMarkers {
+		if strings.Contains(body, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,426 @@
+package ssg
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	cases := []struct {
+		name  string
+		src   string
+		title string
+		tags  []string
+		date  time.Time
+		body  string
+	}{
+		{
+			name:  "yaml",
+			src:   "---\ntitle: Hello\ntags: [go, web]\ndate: 2024-02-03\n---\nBody\n",
+			title: "Hello",
+			tags:  []string{"go", "web"},
+			date:  time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC),
+			body:  "Body\n",
+		},
+		{
+			name:  "toml",
+			src:   "+++\ntitle = \"Hello\" # comment\ntags = [\n  \"go\",\n  \"web\"\n]\ndate = 2024-02-03T10:00:00Z\n+++\nBody\n",
+			title: "Hello",
+			tags:  []string{"go", "web"},
+			date:  time.Date(2024, time.February, 3, 10, 0, 0, 0, time.UTC),
+			body:  "Body\n",
+		},
+		{
+			name: "none",
+			src:  "Just text\n",
+			body: "Just text\n",
+		},
+	}
+
+	for _, c := range cases {
+		fm, body, err := splitFrontMatter([]byte(c.src))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if got := fm.String("title"); got != c.title {
+			t.Errorf("%s: expected title %q, got %q", c.name, c.title, got)
+		}
+		if got := fm.Strings("tags"); !reflect.DeepEqual(got, c.tags) {
+			t.Errorf("%s: expected tags %v, got %v", c.name, c.tags, got)
+		}
+		if got := fm.Time("date"); !got.Equal(c.date) {
+			t.Errorf("%s: expected date %v, got %v", c.name, c.date, got)
+		}
+		if body != c.body {
+			t.Errorf("%s: expected body %q, got %q", c.name, c.body, body)
+		}
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	src := `title = "a \" # b" # comment
+matrix = [[1, 2], [3]]
+author = { name = "Ann", links = ["x"] }
+summary = """
+First line
+Second line"""
+day = 2024-02-03
+
+[[menu.main]]
+name = "Home"
+
+[[menu.main]]
+name = "Blog"
+`
+
+	fm, err := parseTOML(src)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if got := fm.String("title"); got != `a " # b` {
+		t.Errorf("expected title with quote and hash, got %q", got)
+	}
+	matrix := []any{[]any{1, 2}, []any{3}}
+	if got := fm["matrix"]; !reflect.DeepEqual(got, matrix) {
+		t.Errorf("expected matrix %v, got %#v", matrix, got)
+	}
+	if got := fm.Map("author"); got.String("name") != "Ann" || !reflect.DeepEqual(got.Strings("links"), []string{"x"}) {
+		t.Errorf("expected inline table author, got %#v", got)
+	}
+	if got := fm.String("summary"); got != "First line\nSecond line" {
+		t.Errorf("expected multi-line summary, got %q", got)
+	}
+	if got := fm.Time("day"); !got.Equal(time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected local date in UTC, got %v", got)
+	}
+	main, _ := fm.Map("menu")["main"].([]any)
+	if len(main) != 2 || main[1].(map[string]any)["name"] != "Blog" {
+		t.Errorf("expected an array of two menu tables, got %#v", fm["menu"])
+	}
+
+	if _, err := parseTOML("title = \"unterminated\n"); err == nil {
+		t.Error("expected an error for an unterminated string")
+	}
+}
+
+func TestReadHugo(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"hugo.toml":                   "[permalinks]\n  blog = \"/:year/:month/:slug/\"\n",
+		"content/blog/_index.md":      "---\ntitle: The Blog\n---\n",
+		"content/blog/first.md":       "---\ntitle: First\ndate: 2023-04-05\ncategories: [Dev]\naliases: [/old/first/]\n---\nHello\n",
+		"content/blog/first.es.md":    "---\ntitle: Primero\ndate: 2023-04-05\n---\nHola\n",
+		"content/blog/trip/index.md":  "---\ntitle: Trip\ndate: 2023-05-01\ndraft: true\n---\n![pic](photo.jpg)\n",
+		"content/blog/trip/photo.jpg": "jpg",
+		"static/img/logo.png":         "png",
+	})
+
+	src, err := ReadHugo(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := map[string]ImportItem{}
+	for _, item := range src.Items {
+		items[item.Source] = item
+	}
+
+	first := items["blog/first.md"]
+	if first.SectionPath != "/blog" || first.SectionName != "The Blog" {
+		t.Errorf("expected section /blog named The Blog, got %q named %q", first.SectionPath, first.SectionName)
+	}
+	if want := []string{"/2023/04/first/", "/old/first/"}; !reflect.DeepEqual(first.URLs, want) {
+		t.Errorf("expected URLs %v, got %v", want, first.URLs)
+	}
+	if es := items["blog/first.es.md"]; es.Lang != "es" || es.TranslationKey != first.TranslationKey {
+		t.Errorf("expected es translation of %q, got lang %q key %q", first.TranslationKey, es.Lang, es.TranslationKey)
+	}
+
+	trip := items["blog/trip/index.md"]
+	if !trip.Draft || trip.Slug != "trip" || !strings.Contains(trip.Body, "](/2023/05/trip/photo.jpg)") {
+		t.Errorf("expected draft bundle with rewritten resource, got %+v", trip)
+	}
+
+	var media []string
+	for _, m := range src.Media {
+		media = append(media, m.Path)
+	}
+	if want := []string{"/2023/05/trip/photo.jpg", "/img/logo.png"}; !reflect.DeepEqual(media, want) {
+		t.Errorf("expected media %v, got %v", want, media)
+	}
+}
+
+func TestReadJekyll(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"_config.yml":                  "permalink: pretty\n",
+		"_posts/2022-01-02-hello.md":   "---\ntitle: Hello\ncategories: news\ntags: [intro]\nredirect_from: /hello.html\n---\nHi\n",
+		"_posts/notes.md":              "---\ntitle: Notes\n---\nx\n",
+		"_drafts/wip.md":               "---\ntitle: WIP\n---\nx\n",
+		"_layouts/default.html":        "{{ content }}",
+		"contact.md":                   "---\ntitle: Contact\n---\nMail\n",
+		"assets/site.css":              "css",
+		"Gemfile":                      "gem",
+		"index.md":                     "---\ntitle: Home\n---\n",
+		"_posts/2022-02-03-liquid.md":  "---\ntitle: Liquid\n---\n{% include x.html %}\n",
+		"_posts/2022-03-04-hidden.md":  "---\ntitle: Hidden\npublished: false\n---\nx\n",
+		"_posts/2022-04-05-custom.md":  "---\ntitle: Custom\npermalink: /custom/\n---\nx\n",
+		"about/index.markdown":         "---\ntitle: About\n---\nUs\n",
+		"_sass/main.scss":              "body {}",
+		"node_modules/pkg/index.js":    "js",
+		"vendor/bundle/gems/readme.md": "---\ntitle: Vendored\n---\n",
+	})
+
+	src, err := ReadJekyll(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	urls := map[string][]string{}
+	drafts := map[string]bool{}
+	for _, item := range src.Items {
+		urls[item.Source] = item.URLs
+		drafts[item.Source] = item.Draft
+	}
+
+	want := map[string][]string{
+		"_posts/2022-01-02-hello.md":  {"/news/2022/01/02/hello/", "/hello.html"},
+		"_posts/2022-02-03-liquid.md": {"/2022/02/03/liquid/"},
+		"_posts/2022-03-04-hidden.md": {"/2022/03/04/hidden/"},
+		"_posts/2022-04-05-custom.md": {"/custom/"},
+		"_drafts/wip.md":              nil,
+		"contact.md":                  {"/contact/"},
+		"about/index.markdown":        {"/about/"},
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("expected URLs %v, got %v", want, urls)
+	}
+	if !drafts["_drafts/wip.md"] || !drafts["_posts/2022-03-04-hidden.md"] || drafts["contact.md"] {
+		t.Errorf("unexpected drafts %v", drafts)
+	}
+	if len(src.Media) != 1 || src.Media[0].Path != "/assets/site.css" {
+		t.Errorf("expected only /assets/site.css as media, got %v", src.Media)
+	}
+	// Misnamed post, home page and Liquid tags.
+	if len(src.Warnings) != 3 {
+		t.Errorf("expected 3 warnings, got %v", src.Warnings)
+	}
+}
+
+func TestReadWordPress(t *testing.T) {
+	export := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<link>https://wp.example.com</link>
+	<wp:base_site_url>https://wp.example.com</wp:base_site_url>
+	<item>
+		<title>Post</title>
+		<link>https://wp.example.com/2021/03/post/</link>
+		<content:encoded><![CDATA[<a href="https://wp.example.com/about/">About</a>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Excerpt]]></excerpt:encoded>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_date>2021-03-04 05:06:07</wp:post_date>
+		<wp:post_name>post</wp:post_name>
+		<wp:status>publish</wp:status>
+		<wp:post_type>post</wp:post_type>
+		<category domain="category" nicename="news"><![CDATA[News]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+	</item>
+	<item>
+		<title>About</title>
+		<link>https://wp.example.com/?page_id=11</link>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_name>about</wp:post_name>
+		<wp:status>draft</wp:status>
+		<wp:post_type>page</wp:post_type>
+	</item>
+	<item>
+		<title>Trashed</title>
+		<wp:post_id>12</wp:post_id>
+		<wp:status>trash</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+	<item>
+		<title>Image</title>
+		<wp:post_id>13</wp:post_id>
+		<wp:status>inherit</wp:status>
+		<wp:post_type>attachment</wp:post_type>
+		<wp:attachment_url>https://wp.example.com/wp-content/uploads/a.png</wp:attachment_url>
+	</item>
+</channel>
+</rss>`
+
+	src, err := ReadWordPress(strings.NewReader(export), "export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(src.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(src.Items))
+	}
+
+	post := src.Items[0]
+	if post.Body != `<a href="/about/">About</a>` {
+		t.Errorf("expected site links made relative, got %q", post.Body)
+	}
+	if post.SectionPath != postsSectionPath || post.Draft || !post.Date.Equal(time.Date(2021, time.March, 4, 5, 6, 7, 0, time.UTC)) {
+		t.Errorf("unexpected post %+v", post)
+	}
+	if !reflect.DeepEqual(post.Categories, []string{"News"}) || !reflect.DeepEqual(post.Tags, []string{"Go"}) {
+		t.Errorf("expected News category and Go tag, got %v and %v", post.Categories, post.Tags)
+	}
+	if !reflect.DeepEqual(post.URLs, []string{"/2021/03/post/"}) {
+		t.Errorf("expected original URL kept, got %v", post.URLs)
+	}
+
+	page := src.Items[1]
+	if page.SectionPath != "/" || !page.Draft || len(page.URLs) != 0 {
+		t.Errorf("expected draft root page without redirects, got %+v", page)
+	}
+
+	if len(src.Media) != 1 || src.Media[0].Path != "/wp-content/uploads/a.png" {
+		t.Errorf("expected attachment at its original path, got %v", src.Media)
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(file), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(file, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// importRepo stores what an import creates in memory and records how its
+// transaction ends and when webhooks are looked up for an event.
+type importRepo struct {
+	Repo
+	sections  []Section
+	contents  []Content
+	fail      string // Heading of the content whose creation fails
+	committed bool
+	lookups   []bool // Whether the import was committed at each webhook lookup
+}
+
+type importTx struct{ repo *importRepo }
+
+func (tx importTx) Commit() error {
+	tx.repo.committed = true
+	return nil
+}
+
+func (tx importTx) Rollback() error { return nil }
+
+func (r *importRepo) BeginTx(ctx context.Context) (context.Context, am.Tx, error) {
+	return ctx, importTx{repo: r}, nil
+}
+
+func (r *importRepo) GetSections(ctx context.Context) ([]Section, error) {
+	return r.sections, nil
+}
+
+func (r *importRepo) CreateSection(ctx context.Context, section Section) error {
+	r.sections = append(r.sections, section)
+	return nil
+}
+
+func (r *importRepo) GetAllLayouts(ctx context.Context) ([]Layout, error) {
+	return nil, nil
+}
+
+func (r *importRepo) GetAllContent(ctx context.Context) ([]Content, error) {
+	return r.contents, nil
+}
+
+func (r *importRepo) CreateContent(ctx context.Context, content Content) error {
+	if content.Heading == r.fail {
+		return errors.New("disk full")
+	}
+	r.contents = append(r.contents, content)
+	return nil
+}
+
+func (r *importRepo) GetAllRedirects(ctx context.Context) ([]Redirect, error) {
+	return nil, nil
+}
+
+func (r *importRepo) CreateRedirect(ctx context.Context, redirect Redirect) error {
+	return nil
+}
+
+func (r *importRepo) GetTerms(ctx context.Context) ([]Term, error) {
+	return nil, nil
+}
+
+func (r *importRepo) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	r.lookups = append(r.lookups, r.committed)
+	return nil, nil
+}
+
+func TestImportSite(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	src := ImportSource{Format: ImportHugo, Items: []ImportItem{
+		{Source: "a.md", Title: "First", Date: date, SectionPath: "/posts", SectionName: "Posts", URLs: []string{"/2020/first/"}},
+		{Source: "b.md", Title: "Second", Draft: true, SectionPath: "/posts", SectionName: "Posts"},
+	}}
+
+	newService := func(repo *importRepo) *BaseService {
+		cfg := am.NewConfig()
+		cfg.SetValues(map[string]string{am.Key.SSGReportsDir: t.TempDir()})
+		gen := NewGenerator(embed.FS{}, repo, am.WithCfg(cfg))
+		return &BaseService{
+			Service:    am.NewService("ssg-service", am.WithCfg(cfg), am.WithLog(am.NewLogger("error"))),
+			repo:       repo,
+			gen:        gen,
+			dispatcher: NewDispatcher(repo, am.WithCfg(cfg)),
+		}
+	}
+
+	t.Run("failure is rolled back", func(t *testing.T) {
+		repo := &importRepo{fail: "Second"}
+		if _, err := newService(repo).ImportSite(ctx, src, uuid.Nil, ImportOptions{NoMedia: true}); err == nil {
+			t.Fatal("ImportSite() error = nil, want the failure of the second content")
+		}
+		if repo.committed || len(repo.lookups) != 0 {
+			t.Errorf("failed import committed = %t with %d events dispatched", repo.committed, len(repo.lookups))
+		}
+	})
+
+	t.Run("events are dispatched after commit", func(t *testing.T) {
+		repo := &importRepo{}
+		report, err := newService(repo).ImportSite(ctx, src, uuid.Nil, ImportOptions{NoMedia: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Contents != 2 || !repo.committed {
+			t.Fatalf("imported %d contents, committed = %t", report.Contents, repo.committed)
+		}
+		// Created and published for the first content, created for the draft.
+		if want := []bool{true, true, true}; !reflect.DeepEqual(repo.lookups, want) {
+			t.Errorf("webhook lookups at commit state %v, want %v", repo.lookups, want)
+		}
+		if got := repo.contents[0].PublishedAt; !got.Equal(date) {
+			t.Errorf("PublishedAt = %v, want the original date %v", got, date)
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package ssg
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var hugoConfigFiles = []string{"hugo.toml", "hugo.yaml", "hugo.yml", "config.toml", "config.yaml", "config.yml"}
+
+// ReadHugo reads the content and static dirs of a Hugo site. Sections are
+// the top level dirs of content, leaf bundles keep their resources and the
+// permalinks configured for each section are used to work out the URLs
+// pages were served at.
+func ReadHugo(root string) (ImportSource, error) {
+	src := ImportSource{Format: ImportHugo, Path: root}
+
+	contentDir := filepath.Join(root, "content")
+	info, err := os.Stat(contentDir)
+	if err != nil || !info.IsDir() {
+		return src, fmt.Errorf("%s is not a Hugo site: no content dir", root)
+	}
+
+	permalinks, err := readHugoPermalinks(root)
+	if err != nil {
+		src.warn("Cannot read site config, default permalinks assumed: %v", err)
+	}
+
+	// Section titles come from _index files, which are otherwise skipped.
+	titles := map[string]string{}
+	var files []string
+	err = filepath.WalkDir(contentDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, _ := filepath.Rel(contentDir, p)
+		rel = filepath.ToSlash(rel)
+		if !isContentFile(rel) {
+			if !inLeafBundle(contentDir, rel) {
+				src.Media = append(src.Media, ImportMedia{Source: p, Path: "/" + rel})
+			}
+			return nil
+		}
+		if strings.HasPrefix(path.Base(rel), "_index.") {
+			fm, _, err := readFrontMatter(p)
+			if err == nil && fm.String("title") != "" {
+				titles[path.Dir(rel)] = fm.String("title")
+			}
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return src, err
+	}
+
+	for _, rel := range files {
+		item, media, err := readHugoPage(contentDir, rel, permalinks)
+		if err != nil {
+			src.warn("Cannot read %s: %v", rel, err)
+			continue
+		}
+		if name, ok := titles[strings.TrimPrefix(item.SectionPath, "/")]; ok {
+			item.SectionName = name
+		}
+		if hasShortcodes(item.Body) {
+			src.warn("%s uses shortcodes, they were left as they are", rel)
+		}
+		src.Items = append(src.Items, item)
+		src.Media = append(src.Media, media...)
+	}
+
+	staticMedia, err := readStaticDir(filepath.Join(root, "static"))
+	if err != nil {
+		return src, err
+	}
+	src.Media = append(src.Media, staticMedia...)
+
+	return src, nil
+}
+
+func readHugoPage(contentDir, rel string, permalinks map[string]string) (ImportItem, []ImportMedia, error) {
+	file := filepath.Join(contentDir, filepath.FromSlash(rel))
+	fm, body, err := readFrontMatter(file)
+	if err != nil {
+		return ImportItem{}, nil, err
+	}
+
+	dir := path.Dir(rel)
+	name, lang := splitLangSuffix(strings.TrimSuffix(path.Base(rel), path.Ext(rel)))
+	bundle := name == "index"
+	if bundle {
+		name = path.Base(dir)
+		dir = path.Dir(dir)
+	}
+
+	section := ""
+	if dir != "." {
+		section = strings.SplitN(dir, "/", 2)[0]
+	}
+
+	item := ImportItem{
+		Source:         rel,
+		Title:          fm.String("title", "linkTitle"),
+		Slug:           fm.String("slug"),
+		Body:           body,
+		Lang:           fm.String("lang", "language"),
+		Draft:          fm.Bool("draft", false),
+		Date:           fm.Time("date", "publishDate", "pubdate", "published"),
+		SectionPath:    "/" + section,
+		SectionName:    sectionName(section),
+		Categories:     fm.Strings("categories"),
+		Tags:           fm.Strings("tags"),
+		TranslationKey: path.Join(dir, name),
+	}
+	if item.Title == "" {
+		item.Title = strings.ReplaceAll(name, "-", " ")
+	}
+	if item.Slug == "" {
+		item.Slug = name
+	}
+	if item.Lang == "" {
+		item.Lang = lang
+	}
+
+	pageURL := fm.String("url")
+	if pageURL == "" {
+		pageURL = hugoURL(permalinks[section], dir, section, name, item)
+	}
+	item.URLs = append(item.URLs, pageURL)
+	pageDir := pageURL
+	if !strings.HasSuffix(pageDir, "/") {
+		pageDir = path.Dir(pageDir) + "/"
+	}
+	for _, alias := range fm.Strings("aliases") {
+		if !strings.HasPrefix(alias, "/") {
+			alias = pageDir + alias
+		}
+		item.URLs = append(item.URLs, alias)
+	}
+
+	if !bundle {
+		return item, nil, nil
+	}
+
+	// Bundle resources are referenced relative to the page. They are kept at
+	// the original page path and references to them made absolute so they
+	// still resolve from the new URL.
+	var media []ImportMedia
+	bundleDir := filepath.Dir(file)
+	entries, err := os.ReadDir(bundleDir)
+	if err != nil {
+		return item, nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || isContentFile(e.Name()) {
+			continue
+		}
+		target := pageDir + e.Name()
+		media = append(media, ImportMedia{Source: filepath.Join(bundleDir, e.Name()), Path: target})
+		item.Body = strings.NewReplacer(
+			"]("+e.Name(), "]("+target,
+			`"`+e.Name()+`"`, `"`+target+`"`,
+		).Replace(item.Body)
+	}
+	return item, media, nil
+}
+
+// hugoURL expands a Hugo permalink pattern, falling back to Hugo's default of
+// serving pages at their content path.
+func hugoURL(pattern, dir, section, name string, item ImportItem) string {
+	if pattern == "" {
+		return strings.ToLower(path.Join("/", dir, item.Slug) + "/")
+	}
+
+	date := item.Date
+	// Longer tokens go first so they are not taken for their prefixes.
+	r := strings.NewReplacer(
+		":monthname", strings.ToLower(date.Format("January")),
+		":month", date.Format("01"),
+		":year", date.Format("2006"),
+		":day", date.Format("02"),
+		":sections", dir,
+		":section", section,
+		":title", Slugify(item.Title),
+		":slug", item.Slug,
+		":filename", name,
+		":contentbasename", name,
+	)
+	p := r.Replace(pattern)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// readHugoPermalinks returns the permalink pattern of each section from the
+// site config.
+func readHugoPermalinks(root string) (map[string]string, error) {
+	permalinks := map[string]string{}
+	for _, name := range hugoConfigFiles {
+		b, err := os.ReadFile(filepath.Join(root, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return permalinks, err
+		}
+
+		cfg := frontMatter{}
+		if strings.HasSuffix(name, ".toml") {
+			cfg, err = parseTOML(string(b))
+		} else {
+			err = yaml.Unmarshal(b, &cfg)
+		}
+		if err != nil {
+			return permalinks, fmt.Errorf("%s: %w", name, err)
+		}
+
+		links := cfg.Map("permalinks")
+		// Newer versions nest the patterns by page kind.
+		if page := links.Map("page"); page != nil {
+			links = page
+		}
+		for section := range links {
+			if pattern := links.String(section); pattern != "" {
+				permalinks[section] = pattern
+			}
+		}
+		return permalinks, nil
+	}
+	return permalinks, nil
+}
+
+// readStaticDir lists the files of a dir served as they are at the site root.
+func readStaticDir(dir string) ([]ImportMedia, error) {
+	var media []ImportMedia
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && p == dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		media = append(media, ImportMedia{Source: p, Path: "/" + filepath.ToSlash(rel)})
+		return nil
+	})
+	return media, err
+}
+
+func readFrontMatter(file string) (frontMatter, string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, "", err
+	}
+	fm, body, err := splitFrontMatter(b)
+	if fm == nil {
+		fm = frontMatter{}
+	}
+	return fm, body, err
+}
+
+func isContentFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// inLeafBundle reports whether a file sits next to an index page, in which
+// case it is imported as a resource of that page.
+func inLeafBundle(contentDir, rel string) bool {
+	matches, _ := filepath.Glob(filepath.Join(contentDir, filepath.FromSlash(path.Dir(rel)), "index.*"))
+	for _, m := range matches {
+		if isContentFile(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLangSuffix splits the language of a Hugo translation file name, as in
+// about.es for the Spanish version of about.
+func splitLangSuffix(name string) (string, string) {
+	base, lang, ok := strings.Cut(name, ".")
+	if !ok || len(lang) != 2 {
+		return name, ""
+	}
+	return base, strings.ToLower(lang)
+}
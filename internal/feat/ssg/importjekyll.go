@@ -0,0 +1,278 @@
+package ssg
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	jekyllPostsDir  = "_posts"
+	jekyllDraftsDir = "_drafts"
+)
+
+var (
+	jekyllPostName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+	// Built in permalink styles.
+	jekyllStyles = map[string]string{
+		"date":    "/:categories/:year/:month/:day/:title:output_ext",
+		"pretty":  "/:categories/:year/:month/:day/:title/",
+		"ordinal": "/:categories/:year/:y_day/:title:output_ext",
+		"none":    "/:categories/:title:output_ext",
+	}
+
+	// Site files that are not served.
+	jekyllIgnored = []string{"Gemfile", "Gemfile.lock", "README.md", "LICENSE", "CNAME", "package.json", "package-lock.json"}
+
+	// Files at the site root copied as they are.
+	jekyllRootAssets = []string{".ico", ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".txt", ".pdf", ".css", ".js"}
+)
+
+// ReadJekyll reads the posts, drafts, pages and assets of a Jekyll site.
+// Posts go to the posts section and pages to the root one. The permalink
+// style of the site config is used to work out the URLs they were served at.
+func ReadJekyll(root string) (ImportSource, error) {
+	src := ImportSource{Format: ImportJekyll, Path: root}
+
+	info, err := os.Stat(filepath.Join(root, jekyllPostsDir))
+	if err != nil || !info.IsDir() {
+		return src, fmt.Errorf("%s is not a Jekyll site: no %s dir", root, jekyllPostsDir)
+	}
+
+	permalink, err := readJekyllPermalink(root)
+	if err != nil {
+		src.warn("Cannot read site config, default permalinks assumed: %v", err)
+	}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		name := d.Name()
+
+		if d.IsDir() {
+			switch {
+			case rel == ".":
+				return nil
+			case name == jekyllPostsDir || name == jekyllDraftsDir:
+				src.readJekyllPosts(p, name == jekyllDraftsDir, permalink)
+				return filepath.SkipDir
+			case strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || contains(jekyllIgnored, rel) {
+			return nil
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		switch {
+		case isContentFile(name):
+			src.readJekyllPage(p, rel, permalink)
+		case ext == ".html" && hasFrontMatter(p):
+			src.warn("%s is a template page, it was not imported", rel)
+		case ext == ".scss" || ext == ".sass":
+			src.warn("%s must be compiled, it was not imported", rel)
+		case !strings.Contains(rel, "/") && !contains(jekyllRootAssets, ext):
+			// Site files such as configs and build scripts.
+		default:
+			src.Media = append(src.Media, ImportMedia{Source: p, Path: "/" + rel})
+		}
+		return nil
+	})
+	return src, err
+}
+
+func (src *ImportSource) readJekyllPosts(dir string, drafts bool, permalink string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		src.warn("Cannot read %s: %v", dir, err)
+		return
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !isContentFile(e.Name()) {
+			continue
+		}
+		rel := path.Join(path.Base(dir), e.Name())
+		fm, body, err := readFrontMatter(filepath.Join(dir, e.Name()))
+		if err != nil {
+			src.warn("Cannot read %s: %v", rel, err)
+			continue
+		}
+
+		name := strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+		var date time.Time
+		if m := jekyllPostName.FindStringSubmatch(name); m != nil {
+			date = parseDate(m[1])
+			name = m[2]
+		} else if !drafts {
+			src.warn("%s is not named YYYY-MM-DD-title, it was not imported", rel)
+			continue
+		}
+		if d := fm.Time("date"); !d.IsZero() {
+			date = d
+		}
+
+		item := src.jekyllItem(rel, name, fm, body)
+		item.Date = date
+		item.Draft = drafts || !fm.Bool("published", true)
+		item.SectionPath = postsSectionPath
+		item.SectionName = sectionName(postsSectionPath)
+
+		// Drafts were never served, so they have no URL to keep.
+		if !drafts {
+			pattern := fm.String("permalink")
+			if pattern == "" {
+				pattern = permalink
+			}
+			item.URLs = append([]string{jekyllURL(pattern, name, item)}, item.URLs...)
+		}
+		src.Items = append(src.Items, item)
+	}
+}
+
+func (src *ImportSource) readJekyllPage(file, rel, permalink string) {
+	if !hasFrontMatter(file) {
+		src.warn("%s has no front matter, Jekyll did not publish it", rel)
+		return
+	}
+	fm, body, err := readFrontMatter(file)
+	if err != nil {
+		src.warn("Cannot read %s: %v", rel, err)
+		return
+	}
+
+	name := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	if name == "index" && path.Dir(rel) == "." {
+		src.warn("%s is the home page, it was not imported", rel)
+		return
+	}
+
+	item := src.jekyllItem(rel, name, fm, body)
+	item.Draft = !fm.Bool("published", true)
+	item.SectionPath = "/"
+	item.SectionName = sectionName("/")
+
+	pageURL := fm.String("permalink")
+	if pageURL == "" {
+		dir := path.Dir(rel)
+		if name == "index" {
+			name, dir = path.Base(dir), path.Dir(dir)
+		}
+		if style, ok := jekyllStyles[permalink]; ok {
+			permalink = style
+		}
+		pageURL = path.Join("/", dir, name)
+		if strings.HasSuffix(permalink, "/") {
+			pageURL += "/"
+		} else {
+			pageURL += ".html"
+		}
+	}
+	item.URLs = append([]string{pageURL}, item.URLs...)
+	src.Items = append(src.Items, item)
+}
+
+// jekyllItem maps the front matter shared by posts and pages.
+func (src *ImportSource) jekyllItem(rel, name string, fm frontMatter, body string) ImportItem {
+	item := ImportItem{
+		Source:         rel,
+		Title:          fm.String("title"),
+		Slug:           fm.String("slug"),
+		Body:           body,
+		Lang:           fm.String("lang"),
+		Categories:     fm.Strings("categories", "category"),
+		Tags:           fm.Strings("tags", "tag"),
+		URLs:           fm.Strings("redirect_from"),
+		TranslationKey: rel,
+	}
+	if item.Title == "" {
+		item.Title = strings.ReplaceAll(name, "-", " ")
+	}
+	if item.Slug == "" {
+		item.Slug = name
+	}
+	if hasShortcodes(body) {
+		src.warn("%s uses Liquid tags, they were left as they are", rel)
+	}
+	return item
+}
+
+// jekyllURL expands a Jekyll permalink pattern or style for a post.
+func jekyllURL(pattern, name string, item ImportItem) string {
+	if style, ok := jekyllStyles[pattern]; ok {
+		pattern = style
+	}
+
+	var cats []string
+	for _, c := range item.Categories {
+		cats = append(cats, Slugify(c))
+	}
+
+	date := item.Date
+	// Longer tokens go first so they are not taken for their prefixes.
+	r := strings.NewReplacer(
+		":categories", strings.Join(cats, "/"),
+		":output_ext", ".html",
+		":short_year", date.Format("06"),
+		":year", date.Format("2006"),
+		":i_month", date.Format("1"),
+		":short_month", date.Format("Jan"),
+		":month", date.Format("01"),
+		":i_day", date.Format("2"),
+		":y_day", date.Format("002"),
+		":day", date.Format("02"),
+		":title", name,
+		":slug", Slugify(item.Slug),
+	)
+	p := path.Clean("/" + r.Replace(pattern))
+	if strings.HasSuffix(pattern, "/") && p != "/" {
+		p += "/"
+	}
+	return p
+}
+
+// readJekyllPermalink returns the permalink style or pattern of the site.
+func readJekyllPermalink(root string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(root, "_config.yml"))
+	if os.IsNotExist(err) {
+		return "date", nil
+	}
+	if err != nil {
+		return "date", err
+	}
+
+	cfg := frontMatter{}
+	err = yaml.Unmarshal(b, &cfg)
+	if err != nil {
+		return "date", err
+	}
+	if p := cfg.String("permalink"); p != "" {
+		return p, nil
+	}
+	return "date", nil
+}
+
+func hasFrontMatter(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	head := make([]byte, 4)
+	n, _ := f.Read(head)
+	return string(head[:n]) == "---\n" || string(head[:n]) == "---\r"
+}
@@ -0,0 +1,144 @@
+package ssg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// wxr mirrors the parts of a WordPress eXtended RSS export that are
+// imported. Elements are matched by local name, except for the post content
+// which shares its name with the excerpt.
+type wxr struct {
+	Channel struct {
+		Link    string    `xml:"link"`
+		BaseURL string    `xml:"base_site_url"`
+		Items   []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title         string        `xml:"title"`
+	Link          string        `xml:"link"`
+	Content       string        `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID        string        `xml:"post_id"`
+	PostName      string        `xml:"post_name"`
+	PostType      string        `xml:"post_type"`
+	Status        string        `xml:"status"`
+	PostDate      string        `xml:"post_date"`
+	AttachmentURL string        `xml:"attachment_url"`
+	Categories    []wxrCategory `xml:"category"`
+}
+
+type wxrCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// ReadWordPress reads the posts, pages and attachments of a WordPress export.
+// Posts go to the posts section and pages to the root one. Attachments are
+// downloaded on import and kept at their original path.
+func ReadWordPress(r io.Reader, name string) (ImportSource, error) {
+	src := ImportSource{Format: ImportWordPress, Path: name}
+
+	var doc wxr
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return src, fmt.Errorf("%s is not a WordPress export: %w", name, err)
+	}
+
+	base := strings.TrimSuffix(doc.Channel.BaseURL, "/")
+	if base == "" {
+		base = strings.TrimSuffix(doc.Channel.Link, "/")
+	}
+
+	for _, it := range doc.Channel.Items {
+		ref := fmt.Sprintf("%s #%s", it.PostType, it.PostID)
+
+		if it.PostType == "attachment" {
+			if p := urlPath(it.AttachmentURL); p != "" {
+				src.Media = append(src.Media, ImportMedia{Source: it.AttachmentURL, Path: p})
+			}
+			continue
+		}
+		if it.PostType != "post" && it.PostType != "page" {
+			continue
+		}
+
+		var draft bool
+		switch it.Status {
+		case "publish":
+		case "draft", "pending", "private", "future":
+			draft = true
+		default:
+			// Trashed posts, auto drafts and revisions.
+			continue
+		}
+
+		item := ImportItem{
+			Source:         ref,
+			Title:          strings.TrimSpace(it.Title),
+			Slug:           it.PostName,
+			Body:           rewriteSiteLinks(it.Content, base),
+			Draft:          draft,
+			Date:           parseDate(it.PostDate),
+			SectionPath:    "/",
+			TranslationKey: ref,
+		}
+		if it.PostType == "post" {
+			item.SectionPath = postsSectionPath
+		}
+		item.SectionName = sectionName(item.SectionPath)
+		if item.Slug == "" {
+			item.Slug = Slugify(item.Title)
+		}
+		if item.Title == "" {
+			item.Title = item.Slug
+		}
+
+		for _, c := range it.Categories {
+			switch c.Domain {
+			case "category":
+				item.Categories = append(item.Categories, strings.TrimSpace(c.Name))
+			case "post_tag":
+				item.Tags = append(item.Tags, strings.TrimSpace(c.Name))
+			}
+		}
+
+		if p := urlPath(it.Link); p != "" {
+			item.URLs = append(item.URLs, p)
+		} else if it.Link != "" && !draft {
+			src.warn("%s is served at %s, which cannot be redirected from a static site", ref, it.Link)
+		}
+		if hasShortcodes(item.Body) || strings.Contains(item.Body, "[/") {
+			src.warn("%s uses shortcodes, they were left as they are", ref)
+		}
+		src.Items = append(src.Items, item)
+	}
+
+	return src, nil
+}
+
+// rewriteSiteLinks makes links to the exported site relative to the root so
+// they keep working on the new host.
+func rewriteSiteLinks(body, base string) string {
+	if base == "" {
+		return body
+	}
+	u, err := url.Parse(base)
+	if err != nil || u.Host == "" {
+		return body
+	}
+
+	host := u.Host + strings.TrimSuffix(u.Path, "/")
+	var pairs []string
+	for _, scheme := range []string{"https://", "http://", "//"} {
+		for _, q := range []string{`"`, `'`, "("} {
+			pairs = append(pairs, q+scheme+host+"/", q+"/")
+		}
+	}
+	return strings.NewReplacer(pairs...).Replace(body)
+}
@@ -0,0 +1,150 @@
+package ssg
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defLang = "en"
+
+	// LangFallbackNone skips pages that have no translation in a language.
+	LangFallbackNone = "none"
+	// LangFallbackDefault publishes the default language version in place of
+	// a missing translation.
+	LangFallbackDefault = "default"
+)
+
+const (
+	TranslationCurrent = "current"
+	TranslationStale   = "stale"
+	TranslationMissing = "missing"
+)
+
+// Alternate is a link to the same piece of content in another language.
+type Alternate struct {
+	Lang string
+	URL  string
+}
+
+// TranslationCell describes the state of a translation group in one language.
+type TranslationCell struct {
+	Lang    string
+	Status  string
+	Content Content
+}
+
+// TranslationGroup holds all language versions of the same piece of content.
+// Source is the version translations are compared against: the one in the
+// default language or, if there is none, the oldest one.
+type TranslationGroup struct {
+	ID     uuid.UUID
+	Source Content
+	Cells  []TranslationCell
+}
+
+// Languages returns the configured site languages with the default one first.
+func Languages(cfg *am.Config) []string {
+	def := DefaultLang(cfg)
+	langs := []string{def}
+	raw := cfg.StrValOrDef(am.Key.SSGLanguages, def)
+	for _, l := range strings.Split(raw, ",") {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if l == "" || contains(langs, l) {
+			continue
+		}
+		langs = append(langs, l)
+	}
+	return langs
+}
+
+// DefaultLang returns the configured default language.
+func DefaultLang(cfg *am.Config) string {
+	return strings.ToLower(cfg.StrValOrDef(am.Key.SSGDefaultLang, defLang))
+}
+
+// LangFallback returns the configured policy for missing translations.
+func LangFallback(cfg *am.Config) string {
+	fallback := cfg.StrValOrDef(am.Key.SSGLangFallback, LangFallbackNone)
+	if fallback != LangFallbackDefault {
+		return LangFallbackNone
+	}
+	return fallback
+}
+
+// LangPrefix returns the URL prefix for a language. The default language is
+// published at the site root.
+func LangPrefix(lang, def string) string {
+	if lang == "" || lang == def {
+		return ""
+	}
+	return "/" + lang
+}
+
+// GroupTranslations arranges contents into translation groups and reports,
+// for every configured language, whether the translation is current, stale
+// (older than its source) or missing.
+func GroupTranslations(contents []Content, langs []string, def string) []TranslationGroup {
+	byGroup := make(map[uuid.UUID][]Content)
+	var order []uuid.UUID
+	for _, c := range contents {
+		key := c.TranslationKey()
+		if _, ok := byGroup[key]; !ok {
+			order = append(order, key)
+		}
+		byGroup[key] = append(byGroup[key], c)
+	}
+
+	groups := make([]TranslationGroup, 0, len(order))
+	for _, id := range order {
+		versions := byGroup[id]
+		source := translationSource(versions, def)
+
+		group := TranslationGroup{ID: id, Source: source}
+		for _, lang := range langs {
+			cell := TranslationCell{Lang: lang, Status: TranslationMissing}
+			for _, v := range versions {
+				if v.LangOr(def) != lang {
+					continue
+				}
+				cell.Content = v
+				cell.Status = TranslationCurrent
+				if v.ID() != source.ID() && v.UpdatedAt().Before(source.UpdatedAt()) {
+					cell.Status = TranslationStale
+				}
+				break
+			}
+			group.Cells = append(group.Cells, cell)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+func translationSource(versions []Content, def string) Content {
+	for _, v := range versions {
+		if v.LangOr(def) == def {
+			return v
+		}
+	}
+
+	sorted := make([]Content, len(versions))
+	copy(sorted, versions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt().Before(sorted[j].CreatedAt())
+	})
+	return sorted[0]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package ssg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestGroupTranslations(t *testing.T) {
+	now := time.Now()
+	groupID := uuid.New()
+
+	version := func(id uuid.UUID, lang string, updatedAt time.Time) Content {
+		return Content{
+			BaseModel: am.NewModel(
+				am.WithID(id),
+				am.WithCreatedAt(now.Add(-time.Hour)),
+				am.WithUpdatedAt(updatedAt),
+			),
+			Lang:          lang,
+			TranslationID: groupID,
+		}
+	}
+
+	en := version(groupID, "en", now)
+	es := version(uuid.New(), "es", now.Add(time.Minute))
+	de := version(uuid.New(), "de", now.Add(-time.Minute))
+	single := Content{BaseModel: am.NewModel(am.WithID(uuid.New()))}
+
+	groups := GroupTranslations([]Content{es, en, de, single}, []string{"en", "es", "de"}, "en")
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+
+	cases := []struct {
+		group  int
+		lang   string
+		status string
+	}{
+		{0, "en", TranslationCurrent},
+		{0, "es", TranslationCurrent},
+		{0, "de", TranslationStale},
+		{1, "en", TranslationCurrent},
+		{1, "es", TranslationMissing},
+		{1, "de", TranslationMissing},
+	}
+
+	for _, c := range cases {
+		var got string
+		for _, cell := range groups[c.group].Cells {
+			if cell.Lang == c.lang {
+				got = cell.Status
+			}
+		}
+		if got != c.status {
+			t.Errorf("group %d, lang %s: expected %q, got %q", c.group, c.lang, c.status, got)
+		}
+	}
+
+	if groups[0].Source.ID() != en.ID() {
+		t.Errorf("expected default language version as source, got %s", groups[0].Source.Lang)
+	}
+}
+
+func TestLangPrefix(t *testing.T) {
+	cases := []struct {
+		lang     string
+		expected string
+	}{
+		{"en", ""},
+		{"", ""},
+		{"es", "/es"},
+		{"de", "/de"},
+	}
+
+	for _, c := range cases {
+		if got := LangPrefix(c.lang, "en"); got != c.expected {
+			t.Errorf("lang %q: expected %q, got %q", c.lang, c.expected, got)
+		}
+	}
+}
@@ -15,7 +15,7 @@ type Layout struct {
 	*am.BaseModel
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	Code        string `json:"header"`
+	Code        string `json:"code"`
 }
 
 func Newlayout(name, description, path string, layoutID uuid.UUID) Layout {
@@ -9,6 +9,7 @@ import (
 type LayoutDA struct {
 	ID          uuid.UUID  `db:"id"`
 	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
 	Name        string     `db:"name"`
 	Description string     `db:"description"`
 	Code        string     `db:"code"`
@@ -0,0 +1,339 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+)
+
+const (
+	IssueBrokenLink    = "broken-link"
+	IssueMissingAnchor = "missing-anchor"
+	IssueMissingAsset  = "missing-asset"
+	IssueExternalLink  = "external-link"
+)
+
+// HTTPClient is the client used to check external links. *http.Client
+// satisfies it; tests can provide a stub.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// LinkIssue is a reference in a generated page that does not resolve.
+type LinkIssue struct {
+	Kind      string    `json:"kind"`
+	Page      string    `json:"page"`
+	ContentID uuid.UUID `json:"content_id"`
+	Ref       string    `json:"ref"`
+	Detail    string    `json:"detail"`
+}
+
+// HasContent reports whether the issue was found in a page rendered from a
+// content, as opposed to generated pages such as redirect stubs.
+func (i LinkIssue) HasContent() bool {
+	return i.ContentID != uuid.Nil
+}
+
+// LinkReport is the result of checking a generated site.
+type LinkReport struct {
+	CheckedAt time.Time   `json:"checked_at"`
+	Pages     int         `json:"pages"`
+	Refs      int         `json:"refs"`
+	External  bool        `json:"external"` // True if external links were checked
+	Issues    []LinkIssue `json:"issues"`
+}
+
+func (r LinkReport) HasIssues() bool {
+	return len(r.Issues) > 0
+}
+
+// LinkChecker validates the links, anchors and asset references of a
+// generated site against its output tree.
+type LinkChecker struct {
+	client HTTPClient
+}
+
+// NewLinkChecker returns a checker that uses client for external links. A nil
+// client disables external checks.
+func NewLinkChecker(client HTTPClient) *LinkChecker {
+	return &LinkChecker{client: client}
+}
+
+// ref is a reference found in a page. Assets are references whose target is
+// loaded by the page (images, scripts, stylesheets) rather than navigated to.
+type ref struct {
+	value string
+	asset bool
+}
+
+type parsedPage struct {
+	url  string
+	ids  map[string]bool
+	refs []ref
+}
+
+// Check parses every HTML file under dir and reports references that do not
+// resolve. origins maps page URLs to the content they were rendered from.
+// Paths covered by a wildcard redirect are considered valid.
+func (lc *LinkChecker) Check(ctx context.Context, dir string, origins map[string]uuid.UUID, redirects []Redirect) (LinkReport, error) {
+	report := LinkReport{
+		CheckedAt: time.Now(),
+		External:  lc.client != nil,
+		Issues:    []LinkIssue{},
+	}
+
+	pages, err := parsePages(dir)
+	if err != nil {
+		return report, err
+	}
+
+	byURL := make(map[string]parsedPage, len(pages))
+	for _, p := range pages {
+		byURL[p.url] = p
+	}
+
+	external := make(map[string]string)
+	for _, p := range pages {
+		report.Pages++
+		for _, r := range p.refs {
+			report.Refs++
+
+			issue := LinkIssue{Page: p.url, ContentID: origins[p.url], Ref: r.value}
+			target, err := url.Parse(r.value)
+			if err != nil {
+				issue.Kind, issue.Detail = IssueBrokenLink, "malformed URL"
+				report.Issues = append(report.Issues, issue)
+				continue
+			}
+
+			switch {
+			case target.Scheme == "http" || target.Scheme == "https":
+				if lc.client == nil {
+					continue
+				}
+				detail, ok := external[target.String()]
+				if !ok {
+					detail = lc.checkExternal(ctx, target.String())
+					external[target.String()] = detail
+				}
+				if detail != "" {
+					issue.Kind, issue.Detail = IssueExternalLink, detail
+					report.Issues = append(report.Issues, issue)
+				}
+
+			case target.Scheme != "" || target.Host != "":
+				// mailto:, tel:, data: and similar references are not checked.
+				continue
+
+			default:
+				kind, detail := checkInternal(dir, byURL, p.url, target, r.asset, redirects)
+				if kind != "" {
+					issue.Kind, issue.Detail = kind, detail
+					report.Issues = append(report.Issues, issue)
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// checkInternal resolves a site relative reference and returns the kind of
+// issue and a description if it does not resolve.
+func checkInternal(dir string, pages map[string]parsedPage, from string, target *url.URL, asset bool, redirects []Redirect) (kind, detail string) {
+	p := target.Path
+	if p == "" {
+		p = from
+	} else if !strings.HasPrefix(p, "/") {
+		base := from
+		if !strings.HasSuffix(base, "/") {
+			base = path.Dir(base)
+		}
+		p = path.Join(base, p)
+		if strings.HasSuffix(target.Path, "/") {
+			p += "/"
+		}
+	}
+
+	file, ok := resolveFile(dir, p)
+	if !ok {
+		if matchesWildcard(redirects, p) {
+			return "", ""
+		}
+		if asset {
+			return IssueMissingAsset, fmt.Sprintf("%s not found in output", p)
+		}
+		return IssueBrokenLink, fmt.Sprintf("%s not found in output", p)
+	}
+
+	if target.Fragment == "" || asset {
+		return "", ""
+	}
+
+	page, ok := pages[pageURL(dir, file)]
+	if !ok || !page.ids[target.Fragment] {
+		return IssueMissingAnchor, fmt.Sprintf("no element with id %q in %s", target.Fragment, p)
+	}
+	return "", ""
+}
+
+// resolveFile returns the output file a site path is served from, following
+// the usual static host rules for directory indexes.
+func resolveFile(dir, p string) (string, bool) {
+	file := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(p, "/")))
+	candidates := []string{file}
+	if strings.HasSuffix(p, "/") {
+		candidates = []string{filepath.Join(file, indexFile)}
+	} else if path.Ext(p) == "" {
+		candidates = append(candidates, filepath.Join(file, indexFile), file+".html")
+	}
+
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err == nil && !info.IsDir() {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func matchesWildcard(redirects []Redirect, p string) bool {
+	for _, r := range redirects {
+		if !r.Wildcard {
+			continue
+		}
+		prefix := strings.TrimSuffix(r.SourcePath, "/")
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (lc *LinkChecker) checkExternal(ctx context.Context, target string) string {
+	status, err := lc.request(ctx, http.MethodHead, target)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = lc.request(ctx, http.MethodGet, target)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if status >= http.StatusBadRequest {
+		return fmt.Sprintf("%d %s", status, http.StatusText(status))
+	}
+	return ""
+}
+
+func (lc *LinkChecker) request(ctx context.Context, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := lc.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return res.StatusCode, nil
+}
+
+// parsePages parses every HTML file under dir collecting element IDs and
+// references.
+func parsePages(dir string) ([]parsedPage, error) {
+	var pages []parsedPage
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(file) != ".html" {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		doc, err := html.Parse(f)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %w", file, err)
+		}
+
+		page := parsedPage{url: pageURL(dir, file), ids: make(map[string]bool)}
+		collect(doc, &page)
+		pages = append(pages, page)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].url < pages[j].url })
+	return pages, err
+}
+
+// pageURL returns the URL a generated file is served at.
+func pageURL(dir, file string) string {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return ""
+	}
+	u := "/" + filepath.ToSlash(rel)
+	if path.Base(u) == indexFile {
+		return strings.TrimSuffix(u, indexFile)
+	}
+	return u
+}
+
+func collect(n *html.Node, page *parsedPage) {
+	if n.Type == html.ElementNode {
+		for _, a := range n.Attr {
+			switch {
+			case a.Key == "id" || (a.Key == "name" && n.Data == "a"):
+				page.ids[a.Val] = true
+			case a.Key == "href" && (n.Data == "a" || n.Data == "area"):
+				page.refs = append(page.refs, ref{value: a.Val})
+			case a.Key == "href" && n.Data == "link":
+				page.refs = append(page.refs, ref{value: a.Val, asset: linkIsAsset(n)})
+			case a.Key == "src" && isAssetElement(n.Data):
+				page.refs = append(page.refs, ref{value: a.Val, asset: true})
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collect(c, page)
+	}
+}
+
+// linkIsAsset reports whether a link element loads a resource (stylesheets,
+// icons) rather than pointing to another page (alternates, canonical).
+func linkIsAsset(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if a.Key == "rel" {
+			return a.Val != "alternate" && a.Val != "canonical"
+		}
+	}
+	return true
+}
+
+func isAssetElement(name string) bool {
+	switch name {
+	case "img", "script", "source", "video", "audio", "iframe", "embed", "track":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package ssg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type stubClient map[string]int
+
+func (c stubClient) Do(req *http.Request) (*http.Response, error) {
+	status, ok := c[req.URL.String()]
+	if !ok {
+		status = http.StatusOK
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestLinkCheckerCheck(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html": `<a href="/blog/post/">post</a><a href="/missing/">missing</a>`,
+		"blog/post/index.html": `<h2 id="intro">Intro</h2>
+<a href="#intro">ok</a><a href="#nope">bad anchor</a>
+<a href="../other/">relative missing</a>
+<a href="/#top">bad anchor on other page</a>
+<img src="img/cover.png"><img src="/static/logo.png">
+<a href="/legacy/some/page">wildcard</a>
+<a href="mailto:me@example.com">mail</a>
+<a href="https://example.com/ok">ok</a><a href="https://example.com/gone">gone</a>`,
+		"blog/post/img/cover.png": "png",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	postID := uuid.New()
+	origins := map[string]uuid.UUID{"/blog/post/": postID}
+	redirects := []Redirect{{SourcePath: "/legacy", TargetPath: "/", StatusCode: 301, Wildcard: true}}
+	client := stubClient{"https://example.com/gone": http.StatusNotFound}
+
+	report, err := NewLinkChecker(client).Check(context.Background(), dir, origins, redirects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type key struct{ kind, page, ref string }
+	got := make(map[key]LinkIssue)
+	for _, issue := range report.Issues {
+		got[key{issue.Kind, issue.Page, issue.Ref}] = issue
+	}
+
+	expected := []key{
+		{IssueBrokenLink, "/", "/missing/"},
+		{IssueMissingAnchor, "/blog/post/", "#nope"},
+		{IssueBrokenLink, "/blog/post/", "../other/"},
+		{IssueMissingAnchor, "/blog/post/", "/#top"},
+		{IssueMissingAsset, "/blog/post/", "/static/logo.png"},
+		{IssueExternalLink, "/blog/post/", "https://example.com/gone"},
+	}
+	for _, k := range expected {
+		if _, ok := got[k]; !ok {
+			t.Errorf("expected issue %+v, got %+v", k, report.Issues)
+		}
+	}
+	if len(report.Issues) != len(expected) {
+		t.Errorf("expected %d issues, got %d: %+v", len(expected), len(report.Issues), report.Issues)
+	}
+
+	if issue := got[key{IssueMissingAsset, "/blog/post/", "/static/logo.png"}]; issue.ContentID != postID {
+		t.Errorf("expected issue to point to content %s, got %s", postID, issue.ContentID)
+	}
+	if report.Pages != 2 {
+		t.Errorf("expected 2 pages, got %d", report.Pages)
+	}
+}
@@ -0,0 +1,78 @@
+package ssg
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Listing is the list of pages of a generated archive page, such as the page
+// of an author or of a month, as layouts see it in .Listing. Archive pages
+// have no body of their own, their HTML is the listing.
+type Listing struct {
+	Title       string
+	Description string
+	Entries     []ListEntry // Newest first, the ones on this page only
+	FeedURL     string      // Atom feed of the listing, empty if it has none
+	Page        int         // Number of this page of the listing, from 1
+	Pages       int         // Number of pages of the listing
+	PrevURL     string      // Page with newer entries, empty on the first page
+	NextURL     string      // Page with older entries, empty on the last page
+}
+
+// ListEntry is a page in a listing or a feed.
+type ListEntry struct {
+	Content Content
+	URL     string
+	Authors []PageAuthor
+}
+
+// sortEntries orders entries newest first. Entries published at the same
+// time keep their order.
+func sortEntries(entries []ListEntry) {
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Content.PublishedAt.After(entries[b].Content.PublishedAt)
+	})
+}
+
+// listingHTML renders the default markup of a listing. Layouts that want
+// their own range over .Listing.Entries instead of rendering .HTML.
+func listingHTML(site SiteSettings, l Listing) template.HTML {
+	var b strings.Builder
+	esc := template.HTMLEscapeString
+
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(l.Title))
+	if l.Description != "" {
+		fmt.Fprintf(&b, "<p>%s</p>\n", esc(l.Description))
+	}
+
+	b.WriteString("<ul class=\"listing\">\n")
+	for _, e := range l.Entries {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a>", esc(e.URL), esc(e.Content.Heading))
+		if !e.Content.PublishedAt.IsZero() {
+			fmt.Fprintf(&b, " <time datetime=\"%s\">%s</time>",
+				e.Content.PublishedAt.Format(time.DateOnly), esc(site.FormatDate(e.Content.PublishedAt)))
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+
+	if l.Pages > 1 {
+		b.WriteString("<nav class=\"pager\">")
+		if l.PrevURL != "" {
+			fmt.Fprintf(&b, "<a href=\"%s\" rel=\"prev\">Newer</a> ", esc(l.PrevURL))
+		}
+		fmt.Fprintf(&b, "<span>Page %d of %d</span>", l.Page, l.Pages)
+		if l.NextURL != "" {
+			fmt.Fprintf(&b, " <a href=\"%s\" rel=\"next\">Older</a>", esc(l.NextURL))
+		}
+		b.WriteString("</nav>\n")
+	}
+
+	if l.FeedURL != "" {
+		fmt.Fprintf(&b, "<p><a href=\"%s\" type=\"application/atom+xml\">Feed</a></p>\n", esc(l.FeedURL))
+	}
+	return template.HTML(b.String())
+}
@@ -0,0 +1,141 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalPublisher keeps a directory in sync with the build output, for sites
+// served straight from the filesystem of the host. Only files it wrote are
+// deleted, as recorded in a manifest in the directory, and it refuses to
+// publish into a directory with other files but no manifest, which is most
+// likely a misconfigured target.
+type LocalPublisher struct {
+	dir string
+}
+
+func NewLocalPublisher(dir string) *LocalPublisher {
+	return &LocalPublisher{dir: dir}
+}
+
+func (p *LocalPublisher) Publish(ctx context.Context, dir string, build Build, author Author, dryRun bool) (PublishResult, error) {
+	result := PublishResult{DryRun: dryRun}
+
+	local, err := listFiles(dir)
+	if err != nil {
+		return result, err
+	}
+
+	manifest, err := p.readManifest()
+	if err != nil {
+		return result, err
+	}
+
+	remote, err := listFiles(p.dir)
+	if err != nil {
+		return result, err
+	}
+
+	// Files of others are left alone, the ones in the way of the output
+	// are overwritten.
+	owned := make(map[string]string, len(manifest.Files))
+	for name, sum := range remote {
+		_, written := manifest.Files[name]
+		_, generated := local[name]
+		if written || generated {
+			owned[name] = sum
+		}
+	}
+
+	result.Changes = diffFiles(local, owned)
+	if dryRun {
+		return result, nil
+	}
+
+	for _, c := range result.Changes {
+		err = ctx.Err()
+		if err != nil {
+			return result, err
+		}
+
+		target := filepath.Join(p.dir, filepath.FromSlash(c.Path))
+		if c.Op == ChangeDeleted {
+			err = os.Remove(target)
+			if err != nil {
+				return result, err
+			}
+			removeEmptyDirs(p.dir, filepath.Dir(target))
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0o755)
+		if err != nil {
+			return result, err
+		}
+		err = copyFile(filepath.Join(dir, filepath.FromSlash(c.Path)), target)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, p.writeManifest(publishManifest{Files: local})
+}
+
+// readManifest returns the manifest of the last publish to the directory, an
+// empty one if the directory is empty or does not exist yet.
+func (p *LocalPublisher) readManifest() (publishManifest, error) {
+	manifest := publishManifest{Files: map[string]string{}}
+
+	b, err := os.ReadFile(filepath.Join(p.dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		entries, err := os.ReadDir(p.dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return manifest, err
+		}
+		if len(entries) > 0 {
+			return manifest, fmt.Errorf("%w: %s", ErrTargetNotEmpty, p.dir)
+		}
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+
+	err = json.Unmarshal(b, &manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("cannot read publish manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest, nil
+}
+
+func (p *LocalPublisher) writeManifest(manifest publishManifest) error {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(p.dir, 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(p.dir, manifestFile), b, 0o644)
+}
+
+// removeEmptyDirs removes dir and its parents up to root while they are
+// empty.
+func removeEmptyDirs(root, dir string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
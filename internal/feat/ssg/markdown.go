@@ -0,0 +1,195 @@
+package ssg
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	defTOCMinDepth = 2
+	defTOCMaxDepth = 3
+)
+
+// md renders content bodies. Raw HTML is kept because content is authored by
+// site editors, who often need to embed snippets markdown cannot express.
+// Headings accept attributes so that authors can pin an anchor with {#id}.
+var md = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAttribute()),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// TOC is the table of contents of a content, as layouts see it in .TOC.
+type TOC struct {
+	Entries []TOCEntry    // Headings within the depth range, nested by level
+	HTML    template.HTML // Entries as nested lists, empty if there are none
+}
+
+// TOCEntry is a heading of a content along with the headings under it.
+type TOCEntry struct {
+	Level    int
+	ID       string // Anchor of the heading in the rendered body
+	Text     string
+	Children []TOCEntry
+}
+
+// RenderMarkdown converts a markdown source into HTML. Headings get anchor IDs.
+func RenderMarkdown(src string) (template.HTML, error) {
+	out, _, err := RenderMarkdownTOC(src, defTOCMinDepth, defTOCMaxDepth)
+	return out, err
+}
+
+// RenderMarkdownTOC converts a markdown source into HTML and returns the table
+// of contents of the headings from level minDepth to maxDepth.
+//
+// Every heading gets an anchor ID made from its text, numbered when the same
+// text appears more than once, so links to a heading keep working as long as
+// the headings above it with the same text do not change. IDs set by the
+// author are kept.
+func RenderMarkdownTOC(src string, minDepth, maxDepth int) (template.HTML, TOC, error) {
+	var toc TOC
+	source := []byte(src)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var headings []*ast.Heading
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering {
+			headings = append(headings, h)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return "", toc, err
+	}
+
+	// Author IDs are reserved first so that generated ones never take them.
+	used := make(map[string]bool)
+	for _, h := range headings {
+		if id, ok := headingID(h); ok {
+			used[id] = true
+		}
+	}
+
+	var flat []TOCEntry
+	for _, h := range headings {
+		label := strings.TrimSpace(nodeText(h, source))
+		id, ok := headingID(h)
+		if !ok {
+			id = uniqueAnchor(Slugify(label), used)
+			h.SetAttributeString("id", []byte(id))
+		}
+		if h.Level >= minDepth && h.Level <= maxDepth {
+			flat = append(flat, TOCEntry{Level: h.Level, ID: id, Text: label})
+		}
+	}
+
+	var buf bytes.Buffer
+	err = md.Renderer().Render(&buf, source, doc)
+	if err != nil {
+		return "", toc, err
+	}
+
+	toc.Entries = nestTOC(flat)
+	toc.HTML = tocHTML(toc.Entries)
+	return template.HTML(buf.String()), toc, nil
+}
+
+// headingID returns the ID the author set on a heading, if any.
+func headingID(h *ast.Heading) (string, bool) {
+	v, ok := h.AttributeString("id")
+	if !ok {
+		return "", false
+	}
+	id, ok := v.([]byte)
+	if !ok || len(id) == 0 {
+		return "", false
+	}
+	return string(id), true
+}
+
+// uniqueAnchor returns base, or base followed by the first free number if it
+// is already used, and marks it as used.
+func uniqueAnchor(base string, used map[string]bool) string {
+	if base == "" {
+		base = "section"
+	}
+	id := base
+	for i := 1; used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[id] = true
+	return id
+}
+
+// nodeText returns the plain text of an inline tree, without markup.
+func nodeText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		case *ast.RawHTML:
+			// Inline tags are markup, not text.
+		default:
+			b.WriteString(nodeText(c, source))
+		}
+	}
+	return b.String()
+}
+
+// nestTOC arranges headings under the closest previous heading of a lower
+// level. Skipped levels do not add empty entries.
+func nestTOC(flat []TOCEntry) []TOCEntry {
+	var entries []TOCEntry
+	for i := 0; i < len(flat); {
+		e := flat[i]
+		j := i + 1
+		for j < len(flat) && flat[j].Level > e.Level {
+			j++
+		}
+		e.Children = nestTOC(flat[i+1 : j])
+		entries = append(entries, e)
+		i = j
+	}
+	return entries
+}
+
+func tocHTML(entries []TOCEntry) template.HTML {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	var write func(entries []TOCEntry)
+	write = func(entries []TOCEntry) {
+		b.WriteString("<ul>")
+		for _, e := range entries {
+			fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, template.HTMLEscapeString(e.ID), template.HTMLEscapeString(e.Text))
+			if len(e.Children) > 0 {
+				write(e.Children)
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</ul>")
+	}
+
+	b.WriteString(`<nav class="toc">`)
+	write(entries)
+	b.WriteString("</nav>")
+	return template.HTML(b.String())
+}
@@ -0,0 +1,92 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownTOC(t *testing.T) {
+	src := `# Guide
+
+## Install
+
+### On Linux
+
+## Install
+
+#### Deep
+
+## Configure {#setup}
+
+## Setup
+
+### Ünïcode *and* ` + "`code`" + `
+`
+
+	out, toc, err := RenderMarkdownTOC(src, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<h1 id="guide">`,
+		`<h2 id="install">`,
+		`<h3 id="on-linux">`,
+		`<h2 id="install-1">`,
+		`<h4 id="deep">`,
+		`<h2 id="setup">Configure</h2>`,
+		`<h2 id="setup-1">Setup</h2>`,
+		`<h3 id="unicode-and-code">`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("HTML misses %s:\n%s", want, out)
+		}
+	}
+
+	var got []string
+	var walk func(entries []TOCEntry, indent string)
+	walk = func(entries []TOCEntry, indent string) {
+		for _, e := range entries {
+			got = append(got, indent+e.ID+" "+e.Text)
+			walk(e.Children, indent+"  ")
+		}
+	}
+	walk(toc.Entries, "")
+
+	want := []string{
+		"install Install",
+		"  on-linux On Linux",
+		"install-1 Install",
+		"setup Configure",
+		"setup-1 Setup",
+		"  unicode-and-code Ünïcode and code",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if !strings.HasPrefix(string(toc.HTML), `<nav class="toc"><ul><li><a href="#install">Install</a><ul><li><a href="#on-linux">`) {
+		t.Errorf("TOC HTML = %s", toc.HTML)
+	}
+}
+
+func TestNestTOCSkippedLevels(t *testing.T) {
+	entries := nestTOC([]TOCEntry{{Level: 3, ID: "a"}, {Level: 2, ID: "b"}, {Level: 4, ID: "c"}, {Level: 3, ID: "d"}})
+	if len(entries) != 2 || entries[0].ID != "a" || entries[1].ID != "b" {
+		t.Fatalf("top level = %+v", entries)
+	}
+	children := entries[1].Children
+	if len(children) != 2 || children[0].ID != "c" || children[1].ID != "d" {
+		t.Errorf("children of b = %+v", children)
+	}
+}
+
+func TestRenderMarkdownTOCEmpty(t *testing.T) {
+	_, toc, err := RenderMarkdownTOC("Just a paragraph.", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toc.Entries != nil || toc.HTML != "" {
+		t.Errorf("toc = %+v, want zero", toc)
+	}
+}
@@ -0,0 +1,275 @@
+package ssg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	menuType     = "menu"
+	menuItemType = "menu-item"
+)
+
+// Menu item kinds.
+const (
+	MenuItemSection = "section"
+	MenuItemContent = "content"
+	MenuItemURL     = "url"
+)
+
+var menuItemKinds = []string{MenuItemSection, MenuItemContent, MenuItemURL}
+
+// menuNames are the menus layouts commonly render. Others can be created too.
+var menuNames = []string{"main", "footer", "sidebar"}
+
+// Menu is a navigation menu of the generated site. Layouts render its items
+// as .Site.Menus.<name>.
+type Menu struct {
+	*am.BaseModel
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func NewMenu(name, description string) Menu {
+	return Menu{
+		BaseModel:   am.NewModel(am.WithType(menuType)),
+		Name:        name,
+		Description: description,
+	}
+}
+
+func (m Menu) IsZero() bool {
+	return m.BaseModel == nil || m.BaseModel.IsZero()
+}
+
+func (m *Menu) Slug() string {
+	return m.Name
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (m *Menu) UnmarshalJSON(data []byte) error {
+	type Alias Menu
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*m = Menu(*temp)
+	if m.BaseModel == nil {
+		m.BaseModel = am.NewModel(am.WithType(menuType))
+	}
+	return nil
+}
+
+// MenuItem is an entry of a menu. It points at a section, a content or an
+// external URL and may have items of its own.
+type MenuItem struct {
+	*am.BaseModel
+	MenuID   uuid.UUID `json:"menu_id"`
+	ParentID uuid.UUID `json:"parent_id"` // Nil for top level items
+	Label    string    `json:"label"`     // Defaults to the name of the section or the heading of the content
+	Kind     string    `json:"kind"`
+	TargetID uuid.UUID `json:"target_id"` // Section or content the item points at
+	URL      string    `json:"url"`       // For url items
+	Position int       `json:"position"`  // Order among the items of the same parent
+}
+
+func NewMenuItem(menuID uuid.UUID, label, kind string) MenuItem {
+	return MenuItem{
+		BaseModel: am.NewModel(am.WithType(menuItemType)),
+		MenuID:    menuID,
+		Label:     label,
+		Kind:      kind,
+	}
+}
+
+func (i MenuItem) IsZero() bool {
+	return i.BaseModel == nil || i.BaseModel.IsZero()
+}
+
+func (i *MenuItem) Slug() string {
+	return am.Normalize(i.Label) + "-" + i.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (i *MenuItem) UnmarshalJSON(data []byte) error {
+	type Alias MenuItem
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*i = MenuItem(*temp)
+	if i.BaseModel == nil {
+		i.BaseModel = am.NewModel(am.WithType(menuItemType))
+	}
+	return nil
+}
+
+// MenuItemNode is a menu item along with its items, as the admin shows them.
+type MenuItemNode struct {
+	MenuItem
+	Target   string // What the item points at
+	Children []MenuItemNode
+}
+
+// menuItemTree arranges the items of a menu under their parents, in order.
+// Items whose parent is missing are shown at the top level.
+func menuItemTree(items []MenuItem, target func(MenuItem) string) []MenuItemNode {
+	ids := make(map[uuid.UUID]bool, len(items))
+	for _, it := range items {
+		ids[it.ID()] = true
+	}
+
+	byParent := make(map[uuid.UUID][]MenuItem)
+	for _, it := range items {
+		parent := it.ParentID
+		if !ids[parent] {
+			parent = uuid.Nil
+		}
+		byParent[parent] = append(byParent[parent], it)
+	}
+
+	placed := make(map[uuid.UUID]bool, len(items))
+	var build func(parent uuid.UUID) []MenuItemNode
+	build = func(parent uuid.UUID) []MenuItemNode {
+		var nodes []MenuItemNode
+		for _, it := range byParent[parent] {
+			if placed[it.ID()] {
+				continue
+			}
+			placed[it.ID()] = true
+			nodes = append(nodes, MenuItemNode{
+				MenuItem: it,
+				Target:   target(it),
+				Children: build(it.ID()),
+			})
+		}
+		return nodes
+	}
+	return build(uuid.Nil)
+}
+
+// nextMenuPosition returns the position after the last item of a parent.
+func nextMenuPosition(items []MenuItem, parentID uuid.UUID) int {
+	next := 0
+	for _, it := range items {
+		if it.ParentID == parentID && it.Position >= next {
+			next = it.Position + 1
+		}
+	}
+	return next
+}
+
+// MenuMove is the place an item takes when a menu is reordered.
+type MenuMove struct {
+	ID       uuid.UUID
+	ParentID uuid.UUID
+	Position int
+}
+
+// checkMenuMoves checks that a reorder only moves items of the menu and
+// leaves them as a tree. Items not moved keep their parent.
+func checkMenuMoves(items []MenuItem, moves []MenuMove) error {
+	inMenu := make(map[uuid.UUID]bool, len(items))
+	parents := make(map[uuid.UUID]uuid.UUID, len(items))
+	for _, it := range items {
+		inMenu[it.ID()] = true
+		parents[it.ID()] = it.ParentID
+	}
+
+	for _, m := range moves {
+		if !inMenu[m.ID] {
+			return fmt.Errorf("%w: item %s is not in the menu", ErrInvalidMenuOrder, m.ID)
+		}
+		if m.ParentID != uuid.Nil && !inMenu[m.ParentID] {
+			return fmt.Errorf("%w: parent %s is not in the menu", ErrInvalidMenuOrder, m.ParentID)
+		}
+		parents[m.ID] = m.ParentID
+	}
+
+	for _, m := range moves {
+		seen := map[uuid.UUID]bool{m.ID: true}
+		for p := parents[m.ID]; p != uuid.Nil; p = parents[p] {
+			if seen[p] {
+				return fmt.Errorf("%w: item %s is nested in itself", ErrInvalidMenuOrder, m.ID)
+			}
+			seen[p] = true
+		}
+	}
+	return nil
+}
+
+// MenuEntry is a menu item as layouts see it in .Site.Menus.
+type MenuEntry struct {
+	Label       string
+	URL         string
+	External    bool        // True for items pointing outside the site
+	Active      bool        // True if the entry points at the page being rendered
+	ActiveTrail bool        // True if the entry or one of its entries is active, or the page is in the section it points at
+	Children    []MenuEntry // Nested entries
+	within      string      // URL prefix of the pages in the section the entry points at, empty for other entries
+}
+
+// menuLink resolves an item into an entry without children. ok is false if
+// the item has nothing to point at, for instance a content not published in
+// the language being rendered.
+type menuLink func(item MenuItem) (entry MenuEntry, ok bool)
+
+// siteMenus resolves the items of every menu into entries, by menu name.
+func siteMenus(menus []Menu, items []MenuItem, link menuLink) map[string][]MenuEntry {
+	byMenu := make(map[uuid.UUID][]MenuItem)
+	for _, it := range items {
+		byMenu[it.MenuID] = append(byMenu[it.MenuID], it)
+	}
+
+	result := make(map[string][]MenuEntry, len(menus))
+	for _, m := range menus {
+		nodes := menuItemTree(byMenu[m.ID()], func(MenuItem) string { return "" })
+		result[m.Name] = menuEntries(nodes, link)
+	}
+	return result
+}
+
+func menuEntries(nodes []MenuItemNode, link menuLink) []MenuEntry {
+	var entries []MenuEntry
+	for _, n := range nodes {
+		e, ok := link(n.MenuItem)
+		if !ok {
+			continue
+		}
+		e.Children = menuEntries(n.Children, link)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// activeMenus returns a copy of the menus with the entries leading to the
+// page at url marked as active.
+func activeMenus(menus map[string][]MenuEntry, url string) map[string][]MenuEntry {
+	result := make(map[string][]MenuEntry, len(menus))
+	for name, entries := range menus {
+		result[name] = markActive(entries, url)
+	}
+	return result
+}
+
+func markActive(entries []MenuEntry, url string) []MenuEntry {
+	if entries == nil {
+		return nil
+	}
+
+	marked := make([]MenuEntry, len(entries))
+	for i, e := range entries {
+		e.Children = markActive(e.Children, url)
+		e.Active = !e.External && e.URL == url
+		e.ActiveTrail = e.Active || (e.within != "" && strings.HasPrefix(url, e.within))
+		for _, c := range e.Children {
+			e.ActiveTrail = e.ActiveTrail || c.ActiveTrail
+		}
+		marked[i] = e
+	}
+	return marked
+}
@@ -0,0 +1,111 @@
+package ssg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestMenuItems(menuID uuid.UUID) (home, blog, post, about MenuItem) {
+	home = NewMenuItem(menuID, "Home", MenuItemURL)
+	blog = NewMenuItem(menuID, "Blog", MenuItemURL)
+	post = NewMenuItem(menuID, "Post", MenuItemURL)
+	about = NewMenuItem(menuID, "About", MenuItemURL)
+	for _, it := range []MenuItem{home, blog, post, about} {
+		it.GenCreateValues()
+	}
+	post.ParentID = blog.ID()
+	about.ParentID = blog.ID()
+	about.Position = 1
+	blog.Position = 1
+	return home, blog, post, about
+}
+
+func TestMenuItemTree(t *testing.T) {
+	home, blog, post, about := newTestMenuItems(uuid.New())
+	orphan := NewMenuItem(home.MenuID, "Orphan", MenuItemURL)
+	orphan.GenCreateValues()
+	orphan.ParentID = uuid.New()
+
+	tree := menuItemTree([]MenuItem{home, blog, post, about, orphan}, func(it MenuItem) string { return it.Label })
+
+	var labels []string
+	for _, n := range tree {
+		labels = append(labels, n.Label)
+	}
+	if len(tree) != 3 || labels[0] != "Home" || labels[1] != "Blog" || labels[2] != "Orphan" {
+		t.Fatalf("top level = %v", labels)
+	}
+	children := tree[1].Children
+	if len(children) != 2 || children[0].Label != "Post" || children[1].Target != "About" {
+		t.Errorf("children of Blog = %+v", children)
+	}
+}
+
+func TestCheckMenuMoves(t *testing.T) {
+	home, blog, post, about := newTestMenuItems(uuid.New())
+	items := []MenuItem{home, blog, post, about}
+
+	tests := []struct {
+		name  string
+		moves []MenuMove
+		ok    bool
+	}{
+		{"reorder", []MenuMove{{ID: blog.ID()}, {ID: home.ID(), Position: 1}}, true},
+		{"nest", []MenuMove{{ID: home.ID(), ParentID: post.ID()}}, true},
+		{"into own child", []MenuMove{{ID: blog.ID(), ParentID: post.ID()}}, false},
+		{"into itself", []MenuMove{{ID: home.ID(), ParentID: home.ID()}}, false},
+		{"unknown item", []MenuMove{{ID: uuid.New()}}, false},
+		{"unknown parent", []MenuMove{{ID: home.ID(), ParentID: uuid.New()}}, false},
+	}
+
+	for _, tt := range tests {
+		err := checkMenuMoves(items, tt.moves)
+		if tt.ok && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidMenuOrder) {
+			t.Errorf("%s: err = %v, want ErrInvalidMenuOrder", tt.name, err)
+		}
+	}
+}
+
+func TestActiveMenus(t *testing.T) {
+	menus := map[string][]MenuEntry{
+		"main": {
+			{Label: "Home", URL: "/"},
+			{Label: "Blog", URL: "/blog/", within: "/blog/", Children: []MenuEntry{
+				{Label: "First", URL: "/blog/first/"},
+				{Label: "Second", URL: "/blog/second/"},
+			}},
+			{Label: "Elsewhere", URL: "https://example.com/blog/first/", External: true},
+		},
+	}
+
+	got := activeMenus(menus, "/blog/first/")["main"]
+	if got[0].Active || got[0].ActiveTrail {
+		t.Errorf("Home = %+v, want inactive", got[0])
+	}
+	if got[1].Active || !got[1].ActiveTrail {
+		t.Errorf("Blog = %+v, want in the active trail only", got[1])
+	}
+	if !got[1].Children[0].Active || got[1].Children[1].ActiveTrail {
+		t.Errorf("children of Blog = %+v", got[1].Children)
+	}
+	if got[2].Active {
+		t.Errorf("external entry = %+v, want inactive", got[2])
+	}
+	if menus["main"][1].ActiveTrail {
+		t.Error("activeMenus changed the menus it was given")
+	}
+
+	got = activeMenus(menus, "/blog/third/")["main"]
+	if !got[1].ActiveTrail {
+		t.Error("Blog should be in the trail of pages of its section")
+	}
+	got = activeMenus(menus, "/")["main"]
+	if !got[0].Active || got[1].ActiveTrail {
+		t.Errorf("menu on / = %+v", got)
+	}
+}
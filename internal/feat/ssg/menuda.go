@@ -0,0 +1,36 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MenuDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
+	Name        string     `db:"name"`
+	Description string     `db:"description"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
+
+type MenuItemDA struct {
+	ID        uuid.UUID  `db:"id"`
+	ShortID   string     `db:"short_id"`
+	SiteID    string     `db:"site_id"`
+	MenuID    string     `db:"menu_id"`
+	ParentID  string     `db:"parent_id"`
+	Label     string     `db:"label"`
+	Kind      string     `db:"kind"`
+	TargetID  string     `db:"target_id"`
+	URL       string     `db:"url"`
+	Position  int        `db:"position"`
+	CreatedBy *string    `db:"created_by"`
+	UpdatedBy *string    `db:"updated_by"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,140 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+type MenuForm struct {
+	*am.BaseForm
+	ID          string `form:"id"`
+	Name        string `form:"name" required:"true"`
+	Description string `form:"description"`
+}
+
+func NewMenuForm(r *http.Request) MenuForm {
+	return MenuForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func MenuFormFromRequest(r *http.Request) (mf MenuForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return mf, err
+	}
+
+	return MenuForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Name:        strings.TrimSpace(r.Form.Get("name")),
+		Description: strings.TrimSpace(r.Form.Get("description")),
+	}, nil
+}
+
+func (form *MenuForm) Validate() error {
+	validate := am.ComposeValidators(
+		validDataName("name", form.Name),
+		am.MaxLength("name", form.Name, 64),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+// MenuItemForm edits a menu item. Only the target of the selected kind is
+// kept.
+type MenuItemForm struct {
+	*am.BaseForm
+	ID        string `form:"id"`
+	MenuID    string `form:"menu_id" required:"true"`
+	ParentID  string `form:"parent_id"`
+	Label     string `form:"label"`
+	Kind      string `form:"kind" required:"true"`
+	SectionID string `form:"section_id"`
+	ContentID string `form:"content_id"`
+	URL       string `form:"url"`
+}
+
+func NewMenuItemForm(r *http.Request, menuID string) MenuItemForm {
+	return MenuItemForm{
+		BaseForm: am.NewBaseForm(r),
+		MenuID:   menuID,
+		Kind:     MenuItemSection,
+	}
+}
+
+func MenuItemFormFromRequest(r *http.Request) (mf MenuItemForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return mf, err
+	}
+
+	return MenuItemForm{
+		BaseForm:  am.NewBaseForm(r),
+		ID:        r.Form.Get("id"),
+		MenuID:    r.Form.Get("menu_id"),
+		ParentID:  r.Form.Get("parent_id"),
+		Label:     strings.TrimSpace(r.Form.Get("label")),
+		Kind:      r.Form.Get("kind"),
+		SectionID: r.Form.Get("section_id"),
+		ContentID: r.Form.Get("content_id"),
+		URL:       strings.TrimSpace(r.Form.Get("url")),
+	}, nil
+}
+
+func (form *MenuItemForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MaxLength("label", form.Label, 128),
+		validOption("kind", form.Kind, menuItemKinds),
+		form.validTarget(),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+// validTarget checks the target the selected kind requires. URL items need
+// a label as there is nothing to take it from.
+func (form MenuItemForm) validTarget() am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		switch form.Kind {
+		case MenuItemSection:
+			if am.ParseUUID(form.SectionID) == uuid.Nil {
+				v.AddFieldError("section_id", form.SectionID, fmt.Sprintf("%s: required for %s items", "section_id", form.Kind))
+			}
+		case MenuItemContent:
+			if am.ParseUUID(form.ContentID) == uuid.Nil {
+				v.AddFieldError("content_id", form.ContentID, fmt.Sprintf("%s: required for %s items", "content_id", form.Kind))
+			}
+		case MenuItemURL:
+			if form.Label == "" {
+				v.AddFieldError("label", form.Label, fmt.Sprintf("%s: required for %s items", "label", form.Kind))
+			}
+			if !validMenuURL(form.URL) {
+				v.AddFieldError("url", form.URL, "url: must be a path starting with '/' or an http or https URL")
+			}
+		}
+		return v, nil
+	}
+}
+
+func validMenuURL(val string) bool {
+	if strings.HasPrefix(val, "/") {
+		return !strings.HasPrefix(val, "//") && !strings.ContainsAny(val, " ")
+	}
+	u, err := url.Parse(val)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,138 @@
+package ssg
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defPermalink = "/:section/:slug/"
+)
+
+var (
+	permalinkToken  = regexp.MustCompile(`:[a-z]+`)
+	permalinkTokens = []string{":year", ":month", ":day", ":section", ":slug", ":id"}
+
+	// diacritics folds the accented letters of the site languages to ASCII.
+	diacritics = strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a",
+		"é", "e", "è", "e", "ê", "e", "ë", "e",
+		"í", "i", "ì", "i", "î", "i", "ï", "i",
+		"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+		"ú", "u", "ù", "u", "û", "u", "ü", "u",
+		"ñ", "n", "ç", "c", "ß", "ss",
+	)
+)
+
+// Permalink expands a permalink pattern for a content published under section.
+//
+// Supported tokens are :year, :month and :day (from the publication date or,
+// for unpublished content, the creation date), :section (the section path),
+// :slug and :id (the content short ID).
+func Permalink(pattern string, section Section, content Content) string {
+	date := content.PublishedAt
+	if date.IsZero() {
+		date = content.CreatedAt()
+	}
+
+	expanded := permalinkToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		switch token {
+		case ":year":
+			return fmt.Sprintf("%04d", date.Year())
+		case ":month":
+			return fmt.Sprintf("%02d", int(date.Month()))
+		case ":day":
+			return fmt.Sprintf("%02d", date.Day())
+		case ":section":
+			return strings.Trim(section.Path, "/")
+		case ":slug":
+			return content.Slug()
+		case ":id":
+			return content.ShortID()
+		default:
+			return token
+		}
+	})
+
+	url := path.Join("/", expanded)
+	if strings.HasSuffix(pattern, "/") && url != "/" {
+		url += "/"
+	}
+	return url
+}
+
+// ValidatePermalink checks that a pattern only uses known tokens and that it
+// identifies a single content.
+func ValidatePermalink(pattern string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("%w: must start with '/'", ErrInvalidPermalink)
+	}
+
+	for _, token := range permalinkToken.FindAllString(pattern, -1) {
+		if !contains(permalinkTokens, token) {
+			return fmt.Errorf("%w: unknown token %s", ErrInvalidPermalink, token)
+		}
+	}
+
+	if !strings.Contains(pattern, ":slug") && !strings.Contains(pattern, ":id") {
+		return fmt.Errorf("%w: must contain :slug or :id", ErrInvalidPermalink)
+	}
+
+	return nil
+}
+
+// ContentURL returns the site relative URL of a content published in lang.
+func ContentURL(lang, def string, section Section, content Content) string {
+	url := Permalink(section.PermalinkOrDefault(), section, content)
+	prefix := LangPrefix(lang, def)
+	if prefix == "" {
+		return url
+	}
+	return prefix + url
+}
+
+// SectionURL returns the site relative URL of a section in lang.
+func SectionURL(lang, def string, section Section) string {
+	url := path.Join("/", section.Path)
+	if url != "/" {
+		url += "/"
+	}
+	return LangPrefix(lang, def) + url
+}
+
+// Slugify turns a text into a lowercase, dash separated, URL friendly slug.
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range diacritics.Replace(strings.ToLower(s)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// sectionOf returns the section a content is published under. Content without
+// a known section is published under the root one.
+func sectionOf(sections []Section, id uuid.UUID) Section {
+	var root Section
+	for _, s := range sections {
+		if s.ID() == id {
+			return s
+		}
+		if s.Path == "/" {
+			root = s
+		}
+	}
+	return root
+}
@@ -0,0 +1,82 @@
+package ssg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+func TestPermalink(t *testing.T) {
+	created := time.Date(2025, time.March, 9, 10, 0, 0, 0, time.UTC)
+	published := time.Date(2025, time.July, 14, 10, 0, 0, 0, time.UTC)
+
+	section := Section{BaseModel: am.NewModel(), Path: "/blog"}
+	content := Content{
+		BaseModel: am.NewModel(am.WithShortID("abc123"), am.WithCreatedAt(created)),
+		SlugValue: "hello-world",
+	}
+	draft := content
+	content.PublishedAt = published
+
+	cases := []struct {
+		pattern  string
+		content  Content
+		expected string
+	}{
+		{defPermalink, content, "/blog/hello-world/"},
+		{"/:year/:month/:slug/", content, "/2025/07/hello-world/"},
+		{"/:year/:month/:day/:slug/", draft, "/2025/03/09/hello-world/"},
+		{"/:section/:id.html", content, "/blog/abc123.html"},
+	}
+
+	for _, c := range cases {
+		if got := Permalink(c.pattern, section, c.content); got != c.expected {
+			t.Errorf("pattern %q: expected %q, got %q", c.pattern, c.expected, got)
+		}
+	}
+}
+
+func TestValidatePermalink(t *testing.T) {
+	cases := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"/:section/:slug/", true},
+		{"/:year/:month/:slug/", true},
+		{"/p/:id/", true},
+		{":slug/", false},
+		{"/:year/:month/", false},
+		{"/:category/:slug/", false},
+	}
+
+	for _, c := range cases {
+		err := ValidatePermalink(c.pattern)
+		if c.valid && err != nil {
+			t.Errorf("pattern %q: unexpected error %v", c.pattern, err)
+		}
+		if !c.valid && !errors.Is(err, ErrInvalidPermalink) {
+			t.Errorf("pattern %q: expected ErrInvalidPermalink, got %v", c.pattern, err)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Go 1.23: what's new?  ", "go-1-23-what-s-new"},
+		{"Año nuevo, señor", "ano-nuevo-senor"},
+		{"Über Straße", "uber-strasse"},
+		{"---", ""},
+	}
+
+	for _, c := range cases {
+		if got := Slugify(c.text); got != c.expected {
+			t.Errorf("text %q: expected %q, got %q", c.text, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ssg
+
+import (
+	"context"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// Preview renders a content as the next build would if it were saved: through
+// the same markdown pipeline, with the layout of its section or type and the
+// rest of the site as it is. Drafts are previewed as they would be once
+// published and content not created yet as a new one. ErrNoPreview is
+// returned if the section of the content does not serve its language.
+func (g *Generator) Preview(ctx context.Context, content Content) ([]byte, error) {
+	in, err := g.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	content.Status = StatusPublished
+	in, content = withContent(in, content)
+	def := DefaultLang(g.Cfg())
+	pages, _, _ := g.site(in)
+	for _, p := range pages {
+		if p.Content.ID() == content.ID() && p.Lang == content.LangOr(def) {
+			return newRenderer(g.assetsFS, in.layouts).render(p)
+		}
+	}
+	return nil, ErrNoPreview
+}
+
+// withContent returns the input with content as it would be saved, in place
+// of its saved version or added if it was not created yet.
+func withContent(in siteInput, content Content) (siteInput, Content) {
+	var saved *Content
+	for i := range in.contents {
+		if in.contents[i].ID() == content.ID() {
+			saved = &in.contents[i]
+		}
+	}
+
+	if saved != nil {
+		content.BaseModel = saved.BaseModel
+		content.UserID = saved.UserID
+		content.PublishedAt = saved.PublishedAt
+	} else {
+		content.BaseModel = am.NewModel(am.WithType(contentType))
+		content.GenCreateValues()
+	}
+	if content.TranslationID == uuid.Nil {
+		content.TranslationID = content.ID()
+	}
+	if content.SlugValue == "" {
+		content.SlugValue = Slugify(content.Heading)
+	}
+	if content.IsPublished() && content.PublishedAt.IsZero() {
+		content.PublishedAt = am.Now()
+	}
+
+	contents := make([]Content, 0, len(in.contents)+1)
+	for _, c := range in.contents {
+		if c.ID() == content.ID() {
+			c = content
+		}
+		contents = append(contents, c)
+	}
+	if saved == nil {
+		contents = append(contents, content)
+	}
+	in.contents = contents
+
+	if content.AuthorIDs != nil {
+		credits := make([]ContentAuthor, 0, len(in.credits)+len(content.AuthorIDs))
+		for _, c := range in.credits {
+			if c.ContentID != content.ID() {
+				credits = append(credits, c)
+			}
+		}
+		for i, id := range content.AuthorIDs {
+			credits = append(credits, ContentAuthor{ContentID: content.ID(), ProfileID: id, Position: i})
+		}
+		in.credits = credits
+	}
+	return in, content
+}
@@ -0,0 +1,61 @@
+package ssg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWithContent(t *testing.T) {
+	saved := NewContent("Hello", "Saved body")
+	saved.GenCreateValues()
+	saved.TranslationID = saved.ID()
+	saved.SlugValue, saved.Status = "hello", StatusPublished
+	saved.PublishedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	other := NewContent("Other", "")
+	other.GenCreateValues()
+	author := uuid.New()
+	in := siteInput{
+		contents: []Content{saved, other},
+		credits: []ContentAuthor{
+			{ContentID: saved.ID(), ProfileID: uuid.New()},
+			{ContentID: other.ID(), ProfileID: uuid.New()},
+		},
+	}
+
+	edited := NewContent("Hello again", "Edited body")
+	edited.SetID(saved.ID(), true)
+	edited.SlugValue, edited.Status = "hello", StatusPublished
+	edited.AuthorIDs = []uuid.UUID{author}
+
+	got, content := withContent(in, edited)
+	if len(got.contents) != 2 || got.contents[0].Body != "Edited body" || got.contents[1].ID() != other.ID() {
+		t.Fatalf("edited content not put in place of the saved one: %+v", got.contents)
+	}
+	if !content.CreatedAt().Equal(saved.CreatedAt()) || content.TranslationID != saved.ID() {
+		t.Error("saved model not kept")
+	}
+	if !content.PublishedAt.Equal(saved.PublishedAt) {
+		t.Errorf("got published at %v, want %v", content.PublishedAt, saved.PublishedAt)
+	}
+	if len(got.credits) != 2 || got.credits[1].ProfileID != author || got.credits[0].ContentID != other.ID() {
+		t.Errorf("credits not replaced: %+v", got.credits)
+	}
+	if len(in.contents) != 2 || in.contents[0].Body != "Saved body" {
+		t.Error("input contents modified")
+	}
+
+	draft := NewContent("New one", "")
+	draft.Status = StatusPublished
+	got, content = withContent(in, draft)
+	if len(got.contents) != 3 || content.ID() == uuid.Nil {
+		t.Fatalf("new content not added with an ID: %+v", got.contents)
+	}
+	if content.TranslationID != content.ID() || content.SlugValue != "new-one" || content.PublishedAt.IsZero() {
+		t.Errorf("new content not set as it would be created: %+v", content)
+	}
+	if len(got.credits) != 2 {
+		t.Errorf("credits changed without authors: %+v", got.credits)
+	}
+}
@@ -0,0 +1,144 @@
+package ssg
+
+import (
+	"encoding/json"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	profileType = "profile"
+	authorsPath = "/authors/"
+)
+
+// Profile is the public face of an author: what generated pages show about
+// who wrote a content. It is kept apart from the user account so that private
+// account data, such as the email, never reaches the site.
+type Profile struct {
+	*am.BaseModel
+	UserID    uuid.UUID    `json:"user_id"` // Account the profile belongs to, Nil for guest authors
+	Name      string       `json:"name"`
+	SlugValue string       `json:"slug"`
+	Bio       string       `json:"bio"`
+	Avatar    string       `json:"avatar"` // Site path or URL of the avatar image
+	Links     []SocialLink `json:"links"`
+}
+
+func NewProfile(name, bio string) Profile {
+	return Profile{
+		BaseModel: am.NewModel(am.WithType(profileType)),
+		Name:      name,
+		SlugValue: Slugify(name),
+		Bio:       bio,
+	}
+}
+
+func (p Profile) IsZero() bool {
+	return p.BaseModel == nil || p.BaseModel.IsZero()
+}
+
+func (p *Profile) Slug() string {
+	return p.SlugValue
+}
+
+// OptValue and OptLabel make profiles selectable.
+func (p Profile) OptValue() string {
+	return p.ID().String()
+}
+
+func (p Profile) OptLabel() string {
+	return p.Name
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	type Alias Profile
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*p = Profile(*temp)
+	if p.BaseModel == nil {
+		p.BaseModel = am.NewModel(am.WithType(profileType))
+	}
+	return nil
+}
+
+// ContentAuthor credits a profile as an author of a content. Position orders
+// the authors of a content in its byline.
+type ContentAuthor struct {
+	ContentID uuid.UUID
+	ProfileID uuid.UUID
+	Position  int
+}
+
+// contentAuthors returns the IDs of the authors of a content, in byline order.
+func contentAuthors(links []ContentAuthor, contentID uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, l := range links {
+		if l.ContentID == contentID {
+			ids = append(ids, l.ProfileID)
+		}
+	}
+	return ids
+}
+
+// authorsOf returns the authors of a content in byline order. Content nobody
+// was credited for falls back to the profile of the user who created it, if
+// there is one.
+func authorsOf(content Content, profiles []Profile, links []ContentAuthor) []Profile {
+	byID := make(map[uuid.UUID]Profile, len(profiles))
+	for _, p := range profiles {
+		byID[p.ID()] = p
+	}
+
+	var authors []Profile
+	for _, id := range contentAuthors(links, content.ID()) {
+		if p, ok := byID[id]; ok {
+			authors = append(authors, p)
+		}
+	}
+	if len(authors) > 0 {
+		return authors
+	}
+
+	for _, p := range profiles {
+		if p.UserID != uuid.Nil && p.UserID == content.UserID {
+			return []Profile{p}
+		}
+	}
+	return nil
+}
+
+// PageAuthor is an author as layouts see it, in .Authors for the authors of
+// a content and in .Author on the archive page of an author.
+type PageAuthor struct {
+	Name    string
+	Slug    string
+	Bio     string
+	Avatar  string // Absolute if the site has a base URL
+	Links   []SocialLink
+	URL     string // Archive page of the author in the language of the page
+	FeedURL string // Atom feed of the author in the language of the page
+}
+
+// pageAuthor returns the author as shown on pages in lang.
+func pageAuthor(site SiteSettings, p Profile, lang, def string) PageAuthor {
+	url := AuthorURL(lang, def, p.SlugValue)
+	return PageAuthor{
+		Name:    p.Name,
+		Slug:    p.SlugValue,
+		Bio:     p.Bio,
+		Avatar:  absMediaURL(site, p.Avatar),
+		Links:   p.Links,
+		URL:     url,
+		FeedURL: url + feedFile,
+	}
+}
+
+// AuthorURL returns the site relative URL of the archive page of an author in
+// lang.
+func AuthorURL(lang, def, slug string) string {
+	return LangPrefix(lang, def) + authorsPath + slug + "/"
+}
@@ -0,0 +1,93 @@
+package ssg
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestProfile(name string, userID uuid.UUID) Profile {
+	p := NewProfile(name, "")
+	p.GenCreateValues()
+	p.UserID = userID
+	return p
+}
+
+func TestAuthorsOf(t *testing.T) {
+	owner := uuid.New()
+	jane := newTestProfile("Jane Doe", owner)
+	john := newTestProfile("John Roe", uuid.Nil)
+	profiles := []Profile{jane, john}
+
+	credited := NewContent("Credited", "")
+	credited.GenCreateValues()
+	credited.UserID = owner
+
+	uncredited := NewContent("Uncredited", "")
+	uncredited.GenCreateValues()
+	uncredited.UserID = owner
+
+	orphan := NewContent("Orphan", "")
+	orphan.GenCreateValues()
+	orphan.UserID = uuid.New()
+
+	links := []ContentAuthor{
+		{ContentID: credited.ID(), ProfileID: john.ID(), Position: 0},
+		{ContentID: credited.ID(), ProfileID: uuid.New(), Position: 1},
+		{ContentID: credited.ID(), ProfileID: jane.ID(), Position: 2},
+	}
+
+	got := authorsOf(credited, profiles, links)
+	if len(got) != 2 || got[0].Name != "John Roe" || got[1].Name != "Jane Doe" {
+		t.Errorf("credited authors = %v", got)
+	}
+
+	got = authorsOf(uncredited, profiles, links)
+	if len(got) != 1 || got[0].Name != "Jane Doe" {
+		t.Errorf("uncredited authors = %v, want the profile of the user", got)
+	}
+
+	if got := authorsOf(orphan, profiles, links); got != nil {
+		t.Errorf("orphan authors = %v", got)
+	}
+}
+
+func TestContentAuthors(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	content := uuid.New()
+	links := []ContentAuthor{
+		{ContentID: uuid.New(), ProfileID: a},
+		{ContentID: content, ProfileID: b, Position: 0},
+		{ContentID: content, ProfileID: a, Position: 1},
+	}
+
+	got := contentAuthors(links, content)
+	if len(got) != 2 || got[0] != b || got[1] != a {
+		t.Errorf("contentAuthors = %v", got)
+	}
+
+	// Content without authors gets an empty list, not nil, so that saving it
+	// back clears them rather than keeping them.
+	if got := contentAuthors(links, uuid.New()); got == nil || len(got) != 0 {
+		t.Errorf("contentAuthors = %#v", got)
+	}
+}
+
+func TestPageAuthor(t *testing.T) {
+	site := NewSiteSettings("Notes", "en")
+	site.BaseURL = "https://example.com"
+	p := NewProfile("Jane Doe", "Writes things")
+	p.Avatar = "/img/jane.png"
+
+	author := pageAuthor(site, p, "es", "en")
+	if author.URL != "/es/authors/jane-doe/" || author.FeedURL != "/es/authors/jane-doe/feed.xml" {
+		t.Errorf("URL = %q, FeedURL = %q", author.URL, author.FeedURL)
+	}
+	if author.Avatar != "https://example.com/img/jane.png" {
+		t.Errorf("Avatar = %q", author.Avatar)
+	}
+
+	if url := AuthorURL("en", "en", "jane-doe"); url != "/authors/jane-doe/" {
+		t.Errorf("AuthorURL = %q", url)
+	}
+}
@@ -0,0 +1,30 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProfileDA struct {
+	ID        uuid.UUID  `db:"id"`
+	ShortID   string     `db:"short_id"`
+	SiteID    string     `db:"site_id"`
+	UserID    string     `db:"user_id"`
+	Name      string     `db:"name"`
+	Slug      string     `db:"slug"`
+	Bio       string     `db:"bio"`
+	Avatar    string     `db:"avatar"`
+	Links     string     `db:"links"`
+	CreatedBy *string    `db:"created_by"`
+	UpdatedBy *string    `db:"updated_by"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type ContentAuthorDA struct {
+	SiteID    string `db:"site_id"`
+	ContentID string `db:"content_id"`
+	ProfileID string `db:"profile_id"`
+	Position  int    `db:"position"`
+}
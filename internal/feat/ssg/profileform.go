@@ -0,0 +1,60 @@
+package ssg
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+type ProfileForm struct {
+	*am.BaseForm
+	ID     string `form:"id"`
+	UserID string `form:"user_id"`
+	Name   string `form:"name" required:"true"`
+	Slug   string `form:"slug"`
+	Bio    string `form:"bio"`
+	Avatar string `form:"avatar"`
+	Links  string `form:"links"` // One per line, the name and then the URL
+}
+
+func NewProfileForm(r *http.Request) ProfileForm {
+	return ProfileForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func ProfileFormFromRequest(r *http.Request) (pf ProfileForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return pf, err
+	}
+
+	return ProfileForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       r.Form.Get("id"),
+		UserID:   r.Form.Get("user_id"),
+		Name:     strings.TrimSpace(r.Form.Get("name")),
+		Slug:     strings.TrimSpace(r.Form.Get("slug")),
+		Bio:      strings.TrimSpace(r.Form.Get("bio")),
+		Avatar:   strings.TrimSpace(r.Form.Get("avatar")),
+		Links:    strings.TrimSpace(r.Form.Get("links")),
+	}, nil
+}
+
+func (form *ProfileForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 128),
+		validSlug("slug", form.Slug),
+		am.MaxLength("bio", form.Bio, 2048),
+		validMediaRef("avatar", form.Avatar),
+		validSocialLinks("links", form.Links),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
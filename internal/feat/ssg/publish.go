@@ -0,0 +1,135 @@
+package ssg
+
+import (
+	"context"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Publisher kinds.
+const (
+	PublishTargetGit   = "git"
+	PublishTargetLocal = "local"
+	PublishTargetSFTP  = "sftp"
+	PublishTargetS3    = "s3"
+)
+
+const (
+	ChangeAdded    = "A"
+	ChangeModified = "M"
+	ChangeDeleted  = "D"
+)
+
+const (
+	// manifestFile is where publishers that prune a directory record the
+	// files they wrote to it.
+	manifestFile = ".hermes-manifest.json"
+)
+
+const (
+	htmlCacheControl  = "public, max-age=0, must-revalidate"
+	assetCacheControl = "public, max-age=86400"
+)
+
+// Publisher deploys the output of a build to where the site is served from.
+// Implementations only transfer what changed since the last publish and
+// remove files that are no longer generated. On dry runs they report the
+// changes without applying them.
+type Publisher interface {
+	Publish(ctx context.Context, dir string, build Build, author Author, dryRun bool) (PublishResult, error)
+}
+
+// Author is the identity a publish is made with, for targets that record it.
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Change is a file added, modified or deleted by a publish.
+type Change struct {
+	Op   string
+	Path string
+}
+
+func (c Change) String() string {
+	return c.Op + " " + c.Path
+}
+
+// PublishResult describes what a publish did, or would do in a dry run.
+type PublishResult struct {
+	DryRun  bool
+	Changes []Change
+	Ref     string // Target reference of the publish, such as the git commit
+}
+
+func (r PublishResult) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// publishManifest records the hashes of the files of the last publish to a
+// directory. Files not listed in it were not written by Hermes and are never
+// deleted.
+type publishManifest struct {
+	Files map[string]string `json:"files"`
+}
+
+// diffFiles returns the changes that turn the remote files into the local
+// ones. Both maps hold file hashes keyed by slash separated relative path.
+func diffFiles(local, remote map[string]string) []Change {
+	var changes []Change
+	for name, sum := range local {
+		prev, ok := remote[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Op: ChangeAdded, Path: name})
+		case prev != sum:
+			changes = append(changes, Change{Op: ChangeModified, Path: name})
+		}
+	}
+	for name := range remote {
+		if _, ok := local[name]; !ok {
+			changes = append(changes, Change{Op: ChangeDeleted, Path: name})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// mediaType returns the media type a published file is served with.
+func mediaType(name string) string {
+	switch path.Ext(name) {
+	case ".html", ".htm":
+		return "text/html; charset=utf-8"
+	case ".css":
+		return "text/css; charset=utf-8"
+	case ".js", ".mjs":
+		return "text/javascript; charset=utf-8"
+	case ".json":
+		return "application/json"
+	case ".xml":
+		return "application/xml"
+	case ".svg":
+		return "image/svg+xml"
+	case ".webmanifest":
+		return "application/manifest+json"
+	case ".conf", ".txt", "":
+		return "text/plain; charset=utf-8"
+	}
+
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// cacheControl returns the caching policy for a published file. Pages must
+// be revalidated so that updates show up at once, assets can be cached.
+func cacheControl(name string) string {
+	if strings.HasPrefix(mediaType(name), "text/html") {
+		return htmlCacheControl
+	}
+	return assetCacheControl
+}
@@ -0,0 +1,113 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMediaType(t *testing.T) {
+	tests := map[string]string{
+		"index.html":           "text/html; charset=utf-8",
+		"css/main.css":         "text/css; charset=utf-8",
+		"img/logo.png":         "image/png",
+		"_redirects":           "text/plain; charset=utf-8",
+		"nginx-redirects.conf": "text/plain; charset=utf-8",
+		"data.unknownext":      "application/octet-stream",
+	}
+	for name, want := range tests {
+		if got := mediaType(name); got != want {
+			t.Errorf("mediaType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLocalPublisherPublish(t *testing.T) {
+	ctx := context.Background()
+	output := t.TempDir()
+	target := filepath.Join(t.TempDir(), "www")
+	for name, content := range map[string]string{"/": "home", "/old/": "old"} {
+		if err := writePage(output, name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewLocalPublisher(target)
+	build := NewBuild(BuildTriggerManual, uuid.Nil)
+	if _, err := p.Publish(ctx, output, build, Author{}, false); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	assertFile(t, filepath.Join(target, "old", indexFile), "old")
+
+	// Files put there by others are kept.
+	if err := os.WriteFile(filepath.Join(target, "notes.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(output, "old")); err != nil {
+		t.Fatal(err)
+	}
+	res, err := p.Publish(ctx, output, build, Author{}, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(res.Changes) != 1 || res.Changes[0] != (Change{Op: ChangeDeleted, Path: "old/index.html"}) {
+		t.Errorf("Publish() changes = %v, want old page deleted", res.Changes)
+	}
+	if _, err := os.Stat(filepath.Join(target, "old")); !os.IsNotExist(err) {
+		t.Errorf("stale dir still present, stat error = %v", err)
+	}
+	assertFile(t, filepath.Join(target, indexFile), "home")
+	assertFile(t, filepath.Join(target, "notes.txt"), "mine")
+}
+
+func TestLocalPublisherRefusesForeignDir(t *testing.T) {
+	ctx := context.Background()
+	output := t.TempDir()
+	if err := writePage(output, "/", []byte("home")); err != nil {
+		t.Fatal(err)
+	}
+	target := t.TempDir()
+	if err := os.WriteFile(filepath.Join(target, ".bashrc"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewLocalPublisher(target)
+	build := NewBuild(BuildTriggerManual, uuid.Nil)
+	for _, dryRun := range []bool{true, false} {
+		if _, err := p.Publish(ctx, output, build, Author{}, dryRun); !errors.Is(err, ErrTargetNotEmpty) {
+			t.Errorf("Publish(dryRun=%t) error = %v, want ErrTargetNotEmpty", dryRun, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target, indexFile)); !os.IsNotExist(err) {
+		t.Errorf("published into a foreign dir, stat error = %v", err)
+	}
+	assertFile(t, filepath.Join(target, ".bashrc"), "mine")
+}
+
+func TestPublishTargetPublisher(t *testing.T) {
+	s3 := NewPublishTarget("prod", EnvProduction, PublishTargetS3)
+	s3.Settings[SettingEndpoint] = "https://s3.example.com"
+	s3.Settings[SettingBucket] = "site"
+	if _, err := s3.Publisher(); !errors.Is(err, ErrPublishNotConfigured) {
+		t.Errorf("Publisher() without credentials error = %v, want ErrPublishNotConfigured", err)
+	}
+
+	s3.Credentials[CredAccessKey] = "key"
+	s3.Credentials[CredSecretKey] = "secret"
+	p, err := s3.Publisher()
+	if err != nil {
+		t.Fatalf("Publisher() error = %v", err)
+	}
+	if _, ok := p.(*S3Publisher); !ok {
+		t.Errorf("Publisher() = %T, want *S3Publisher", p)
+	}
+
+	unknown := NewPublishTarget("ftp", EnvStaging, "ftp")
+	if _, err := unknown.Publisher(); !errors.Is(err, ErrPublishNotConfigured) {
+		t.Errorf("Publisher() for unknown kind error = %v, want ErrPublishNotConfigured", err)
+	}
+}
@@ -0,0 +1,156 @@
+package ssg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	publishTargetType = "publish-target"
+)
+
+const (
+	EnvStaging    = "staging"
+	EnvProduction = "production"
+)
+
+// Publish target settings. Which ones apply depends on the target kind.
+const (
+	SettingRepo       = "repo"
+	SettingBranch     = "branch"
+	SettingDir        = "dir"
+	SettingAddr       = "addr"
+	SettingUser       = "user"
+	SettingKeyFile    = "key_file"
+	SettingKnownHosts = "known_hosts"
+	SettingEndpoint   = "endpoint"
+	SettingRegion     = "region"
+	SettingBucket     = "bucket"
+	SettingPrefix     = "prefix"
+)
+
+// Publish target credentials, stored encrypted.
+const (
+	CredPassword  = "password"
+	CredAccessKey = "access_key"
+	CredSecretKey = "secret_key"
+)
+
+var (
+	publishTargetKinds = []string{PublishTargetGit, PublishTargetLocal, PublishTargetSFTP, PublishTargetS3}
+	environments       = []string{EnvStaging, EnvProduction}
+)
+
+// PublishTarget is a place a site is published to, such as the staging or
+// production host.
+type PublishTarget struct {
+	*am.BaseModel
+	Name           string            `json:"name"`
+	Environment    string            `json:"environment"`
+	BaseURL        string            `json:"base_url"` // URL the target serves the site at
+	Kind           string            `json:"kind"`     // Publisher used, one of git, local, sftp or s3
+	Settings       map[string]string `json:"settings"`
+	Credentials    map[string]string `json:"-"` // Never serialized, stored encrypted in CredentialsEnc
+	CredentialsEnc []byte            `json:"-"`
+}
+
+func NewPublishTarget(name, environment, kind string) PublishTarget {
+	return PublishTarget{
+		BaseModel:   am.NewModel(am.WithType(publishTargetType)),
+		Name:        name,
+		Environment: environment,
+		Kind:        kind,
+		Settings:    map[string]string{},
+		Credentials: map[string]string{},
+	}
+}
+
+func (t PublishTarget) IsZero() bool {
+	return t.BaseModel == nil || t.BaseModel.IsZero()
+}
+
+func (t PublishTarget) IsProduction() bool {
+	return t.Environment == EnvProduction
+}
+
+func (t PublishTarget) Setting(name string) string {
+	return t.Settings[name]
+}
+
+// HasCredential reports whether a credential is set without exposing it.
+func (t PublishTarget) HasCredential(name string) bool {
+	return t.Credentials[name] != ""
+}
+
+// Publisher returns the publisher for the target. ErrPublishNotConfigured is
+// returned if a setting the kind requires is missing.
+func (t PublishTarget) Publisher() (Publisher, error) {
+	missing := func(what string) error {
+		return fmt.Errorf("%w: %s requires %s", ErrPublishNotConfigured, t.Name, what)
+	}
+
+	switch t.Kind {
+	case PublishTargetGit:
+		if t.Setting(SettingRepo) == "" {
+			return nil, missing("a repository")
+		}
+		return NewGitPublisher(t.Setting(SettingRepo), t.Setting(SettingBranch)), nil
+
+	case PublishTargetLocal:
+		if t.Setting(SettingDir) == "" {
+			return nil, missing("a directory")
+		}
+		return NewLocalPublisher(t.Setting(SettingDir)), nil
+
+	case PublishTargetSFTP:
+		cfg := SFTPConfig{
+			Addr:       t.Setting(SettingAddr),
+			User:       t.Setting(SettingUser),
+			KeyFile:    t.Setting(SettingKeyFile),
+			KnownHosts: t.Setting(SettingKnownHosts),
+			Dir:        t.Setting(SettingDir),
+			Password:   t.Credentials[CredPassword],
+		}
+		if cfg.Addr == "" || cfg.User == "" || cfg.Dir == "" {
+			return nil, missing("an address, user and directory")
+		}
+		return NewSFTPPublisher(cfg), nil
+
+	case PublishTargetS3:
+		cfg := S3Config{
+			Endpoint:  t.Setting(SettingEndpoint),
+			Region:    t.Setting(SettingRegion),
+			Bucket:    t.Setting(SettingBucket),
+			Prefix:    t.Setting(SettingPrefix),
+			AccessKey: t.Credentials[CredAccessKey],
+			SecretKey: t.Credentials[CredSecretKey],
+		}
+		if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, missing("an endpoint, bucket and credentials")
+		}
+		return NewS3Publisher(cfg, nil), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown publisher %q", ErrPublishNotConfigured, t.Kind)
+	}
+}
+
+func (t *PublishTarget) Slug() string {
+	return am.Normalize(t.Name) + "-" + t.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (t *PublishTarget) UnmarshalJSON(data []byte) error {
+	type Alias PublishTarget
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*t = PublishTarget(*temp)
+	if t.BaseModel == nil {
+		t.BaseModel = am.NewModel(am.WithType(publishTargetType))
+	}
+	return nil
+}
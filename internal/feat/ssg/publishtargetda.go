@@ -0,0 +1,23 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PublishTargetDA struct {
+	ID             uuid.UUID  `db:"id"`
+	ShortID        string     `db:"short_id"`
+	SiteID         string     `db:"site_id"`
+	Name           string     `db:"name"`
+	Environment    string     `db:"environment"`
+	BaseURL        string     `db:"base_url"`
+	Kind           string     `db:"kind"`
+	Settings       string     `db:"settings"`
+	CredentialsEnc []byte     `db:"credentials_enc"`
+	CreatedBy      *string    `db:"created_by"`
+	UpdatedBy      *string    `db:"updated_by"`
+	CreatedAt      *time.Time `db:"created_at"`
+	UpdatedAt      *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,162 @@
+package ssg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// PublishTargetForm holds the settings of every publisher kind, only the ones
+// of the selected kind are kept. Credentials are never rendered back, the Has
+// fields tell whether one is already stored.
+type PublishTargetForm struct {
+	*am.BaseForm
+	ID           string `form:"id"`
+	Name         string `form:"name" required:"true"`
+	Environment  string `form:"environment" required:"true"`
+	BaseURL      string `form:"base_url"`
+	Kind         string `form:"kind" required:"true"`
+	Repo         string `form:"repo"`
+	Branch       string `form:"branch"`
+	Dir          string `form:"dir"`
+	Addr         string `form:"addr"`
+	User         string `form:"user"`
+	KeyFile      string `form:"key_file"`
+	KnownHosts   string `form:"known_hosts"`
+	Endpoint     string `form:"endpoint"`
+	Region       string `form:"region"`
+	Bucket       string `form:"bucket"`
+	Prefix       string `form:"prefix"`
+	Password     string `form:"password"`
+	AccessKey    string `form:"access_key"`
+	SecretKey    string `form:"secret_key"`
+	HasPassword  bool
+	HasAccessKey bool
+	HasSecretKey bool
+}
+
+func NewPublishTargetForm(r *http.Request) PublishTargetForm {
+	return PublishTargetForm{
+		BaseForm:    am.NewBaseForm(r),
+		Environment: EnvStaging,
+		Kind:        PublishTargetGit,
+	}
+}
+
+func PublishTargetFormFromRequest(r *http.Request) (tf PublishTargetForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return tf, err
+	}
+
+	field := func(name string) string {
+		return strings.TrimSpace(r.Form.Get(name))
+	}
+
+	return PublishTargetForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Name:        field("name"),
+		Environment: r.Form.Get("environment"),
+		BaseURL:     field("base_url"),
+		Kind:        r.Form.Get("kind"),
+		Repo:        field("repo"),
+		Branch:      field("branch"),
+		Dir:         field("dir"),
+		Addr:        field("addr"),
+		User:        field("user"),
+		KeyFile:     field("key_file"),
+		KnownHosts:  field("known_hosts"),
+		Endpoint:    field("endpoint"),
+		Region:      field("region"),
+		Bucket:      field("bucket"),
+		Prefix:      field("prefix"),
+		Password:    r.Form.Get("password"),
+		AccessKey:   field("access_key"),
+		SecretKey:   r.Form.Get("secret_key"),
+	}, nil
+}
+
+func (form *PublishTargetForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 64),
+		validOption("environment", form.Environment, environments),
+		validOption("kind", form.Kind, publishTargetKinds),
+		validBaseURL("base_url", form.BaseURL),
+		form.validKindSettings(),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+// validKindSettings checks the settings the selected kind requires. Stored
+// credentials count as set.
+func (form PublishTargetForm) validKindSettings() am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		require := func(field, val string, stored bool) {
+			if val == "" && !stored {
+				v.AddFieldError(field, val, fmt.Sprintf("%s: required for %s targets", field, form.Kind))
+			}
+		}
+
+		switch form.Kind {
+		case PublishTargetGit:
+			require("repo", form.Repo, false)
+			if form.Repo != "" && !validGitRepo(form.Repo) {
+				v.AddFieldError("repo", form.Repo, "repo: must not start with -")
+			}
+			if form.Branch != "" && !validGitBranch(context.Background(), form.Branch) {
+				v.AddFieldError("branch", form.Branch, "branch: not a valid branch name")
+			}
+		case PublishTargetLocal:
+			require("dir", form.Dir, false)
+		case PublishTargetSFTP:
+			require("addr", form.Addr, false)
+			require("user", form.User, false)
+			require("dir", form.Dir, false)
+		case PublishTargetS3:
+			require("endpoint", form.Endpoint, false)
+			require("bucket", form.Bucket, false)
+			require("access_key", form.AccessKey, form.HasAccessKey)
+			require("secret_key", form.SecretKey, form.HasSecretKey)
+		}
+		return v, nil
+	}
+}
+
+func validOption(field, val string, options []string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		for _, o := range options {
+			if val == o {
+				return v, nil
+			}
+		}
+		v.AddFieldError(field, val, fmt.Sprintf("%s: must be one of %s", field, strings.Join(options, ", ")))
+		return v, nil
+	}
+}
+
+func validBaseURL(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val == "" {
+			return v, nil
+		}
+		u, err := url.Parse(val)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be an http or https URL", field))
+		}
+		return v, nil
+	}
+}
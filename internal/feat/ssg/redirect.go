@@ -0,0 +1,60 @@
+package ssg
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	redirectType = "redirect"
+)
+
+type Redirect struct {
+	*am.BaseModel
+	SourcePath string `json:"source_path"`
+	TargetPath string `json:"target_path"`
+	StatusCode int    `json:"status_code"`
+	Wildcard   bool   `json:"wildcard"` // True if SourcePath is a prefix matching every path under it
+	Auto       bool   `json:"auto"`     // True if created because a published URL changed
+}
+
+func NewRedirect(sourcePath, targetPath string, statusCode int) Redirect {
+	return Redirect{
+		BaseModel:  am.NewModel(am.WithType(redirectType)),
+		SourcePath: sourcePath,
+		TargetPath: targetPath,
+		StatusCode: statusCode,
+	}
+}
+
+// NewAutoRedirect returns the permanent redirect recorded when the URL of a
+// published content changes.
+func NewAutoRedirect(sourcePath, targetPath string) Redirect {
+	redirect := NewRedirect(sourcePath, targetPath, http.StatusMovedPermanently)
+	redirect.Auto = true
+	return redirect
+}
+
+func (r Redirect) IsZero() bool {
+	return r.BaseModel.IsZero()
+}
+
+func (r *Redirect) Slug() string {
+	return am.Normalize(r.SourcePath) + "-" + r.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (r *Redirect) UnmarshalJSON(data []byte) error {
+	type Alias Redirect
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*r = Redirect(*temp)
+	if r.BaseModel == nil {
+		r.BaseModel = am.NewModel(am.WithType(redirectType))
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RedirectDA struct {
+	ID         uuid.UUID  `db:"id"`
+	ShortID    string     `db:"short_id"`
+	SiteID     string     `db:"site_id"`
+	SourcePath string     `db:"source_path"`
+	TargetPath string     `db:"target_path"`
+	StatusCode int        `db:"status_code"`
+	Wildcard   bool       `db:"wildcard"`
+	Auto       bool       `db:"auto"`
+	CreatedBy  *string    `db:"created_by"`
+	UpdatedBy  *string    `db:"updated_by"`
+	CreatedAt  *time.Time `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
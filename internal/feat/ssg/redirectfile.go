@@ -0,0 +1,167 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	redirectsFile      = "_redirects"
+	nginxRedirectsFile = "nginx-redirects.conf"
+)
+
+var refreshStub = template.Must(template.New("refresh").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Redirecting…</title>
+<link rel="canonical" href="{{ . }}">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url={{ . }}">
+</head>
+<body>
+<p>This page has moved to <a href="{{ . }}">{{ . }}</a>.</p>
+</body>
+</html>
+`))
+
+// writeRedirects emits the redirect artefacts of the site: a _redirects file
+// (Netlify, Cloudflare Pages and compatible hosts), an nginx map file and an
+// HTML meta refresh stub for every non wildcard redirect so that hosts without
+// redirect support still send visitors to the right place.
+func writeRedirects(dir string, redirects []Redirect) error {
+	redirects = sortRedirects(redirects)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, redirectsFile), redirectsRules(redirects), 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, nginxRedirectsFile), nginxRedirectsMap(redirects), 0o644)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range redirects {
+		if r.Wildcard {
+			continue
+		}
+
+		err = writeRefreshStub(dir, r)
+		if err != nil {
+			return fmt.Errorf("cannot write redirect stub for %s: %w", r.SourcePath, err)
+		}
+	}
+
+	return nil
+}
+
+// sortRedirects orders redirects so that the first matching rule is the most
+// specific one: exact paths first, then wildcards from the longest prefix.
+func sortRedirects(redirects []Redirect) []Redirect {
+	sorted := make([]Redirect, len(redirects))
+	copy(sorted, redirects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Wildcard != b.Wildcard {
+			return !a.Wildcard
+		}
+		if a.Wildcard && len(a.SourcePath) != len(b.SourcePath) {
+			return len(a.SourcePath) > len(b.SourcePath)
+		}
+		return a.SourcePath < b.SourcePath
+	})
+	return sorted
+}
+
+// redirectsRules renders redirects in the _redirects format. Wildcards use the
+// splat placeholder to carry the rest of the path over to the target.
+func redirectsRules(redirects []Redirect) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by Hermes\n")
+	for _, r := range redirects {
+		source, target := r.SourcePath, r.TargetPath
+		if r.Wildcard {
+			source = strings.TrimSuffix(source, "/") + "/*"
+			target = strings.TrimSuffix(target, "/") + "/:splat"
+		}
+		fmt.Fprintf(&buf, "%s %s %d\n", source, target, r.StatusCode)
+	}
+	return buf.Bytes()
+}
+
+// nginxRedirectsMap renders one map per status code because nginx needs the
+// code of a return directive to be a literal. Each map is used as:
+//
+//	if ($hermes_redirect_301) { return 301 $hermes_redirect_301; }
+func nginxRedirectsMap(redirects []Redirect) []byte {
+	byCode := make(map[int][]Redirect)
+	var codes []int
+	for _, r := range redirects {
+		if _, ok := byCode[r.StatusCode]; !ok {
+			codes = append(codes, r.StatusCode)
+		}
+		byCode[r.StatusCode] = append(byCode[r.StatusCode], r)
+	}
+	sort.Ints(codes)
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by Hermes. Include in the http block and, for each map, add\n")
+	buf.WriteString("# to the server block: if ($hermes_redirect_<code>) { return <code> $hermes_redirect_<code>; }\n")
+	for _, code := range codes {
+		fmt.Fprintf(&buf, "\nmap $uri $hermes_redirect_%d {\n", code)
+		for _, r := range byCode[code] {
+			if r.Wildcard {
+				prefix := regexp.QuoteMeta(strings.TrimSuffix(r.SourcePath, "/"))
+				fmt.Fprintf(&buf, "    \"~^%s(?:/(.*))?$\" \"%s/$1\";\n", prefix, strings.TrimSuffix(r.TargetPath, "/"))
+				continue
+			}
+			fmt.Fprintf(&buf, "    \"%s\" \"%s\";\n", r.SourcePath, r.TargetPath)
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes()
+}
+
+// writeRefreshStub writes the meta refresh page of a redirect unless a real
+// page already exists at its source path.
+func writeRefreshStub(dir string, r Redirect) error {
+	file := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(r.SourcePath, "/")))
+	if path.Ext(r.SourcePath) == "" {
+		file = filepath.Join(file, indexFile)
+	}
+
+	_, err := os.Stat(file)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = refreshStub.Execute(&buf, r.TargetPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0o644)
+}
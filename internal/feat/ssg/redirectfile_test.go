@@ -0,0 +1,90 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testRedirects() []Redirect {
+	return []Redirect{
+		NewRedirect("/docs", "/manual/", 301),
+		{SourcePath: "/blog", TargetPath: "/news/", StatusCode: 301, Wildcard: true},
+		NewRedirect("/about.html", "/about/", 302),
+		{SourcePath: "/blog/2024", TargetPath: "/archive/2024/", StatusCode: 301, Wildcard: true},
+	}
+}
+
+func TestRedirectsRules(t *testing.T) {
+	got := string(redirectsRules(sortRedirects(testRedirects())))
+	expected := `# Generated by Hermes
+/about.html /about/ 302
+/docs /manual/ 301
+/blog/2024/* /archive/2024/:splat 301
+/blog/* /news/:splat 301
+`
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestNginxRedirectsMap(t *testing.T) {
+	got := string(nginxRedirectsMap(sortRedirects(testRedirects())))
+
+	for _, line := range []string{
+		"map $uri $hermes_redirect_301 {",
+		`"/docs" "/manual/";`,
+		`"~^/blog/2024(?:/(.*))?$" "/archive/2024/$1";`,
+		"map $uri $hermes_redirect_302 {",
+		`"/about.html" "/about/";`,
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("expected map to contain %q, got:\n%s", line, got)
+		}
+	}
+
+	if strings.Index(got, "/blog/2024") > strings.Index(got, `"~^/blog(?:`) {
+		t.Errorf("expected longer wildcard prefixes first, got:\n%s", got)
+	}
+}
+
+func TestWriteRefreshStub(t *testing.T) {
+	dir := t.TempDir()
+
+	live := filepath.Join(dir, "docs", indexFile)
+	err := os.MkdirAll(filepath.Dir(live), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(live, []byte("live page"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = writeRedirects(dir, testRedirects())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "live page" {
+		t.Errorf("expected live page to be kept, got %q", b)
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "about.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `content="0; url=/about/"`) {
+		t.Errorf("expected meta refresh to /about/, got:\n%s", b)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "blog", indexFile))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected no stub for wildcard redirects, got %v", err)
+	}
+}
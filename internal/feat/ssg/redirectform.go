@@ -0,0 +1,100 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// redirectStatuses are the status codes static hosts can honour.
+var redirectStatuses = []int{
+	http.StatusMovedPermanently,
+	http.StatusFound,
+	http.StatusTemporaryRedirect,
+	http.StatusPermanentRedirect,
+}
+
+type RedirectForm struct {
+	*am.BaseForm
+	ID         string `form:"id"`
+	SourcePath string `form:"source_path" required:"true"`
+	TargetPath string `form:"target_path" required:"true"`
+	StatusCode string `form:"status_code"`
+	Wildcard   string `form:"wildcard"`
+}
+
+func NewRedirectForm(r *http.Request) RedirectForm {
+	return RedirectForm{
+		BaseForm:   am.NewBaseForm(r),
+		StatusCode: strconv.Itoa(http.StatusMovedPermanently),
+	}
+}
+
+func RedirectFormFromRequest(r *http.Request) (rf RedirectForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return rf, err
+	}
+
+	return RedirectForm{
+		BaseForm:   am.NewBaseForm(r),
+		ID:         r.Form.Get("id"),
+		SourcePath: strings.TrimSpace(r.Form.Get("source_path")),
+		TargetPath: strings.TrimSpace(r.Form.Get("target_path")),
+		StatusCode: r.Form.Get("status_code"),
+		Wildcard:   r.Form.Get("wildcard"),
+	}, nil
+}
+
+func (form RedirectForm) IsWildcard() bool {
+	return form.Wildcard != ""
+}
+
+func (form *RedirectForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("source_path", form.SourcePath, 1),
+		am.MinLength("target_path", form.TargetPath, 1),
+		validSourcePath("source_path", form.SourcePath),
+		validStatusCode("status_code", form.StatusCode),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+// validSourcePath checks that a redirect source is a site relative path.
+func validSourcePath(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val != "" && (!strings.HasPrefix(val, "/") || strings.ContainsAny(val, " *?#")) {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be a path starting with '/'", field))
+		}
+		return v, nil
+	}
+}
+
+func validStatusCode(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		code, err := strconv.Atoi(val)
+		if err != nil || !containsInt(redirectStatuses, code) {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be one of 301, 302, 307 or 308", field))
+		}
+		return v, nil
+	}
+}
+
+func containsInt(list []int, n int) bool {
+	for _, i := range list {
+		if i == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package ssg
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defRelatedTerms = 1.0
+	defRelatedText  = 0.5
+	defRelatedLimit = 5
+	minWordLen      = 3
+)
+
+// RelatedWeights sets how much shared terms and similar text count when
+// relating two contents, and how many related contents a page lists.
+type RelatedWeights struct {
+	Terms float64
+	Text  float64
+	Limit int
+}
+
+// relatedWeights returns the configured weights. Negative values count as
+// zero, a weight of zero turns that signal off.
+func relatedWeights(cfg *am.Config) RelatedWeights {
+	return RelatedWeights{
+		Terms: math.Max(0, cfg.FloatVal(am.Key.SSGRelatedTerms, defRelatedTerms)),
+		Text:  math.Max(0, cfg.FloatVal(am.Key.SSGRelatedText, defRelatedText)),
+		Limit: int(cfg.IntVal(am.Key.SSGRelatedLimit, defRelatedLimit)),
+	}
+}
+
+// Related is a page related to the page being rendered, as layouts see it in
+// .Related.
+type Related struct {
+	Content Content
+	URL     string
+	Score   float64 // Weighted sum of the term and text similarities
+}
+
+// relatedPages returns the pages related to every page, by page index, most
+// related first. Pages are only related to other contents in the same
+// language.
+//
+// Term similarity is the share of terms two contents have in common. Text
+// similarity is the cosine of the TF-IDF vectors of their heading and body,
+// so that words used all over the site count for little, and words used in
+// every content of a language for nothing.
+func relatedPages(pages []page, links []ContentTerm, w RelatedWeights) [][]Related {
+	result := make([][]Related, len(pages))
+	if w.Limit <= 0 || (w.Terms == 0 && w.Text == 0) {
+		return result
+	}
+
+	terms := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, l := range links {
+		if terms[l.ContentID] == nil {
+			terms[l.ContentID] = make(map[uuid.UUID]bool)
+		}
+		terms[l.ContentID][l.TermID] = true
+	}
+
+	byLang := make(map[string][]int)
+	for i, p := range pages {
+		byLang[p.Lang] = append(byLang[p.Lang], i)
+	}
+
+	for _, group := range byLang {
+		texts := make([]string, len(group))
+		for k, i := range group {
+			texts[k] = pages[i].Content.Heading + " " + pages[i].Content.Body
+		}
+		vectors := textVectors(texts)
+
+		for k, i := range group {
+			p := pages[i]
+			var related []Related
+			for l, j := range group {
+				other := pages[j]
+				if other.Content.TranslationKey() == p.Content.TranslationKey() {
+					continue
+				}
+
+				score := w.Terms*termSimilarity(terms[p.Content.ID()], terms[other.Content.ID()]) +
+					w.Text*dot(vectors[k], vectors[l])
+				if score <= 0 {
+					continue
+				}
+				related = append(related, Related{Content: other.Content, URL: other.URL, Score: score})
+			}
+
+			sort.SliceStable(related, func(a, b int) bool {
+				if related[a].Score != related[b].Score {
+					return related[a].Score > related[b].Score
+				}
+				return related[a].Content.PublishedAt.After(related[b].Content.PublishedAt)
+			})
+			if len(related) > w.Limit {
+				related = related[:w.Limit]
+			}
+			result[i] = related
+		}
+	}
+	return result
+}
+
+// termSimilarity returns the number of terms in both sets over the number of
+// terms in either.
+func termSimilarity(a, b map[uuid.UUID]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// textVectors returns the TF-IDF vectors of texts, scaled to unit length so
+// that the dot product of two of them is their cosine similarity.
+func textVectors(texts []string) []map[string]float64 {
+	counts := make([]map[string]int, len(texts))
+	df := make(map[string]int)
+	for i, t := range texts {
+		counts[i] = make(map[string]int)
+		for _, w := range words(t) {
+			if counts[i][w] == 0 {
+				df[w]++
+			}
+			counts[i][w]++
+		}
+	}
+
+	n := float64(len(texts))
+	vectors := make([]map[string]float64, len(texts))
+	for i, c := range counts {
+		v := make(map[string]float64, len(c))
+		var norm float64
+		for w, tf := range c {
+			x := float64(tf) * math.Log(n/float64(df[w]))
+			if x == 0 {
+				continue
+			}
+			v[w] = x
+			norm += x * x
+		}
+		norm = math.Sqrt(norm)
+		for w := range v {
+			v[w] /= norm
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func dot(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var sum float64
+	for w, x := range a {
+		sum += x * b[w]
+	}
+	return sum
+}
+
+// words returns the lowercase words of a text, leaving out markup and words
+// too short to tell texts apart.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	result := fields[:0]
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) >= minWordLen {
+			result = append(result, f)
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package ssg
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newRelatedPage(lang, heading, body string) page {
+	c := NewContent(heading, body)
+	c.GenCreateValues()
+	return page{PageData: PageData{Lang: lang, URL: "/" + Slugify(heading) + "/", Content: c}}
+}
+
+func relatedURLs(related []Related) []string {
+	urls := make([]string, len(related))
+	for i, r := range related {
+		urls[i] = r.URL
+	}
+	return urls
+}
+
+func TestRelatedPagesTerms(t *testing.T) {
+	pages := []page{
+		newRelatedPage("en", "Alpha", ""),
+		newRelatedPage("en", "Beta", ""),
+		newRelatedPage("en", "Gamma", ""),
+		newRelatedPage("en", "Delta", ""),
+	}
+	golang, sqlite, css := uuid.New(), uuid.New(), uuid.New()
+	links := []ContentTerm{
+		{ContentID: pages[0].Content.ID(), TermID: golang},
+		{ContentID: pages[0].Content.ID(), TermID: sqlite},
+		{ContentID: pages[1].Content.ID(), TermID: golang},
+		{ContentID: pages[1].Content.ID(), TermID: sqlite},
+		{ContentID: pages[2].Content.ID(), TermID: golang},
+		{ContentID: pages[2].Content.ID(), TermID: css},
+		{ContentID: pages[3].Content.ID(), TermID: css},
+	}
+
+	related := relatedPages(pages, links, RelatedWeights{Terms: 1, Limit: 5})
+	got := relatedURLs(related[0])
+	if len(got) != 2 || got[0] != "/beta/" || got[1] != "/gamma/" {
+		t.Errorf("related to alpha = %v", got)
+	}
+	if related[0][0].Score != 1 {
+		t.Errorf("score = %v", related[0][0].Score)
+	}
+
+	related = relatedPages(pages, links, RelatedWeights{Terms: 1, Limit: 1})
+	if got := relatedURLs(related[0]); len(got) != 1 || got[0] != "/beta/" {
+		t.Errorf("limited = %v", got)
+	}
+
+	related = relatedPages(pages, links, RelatedWeights{Limit: 5})
+	if len(related[0]) != 0 {
+		t.Errorf("related with no weights = %v", relatedURLs(related[0]))
+	}
+}
+
+func TestRelatedPagesText(t *testing.T) {
+	pages := []page{
+		newRelatedPage("en", "Baking bread", "Sourdough bread needs flour, water and a starter."),
+		newRelatedPage("en", "Sourdough starter", "Feed the starter with flour and water every day."),
+		newRelatedPage("en", "Tuning engines", "Carburetors and spark plugs wear out."),
+		newRelatedPage("en", "Garden", "Tomatoes need sun and water."),
+	}
+
+	related := relatedPages(pages, nil, RelatedWeights{Text: 1, Limit: 5})
+	got := relatedURLs(related[0])
+	if len(got) == 0 || got[0] != "/sourdough-starter/" {
+		t.Errorf("related to baking = %v", got)
+	}
+	for _, url := range got {
+		if url == "/tuning-engines/" {
+			t.Errorf("unrelated page listed: %v", got)
+		}
+	}
+}
+
+func TestRelatedPagesLanguages(t *testing.T) {
+	en := newRelatedPage("en", "Bread", "Sourdough bread and flour")
+	es := newRelatedPage("es", "Pan", "Sourdough bread and flour")
+	es.Content.TranslationID = en.Content.ID()
+	other := newRelatedPage("en", "More bread", "Sourdough bread and flour tips")
+	otherEs := newRelatedPage("es", "Más pan", "Sourdough bread and flour tips")
+	pages := []page{en, es, other, otherEs, newRelatedPage("en", "Cars", "Engines"), newRelatedPage("es", "Coches", "Motores")}
+
+	related := relatedPages(pages, nil, RelatedWeights{Text: 1, Limit: 5})
+	if got := relatedURLs(related[0]); len(got) != 1 || got[0] != other.URL {
+		t.Errorf("related to en = %v", got)
+	}
+	if got := relatedURLs(related[1]); len(got) != 1 || got[0] != otherEs.URL {
+		t.Errorf("related to es = %v", got)
+	}
+}
@@ -0,0 +1,388 @@
+package ssg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	defReleasesDir = "releases"
+	defReleases    = 5
+	nextLinkSuffix = ".next"
+)
+
+var (
+	ErrNoRelease      = errors.New("release not available")
+	ErrOutputNotEmpty = errors.New("output directory has files not written by a release, move or empty it")
+)
+
+// ReleaseDiff lists the files that differ between two releases.
+type ReleaseDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d ReleaseDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ReleasesDir returns the directory builds are generated into, one
+// subdirectory per build. The output directory is a symlink to the live one.
+func (g *Generator) ReleasesDir(ctx context.Context) string {
+	return siteDir(ctx, g.Cfg().StrValOrDef(am.Key.SSGReleasesDir, defReleasesDir))
+}
+
+func (g *Generator) releaseDir(ctx context.Context, buildID uuid.UUID) string {
+	return filepath.Join(g.ReleasesDir(ctx), buildID.String())
+}
+
+// HasRelease reports whether the output of a build is still retained.
+func (g *Generator) HasRelease(ctx context.Context, buildID uuid.UUID) bool {
+	info, err := os.Stat(g.releaseDir(ctx, buildID))
+	return err == nil && info.IsDir()
+}
+
+// LiveRelease returns the build the output directory points to. uuid.Nil is
+// returned if the site was never released.
+func (g *Generator) LiveRelease(ctx context.Context) (uuid.UUID, error) {
+	out := g.OutputDir(ctx)
+	info, err := os.Lstat(out)
+	if errors.Is(err, fs.ErrNotExist) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return uuid.Nil, nil
+	}
+
+	target, err := os.Readlink(out)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(filepath.Base(target))
+	if err != nil {
+		return uuid.Nil, nil
+	}
+	return id, nil
+}
+
+// Rollback points the output directory back to the release of a previous
+// build.
+func (g *Generator) Rollback(ctx context.Context, buildID uuid.UUID) error {
+	defer g.lock(ctx)()
+
+	if !g.HasRelease(ctx, buildID) {
+		return ErrNoRelease
+	}
+	return g.activate(ctx, buildID)
+}
+
+// activate points the output directory to the release of a build. The new
+// link is created aside and renamed over the output so that the site is never
+// served half updated. An output directory that is not a link was not created
+// here, so it is only replaced if it is empty.
+func (g *Generator) activate(ctx context.Context, buildID uuid.UUID) error {
+	out := filepath.Clean(g.OutputDir(ctx))
+
+	info, err := os.Lstat(out)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		err = os.MkdirAll(filepath.Dir(out), 0o755)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case info.Mode()&fs.ModeSymlink == 0:
+		err = os.Remove(out)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrOutputNotEmpty, out)
+		}
+	}
+
+	target, err := linkTarget(out, g.releaseDir(ctx, buildID))
+	if err != nil {
+		return err
+	}
+
+	next := out + nextLinkSuffix
+	err = os.Remove(next)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	err = os.Symlink(target, next)
+	if err != nil {
+		return err
+	}
+	return os.Rename(next, out)
+}
+
+// linkTarget returns the path a link at link should hold to reach target,
+// relative when possible so that the whole tree can be moved.
+func linkTarget(link, target string) (string, error) {
+	absLink, err := filepath.Abs(link)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(absLink), absTarget)
+	if err != nil {
+		return absTarget, nil
+	}
+	return rel, nil
+}
+
+// DiffReleases compares the output of two builds.
+func (g *Generator) DiffReleases(ctx context.Context, from, to uuid.UUID) (ReleaseDiff, error) {
+	var diff ReleaseDiff
+
+	old, err := releaseFiles(g.releaseDir(ctx, from))
+	if err != nil {
+		return diff, err
+	}
+
+	current, err := releaseFiles(g.releaseDir(ctx, to))
+	if err != nil {
+		return diff, err
+	}
+
+	for name, sum := range current {
+		prev, ok := old[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case prev != sum:
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range old {
+		if _, ok := current[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+func releaseFiles(dir string) (map[string]string, error) {
+	_, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoRelease
+	}
+	if err != nil {
+		return nil, err
+	}
+	return listFiles(dir)
+}
+
+// listFiles returns the SHA-256 of every file under dir keyed by its slash
+// separated relative path. A missing dir has no files. dir itself can be a
+// symlink, as the output directory is.
+func listFiles(dir string) (map[string]string, error) {
+	return hashFiles(dir, sha256.New)
+}
+
+// hashFiles is like listFiles with the given hash function.
+func hashFiles(dir string, newHash func() hash.Hash) (map[string]string, error) {
+	files := make(map[string]string)
+	dir, err := filepath.EvalSymlinks(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		sum, err := fileHash(file, newHash)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+	return files, err
+}
+
+func fileHash(file string, newHash func() hash.Hash) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := newHash()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// treeHash returns a hash that changes whenever any file of the tree is
+// added, removed or modified.
+func treeHash(files map[string]string) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", files[name], name)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func countDeletedPages(previous, current map[string]string) int {
+	deleted := 0
+	for name := range previous {
+		if _, ok := current[name]; !ok && strings.HasSuffix(name, ".html") {
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// copyTree copies the files under src into dst.
+func copyTree(src, dst string) error {
+	src, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(src, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(file, target)
+	})
+}
+
+// copyStatic copies the static dir into dir, if there is one.
+func copyStatic(static, dir string) error {
+	info, err := os.Stat(static)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a dir", static)
+	}
+	return copyTree(static, dir)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// pruneReleases removes the oldest releases under root so that at most keep
+// are retained. The live release is never removed.
+func pruneReleases(root string, keep int, live uuid.UUID) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+
+	type release struct {
+		name    string
+		modTime int64
+	}
+	var releases []release
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == live.String() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		releases = append(releases, release{e.Name(), info.ModTime().UnixNano()})
+	}
+
+	if live != uuid.Nil {
+		keep--
+	}
+	if keep < 0 {
+		keep = 0
+	}
+
+	sort.Slice(releases, func(i, j int) bool { return releases[i].modTime > releases[j].modTime })
+	for i := keep; i < len(releases); i++ {
+		err = os.RemoveAll(filepath.Join(root, releases[i].name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
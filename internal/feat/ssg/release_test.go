@@ -0,0 +1,196 @@
+package ssg
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestCountDeletedPages(t *testing.T) {
+	previous := map[string]string{
+		"index.html":       "a",
+		"about/index.html": "b",
+		"css/main.css":     "c",
+		"old/index.html":   "d",
+	}
+	current := map[string]string{
+		"index.html":       "a",
+		"about/index.html": "e",
+	}
+
+	if got := countDeletedPages(previous, current); got != 1 {
+		t.Errorf("countDeletedPages() = %d, want 1", got)
+	}
+}
+
+func TestTreeHash(t *testing.T) {
+	a := map[string]string{"index.html": "1", "about/index.html": "2"}
+	b := map[string]string{"about/index.html": "2", "index.html": "1"}
+	c := map[string]string{"index.html": "1", "about/index.html": "3"}
+
+	if treeHash(a) != treeHash(b) {
+		t.Error("treeHash() depends on map order")
+	}
+	if treeHash(a) == treeHash(c) {
+		t.Error("treeHash() did not change with file contents")
+	}
+}
+
+func TestActivateKeepsOutputDirectory(t *testing.T) {
+	root := t.TempDir()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.SSGOutputDir:   filepath.Join(root, "output"),
+		am.Key.SSGReleasesDir: filepath.Join(root, "releases"),
+	})
+	g := NewGenerator(embed.FS{}, nil, am.WithCfg(cfg))
+	ctx := context.Background()
+
+	if err := writePage(g.OutputDir(ctx), "/", []byte("mine")); err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.New()
+	if err := writePage(g.releaseDir(ctx, id), "/", []byte(id.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.activate(ctx, id); !errors.Is(err, ErrOutputNotEmpty) {
+		t.Fatalf("activate() error = %v, want ErrOutputNotEmpty", err)
+	}
+	b, err := os.ReadFile(filepath.Join(g.OutputDir(ctx), indexFile))
+	if err != nil || string(b) != "mine" {
+		t.Errorf("output directory not kept: %q, %v", b, err)
+	}
+}
+
+func TestActivateAndRollback(t *testing.T) {
+	root := t.TempDir()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.SSGOutputDir:   filepath.Join(root, "output"),
+		am.Key.SSGReleasesDir: filepath.Join(root, "releases"),
+	})
+	g := NewGenerator(embed.FS{}, nil, am.WithCfg(cfg))
+	ctx := context.Background()
+
+	// An empty output directory is replaced by the link.
+	if err := os.MkdirAll(g.OutputDir(ctx), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{first, second} {
+		if err := writePage(g.releaseDir(ctx, id), "/", []byte(id.String())); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.activate(ctx, id); err != nil {
+			t.Fatalf("activate() error = %v", err)
+		}
+		assertLive(t, g, id)
+	}
+
+	if err := g.Rollback(ctx, first); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	assertLive(t, g, first)
+
+	if err := g.Rollback(ctx, uuid.New()); !errors.Is(err, ErrNoRelease) {
+		t.Errorf("Rollback() of unknown build error = %v, want ErrNoRelease", err)
+	}
+	assertLive(t, g, first)
+}
+
+func TestSiteDir(t *testing.T) {
+	blog := NewSite("Blog", "")
+	blog.GenCreateValues()
+
+	cases := []struct {
+		ctx  context.Context
+		want string
+	}{
+		{context.Background(), "output"},
+		{WithSite(context.Background(), DefaultSite()), "output"},
+		{WithSite(context.Background(), blog), "output-blog"},
+	}
+	for _, c := range cases {
+		if got := siteDir(c.ctx, "output"); got != c.want {
+			t.Errorf("siteDir() = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func assertLive(t *testing.T, g *Generator, id uuid.UUID) {
+	t.Helper()
+
+	ctx := context.Background()
+	live, err := g.LiveRelease(ctx)
+	if err != nil {
+		t.Fatalf("LiveRelease() error = %v", err)
+	}
+	if live != id {
+		t.Errorf("LiveRelease() = %s, want %s", live, id)
+	}
+
+	b, err := os.ReadFile(filepath.Join(g.OutputDir(ctx), indexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != id.String() {
+		t.Errorf("served output = %q, want %q", b, id)
+	}
+}
+
+func TestPruneReleases(t *testing.T) {
+	root := t.TempDir()
+	names := []string{"first", "second", "third", "fourth"}
+	for i, name := range names {
+		dir := filepath.Join(root, name)
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		mod := time.Now().Add(time.Duration(i-len(names)) * time.Minute)
+		if err := os.Chtimes(dir, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The live release is kept even if it is the oldest one.
+	live := uuid.New()
+	liveDir := filepath.Join(root, live.String())
+	if err := os.Mkdir(liveDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(liveDir, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pruneReleases(root, 3, live); err != nil {
+		t.Fatalf("pruneReleases() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, e := range entries {
+		got[e.Name()] = true
+	}
+	want := []string{live.String(), "third", "fourth"}
+	if len(got) != len(want) {
+		t.Errorf("remaining releases = %v, want %v", got, want)
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("release %s was removed", name)
+		}
+	}
+}
@@ -2,20 +2,117 @@ package ssg
 
 import (
 	"context"
+	"time"
 
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
 type Repo interface {
 	am.Repo
 
+	CreateSite(ctx context.Context, site Site) error
+	GetSites(ctx context.Context) ([]Site, error)
+	GetSite(ctx context.Context, id string) (Site, error)
+	GetSiteBySlug(ctx context.Context, slug string) (Site, error)
+	UpdateSite(ctx context.Context, site Site) error
+	GetSiteSettings(ctx context.Context) (SiteSettings, error)
+	SaveSiteSettings(ctx context.Context, settings SiteSettings) error
+	CreateContentType(ctx context.Context, ct ContentType) error
+	GetContentTypes(ctx context.Context) ([]ContentType, error)
+	GetContentType(ctx context.Context, id string) (ContentType, error)
+	UpdateContentType(ctx context.Context, ct ContentType) error
+	CreateDataFile(ctx context.Context, file DataFile) error
+	GetDataFiles(ctx context.Context) ([]DataFile, error)
+	GetDataFile(ctx context.Context, id string) (DataFile, error)
+	UpdateDataFile(ctx context.Context, file DataFile) error
+	DeleteDataFile(ctx context.Context, id string) error
+	CreateMenu(ctx context.Context, menu Menu) error
+	GetMenus(ctx context.Context) ([]Menu, error)
+	GetMenu(ctx context.Context, id string) (Menu, error)
+	UpdateMenu(ctx context.Context, menu Menu) error
+	DeleteMenu(ctx context.Context, id string) error
+	CreateMenuItem(ctx context.Context, item MenuItem) error
+	GetMenuItems(ctx context.Context) ([]MenuItem, error)
+	GetMenuItem(ctx context.Context, id string) (MenuItem, error)
+	UpdateMenuItem(ctx context.Context, item MenuItem) error
+	MoveMenuItems(ctx context.Context, moves []MenuMove) error
+	DeleteMenuItem(ctx context.Context, id string) error
+	CreateSeries(ctx context.Context, series Series) error
+	GetAllSeries(ctx context.Context) ([]Series, error)
+	GetSeries(ctx context.Context, id string) (Series, error)
+	UpdateSeries(ctx context.Context, series Series) error
+	DeleteSeries(ctx context.Context, id string) error
+	GetSeriesParts(ctx context.Context) ([]SeriesPart, error)
+	SetSeriesParts(ctx context.Context, seriesID uuid.UUID, contentIDs []uuid.UUID) error
+	CreateProfile(ctx context.Context, profile Profile) error
+	GetProfiles(ctx context.Context) ([]Profile, error)
+	GetProfile(ctx context.Context, id string) (Profile, error)
+	UpdateProfile(ctx context.Context, profile Profile) error
+	DeleteProfile(ctx context.Context, id string) error
+	GetContentAuthors(ctx context.Context) ([]ContentAuthor, error)
+	SetContentAuthors(ctx context.Context, contentID uuid.UUID, profileIDs []uuid.UUID) error
 	CreateContent(ctx context.Context, content Content) error
 	GetContent(ctx context.Context, id string) (Content, error)
 	UpdateContent(ctx context.Context, content Content) error
 	GetAllContent(ctx context.Context) ([]Content, error)
+	GetContentTranslations(ctx context.Context, translationID uuid.UUID) ([]Content, error)
+	AcquireContentLock(ctx context.Context, lock ContentLock) (ContentLock, error)
+	GetContentLocks(ctx context.Context) ([]ContentLock, error)
+	ReleaseContentLock(ctx context.Context, contentID, userID uuid.UUID) error
+	SaveContentDraft(ctx context.Context, draft ContentDraft) error
+	GetContentDraft(ctx context.Context, contentID, userID uuid.UUID) (ContentDraft, error)
+	DeleteContentDraft(ctx context.Context, contentID, userID uuid.UUID) error
 	// DeleteContent(ctx context.Context, contentID uuid.UUID) error
 	CreateSection(ctx context.Context, section Section) error
 	GetSections(ctx context.Context) ([]Section, error)
 	CreateLayout(ctx context.Context, layout Layout) error
 	GetAllLayouts(ctx context.Context) ([]Layout, error)
+	CreateRedirect(ctx context.Context, redirect Redirect) error
+	GetAllRedirects(ctx context.Context) ([]Redirect, error)
+	GetRedirect(ctx context.Context, id string) (Redirect, error)
+	UpdateRedirect(ctx context.Context, redirect Redirect) error
+	DeleteRedirect(ctx context.Context, id string) error
+	RetargetRedirects(ctx context.Context, oldTarget, newTarget string) error
+	DeleteRedirectBySource(ctx context.Context, sourcePath string) error
+	CreateBuild(ctx context.Context, build Build) error
+	UpdateBuild(ctx context.Context, build Build) error
+	GetBuilds(ctx context.Context) ([]Build, error)
+	GetBuild(ctx context.Context, id string) (Build, error)
+	CreatePublishTarget(ctx context.Context, target PublishTarget) error
+	GetPublishTargets(ctx context.Context) ([]PublishTarget, error)
+	GetPublishTarget(ctx context.Context, id string) (PublishTarget, error)
+	GetPublishTargetByName(ctx context.Context, name string) (PublishTarget, error)
+	UpdatePublishTarget(ctx context.Context, target PublishTarget) error
+	DeletePublishTarget(ctx context.Context, id string) error
+	CreateDeployment(ctx context.Context, deployment Deployment) error
+	UpdateDeployment(ctx context.Context, deployment Deployment) error
+	GetTargetDeployments(ctx context.Context, targetID string) ([]Deployment, error)
+	GetBuildDeployments(ctx context.Context, buildID string) ([]Deployment, error)
+	CreateWebhook(ctx context.Context, hook Webhook) error
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id string) (Webhook, error)
+	UpdateWebhook(ctx context.Context, hook Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+	CreateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context) ([]WebhookDelivery, error)
+	GetDeliveriesByWebhook(ctx context.Context, webhookID string) ([]WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id string) (WebhookDelivery, error)
+	GetPendingWebhookDeliveries(ctx context.Context) ([]WebhookDelivery, error)
+	ClearWebhookDeliveryRetry(ctx context.Context, id uuid.UUID) error
+	CreateAPIToken(ctx context.Context, token APIToken) error
+	GetAPITokens(ctx context.Context) ([]APIToken, error)
+	GetAPITokenByHash(ctx context.Context, hash string) (APIToken, error)
+	TouchAPIToken(ctx context.Context, token APIToken) error
+	DeleteAPIToken(ctx context.Context, id string) error
+	CreateIdempotencyKey(ctx context.Context, key IdempotencyKey) error
+	GetIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) (IdempotencyKey, error)
+	UpdateIdempotencyKey(ctx context.Context, key IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, cutoff time.Time) error
+	CreateTerm(ctx context.Context, term Term) error
+	GetTerms(ctx context.Context) ([]Term, error)
+	GetContentTerms(ctx context.Context, contentID uuid.UUID) ([]Term, error)
+	AddContentTerm(ctx context.Context, contentID, termID uuid.UUID) error
+	GetAllContentTerms(ctx context.Context) ([]ContentTerm, error)
 }
@@ -7,6 +7,67 @@ import (
 func NewWebRouter(handler *WebHandler, mw []am.Middleware, opts ...am.Option) *am.Router {
 	core := am.NewWebRouter("web-router", opts...)
 	core.SetMiddlewares(mw)
+	core.Use(handler.SiteMw)
+
+	// Site routes
+	core.Get("/new-site", handler.NewSite)
+	core.Post("/create-site", handler.CreateSite)
+	core.Get("/edit-site", handler.EditSite)
+	core.Post("/update-site", handler.UpdateSite)
+	core.Get("/list-sites", handler.ListSites)
+	core.Get("/site-switcher", handler.SiteSwitcher)
+	core.Post("/switch-site", handler.SwitchSite)
+	core.Get("/edit-settings", handler.EditSiteSettings)
+	core.Post("/update-settings", handler.UpdateSiteSettings)
+
+	// Content type routes
+	core.Get("/new-content-type", handler.NewContentType)
+	core.Post("/create-content-type", handler.CreateContentType)
+	core.Get("/edit-content-type", handler.EditContentType)
+	core.Post("/update-content-type", handler.UpdateContentType)
+	core.Get("/list-content-types", handler.ListContentTypes)
+
+	// Data file routes
+	core.Get("/new-data-file", handler.NewDataFile)
+	core.Post("/create-data-file", handler.CreateDataFile)
+	core.Get("/edit-data-file", handler.EditDataFile)
+	core.Post("/update-data-file", handler.UpdateDataFile)
+	core.Get("/list-data-files", handler.ListDataFiles)
+	core.Post("/delete-data-file", handler.DeleteDataFile)
+	core.Post("/sync-data-files", handler.SyncDataFiles)
+
+	// Menu routes
+	core.Get("/new-menu", handler.NewMenu)
+	core.Post("/create-menu", handler.CreateMenu)
+	core.Get("/edit-menu", handler.EditMenu)
+	core.Post("/update-menu", handler.UpdateMenu)
+	core.Get("/list-menus", handler.ListMenus)
+	core.Post("/delete-menu", handler.DeleteMenu)
+	core.Post("/reorder-menu-items", handler.ReorderMenuItems)
+	core.Get("/new-menu-item", handler.NewMenuItem)
+	core.Post("/create-menu-item", handler.CreateMenuItem)
+	core.Get("/edit-menu-item", handler.EditMenuItem)
+	core.Post("/update-menu-item", handler.UpdateMenuItem)
+	core.Post("/delete-menu-item", handler.DeleteMenuItem)
+
+	// Series routes
+	core.Get("/new-series", handler.NewSeries)
+	core.Post("/create-series", handler.CreateSeries)
+	core.Get("/edit-series", handler.EditSeries)
+	core.Post("/update-series", handler.UpdateSeries)
+	core.Get("/list-series", handler.ListSeries)
+	core.Post("/delete-series", handler.DeleteSeries)
+	core.Post("/add-series-content", handler.AddSeriesContent)
+	core.Post("/remove-series-content", handler.RemoveSeriesContent)
+	core.Post("/reorder-series", handler.ReorderSeries)
+
+	// Profile routes
+	core.Get("/new-profile", handler.NewProfile)
+	core.Post("/create-profile", handler.CreateProfile)
+	core.Get("/edit-profile", handler.EditProfile)
+	core.Post("/update-profile", handler.UpdateProfile)
+	core.Get("/list-profiles", handler.ListProfiles)
+	core.Post("/delete-profile", handler.DeleteProfile)
 
 	// Content routes
 	core.Get("/new-content", handler.NewContent)
@@ -14,6 +75,12 @@ func NewWebRouter(handler *WebHandler, mw []am.Middleware, opts ...am.Option) *a
 	core.Get("/edit-content", handler.EditContent)
 	core.Post("/update-content", handler.UpdateContent)
 	core.Get("/list-content", handler.ListContent)
+	core.Post("/unlock-content", handler.UnlockContent)
+	core.Post("/save-content-draft", handler.SaveContentDraft)
+	core.Post("/discard-content-draft", handler.DiscardContentDraft)
+	core.Post("/preview-content", handler.PreviewContent)
+	core.Get("/new-translation", handler.NewTranslation)
+	core.Get("/list-translations", handler.ListTranslations)
 	// core.Post("/delete-content", handler.DeleteContent)
 	// Section routes
 	core.Get("/new-section", handler.NewSection)
@@ -23,5 +90,63 @@ func NewWebRouter(handler *WebHandler, mw []am.Middleware, opts ...am.Option) *a
 	core.Get("/new-layout", handler.NewLayout)
 	core.Post("/create-layout", handler.CreateLayout)
 
+	// Redirect routes
+	core.Get("/new-redirect", handler.NewRedirect)
+	core.Post("/create-redirect", handler.CreateRedirect)
+	core.Get("/edit-redirect", handler.EditRedirect)
+	core.Post("/update-redirect", handler.UpdateRedirect)
+	core.Get("/list-redirects", handler.ListRedirects)
+	core.Post("/delete-redirect", handler.DeleteRedirect)
+
+	// Publish target routes
+	core.Get("/new-publish-target", handler.NewPublishTarget)
+	core.Post("/create-publish-target", handler.CreatePublishTarget)
+	core.Get("/edit-publish-target", handler.EditPublishTarget)
+	core.Post("/update-publish-target", handler.UpdatePublishTarget)
+	core.Get("/list-publish-targets", handler.ListPublishTargets)
+	core.Get("/show-publish-target", handler.ShowPublishTarget)
+	core.Post("/delete-publish-target", handler.DeletePublishTarget)
+
+	// Webhook routes
+	core.Get("/new-webhook", handler.NewWebhook)
+	core.Post("/create-webhook", handler.CreateWebhook)
+	core.Get("/edit-webhook", handler.EditWebhook)
+	core.Post("/update-webhook", handler.UpdateWebhook)
+	core.Get("/list-webhooks", handler.ListWebhooks)
+	core.Post("/delete-webhook", handler.DeleteWebhook)
+	core.Get("/list-webhook-deliveries", handler.ListWebhookDeliveries)
+	core.Post("/redeliver-webhook", handler.RedeliverWebhook)
+
+	// API token routes
+	core.Get("/new-api-token", handler.NewAPIToken)
+	core.Post("/create-api-token", handler.CreateAPIToken)
+	core.Get("/list-api-tokens", handler.ListAPITokens)
+	core.Post("/delete-api-token", handler.DeleteAPIToken)
+
+	// Site generation routes
+	core.Post("/generate-site", handler.GenerateSite)
+	core.Get("/list-builds", handler.ListBuilds)
+	core.Get("/show-build", handler.ShowBuild)
+	core.Post("/rollback-build", handler.RollbackBuild)
+	core.Post("/publish-build", handler.PublishBuild)
+	core.Post("/promote-build", handler.PromoteBuild)
+	core.Get("/show-link-report", handler.ShowLinkReport)
+	core.Get("/link-report.json", handler.LinkReportJSON)
+
+	return core
+}
+
+// NewAPIRouter routes the token authenticated JSON API.
+func NewAPIRouter(handler *APIHandler, opts ...am.Option) *am.Router {
+	core := am.NewAPIRouter("api-router", opts...)
+	core.Use(handler.Authenticate)
+	core.Use(handler.Site)
+
+	// Build routes
+	core.With(handler.RequireScope(ScopeBuildsRead)).Get("/builds", handler.ListBuilds)
+	core.With(handler.RequireScope(ScopeBuildsWrite), handler.Idempotent).Post("/builds", handler.TriggerBuild)
+	core.With(handler.RequireScope(ScopeBuildsRead)).Get("/builds/{id}", handler.GetBuild)
+	core.With(handler.RequireScope(ScopePublish), handler.Idempotent).Post("/builds/{id}/publish", handler.PublishBuild)
+
 	return core
 }
@@ -0,0 +1,346 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defS3Region   = "us-east-1"
+	s3Timeout     = 60 * time.Second
+	s3ErrBodySize = 1024
+	amzDateFormat = "20060102T150405Z"
+	emptySHA256   = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Config holds the settings of an S3 compatible target. Objects are
+// addressed path style (endpoint/bucket/key), which AWS, MinIO and most
+// other implementations accept.
+type S3Config struct {
+	Endpoint  string // Scheme defaults to https
+	Region    string
+	Bucket    string
+	Prefix    string // Key prefix the site is published under
+	AccessKey string
+	SecretKey string
+}
+
+// S3Publisher uploads the build output to an S3 compatible bucket. Objects
+// are compared by ETag, which S3 sets to the MD5 of objects uploaded in a
+// single request, so only changed files are transferred. Like the other
+// publishers it records what it wrote in a manifest object and only deletes
+// objects listed there.
+type S3Publisher struct {
+	cfg    S3Config
+	client HTTPClient
+	now    func() time.Time
+}
+
+// NewS3Publisher returns a publisher that uses client for requests. A nil
+// client uses a default one.
+func NewS3Publisher(cfg S3Config, client HTTPClient) *S3Publisher {
+	if client == nil {
+		client = &http.Client{Timeout: s3Timeout}
+	}
+	if cfg.Region == "" {
+		cfg.Region = defS3Region
+	}
+	if !strings.Contains(cfg.Endpoint, "://") {
+		cfg.Endpoint = "https://" + cfg.Endpoint
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	return &S3Publisher{cfg: cfg, client: client, now: time.Now}
+}
+
+func (p *S3Publisher) Publish(ctx context.Context, dir string, build Build, author Author, dryRun bool) (PublishResult, error) {
+	result := PublishResult{DryRun: dryRun}
+
+	local, err := hashFiles(dir, md5.New)
+	if err != nil {
+		return result, err
+	}
+
+	remote, err := p.list(ctx)
+	if err != nil {
+		return result, fmt.Errorf("cannot list bucket: %w", err)
+	}
+
+	manifest, err := p.readManifest(ctx, remote)
+	if err != nil {
+		return result, err
+	}
+
+	// Objects are compared by their ETag so that the ones changed or removed
+	// by others are uploaded again, but only those in the manifest are deleted.
+	for _, c := range diffFiles(local, remote) {
+		if _, ok := manifest.Files[c.Path]; c.Op == ChangeDeleted && !ok {
+			continue
+		}
+		result.Changes = append(result.Changes, c)
+	}
+	if dryRun || !result.HasChanges() {
+		return result, nil
+	}
+
+	for _, c := range result.Changes {
+		if c.Op == ChangeDeleted {
+			err = p.delete(ctx, c.Path)
+		} else {
+			err = p.putFile(ctx, filepath.Join(dir, filepath.FromSlash(c.Path)), c.Path)
+		}
+		if err != nil {
+			return result, fmt.Errorf("cannot publish %s: %w", c.Path, err)
+		}
+	}
+
+	return result, p.writeManifest(ctx, publishManifest{Files: local})
+}
+
+// readManifest returns the manifest of the last publish, an empty one if there
+// is none among the remote objects.
+func (p *S3Publisher) readManifest(ctx context.Context, remote map[string]string) (publishManifest, error) {
+	manifest := publishManifest{Files: map[string]string{}}
+	if _, ok := remote[manifestFile]; !ok {
+		return manifest, nil
+	}
+
+	res, err := p.do(ctx, http.MethodGet, p.key(manifestFile), nil, nil, nil)
+	if err != nil {
+		return manifest, err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("cannot read publish manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest, nil
+}
+
+func (p *S3Publisher) writeManifest(ctx context.Context, manifest publishManifest) error {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return p.put(ctx, manifestFile, b)
+}
+
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+	} `xml:"Contents"`
+}
+
+// list returns the ETag of every object under the prefix keyed by its path
+// relative to the prefix.
+func (p *S3Publisher) list(ctx context.Context) (map[string]string, error) {
+	objects := make(map[string]string)
+	prefix := ""
+	if p.cfg.Prefix != "" {
+		prefix = p.cfg.Prefix + "/"
+	}
+
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		res, err := p.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list s3ListResult
+		err = xml.NewDecoder(res.Body).Decode(&list)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range list.Contents {
+			objects[strings.TrimPrefix(o.Key, prefix)] = strings.Trim(o.ETag, `"`)
+		}
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = list.NextContinuationToken
+	}
+}
+
+func (p *S3Publisher) putFile(ctx context.Context, file, name string) error {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return p.put(ctx, name, body)
+}
+
+func (p *S3Publisher) put(ctx context.Context, name string, body []byte) error {
+	sum := md5.Sum(body)
+	header := http.Header{
+		"Content-Type":  {mediaType(name)},
+		"Cache-Control": {cacheControl(name)},
+		"Content-Md5":   {base64.StdEncoding.EncodeToString(sum[:])},
+	}
+
+	res, err := p.do(ctx, http.MethodPut, p.key(name), nil, header, body)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (p *S3Publisher) delete(ctx context.Context, name string) error {
+	res, err := p.do(ctx, http.MethodDelete, p.key(name), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (p *S3Publisher) key(name string) string {
+	if p.cfg.Prefix == "" {
+		return name
+	}
+	return p.cfg.Prefix + "/" + name
+}
+
+// do sends a signed request for an object key, or for the bucket if key is
+// empty. Responses other than 2xx are returned as errors.
+func (p *S3Publisher) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	segments := []string{awsEscape(p.cfg.Bucket)}
+	if key != "" {
+		for _, s := range strings.Split(key, "/") {
+			segments = append(segments, awsEscape(s))
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.cfg.Endpoint+"/"+strings.Join(segments, "/"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = canonicalQuery(query)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	signV4(req, body, p.cfg.AccessKey, p.cfg.SecretKey, p.cfg.Region, p.now())
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, s3ErrBodySize))
+		return nil, fmt.Errorf("%s %s: %s: %s", method, req.URL.Path, res.Status, bytes.TrimSpace(msg))
+	}
+	return res, nil
+}
+
+// signV4 signs a request with AWS Signature Version 4 for the S3 service.
+func signV4(req *http.Request, body []byte, accessKey, secretKey, region string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format(amzDateFormat)
+	date := amzDate[:8]
+
+	payloadHash := emptySHA256
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes a query the way Signature Version 4 expects it:
+// sorted by key and with every reserved character escaped.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent encodes everything but unreserved characters.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
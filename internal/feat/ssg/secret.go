@@ -0,0 +1,16 @@
+package ssg
+
+import (
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// seal encrypts data stored at rest, such as publish credentials and webhook
+// secrets, with the configured encryption key.
+func seal(cfg *am.Config, data []byte) ([]byte, error) {
+	return am.NewCrypto(cfg.ByteSliceVal(key.SecEncryptionKey)).Encrypt(data)
+}
+
+// unseal decrypts data encrypted by seal.
+func unseal(cfg *am.Config, data []byte) ([]byte, error) {
+	return am.NewCrypto(cfg.ByteSliceVal(key.SecEncryptionKey)).Decrypt(data)
+}
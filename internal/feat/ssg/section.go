@@ -17,8 +17,11 @@ type Section struct {
 	Description string    `json:"description"`
 	Path        string    `json:"path"`
 	LayoutID    uuid.UUID `json:"layout_id"`
+	Lang        string    `json:"lang"`
+	Permalink   string    `json:"permalink"`
 	Image       string    `json:"image"`
 	Header      string    `json:"header"`
+	PageMeta
 }
 
 func NewSection(name, description, path string, layoutID uuid.UUID) Section {
@@ -31,6 +34,21 @@ func NewSection(name, description, path string, layoutID uuid.UUID) Section {
 	}
 }
 
+// ServesLang reports whether content in the given language can be published
+// under this section. Sections without a language serve every language.
+func (s Section) ServesLang(lang string) bool {
+	return s.Lang == "" || s.Lang == lang
+}
+
+// PermalinkOrDefault returns the permalink pattern of the section or the
+// default one if none was set.
+func (s Section) PermalinkOrDefault() string {
+	if s.Permalink == "" {
+		return defPermalink
+	}
+	return s.Permalink
+}
+
 func (s *Section) Slug() string {
 	return am.Normalize(s.Name) + "-" + s.ShortID()
 }
@@ -7,16 +7,24 @@ import (
 )
 
 type SectionDA struct {
-	ID          uuid.UUID  `db:"id"`
-	ShortID     string     `db:"short_id"`
-	Name        string     `db:"name"`
-	Description string     `db:"description"`
-	Path        string     `db:"path"`
-	LayoutID    string     `db:"layout_id"`
-	Image       string     `db:"image"`
-	Header      string     `db:"header"`
-	CreatedBy   *string    `db:"created_by"`
-	UpdatedBy   *string    `db:"updated_by"`
-	CreatedAt   *time.Time `db:"created_at"`
-	UpdatedAt   *time.Time `db:"updated_at"`
+	ID              uuid.UUID  `db:"id"`
+	ShortID         string     `db:"short_id"`
+	SiteID          string     `db:"site_id"`
+	Name            string     `db:"name"`
+	Description     string     `db:"description"`
+	Path            string     `db:"path"`
+	LayoutID        string     `db:"layout_id"`
+	Lang            string     `db:"lang"`
+	Permalink       string     `db:"permalink"`
+	Image           string     `db:"image"`
+	Header          string     `db:"header"`
+	MetaTitle       string     `db:"meta_title"`
+	MetaDescription string     `db:"meta_description"`
+	CanonicalURL    string     `db:"canonical_url"`
+	SocialImage     string     `db:"social_image"`
+	NoIndex         bool       `db:"noindex"`
+	CreatedBy       *string    `db:"created_by"`
+	UpdatedBy       *string    `db:"updated_by"`
+	CreatedAt       *time.Time `db:"created_at"`
+	UpdatedAt       *time.Time `db:"updated_at"`
 }
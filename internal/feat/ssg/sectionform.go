@@ -1,6 +1,7 @@
 package ssg
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/adrianpk/hermes/internal/am"
@@ -8,12 +9,19 @@ import (
 
 type SectionForm struct {
 	*am.BaseForm
-	Name        string `form:"name" required:"true"`
-	Description string `form:"description"`
-	Path        string `form:"path"`
-	LayoutID    string `form:"layout_id"`
-	Image       string `form:"image"`
-	Header      string `form:"header"`
+	Name            string `form:"name" required:"true"`
+	Description     string `form:"description"`
+	Path            string `form:"path"`
+	LayoutID        string `form:"layout_id"`
+	Lang            string `form:"lang"`
+	Permalink       string `form:"permalink"`
+	Image           string `form:"image"`
+	Header          string `form:"header"`
+	MetaTitle       string `form:"meta_title"`
+	MetaDescription string `form:"meta_description"`
+	CanonicalURL    string `form:"canonical_url"`
+	SocialImage     string `form:"social_image"`
+	NoIndex         string `form:"noindex"`
 }
 
 func NewSectionForm(r *http.Request) SectionForm {
@@ -36,6 +44,8 @@ func (form *SectionForm) Validate() error {
 	validate := am.ComposeValidators(
 		am.MinLength("name", form.Name, 3),
 		am.MaxLength("name", form.Name, 100),
+		validPermalink("permalink", form.Permalink),
+		validPageMeta(form.MetaTitle, form.MetaDescription, form.CanonicalURL, form.SocialImage),
 	)
 	v, err := validate(*form)
 	if err != nil {
@@ -44,3 +54,18 @@ func (form *SectionForm) Validate() error {
 	form.SetValidation(&v)
 	return nil
 }
+
+// validPermalink checks the permalink pattern of a section. An empty pattern is
+// valid and means the default one is used.
+func validPermalink(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val == "" {
+			return v, nil
+		}
+		if err := ValidatePermalink(val); err != nil {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: %s", field, err))
+		}
+		return v, nil
+	}
+}
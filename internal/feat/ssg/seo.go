@@ -0,0 +1,286 @@
+package ssg
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// PageMeta holds the search engine and social sharing settings of a content
+// or a section. Empty values fall back to the section and then to the site
+// settings.
+type PageMeta struct {
+	MetaTitle       string `json:"meta_title"`       // Title tag, the heading followed by the site title if empty
+	MetaDescription string `json:"meta_description"` // Meta and Open Graph description
+	CanonicalURL    string `json:"canonical_url"`    // Absolute URL, the page URL on the site if empty
+	SocialImage     string `json:"social_image"`     // Open Graph and Twitter card image
+	NoIndex         bool   `json:"noindex"`          // Keeps search engines from indexing the page
+}
+
+// SEO is the resolved metadata of a page, as layouts see it in .SEO.
+// The default title and head blocks render it. Layouts that replace them
+// usually render it as a whole with {{ .SEO.Tags }} in the head and
+// {{ .SEO.JSONLD }} anywhere in the page.
+type SEO struct {
+	Title       string
+	Description string
+	Canonical   string
+	Image       string // Absolute if the site has a base URL
+	NoIndex     bool
+	Lang        string
+	SiteName    string
+	Article     Article
+	Breadcrumbs []Breadcrumb
+	Org         Organization
+}
+
+// Article is the content of a page as described by schema.org. Archive
+// pages have no article and leave it zero.
+type Article struct {
+	Headline  string
+	Authors   []string
+	Published time.Time
+	Modified  time.Time
+}
+
+// Breadcrumb is a step of the path from the site home to a page.
+type Breadcrumb struct {
+	Name string
+	URL  string
+}
+
+// Organization is the publisher of the site as described by schema.org.
+type Organization struct {
+	Name string
+	URL  string
+	Logo string
+}
+
+// pageSEO resolves the metadata of the page of a content published under
+// section at url. def is the default language of the site.
+func pageSEO(site SiteSettings, section Section, content Content, lang, def, url string) SEO {
+	seo := SEO{
+		Title:       content.MetaTitle,
+		Description: firstNonEmpty(content.MetaDescription, section.MetaDescription, site.Description),
+		Canonical:   content.CanonicalURL,
+		Image:       firstNonEmpty(content.SocialImage, section.SocialImage, site.SocialImage),
+		NoIndex:     content.NoIndex || section.NoIndex,
+		Lang:        lang,
+		SiteName:    site.Title,
+		Article: Article{
+			Headline:  content.Heading,
+			Published: content.PublishedAt,
+			Modified:  content.UpdatedAt(),
+		},
+		Org: Organization{
+			Name: site.Title,
+			URL:  site.AbsURL("/"),
+			Logo: absMediaURL(site, site.Logo),
+		},
+	}
+
+	if site.AuthorName != "" {
+		seo.Article.Authors = []string{site.AuthorName}
+	}
+
+	if seo.Title == "" {
+		seo.Title = titleWithSite(site, content.Heading)
+	}
+	if seo.Canonical == "" {
+		seo.Canonical = site.AbsURL(url)
+	}
+	seo.Image = absMediaURL(site, seo.Image)
+
+	seo.Breadcrumbs = []Breadcrumb{{Name: firstNonEmpty(site.Title, "Home"), URL: site.AbsURL(LangPrefix(lang, def) + "/")}}
+	if section.Path != "" && section.Path != "/" {
+		seo.Breadcrumbs = append(seo.Breadcrumbs, Breadcrumb{Name: section.Name, URL: site.AbsURL(SectionURL(lang, def, section))})
+	}
+	seo.Breadcrumbs = append(seo.Breadcrumbs, Breadcrumb{Name: content.Heading, URL: site.AbsURL(url)})
+	return seo
+}
+
+// listingSEO resolves the metadata of an archive page titled title published
+// at url. Archive pages fall back to the site description and image. trail
+// holds the breadcrumbs between the home page and the archive, with site
+// relative URLs.
+func listingSEO(site SiteSettings, title, description, image, lang, def, url string, trail ...Breadcrumb) SEO {
+	seo := SEO{
+		Title:       titleWithSite(site, title),
+		Description: firstNonEmpty(description, site.Description),
+		Canonical:   site.AbsURL(url),
+		Image:       absMediaURL(site, firstNonEmpty(image, site.SocialImage)),
+		Lang:        lang,
+		SiteName:    site.Title,
+		Org: Organization{
+			Name: site.Title,
+			URL:  site.AbsURL("/"),
+			Logo: absMediaURL(site, site.Logo),
+		},
+	}
+
+	seo.Breadcrumbs = []Breadcrumb{{Name: firstNonEmpty(site.Title, "Home"), URL: site.AbsURL(LangPrefix(lang, def) + "/")}}
+	for _, c := range trail {
+		seo.Breadcrumbs = append(seo.Breadcrumbs, Breadcrumb{Name: c.Name, URL: site.AbsURL(c.URL)})
+	}
+	seo.Breadcrumbs = append(seo.Breadcrumbs, Breadcrumb{Name: title, URL: site.AbsURL(url)})
+	return seo
+}
+
+// titleWithSite returns title followed by the site title, if there is one.
+func titleWithSite(site SiteSettings, title string) string {
+	if site.Title == "" {
+		return title
+	}
+	return title + " | " + site.Title
+}
+
+// Tags returns the title tag along with the tags of Meta.
+func (s SEO) Tags() template.HTML {
+	return template.HTML("<title>"+template.HTMLEscapeString(s.Title)+"</title>\n") + s.Meta()
+}
+
+// Meta returns the description, canonical, robots, Open Graph and Twitter
+// card tags, for layouts that write the title tag themselves.
+func (s SEO) Meta() template.HTML {
+	var b strings.Builder
+	esc := template.HTMLEscapeString
+
+	meta := func(attr, key, val string) {
+		if val != "" {
+			fmt.Fprintf(&b, "<meta %s=\"%s\" content=\"%s\">\n", attr, esc(key), esc(val))
+		}
+	}
+
+	meta("name", "description", s.Description)
+	if s.Canonical != "" {
+		fmt.Fprintf(&b, "<link rel=\"canonical\" href=\"%s\">\n", esc(s.Canonical))
+	}
+	if s.NoIndex {
+		meta("name", "robots", "noindex, nofollow")
+	}
+
+	ogType, ogTitle := "article", s.Article.Headline
+	if ogTitle == "" {
+		ogType, ogTitle = "website", s.Title
+	}
+	meta("property", "og:type", ogType)
+	meta("property", "og:title", ogTitle)
+	meta("property", "og:description", s.Description)
+	meta("property", "og:url", s.Canonical)
+	meta("property", "og:site_name", s.SiteName)
+	meta("property", "og:locale", s.Lang)
+	meta("property", "og:image", s.Image)
+
+	card := "summary"
+	if s.Image != "" {
+		card = "summary_large_image"
+	}
+	meta("name", "twitter:card", card)
+	meta("name", "twitter:title", ogTitle)
+	meta("name", "twitter:description", s.Description)
+	meta("name", "twitter:image", s.Image)
+
+	return template.HTML(b.String())
+}
+
+// JSONLD returns the Article, BreadcrumbList and Organization structured data
+// of the page as JSON-LD script tags. Archive pages have no Article.
+func (s SEO) JSONLD() template.HTML {
+	organization := func() map[string]any {
+		org := map[string]any{
+			"@type": "Organization",
+			"name":  s.Org.Name,
+			"url":   s.Org.URL,
+		}
+		if s.Org.Logo != "" {
+			org["logo"] = s.Org.Logo
+		}
+		return org
+	}
+
+	article := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "Article",
+		"headline":         s.Article.Headline,
+		"mainEntityOfPage": s.Canonical,
+		"inLanguage":       s.Lang,
+		"publisher":        organization(),
+	}
+	if s.Description != "" {
+		article["description"] = s.Description
+	}
+	if s.Image != "" {
+		article["image"] = s.Image
+	}
+	authors := make([]map[string]any, len(s.Article.Authors))
+	for i, name := range s.Article.Authors {
+		authors[i] = map[string]any{"@type": "Person", "name": name}
+	}
+	switch len(authors) {
+	case 0:
+	case 1:
+		article["author"] = authors[0]
+	default:
+		article["author"] = authors
+	}
+	if !s.Article.Published.IsZero() {
+		article["datePublished"] = s.Article.Published.Format(time.RFC3339)
+	}
+	if !s.Article.Modified.IsZero() {
+		article["dateModified"] = s.Article.Modified.Format(time.RFC3339)
+	}
+
+	items := make([]map[string]any, len(s.Breadcrumbs))
+	for i, c := range s.Breadcrumbs {
+		items[i] = map[string]any{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     c.Name,
+			"item":     c.URL,
+		}
+	}
+	breadcrumbs := map[string]any{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+
+	org := organization()
+	org["@context"] = "https://schema.org"
+
+	docs := []map[string]any{article, breadcrumbs, org}
+	if s.Article.Headline == "" {
+		docs = docs[1:]
+	}
+
+	var b strings.Builder
+	for _, doc := range docs {
+		// json.Marshal escapes <, > and &, so the data cannot close the script.
+		data, err := json.Marshal(doc)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "<script type=\"application/ld+json\">%s</script>\n", data)
+	}
+	return template.HTML(b.String())
+}
+
+// absMediaURL returns the absolute URL of a site path. URLs elsewhere are
+// returned as they are.
+func absMediaURL(site SiteSettings, ref string) string {
+	if !strings.HasPrefix(ref, "/") {
+		return ref
+	}
+	return site.AbsURL(ref)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package ssg
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestSEOSite() SiteSettings {
+	site := NewSiteSettings("Notes", "en")
+	site.BaseURL = "https://example.com/"
+	site.AuthorName = "Jane Doe"
+	site.Description = "Site description"
+	site.SocialImage = "/img/site.png"
+	site.Logo = "/img/logo.png"
+	return site
+}
+
+func TestPageSEOFallbacks(t *testing.T) {
+	site := newTestSEOSite()
+	section := NewSection("Blog", "", "blog", uuid.Nil)
+	content := NewContent("Hello", "")
+
+	seo := pageSEO(site, section, content, "es", "en", "/es/blog/hello/")
+	if seo.Title != "Hello | Notes" {
+		t.Errorf("Title = %q", seo.Title)
+	}
+	if seo.Description != "Site description" {
+		t.Errorf("Description = %q", seo.Description)
+	}
+	if seo.Canonical != "https://example.com/es/blog/hello/" {
+		t.Errorf("Canonical = %q", seo.Canonical)
+	}
+	if seo.Image != "https://example.com/img/site.png" {
+		t.Errorf("Image = %q", seo.Image)
+	}
+	if seo.Org.Logo != "https://example.com/img/logo.png" {
+		t.Errorf("Org.Logo = %q", seo.Org.Logo)
+	}
+
+	want := []Breadcrumb{
+		{Name: "Notes", URL: "https://example.com/es/"},
+		{Name: "Blog", URL: "https://example.com/es/blog/"},
+		{Name: "Hello", URL: "https://example.com/es/blog/hello/"},
+	}
+	if len(seo.Breadcrumbs) != len(want) {
+		t.Fatalf("Breadcrumbs = %v", seo.Breadcrumbs)
+	}
+	for i, c := range want {
+		if seo.Breadcrumbs[i] != c {
+			t.Errorf("Breadcrumbs[%d] = %v, want %v", i, seo.Breadcrumbs[i], c)
+		}
+	}
+
+	section.MetaDescription = "Section description"
+	section.SocialImage = "https://cdn.example.org/blog.png"
+	section.NoIndex = true
+	seo = pageSEO(site, section, content, "en", "en", "/blog/hello/")
+	if seo.Description != "Section description" || seo.Image != "https://cdn.example.org/blog.png" || !seo.NoIndex {
+		t.Errorf("section values not used: %+v", seo)
+	}
+
+	content.MetaTitle = "Custom title"
+	content.MetaDescription = "Content description"
+	content.CanonicalURL = "https://elsewhere.example.org/hello"
+	content.SocialImage = "/img/hello.png"
+	seo = pageSEO(site, section, content, "en", "en", "/blog/hello/")
+	if seo.Title != "Custom title" || seo.Description != "Content description" ||
+		seo.Canonical != "https://elsewhere.example.org/hello" || seo.Image != "https://example.com/img/hello.png" {
+		t.Errorf("content values not used: %+v", seo)
+	}
+}
+
+func TestPageSEORootSection(t *testing.T) {
+	site := newTestSEOSite()
+	site.Title = ""
+	section := NewSection("Root", "", "/", uuid.Nil)
+	content := NewContent("About", "")
+
+	seo := pageSEO(site, section, content, "en", "en", "/about/")
+	if seo.Title != "About" {
+		t.Errorf("Title = %q", seo.Title)
+	}
+	if len(seo.Breadcrumbs) != 2 || seo.Breadcrumbs[0].Name != "Home" || seo.Breadcrumbs[1].Name != "About" {
+		t.Errorf("Breadcrumbs = %v", seo.Breadcrumbs)
+	}
+}
+
+func TestSEOTags(t *testing.T) {
+	seo := SEO{
+		Title:       `Tom & "Jerry"`,
+		Description: "<b>bold</b>",
+		Canonical:   "https://example.com/a/",
+		NoIndex:     true,
+		Article:     Article{Headline: "Tom & Jerry"},
+	}
+
+	tags := string(seo.Tags())
+	for _, want := range []string{
+		"<title>Tom &amp; &#34;Jerry&#34;</title>",
+		`<meta name="description" content="&lt;b&gt;bold&lt;/b&gt;">`,
+		`<link rel="canonical" href="https://example.com/a/">`,
+		`<meta name="robots" content="noindex, nofollow">`,
+		`<meta name="twitter:card" content="summary">`,
+	} {
+		if !strings.Contains(tags, want) {
+			t.Errorf("missing %s in\n%s", want, tags)
+		}
+	}
+	if strings.Contains(tags, "og:image") {
+		t.Errorf("unexpected og:image in\n%s", tags)
+	}
+
+	seo.NoIndex = false
+	seo.Image = "https://example.com/a.png"
+	tags = string(seo.Tags())
+	if strings.Contains(tags, "robots") || !strings.Contains(tags, `content="summary_large_image"`) {
+		t.Errorf("unexpected tags\n%s", tags)
+	}
+}
+
+func TestSEOJSONLD(t *testing.T) {
+	seo := pageSEO(newTestSEOSite(), NewSection("Blog", "", "blog", uuid.Nil), NewContent("</script><b>", ""), "en", "en", "/blog/x/")
+
+	out := string(seo.JSONLD())
+	if strings.Contains(out, "</script><b>") {
+		t.Fatalf("heading not escaped\n%s", out)
+	}
+
+	re := regexp.MustCompile(`<script type="application/ld\+json">(.*?)</script>`)
+	matches := re.FindAllStringSubmatch(out, -1)
+	if len(matches) != 3 {
+		t.Fatalf("got %d scripts\n%s", len(matches), out)
+	}
+
+	docs := make(map[string]map[string]any)
+	for _, m := range matches {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(m[1]), &doc); err != nil {
+			t.Fatalf("invalid JSON %s: %v", m[1], err)
+		}
+		docs[doc["@type"].(string)] = doc
+	}
+
+	article := docs["Article"]
+	if article["headline"] != "</script><b>" || article["author"].(map[string]any)["name"] != "Jane Doe" {
+		t.Errorf("Article = %v", article)
+	}
+	if _, ok := article["publisher"].(map[string]any)["@context"]; ok {
+		t.Errorf("publisher has @context: %v", article["publisher"])
+	}
+	if items := docs["BreadcrumbList"]["itemListElement"].([]any); len(items) != 3 {
+		t.Errorf("breadcrumbs = %v", items)
+	}
+	if docs["Organization"]["logo"] != "https://example.com/img/logo.png" {
+		t.Errorf("Organization = %v", docs["Organization"])
+	}
+}
+
+func TestListingSEO(t *testing.T) {
+	seo := listingSEO(newTestSEOSite(), "Jane Doe", "", "", "es", "en", "/es/authors/jane-doe/")
+	if seo.Title != "Jane Doe | Notes" || seo.Description != "Site description" || seo.Image != "https://example.com/img/site.png" {
+		t.Errorf("unexpected fallbacks: %+v", seo)
+	}
+	if len(seo.Breadcrumbs) != 2 || seo.Breadcrumbs[1].URL != "https://example.com/es/authors/jane-doe/" {
+		t.Errorf("Breadcrumbs = %v", seo.Breadcrumbs)
+	}
+
+	tags := string(seo.Tags())
+	if !strings.Contains(tags, `<meta property="og:type" content="website">`) ||
+		!strings.Contains(tags, `<meta property="og:title" content="Jane Doe | Notes">`) {
+		t.Errorf("unexpected tags\n%s", tags)
+	}
+
+	out := string(seo.JSONLD())
+	if strings.Contains(out, `"Article"`) || strings.Count(out, "<script") != 2 {
+		t.Errorf("unexpected structured data\n%s", out)
+	}
+}
+
+func TestSEOJSONLDAuthors(t *testing.T) {
+	seo := pageSEO(newTestSEOSite(), NewSection("Blog", "", "blog", uuid.Nil), NewContent("Hello", ""), "en", "en", "/blog/hello/")
+	seo.Article.Authors = []string{"Jane Doe", "John Roe"}
+
+	re := regexp.MustCompile(`<script type="application/ld\+json">(.*?)</script>`)
+	var article map[string]any
+	if err := json.Unmarshal([]byte(re.FindStringSubmatch(string(seo.JSONLD()))[1]), &article); err != nil {
+		t.Fatal(err)
+	}
+
+	authors, ok := article["author"].([]any)
+	if !ok || len(authors) != 2 || authors[1].(map[string]any)["name"] != "John Roe" {
+		t.Errorf("author = %v", article["author"])
+	}
+}
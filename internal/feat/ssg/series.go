@@ -0,0 +1,183 @@
+package ssg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	seriesType = "series"
+)
+
+// Series is an ordered set of contents meant to be read one after the other.
+// Layouts render the series of a page as .Series.
+type Series struct {
+	*am.BaseModel
+	Name        string `json:"name"`
+	SlugValue   string `json:"slug"`
+	Description string `json:"description"`
+}
+
+func NewSeries(name, description string) Series {
+	return Series{
+		BaseModel:   am.NewModel(am.WithType(seriesType)),
+		Name:        name,
+		SlugValue:   Slugify(name),
+		Description: description,
+	}
+}
+
+func (s Series) IsZero() bool {
+	return s.BaseModel == nil || s.BaseModel.IsZero()
+}
+
+func (s *Series) Slug() string {
+	return s.SlugValue
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (s *Series) UnmarshalJSON(data []byte) error {
+	type Alias Series
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*s = Series(*temp)
+	if s.BaseModel == nil {
+		s.BaseModel = am.NewModel(am.WithType(seriesType))
+	}
+	return nil
+}
+
+// SeriesPart places a content in a series. A content is part of one series
+// at most, its translations are the same part in other languages.
+type SeriesPart struct {
+	SeriesID  uuid.UUID
+	ContentID uuid.UUID
+	Position  int
+}
+
+// seriesContents returns the contents of a series in order. Parts whose
+// content is missing are left out.
+func seriesContents(parts []SeriesPart, contents []Content, seriesID uuid.UUID) []Content {
+	byID := make(map[uuid.UUID]Content, len(contents))
+	for _, c := range contents {
+		byID[c.ID()] = c
+	}
+
+	var result []Content
+	for _, p := range parts {
+		if p.SeriesID != seriesID {
+			continue
+		}
+		if c, ok := byID[p.ContentID]; ok {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// seriesOrder returns the IDs of the contents of a series, in order.
+func seriesOrder(parts []SeriesPart, seriesID uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, p := range parts {
+		if p.SeriesID == seriesID {
+			ids = append(ids, p.ContentID)
+		}
+	}
+	return ids
+}
+
+// checkSeriesContent checks that neither a content nor any of its
+// translations is part of a series already.
+func checkSeriesContent(parts []SeriesPart, contents []Content, contentID uuid.UUID) error {
+	keys := make(map[uuid.UUID]uuid.UUID, len(contents))
+	for _, c := range contents {
+		keys[c.ID()] = c.TranslationKey()
+	}
+
+	key, ok := keys[contentID]
+	if !ok {
+		key = contentID
+	}
+	for _, p := range parts {
+		if p.ContentID == contentID || keys[p.ContentID] == key {
+			return fmt.Errorf("%w: %s", ErrContentInSeries, contentID)
+		}
+	}
+	return nil
+}
+
+// checkSeriesOrder checks that a reorder lists every content of the series
+// once and nothing else.
+func checkSeriesOrder(current, order []uuid.UUID) error {
+	if len(current) != len(order) {
+		return fmt.Errorf("%w: expected %d parts, got %d", ErrInvalidSeriesOrder, len(current), len(order))
+	}
+
+	inSeries := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		inSeries[id] = true
+	}
+	for _, id := range order {
+		if !inSeries[id] {
+			return fmt.Errorf("%w: content %s is not in the series or listed twice", ErrInvalidSeriesOrder, id)
+		}
+		delete(inSeries, id)
+	}
+	return nil
+}
+
+// SeriesNav is the series of a page as layouts see it in .Series.
+type SeriesNav struct {
+	Name        string
+	Slug        string
+	Description string
+	Index       int          // Number of the page in the series, from 1
+	Total       int          // Number of parts published in the language of the page
+	Prev        *SeriesLink  // Nil for the first part
+	Next        *SeriesLink  // Nil for the last part
+	Parts       []SeriesLink // Every part in order, the page included
+}
+
+// SeriesLink is a part of a series as layouts see it.
+type SeriesLink struct {
+	Number  int
+	Heading string
+	URL     string
+	Current bool // True for the page being rendered
+}
+
+// seriesNavs returns the navigation of the pages of a series, by page URL.
+// links are the parts published in the language of the pages, in order.
+func seriesNavs(series Series, links []SeriesLink) map[string]*SeriesNav {
+	navs := make(map[string]*SeriesNav, len(links))
+	for i, l := range links {
+		parts := make([]SeriesLink, len(links))
+		for j, other := range links {
+			other.Number = j + 1
+			other.Current = j == i
+			parts[j] = other
+		}
+
+		nav := &SeriesNav{
+			Name:        series.Name,
+			Slug:        series.SlugValue,
+			Description: series.Description,
+			Index:       i + 1,
+			Total:       len(links),
+			Parts:       parts,
+		}
+		if i > 0 {
+			nav.Prev = &parts[i-1]
+		}
+		if i < len(links)-1 {
+			nav.Next = &parts[i+1]
+		}
+		navs[l.URL] = nav
+	}
+	return navs
+}
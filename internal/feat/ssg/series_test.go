@@ -0,0 +1,116 @@
+package ssg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSeriesNavs(t *testing.T) {
+	series := NewSeries("Go basics", "")
+	links := []SeriesLink{
+		{Heading: "One", URL: "/one/"},
+		{Heading: "Two", URL: "/two/"},
+		{Heading: "Three", URL: "/three/"},
+	}
+
+	navs := seriesNavs(series, links)
+	if len(navs) != 3 {
+		t.Fatalf("got %d navs", len(navs))
+	}
+
+	first := navs["/one/"]
+	if first.Index != 1 || first.Total != 3 || first.Prev != nil || first.Next.URL != "/two/" {
+		t.Errorf("first = %+v", first)
+	}
+	if first.Slug != "go-basics" {
+		t.Errorf("Slug = %q", first.Slug)
+	}
+
+	middle := navs["/two/"]
+	if middle.Index != 2 || middle.Prev.URL != "/one/" || middle.Next.URL != "/three/" {
+		t.Errorf("middle = %+v", middle)
+	}
+	for i, p := range middle.Parts {
+		if p.Number != i+1 || p.Current != (i == 1) {
+			t.Errorf("Parts[%d] = %+v", i, p)
+		}
+	}
+
+	last := navs["/three/"]
+	if last.Next != nil || last.Prev.URL != "/two/" || last.Prev.Number != 2 {
+		t.Errorf("last = %+v", last)
+	}
+	if first.Parts[0].Current == last.Parts[0].Current {
+		t.Error("pages share their parts")
+	}
+}
+
+func TestCheckSeriesOrder(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	current := []uuid.UUID{a, b, c}
+
+	tests := []struct {
+		name  string
+		order []uuid.UUID
+		ok    bool
+	}{
+		{"same", []uuid.UUID{a, b, c}, true},
+		{"moved", []uuid.UUID{c, a, b}, true},
+		{"missing", []uuid.UUID{a, b}, false},
+		{"repeated", []uuid.UUID{a, a, b}, false},
+		{"foreign", []uuid.UUID{a, b, uuid.New()}, false},
+	}
+	for _, tt := range tests {
+		err := checkSeriesOrder(current, tt.order)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidSeriesOrder) {
+			t.Errorf("%s: expected ErrInvalidSeriesOrder, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestCheckSeriesContent(t *testing.T) {
+	en := NewContent("Hello", "")
+	en.GenCreateValues()
+	es := NewContent("Hola", "")
+	es.GenCreateValues()
+	es.TranslationID = en.ID()
+	other := NewContent("Other", "")
+	other.GenCreateValues()
+
+	contents := []Content{en, es, other}
+	parts := []SeriesPart{{SeriesID: uuid.New(), ContentID: en.ID()}}
+
+	if err := checkSeriesContent(parts, contents, en.ID()); !errors.Is(err, ErrContentInSeries) {
+		t.Errorf("member: got %v", err)
+	}
+	if err := checkSeriesContent(parts, contents, es.ID()); !errors.Is(err, ErrContentInSeries) {
+		t.Errorf("translation of a member: got %v", err)
+	}
+	if err := checkSeriesContent(parts, contents, other.ID()); err != nil {
+		t.Errorf("other: got %v", err)
+	}
+}
+
+func TestSeriesContents(t *testing.T) {
+	one := NewContent("One", "")
+	one.GenCreateValues()
+	two := NewContent("Two", "")
+	two.GenCreateValues()
+	seriesID := uuid.New()
+	parts := []SeriesPart{
+		{SeriesID: seriesID, ContentID: two.ID(), Position: 0},
+		{SeriesID: uuid.New(), ContentID: one.ID(), Position: 0},
+		{SeriesID: seriesID, ContentID: uuid.New(), Position: 1},
+		{SeriesID: seriesID, ContentID: one.ID(), Position: 2},
+	}
+
+	got := seriesContents(parts, []Content{one, two}, seriesID)
+	if len(got) != 2 || got[0].Heading != "Two" || got[1].Heading != "One" {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,27 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeriesDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
+	Name        string     `db:"name"`
+	Slug        string     `db:"slug"`
+	Description string     `db:"description"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
+
+type SeriesPartDA struct {
+	SiteID    string `db:"site_id"`
+	SeriesID  string `db:"series_id"`
+	ContentID string `db:"content_id"`
+	Position  int    `db:"position"`
+}
@@ -0,0 +1,52 @@
+package ssg
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+type SeriesForm struct {
+	*am.BaseForm
+	ID          string `form:"id"`
+	Name        string `form:"name" required:"true"`
+	Slug        string `form:"slug"`
+	Description string `form:"description"`
+}
+
+func NewSeriesForm(r *http.Request) SeriesForm {
+	return SeriesForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func SeriesFormFromRequest(r *http.Request) (sf SeriesForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return sf, err
+	}
+
+	return SeriesForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Name:        strings.TrimSpace(r.Form.Get("name")),
+		Slug:        strings.TrimSpace(r.Form.Get("slug")),
+		Description: strings.TrimSpace(r.Form.Get("description")),
+	}, nil
+}
+
+func (form *SeriesForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 128),
+		validSlug("slug", form.Slug),
+		am.MaxLength("description", form.Description, 512),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
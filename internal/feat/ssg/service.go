@@ -2,21 +2,121 @@ package ssg
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
 type Service interface {
+	CreateSite(ctx context.Context, site Site) error
+	GetSites(ctx context.Context) ([]Site, error)
+	GetSite(ctx context.Context, id string) (Site, error)
+	GetSiteBySlug(ctx context.Context, slug string) (Site, error)
+	UpdateSite(ctx context.Context, site Site) error
+	GetSiteSettings(ctx context.Context) (SiteSettings, error)
+	UpdateSiteSettings(ctx context.Context, settings SiteSettings) error
+	CreateContentType(ctx context.Context, ct ContentType) error
+	GetContentTypes(ctx context.Context) ([]ContentType, error)
+	GetContentType(ctx context.Context, id string) (ContentType, error)
+	UpdateContentType(ctx context.Context, ct ContentType) error
+	CreateDataFile(ctx context.Context, file DataFile) error
+	GetDataFiles(ctx context.Context) ([]DataFile, error)
+	GetDataFile(ctx context.Context, id string) (DataFile, error)
+	UpdateDataFile(ctx context.Context, file DataFile) error
+	DeleteDataFile(ctx context.Context, id string) error
+	GetDataFileStatuses(ctx context.Context) ([]DataFileStatus, error)
+	SyncDataFiles(ctx context.Context, userID uuid.UUID) (DataSyncReport, error)
+	CreateMenu(ctx context.Context, menu Menu) error
+	GetMenus(ctx context.Context) ([]Menu, error)
+	GetMenu(ctx context.Context, id string) (Menu, error)
+	UpdateMenu(ctx context.Context, menu Menu) error
+	DeleteMenu(ctx context.Context, id string) error
+	GetMenuItems(ctx context.Context, menuID uuid.UUID) ([]MenuItem, error)
+	GetMenuTree(ctx context.Context, menuID uuid.UUID) ([]MenuItemNode, error)
+	CreateMenuItem(ctx context.Context, item MenuItem) error
+	GetMenuItem(ctx context.Context, id string) (MenuItem, error)
+	UpdateMenuItem(ctx context.Context, item MenuItem) error
+	DeleteMenuItem(ctx context.Context, id string) error
+	ReorderMenu(ctx context.Context, menuID uuid.UUID, moves []MenuMove) error
+	CreateSeries(ctx context.Context, series Series) error
+	GetAllSeries(ctx context.Context) ([]Series, error)
+	GetSeries(ctx context.Context, id string) (Series, error)
+	UpdateSeries(ctx context.Context, series Series) error
+	DeleteSeries(ctx context.Context, id string) error
+	GetSeriesContents(ctx context.Context, seriesID uuid.UUID) ([]Content, error)
+	AddSeriesContent(ctx context.Context, seriesID, contentID uuid.UUID) error
+	RemoveSeriesContent(ctx context.Context, seriesID, contentID uuid.UUID) error
+	ReorderSeries(ctx context.Context, seriesID uuid.UUID, order []uuid.UUID) error
+	CreateProfile(ctx context.Context, profile Profile) error
+	GetProfiles(ctx context.Context) ([]Profile, error)
+	GetProfile(ctx context.Context, id string) (Profile, error)
+	UpdateProfile(ctx context.Context, profile Profile) error
+	DeleteProfile(ctx context.Context, id string) error
 	CreateContent(ctx context.Context, content Content) error
 	GetAllContent(ctx context.Context) ([]Content, error)
 	GetContent(ctx context.Context, id string) (Content, error)
-	UpdateContent(ctx context.Context, content Content) error
+	UpdateContent(ctx context.Context, content Content) (Content, error)
+	GetContentTranslations(ctx context.Context, translationID uuid.UUID) ([]Content, error)
+	LockContent(ctx context.Context, contentID, userID uuid.UUID) (ContentLock, error)
+	GetContentLocks(ctx context.Context) (map[uuid.UUID]ContentLock, error)
+	UnlockContent(ctx context.Context, contentID, userID uuid.UUID) error
+	SaveContentDraft(ctx context.Context, draft ContentDraft) error
+	GetContentDraft(ctx context.Context, contentID, userID uuid.UUID) (ContentDraft, error)
+	DiscardContentDraft(ctx context.Context, contentID, userID uuid.UUID) error
+	PreviewContent(ctx context.Context, content Content) ([]byte, error)
+	GetTranslationGroups(ctx context.Context) ([]TranslationGroup, error)
 	// GetAllContent(ctx context.Context) ([]Content, error)
 	// DeleteContent(ctx context.Context, id uuid.UUID) error
 	CreateSection(ctx context.Context, section Section) error
 	GetSections(ctx context.Context) ([]Section, error)
 	CreateLayout(ctx context.Context, layout Layout) error
 	GetAllLayouts(ctx context.Context) ([]Layout, error)
+	CreateRedirect(ctx context.Context, redirect Redirect) error
+	GetAllRedirects(ctx context.Context) ([]Redirect, error)
+	GetRedirect(ctx context.Context, id string) (Redirect, error)
+	UpdateRedirect(ctx context.Context, redirect Redirect) error
+	DeleteRedirect(ctx context.Context, id string) error
+	GenerateSite(ctx context.Context, trigger string, userID uuid.UUID) (Build, error)
+	StartBuild(ctx context.Context, trigger string, userID uuid.UUID) (Build, error)
+	GetBuilds(ctx context.Context) ([]Build, error)
+	GetBuild(ctx context.Context, id string) (Build, error)
+	GetBuildDiff(ctx context.Context, build Build) (prev Build, diff ReleaseDiff, err error)
+	RollbackBuild(ctx context.Context, id string) (Build, error)
+	PublishBuild(ctx context.Context, id, targetID string, userID uuid.UUID, author Author, dryRun bool) (Deployment, PublishResult, error)
+	PromoteBuild(ctx context.Context, id, targetID string, userID uuid.UUID, author Author) (Deployment, PublishResult, error)
+	GetBuildDeployments(ctx context.Context, id string) ([]Deployment, error)
+	CreatePublishTarget(ctx context.Context, target PublishTarget) error
+	GetPublishTargets(ctx context.Context) ([]PublishTarget, error)
+	GetPublishTarget(ctx context.Context, id string) (PublishTarget, error)
+	GetPublishTargetByName(ctx context.Context, name string) (PublishTarget, error)
+	UpdatePublishTarget(ctx context.Context, target PublishTarget) error
+	DeletePublishTarget(ctx context.Context, id string) error
+	GetTargetDeployments(ctx context.Context, targetID string) ([]Deployment, error)
+	CreateWebhook(ctx context.Context, hook Webhook) error
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id string) (Webhook, error)
+	UpdateWebhook(ctx context.Context, hook Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+	GetWebhookDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, deliveryID string) error
+	CreateAPIToken(ctx context.Context, token APIToken) (string, error)
+	GetAPITokens(ctx context.Context) ([]APIToken, error)
+	DeleteAPIToken(ctx context.Context, id string) error
+	AuthenticateAPIToken(ctx context.Context, secret string) (APIToken, error)
+	ReserveIdempotencyKey(ctx context.Context, key IdempotencyKey) (IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key IdempotencyKey) error
+	ReleaseIdempotencyKey(ctx context.Context, key IdempotencyKey) error
+	GetLinkReport(ctx context.Context) (LinkReport, error)
+	ImportSite(ctx context.Context, src ImportSource, userID uuid.UUID, opts ImportOptions) (ImportReport, error)
+	ExportSite(ctx context.Context, w io.Writer) (Archive, error)
+	RestoreSite(ctx context.Context, r io.Reader, userID uuid.UUID, opts RestoreOptions) (RestoreReport, error)
 }
 
 var (
@@ -25,32 +125,987 @@ var (
 
 type BaseService struct {
 	*am.Service
-	repo Repo
+	repo       Repo
+	gen        *Generator
+	dispatcher *Dispatcher
 }
 
-func NewService(repo Repo) *BaseService {
+func NewService(repo Repo, gen *Generator, dispatcher *Dispatcher) *BaseService {
 	return &BaseService{
-		Service: am.NewService("ssg-service"),
-		repo:    repo,
+		Service:    am.NewService("ssg-service"),
+		repo:       repo,
+		gen:        gen,
+		dispatcher: dispatcher,
 	}
 }
 
+// Site related
+
+// CreateSite saves a site along with its root section so that it can be
+// built right away.
+func (svc *BaseService) CreateSite(ctx context.Context, site Site) error {
+	if site.SlugValue == "" {
+		site.SlugValue = Slugify(site.Name)
+	}
+
+	err := svc.checkSiteSlug(ctx, site)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.CreateSite(ctx, site)
+	if err != nil {
+		return err
+	}
+
+	root := NewSection("root", "Top-level section of the site.", "/", uuid.Nil)
+	root.GenCreateValues(site.CreatedBy())
+	return svc.repo.CreateSection(WithSite(ctx, site), root)
+}
+
+func (svc *BaseService) GetSites(ctx context.Context) ([]Site, error) {
+	return svc.repo.GetSites(ctx)
+}
+
+func (svc *BaseService) GetSite(ctx context.Context, id string) (Site, error) {
+	return svc.repo.GetSite(ctx, id)
+}
+
+func (svc *BaseService) GetSiteBySlug(ctx context.Context, slug string) (Site, error) {
+	return svc.repo.GetSiteBySlug(ctx, slug)
+}
+
+// UpdateSite saves a site. Its slug is kept as it was created because it
+// names the directories of the site, releases and reports would be left
+// behind under the old name.
+func (svc *BaseService) UpdateSite(ctx context.Context, site Site) error {
+	saved, err := svc.repo.GetSite(ctx, site.ID().String())
+	if err != nil {
+		return err
+	}
+	site.SlugValue = saved.SlugValue
+
+	return svc.repo.UpdateSite(ctx, site)
+}
+
+// checkSiteSlug returns ErrDuplicateSite if another site already uses the
+// slug. Slugs name the site directories so they must be unique.
+func (svc *BaseService) checkSiteSlug(ctx context.Context, site Site) error {
+	sites, err := svc.repo.GetSites(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range sites {
+		if other.ID() != site.ID() && other.SlugValue == site.SlugValue {
+			return fmt.Errorf("%w: %s", ErrDuplicateSite, site.SlugValue)
+		}
+	}
+
+	return nil
+}
+
+// SiteSettings related
+
+// GetSiteSettings returns the settings of the site of ctx, defaults if the
+// site was never configured.
+func (svc *BaseService) GetSiteSettings(ctx context.Context) (SiteSettings, error) {
+	return loadSiteSettings(ctx, svc.repo, svc.Cfg())
+}
+
+func (svc *BaseService) UpdateSiteSettings(ctx context.Context, settings SiteSettings) error {
+	return svc.repo.SaveSiteSettings(ctx, settings)
+}
+
+// ContentType related
+
+func (svc *BaseService) CreateContentType(ctx context.Context, ct ContentType) error {
+	if ct.SlugValue == "" {
+		ct.SlugValue = Slugify(ct.Name)
+	}
+
+	err := svc.checkContentTypeSlug(ctx, ct)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateContentType(ctx, ct)
+}
+
+func (svc *BaseService) GetContentTypes(ctx context.Context) ([]ContentType, error) {
+	return svc.repo.GetContentTypes(ctx)
+}
+
+func (svc *BaseService) GetContentType(ctx context.Context, id string) (ContentType, error) {
+	return svc.repo.GetContentType(ctx, id)
+}
+
+// UpdateContentType saves a content type. Values of fields removed from it are
+// no longer rendered and are dropped the next time their content is saved.
+func (svc *BaseService) UpdateContentType(ctx context.Context, ct ContentType) error {
+	if ct.SlugValue == "" {
+		ct.SlugValue = Slugify(ct.Name)
+	}
+
+	err := svc.checkContentTypeSlug(ctx, ct)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateContentType(ctx, ct)
+}
+
+func (svc *BaseService) checkContentTypeSlug(ctx context.Context, ct ContentType) error {
+	types, err := svc.repo.GetContentTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range types {
+		if other.ID() != ct.ID() && other.SlugValue == ct.SlugValue {
+			return fmt.Errorf("%w: %s", ErrDuplicateContentType, ct.SlugValue)
+		}
+	}
+
+	return nil
+}
+
+// DataFile related
+
+func (svc *BaseService) CreateDataFile(ctx context.Context, file DataFile) error {
+	err := svc.checkDataFileName(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateDataFile(ctx, file)
+}
+
+func (svc *BaseService) GetDataFiles(ctx context.Context) ([]DataFile, error) {
+	return svc.repo.GetDataFiles(ctx)
+}
+
+func (svc *BaseService) GetDataFile(ctx context.Context, id string) (DataFile, error) {
+	return svc.repo.GetDataFile(ctx, id)
+}
+
+func (svc *BaseService) UpdateDataFile(ctx context.Context, file DataFile) error {
+	err := svc.checkDataFileName(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateDataFile(ctx, file)
+}
+
+func (svc *BaseService) DeleteDataFile(ctx context.Context, id string) error {
+	return svc.repo.DeleteDataFile(ctx, id)
+}
+
+func (svc *BaseService) checkDataFileName(ctx context.Context, file DataFile) error {
+	files, err := svc.repo.GetDataFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range files {
+		if other.ID() != file.ID() && other.Name == file.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateDataFile, file.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetDataFileStatuses returns the data files along with the layouts that use
+// them and whether they changed since the last successful build.
+func (svc *BaseService) GetDataFileStatuses(ctx context.Context) ([]DataFileStatus, error) {
+	files, err := svc.repo.GetDataFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	layouts, err := svc.repo.GetAllLayouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := svc.repo.GetBuilds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var built map[string]string
+	for _, b := range builds {
+		if b.Status == BuildSucceeded {
+			built = b.DataHashes
+			break
+		}
+	}
+
+	deps := dataDeps(layouts, files)
+	statuses := make([]DataFileStatus, len(files))
+	for i, f := range files {
+		layouts := deps[f.Name]
+		statuses[i] = DataFileStatus{
+			DataFile: f,
+			Layouts:  layouts,
+			Stale:    len(layouts) > 0 && built[f.Name] != f.Hash(),
+		}
+	}
+	return statuses, nil
+}
+
+// SyncDataFiles makes the data files synced from disk match the data dir of
+// the site. Files that cannot be read, whose data is invalid or whose name is
+// taken by a data file edited in the UI are reported and left as they were.
+func (svc *BaseService) SyncDataFiles(ctx context.Context, userID uuid.UUID) (DataSyncReport, error) {
+	var report DataSyncReport
+
+	disk, err := readDataDir(svc.gen.DataDir(ctx))
+	if err != nil {
+		return report, fmt.Errorf("cannot read data dir: %w", err)
+	}
+
+	files, err := svc.repo.GetDataFiles(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	byName := make(map[string]DataFile, len(files))
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+
+	onDisk := make(map[string]bool, len(disk))
+	for _, f := range disk {
+		onDisk[f.Name] = true
+		existing, exists := byName[f.Name]
+
+		switch {
+		case !fieldNameRe.MatchString(f.Name):
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: name %s must be lowercase letters, digits and underscores", f.Path, f.Name))
+			continue
+		case exists && !existing.IsSynced():
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: data file %s is edited in the UI", f.Path, f.Name))
+			continue
+		}
+
+		// The schema is set in the UI and kept across syncs.
+		if exists {
+			f.Schema = existing.Schema
+		}
+		if _, err := f.Data(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", f.Path, err))
+			continue
+		}
+
+		if !exists {
+			f.GenCreateValues(userID)
+			err = svc.repo.CreateDataFile(ctx, f)
+			if err != nil {
+				return report, err
+			}
+			report.Created = append(report.Created, f.Name)
+			continue
+		}
+
+		if existing.Hash() == f.Hash() && existing.Path == f.Path {
+			continue
+		}
+		existing.Format = f.Format
+		existing.Content = f.Content
+		existing.Path = f.Path
+		existing.GenUpdateValues(userID)
+		err = svc.repo.UpdateDataFile(ctx, existing)
+		if err != nil {
+			return report, err
+		}
+		report.Updated = append(report.Updated, f.Name)
+	}
+
+	for _, f := range files {
+		if !f.IsSynced() || onDisk[f.Name] {
+			continue
+		}
+		err = svc.repo.DeleteDataFile(ctx, f.ID().String())
+		if err != nil {
+			return report, err
+		}
+		report.Deleted = append(report.Deleted, f.Name)
+	}
+
+	return report, nil
+}
+
+// Menu related
+
+func (svc *BaseService) CreateMenu(ctx context.Context, menu Menu) error {
+	err := svc.checkMenuName(ctx, menu)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateMenu(ctx, menu)
+}
+
+func (svc *BaseService) GetMenus(ctx context.Context) ([]Menu, error) {
+	return svc.repo.GetMenus(ctx)
+}
+
+func (svc *BaseService) GetMenu(ctx context.Context, id string) (Menu, error) {
+	return svc.repo.GetMenu(ctx, id)
+}
+
+func (svc *BaseService) UpdateMenu(ctx context.Context, menu Menu) error {
+	err := svc.checkMenuName(ctx, menu)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateMenu(ctx, menu)
+}
+
+func (svc *BaseService) DeleteMenu(ctx context.Context, id string) error {
+	return svc.repo.DeleteMenu(ctx, id)
+}
+
+func (svc *BaseService) checkMenuName(ctx context.Context, menu Menu) error {
+	menus, err := svc.repo.GetMenus(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range menus {
+		if other.ID() != menu.ID() && other.Name == menu.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateMenu, menu.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetMenuItems returns the items of a menu, in order.
+func (svc *BaseService) GetMenuItems(ctx context.Context, menuID uuid.UUID) ([]MenuItem, error) {
+	all, err := svc.repo.GetMenuItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []MenuItem
+	for _, it := range all {
+		if it.MenuID == menuID {
+			items = append(items, it)
+		}
+	}
+	return items, nil
+}
+
+// GetMenuTree returns the items of a menu under their parents, each one
+// along with a description of what it points at.
+func (svc *BaseService) GetMenuTree(ctx context.Context, menuID uuid.UUID) ([]MenuItemNode, error) {
+	items, err := svc.GetMenuItems(ctx, menuID)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := svc.repo.GetSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := func(it MenuItem) string {
+		switch it.Kind {
+		case MenuItemSection:
+			for _, s := range sections {
+				if s.ID() == it.TargetID {
+					return "Section " + s.Name
+				}
+			}
+		case MenuItemContent:
+			for _, c := range contents {
+				if c.ID() == it.TargetID {
+					return "Content " + c.Heading
+				}
+			}
+		case MenuItemURL:
+			return it.URL
+		}
+		return "Missing " + it.Kind
+	}
+
+	return menuItemTree(items, target), nil
+}
+
+// CreateMenuItem adds an item after the other items of its parent.
+func (svc *BaseService) CreateMenuItem(ctx context.Context, item MenuItem) error {
+	items, err := svc.GetMenuItems(ctx, item.MenuID)
+	if err != nil {
+		return err
+	}
+
+	item.Position = nextMenuPosition(items, item.ParentID)
+	return svc.repo.CreateMenuItem(ctx, item)
+}
+
+func (svc *BaseService) GetMenuItem(ctx context.Context, id string) (MenuItem, error) {
+	return svc.repo.GetMenuItem(ctx, id)
+}
+
+// UpdateMenuItem saves an item. An item moved to another parent goes after
+// the items already there.
+func (svc *BaseService) UpdateMenuItem(ctx context.Context, item MenuItem) error {
+	stored, err := svc.repo.GetMenuItem(ctx, item.ID().String())
+	if err != nil {
+		return err
+	}
+
+	items, err := svc.GetMenuItems(ctx, stored.MenuID)
+	if err != nil {
+		return err
+	}
+
+	item.MenuID = stored.MenuID
+	item.Position = stored.Position
+	if item.ParentID != stored.ParentID {
+		move := MenuMove{ID: item.ID(), ParentID: item.ParentID}
+		err = checkMenuMoves(items, []MenuMove{move})
+		if err != nil {
+			return err
+		}
+		item.Position = nextMenuPosition(items, item.ParentID)
+	}
+
+	return svc.repo.UpdateMenuItem(ctx, item)
+}
+
+// DeleteMenuItem deletes an item. Its items take its place in its parent.
+func (svc *BaseService) DeleteMenuItem(ctx context.Context, id string) error {
+	item, err := svc.repo.GetMenuItem(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	items, err := svc.GetMenuItems(ctx, item.MenuID)
+	if err != nil {
+		return err
+	}
+
+	var moves []MenuMove
+	for _, sibling := range items {
+		if sibling.ParentID != item.ParentID {
+			continue
+		}
+		if sibling.ID() != item.ID() {
+			moves = append(moves, MenuMove{ID: sibling.ID(), ParentID: item.ParentID, Position: len(moves)})
+			continue
+		}
+		for _, child := range items {
+			if child.ParentID == item.ID() {
+				moves = append(moves, MenuMove{ID: child.ID(), ParentID: item.ParentID, Position: len(moves)})
+			}
+		}
+	}
+
+	err = svc.repo.MoveMenuItems(ctx, moves)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.DeleteMenuItem(ctx, id)
+}
+
+// ReorderMenu moves the items of a menu to the given parents and positions.
+func (svc *BaseService) ReorderMenu(ctx context.Context, menuID uuid.UUID, moves []MenuMove) error {
+	items, err := svc.GetMenuItems(ctx, menuID)
+	if err != nil {
+		return err
+	}
+
+	err = checkMenuMoves(items, moves)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.MoveMenuItems(ctx, moves)
+}
+
+// Series related
+
+func (svc *BaseService) CreateSeries(ctx context.Context, series Series) error {
+	if series.SlugValue == "" {
+		series.SlugValue = Slugify(series.Name)
+	}
+
+	err := svc.checkSeriesSlug(ctx, series)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateSeries(ctx, series)
+}
+
+func (svc *BaseService) GetAllSeries(ctx context.Context) ([]Series, error) {
+	return svc.repo.GetAllSeries(ctx)
+}
+
+func (svc *BaseService) GetSeries(ctx context.Context, id string) (Series, error) {
+	return svc.repo.GetSeries(ctx, id)
+}
+
+func (svc *BaseService) UpdateSeries(ctx context.Context, series Series) error {
+	if series.SlugValue == "" {
+		series.SlugValue = Slugify(series.Name)
+	}
+
+	err := svc.checkSeriesSlug(ctx, series)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateSeries(ctx, series)
+}
+
+func (svc *BaseService) DeleteSeries(ctx context.Context, id string) error {
+	return svc.repo.DeleteSeries(ctx, id)
+}
+
+func (svc *BaseService) checkSeriesSlug(ctx context.Context, series Series) error {
+	all, err := svc.repo.GetAllSeries(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range all {
+		if other.ID() != series.ID() && other.SlugValue == series.SlugValue {
+			return fmt.Errorf("%w: %s", ErrDuplicateSeries, series.SlugValue)
+		}
+	}
+
+	return nil
+}
+
+// GetSeriesContents returns the contents of a series, in order.
+func (svc *BaseService) GetSeriesContents(ctx context.Context, seriesID uuid.UUID) ([]Content, error) {
+	parts, err := svc.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return seriesContents(parts, contents, seriesID), nil
+}
+
+// AddSeriesContent adds a content after the last one of a series. A content
+// already in a series, directly or through a translation, is rejected.
+func (svc *BaseService) AddSeriesContent(ctx context.Context, seriesID, contentID uuid.UUID) error {
+	parts, err := svc.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return err
+	}
+
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = checkSeriesContent(parts, contents, contentID)
+	if err != nil {
+		return err
+	}
+
+	order := append(seriesOrder(parts, seriesID), contentID)
+	return svc.repo.SetSeriesParts(ctx, seriesID, order)
+}
+
+// RemoveSeriesContent takes a content out of a series. The content itself is
+// left in place.
+func (svc *BaseService) RemoveSeriesContent(ctx context.Context, seriesID, contentID uuid.UUID) error {
+	parts, err := svc.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return err
+	}
+
+	var order []uuid.UUID
+	for _, id := range seriesOrder(parts, seriesID) {
+		if id != contentID {
+			order = append(order, id)
+		}
+	}
+
+	return svc.repo.SetSeriesParts(ctx, seriesID, order)
+}
+
+// ReorderSeries sets the order of the contents of a series.
+func (svc *BaseService) ReorderSeries(ctx context.Context, seriesID uuid.UUID, order []uuid.UUID) error {
+	parts, err := svc.repo.GetSeriesParts(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = checkSeriesOrder(seriesOrder(parts, seriesID), order)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.SetSeriesParts(ctx, seriesID, order)
+}
+
+// Profile related
+
+func (svc *BaseService) CreateProfile(ctx context.Context, profile Profile) error {
+	if profile.SlugValue == "" {
+		profile.SlugValue = Slugify(profile.Name)
+	}
+
+	err := svc.checkProfile(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateProfile(ctx, profile)
+}
+
+func (svc *BaseService) GetProfiles(ctx context.Context) ([]Profile, error) {
+	return svc.repo.GetProfiles(ctx)
+}
+
+func (svc *BaseService) GetProfile(ctx context.Context, id string) (Profile, error) {
+	return svc.repo.GetProfile(ctx, id)
+}
+
+func (svc *BaseService) UpdateProfile(ctx context.Context, profile Profile) error {
+	if profile.SlugValue == "" {
+		profile.SlugValue = Slugify(profile.Name)
+	}
+
+	err := svc.checkProfile(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateProfile(ctx, profile)
+}
+
+// DeleteProfile deletes a profile. Contents it was credited for are kept and
+// lose it as an author.
+func (svc *BaseService) DeleteProfile(ctx context.Context, id string) error {
+	return svc.repo.DeleteProfile(ctx, id)
+}
+
+// checkProfile rejects a profile whose slug is taken, as it names the author
+// page, or whose user already has a profile.
+func (svc *BaseService) checkProfile(ctx context.Context, profile Profile) error {
+	all, err := svc.repo.GetProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range all {
+		if other.ID() == profile.ID() {
+			continue
+		}
+		if other.SlugValue == profile.SlugValue {
+			return fmt.Errorf("%w: %s", ErrDuplicateProfile, profile.SlugValue)
+		}
+		if profile.UserID != uuid.Nil && other.UserID == profile.UserID {
+			return fmt.Errorf("%w: %s", ErrUserHasProfile, profile.UserID)
+		}
+	}
+	return nil
+}
+
 // Content related
 
 func (svc *BaseService) CreateContent(ctx context.Context, content Content) error {
-	return svc.repo.CreateContent(ctx, content)
+	events, err := svc.createContent(ctx, content)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		svc.dispatch(ctx, event)
+	}
+	return nil
+}
+
+// createContent stores a new content and returns the events it raises, for
+// the caller to dispatch once the content is there to stay. Published content
+// without a publication date is published now.
+func (svc *BaseService) createContent(ctx context.Context, content Content) ([]Event, error) {
+	if content.TranslationID == uuid.Nil {
+		content.TranslationID = content.ID()
+	}
+	if content.SlugValue == "" {
+		content.SlugValue = Slugify(content.Heading)
+	}
+	if content.IsPublished() && content.PublishedAt.IsZero() {
+		content.PublishedAt = am.Now()
+	}
+
+	sections, err := svc.repo.GetSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.checkURL(ctx, content, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.repo.CreateContent(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.setContentAuthors(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	data := contentEventData(content, svc.contentURL(content, sections))
+	events := []Event{NewEvent(EventContentCreated, data)}
+	if content.IsPublished() {
+		events = append(events, NewEvent(EventContentPublished, data))
+	}
+	return events, nil
+}
+
+func (svc *BaseService) GetAllContent(ctx context.Context) ([]Content, error) {
+	return svc.repo.GetAllContent(ctx)
+}
+
+// GetContent returns a content along with the IDs of its authors.
+func (svc *BaseService) GetContent(ctx context.Context, id string) (Content, error) {
+	content, err := svc.repo.GetContent(ctx, id)
+	if err != nil {
+		return content, err
+	}
+
+	links, err := svc.repo.GetContentAuthors(ctx)
+	if err != nil {
+		return content, err
+	}
+
+	content.AuthorIDs = contentAuthors(links, content.ID())
+	return content, nil
 }
 
-func (svc *BaseService) GetAllContent(ctx context.Context) ([]Content, error) {
-	return svc.repo.GetAllContent(ctx)
-}
+// UpdateContent saves the content and, if it was already published and its URL
+// changed, records a permanent redirect from the old URL to the new one. It
+// returns the content as saved.
+//
+// Content based on an older version than the saved one is rejected with
+// ErrContentConflict along with the saved content, so that the changes of
+// someone else are never overwritten unseen. Content without a version, as
+// sent by clients that did not read it first, is rejected the same way.
+// Saving what is already saved changes nothing, not even the version, so
+// that forms left open do not get in the way of each other.
+func (svc *BaseService) UpdateContent(ctx context.Context, content Content) (Content, error) {
+	prev, err := svc.GetContent(ctx, content.ID().String())
+	if err != nil {
+		return content, err
+	}
+
+	if content.SlugValue == "" {
+		content.SlugValue = Slugify(content.Heading)
+	}
+	content.PublishedAt = prev.PublishedAt
+	if content.IsPublished() && content.PublishedAt.IsZero() {
+		content.PublishedAt = am.Now()
+	}
+
+	if len(contentChanges(content, prev, nil, nil, ContentType{})) == 0 {
+		return prev, nil
+	}
+	if content.Version != prev.Version {
+		return prev, fmt.Errorf("%w: %s", ErrContentConflict, content.ID())
+	}
+
+	sections, err := svc.repo.GetSections(ctx)
+	if err != nil {
+		return content, err
+	}
+
+	err = svc.checkURL(ctx, content, sections)
+	if err != nil {
+		return content, err
+	}
+
+	content.Version = prev.Version
+	err = svc.repo.UpdateContent(ctx, content)
+	if errors.Is(err, ErrContentConflict) {
+		// Saved by someone else since it was read above.
+		saved, getErr := svc.GetContent(ctx, content.ID().String())
+		if getErr != nil {
+			return content, getErr
+		}
+		return saved, fmt.Errorf("%w: %s", ErrContentConflict, content.ID())
+	}
+	if err != nil {
+		return content, err
+	}
+	content.Version++
+
+	err = svc.setContentAuthors(ctx, content)
+	if err != nil {
+		return content, err
+	}
+
+	data := contentEventData(content, svc.contentURL(content, sections))
+	svc.dispatch(ctx, NewEvent(EventContentUpdated, data))
+	if content.IsPublished() && !prev.IsPublished() {
+		svc.dispatch(ctx, NewEvent(EventContentPublished, data))
+	}
+
+	if !prev.IsPublished() {
+		return content, nil
+	}
+
+	oldURL := svc.contentURL(prev, sections)
+	newURL := svc.contentURL(content, sections)
+	if oldURL == newURL {
+		return content, nil
+	}
+
+	return content, svc.addAutoRedirect(ctx, oldURL, newURL)
+}
+
+func (svc *BaseService) GetContentTranslations(ctx context.Context, translationID uuid.UUID) ([]Content, error) {
+	return svc.repo.GetContentTranslations(ctx, translationID)
+}
+
+// LockContent takes or renews the edit lock of a content for userID. If
+// someone else holds it, their lock is returned instead.
+func (svc *BaseService) LockContent(ctx context.Context, contentID, userID uuid.UUID) (ContentLock, error) {
+	ttl := time.Duration(svc.Cfg().IntVal(am.Key.SSGEditLockTTL, defEditLockTTL)) * time.Second
+	return svc.repo.AcquireContentLock(ctx, NewContentLock(contentID, userID, ttl))
+}
+
+// GetContentLocks returns the edit locks that have not expired by content.
+func (svc *BaseService) GetContentLocks(ctx context.Context) (map[uuid.UUID]ContentLock, error) {
+	locks, err := svc.repo.GetContentLocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return activeLocks(locks, am.Now()), nil
+}
+
+// UnlockContent releases the edit lock of a content if userID holds it.
+func (svc *BaseService) UnlockContent(ctx context.Context, contentID, userID uuid.UUID) error {
+	return svc.repo.ReleaseContentLock(ctx, contentID, userID)
+}
+
+func (svc *BaseService) SaveContentDraft(ctx context.Context, draft ContentDraft) error {
+	return svc.repo.SaveContentDraft(ctx, draft)
+}
+
+// GetContentDraft returns the draft of a user for a content, a zero one if
+// they have none.
+func (svc *BaseService) GetContentDraft(ctx context.Context, contentID, userID uuid.UUID) (ContentDraft, error) {
+	draft, err := svc.repo.GetContentDraft(ctx, contentID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ContentDraft{}, nil
+	}
+	return draft, err
+}
+
+func (svc *BaseService) DiscardContentDraft(ctx context.Context, contentID, userID uuid.UUID) error {
+	return svc.repo.DeleteContentDraft(ctx, contentID, userID)
+}
+
+// PreviewContent renders a content as the next build would if it were saved.
+func (svc *BaseService) PreviewContent(ctx context.Context, content Content) ([]byte, error) {
+	return svc.gen.Preview(ctx, content)
+}
+
+// setContentAuthors saves the authors of a content. Content whose AuthorIDs
+// were not set, as when saved by a client that does not know about authors,
+// keeps the ones it has.
+func (svc *BaseService) setContentAuthors(ctx context.Context, content Content) error {
+	if content.AuthorIDs == nil {
+		return nil
+	}
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(content.AuthorIDs))
+	for _, id := range content.AuthorIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return svc.repo.SetContentAuthors(ctx, content.ID(), ids)
+}
+
+// checkURL returns ErrDuplicateURL if another content is already published at
+// the URL the given content would get.
+func (svc *BaseService) checkURL(ctx context.Context, content Content, sections []Section) error {
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	url := svc.contentURL(content, sections)
+	for _, other := range contents {
+		if other.ID() == content.ID() {
+			continue
+		}
+		if svc.contentURL(other, sections) == url {
+			return fmt.Errorf("%w: %s", ErrDuplicateURL, url)
+		}
+	}
+
+	return nil
+}
+
+func (svc *BaseService) contentURL(content Content, sections []Section) string {
+	def := DefaultLang(svc.Cfg())
+	return ContentURL(content.LangOr(def), def, sectionOf(sections, content.SectionID), content)
+}
+
+// addAutoRedirect records a redirect from a URL that is no longer served.
+// Redirects pointing to oldURL are moved to newURL so they never chain, and a
+// redirect from newURL is dropped because that URL is live again.
+func (svc *BaseService) addAutoRedirect(ctx context.Context, oldURL, newURL string) error {
+	err := svc.repo.DeleteRedirectBySource(ctx, newURL)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.DeleteRedirectBySource(ctx, oldURL)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.RetargetRedirects(ctx, oldURL, newURL)
+	if err != nil {
+		return err
+	}
 
-func (svc *BaseService) GetContent(ctx context.Context, id string) (Content, error) {
-	return svc.repo.GetContent(ctx, id)
+	redirect := NewAutoRedirect(oldURL, newURL)
+	redirect.GenCreateValues()
+	return svc.repo.CreateRedirect(ctx, redirect)
 }
 
-func (svc *BaseService) UpdateContent(ctx context.Context, content Content) error {
-	return svc.repo.UpdateContent(ctx, content)
+// GetTranslationGroups returns every translation group with the status of each
+// configured language.
+func (svc *BaseService) GetTranslationGroups(ctx context.Context) ([]TranslationGroup, error) {
+	contents, err := svc.repo.GetAllContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return GroupTranslations(contents, Languages(svc.Cfg()), DefaultLang(svc.Cfg())), nil
 }
 
 // Section related
@@ -70,3 +1125,704 @@ func (svc *BaseService) CreateLayout(ctx context.Context, layout Layout) error {
 func (svc *BaseService) GetAllLayouts(ctx context.Context) ([]Layout, error) {
 	return svc.repo.GetAllLayouts(ctx)
 }
+
+// Redirect related
+
+func (svc *BaseService) CreateRedirect(ctx context.Context, redirect Redirect) error {
+	err := svc.checkRedirectSource(ctx, redirect)
+	if err != nil {
+		return err
+	}
+	return svc.repo.CreateRedirect(ctx, redirect)
+}
+
+func (svc *BaseService) GetAllRedirects(ctx context.Context) ([]Redirect, error) {
+	return svc.repo.GetAllRedirects(ctx)
+}
+
+func (svc *BaseService) GetRedirect(ctx context.Context, id string) (Redirect, error) {
+	return svc.repo.GetRedirect(ctx, id)
+}
+
+// UpdateRedirect saves a redirect. Editing a redirect makes it a manual one so
+// that it is no longer rewritten when content URLs change.
+func (svc *BaseService) UpdateRedirect(ctx context.Context, redirect Redirect) error {
+	err := svc.checkRedirectSource(ctx, redirect)
+	if err != nil {
+		return err
+	}
+	redirect.Auto = false
+	return svc.repo.UpdateRedirect(ctx, redirect)
+}
+
+func (svc *BaseService) DeleteRedirect(ctx context.Context, id string) error {
+	return svc.repo.DeleteRedirect(ctx, id)
+}
+
+// checkRedirectSource returns ErrDuplicateRedirect if another redirect already
+// handles the same source path.
+func (svc *BaseService) checkRedirectSource(ctx context.Context, redirect Redirect) error {
+	redirects, err := svc.repo.GetAllRedirects(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range redirects {
+		if other.ID() != redirect.ID() && other.SourcePath == redirect.SourcePath {
+			return fmt.Errorf("%w: %s", ErrDuplicateRedirect, redirect.SourcePath)
+		}
+	}
+
+	return nil
+}
+
+// Site generation related
+
+// GenerateSite runs a build and records it. The build is returned even if
+// generation failed so that callers can point to its report.
+func (svc *BaseService) GenerateSite(ctx context.Context, trigger string, userID uuid.UUID) (Build, error) {
+	build, err := svc.createBuild(ctx, trigger, userID)
+	if err != nil {
+		return Build{}, err
+	}
+
+	return svc.runBuild(ctx, build, userID)
+}
+
+// StartBuild records a build and runs it in the background. The returned
+// build is still running, GetBuild reports how it went once it finishes.
+func (svc *BaseService) StartBuild(ctx context.Context, trigger string, userID uuid.UUID) (Build, error) {
+	build, err := svc.createBuild(ctx, trigger, userID)
+	if err != nil {
+		return Build{}, err
+	}
+
+	go func() {
+		// The build outlives the request but not its site.
+		_, err := svc.runBuild(WithSite(context.Background(), SiteFrom(ctx)), build, userID)
+		if err != nil {
+			svc.Log().Errorf("Build %s failed: %v", build.ID(), err)
+		}
+	}()
+
+	return build, nil
+}
+
+func (svc *BaseService) createBuild(ctx context.Context, trigger string, userID uuid.UUID) (Build, error) {
+	build := NewBuild(trigger, userID)
+	build.GenCreateValues(userID)
+
+	err := svc.repo.CreateBuild(ctx, build)
+	if err != nil {
+		return Build{}, err
+	}
+
+	svc.dispatch(ctx, NewEvent(EventBuildStarted, buildEventData(build)))
+	return build, nil
+}
+
+func (svc *BaseService) runBuild(ctx context.Context, build Build, userID uuid.UUID) (Build, error) {
+	stats, genErr := svc.gen.Generate(ctx, build.ID())
+
+	build.Finish(stats, genErr)
+	build.GenUpdateValues(userID)
+
+	err := svc.repo.UpdateBuild(ctx, build)
+	if err != nil {
+		return build, err
+	}
+
+	if genErr != nil {
+		svc.dispatch(ctx, NewEvent(EventBuildFailed, buildEventData(build)))
+	} else {
+		svc.dispatch(ctx, NewEvent(EventBuildSucceeded, buildEventData(build)))
+	}
+
+	svc.markRelease(ctx, &build, svc.liveRelease(ctx))
+	return build, genErr
+}
+
+func (svc *BaseService) GetBuilds(ctx context.Context) ([]Build, error) {
+	builds, err := svc.repo.GetBuilds(ctx)
+	if err != nil {
+		return builds, err
+	}
+
+	live := svc.liveRelease(ctx)
+	for i := range builds {
+		svc.markRelease(ctx, &builds[i], live)
+	}
+	return builds, nil
+}
+
+func (svc *BaseService) GetBuild(ctx context.Context, id string) (Build, error) {
+	build, err := svc.repo.GetBuild(ctx, id)
+	if err != nil {
+		return build, err
+	}
+
+	svc.markRelease(ctx, &build, svc.liveRelease(ctx))
+	return build, nil
+}
+
+// RollbackBuild makes the output of a previous build live again.
+// ErrNoRelease is returned if the build failed or its output is no longer
+// retained.
+func (svc *BaseService) RollbackBuild(ctx context.Context, id string) (Build, error) {
+	build, err := svc.GetBuild(ctx, id)
+	if err != nil {
+		return build, err
+	}
+
+	if build.Status != BuildSucceeded || !build.Retained {
+		return build, ErrNoRelease
+	}
+
+	err = svc.gen.Rollback(ctx, build.ID())
+	if err != nil {
+		return build, err
+	}
+
+	svc.Log().Infof("Site rolled back to build %s", build.ID())
+	build.Live = true
+	return build, nil
+}
+
+// PublishBuild deploys the output of a build to a publish target and records
+// the deployment. On dry runs the changes are reported but nothing is
+// transferred nor recorded.
+func (svc *BaseService) PublishBuild(ctx context.Context, id, targetID string, userID uuid.UUID, author Author, dryRun bool) (Deployment, PublishResult, error) {
+	target, err := svc.GetPublishTarget(ctx, targetID)
+	if err != nil {
+		return Deployment{}, PublishResult{}, err
+	}
+
+	return svc.publish(ctx, id, target, uuid.Nil, userID, author, dryRun)
+}
+
+// PromoteBuild publishes to a production target a build that was already
+// successfully published to staging. ErrNotStaged is returned otherwise.
+func (svc *BaseService) PromoteBuild(ctx context.Context, id, targetID string, userID uuid.UUID, author Author) (Deployment, PublishResult, error) {
+	target, err := svc.GetPublishTarget(ctx, targetID)
+	if err != nil {
+		return Deployment{}, PublishResult{}, err
+	}
+
+	if !target.IsProduction() {
+		return Deployment{}, PublishResult{}, ErrNotProduction
+	}
+
+	deployments, err := svc.repo.GetBuildDeployments(ctx, id)
+	if err != nil {
+		return Deployment{}, PublishResult{}, err
+	}
+
+	var staged Deployment
+	for _, d := range deployments {
+		if d.Environment == EnvStaging && d.Status == DeploymentSucceeded {
+			staged = d
+			break
+		}
+	}
+	if staged.IsZero() {
+		return Deployment{}, PublishResult{}, ErrNotStaged
+	}
+
+	return svc.publish(ctx, id, target, staged.ID(), userID, author, false)
+}
+
+func (svc *BaseService) publish(ctx context.Context, id string, target PublishTarget, promotedFrom, userID uuid.UUID, author Author, dryRun bool) (Deployment, PublishResult, error) {
+	var result PublishResult
+
+	publisher, err := target.Publisher()
+	if err != nil {
+		return Deployment{}, result, err
+	}
+
+	build, err := svc.GetBuild(ctx, id)
+	if err != nil {
+		return Deployment{}, result, err
+	}
+
+	if build.Status != BuildSucceeded || !build.Retained {
+		return Deployment{}, result, ErrNoRelease
+	}
+
+	if dryRun {
+		result, err = publisher.Publish(ctx, svc.gen.releaseDir(ctx, build.ID()), build, author, true)
+		return Deployment{}, result, err
+	}
+
+	deployment := NewDeployment(target, build.ID(), userID)
+	deployment.PromotedFrom = promotedFrom
+	deployment.GenCreateValues(userID)
+
+	err = svc.repo.CreateDeployment(ctx, deployment)
+	if err != nil {
+		return deployment, result, err
+	}
+
+	result, pubErr := publisher.Publish(ctx, svc.gen.releaseDir(ctx, build.ID()), build, author, false)
+
+	deployment.Finish(result, pubErr)
+	deployment.GenUpdateValues(userID)
+
+	err = svc.repo.UpdateDeployment(ctx, deployment)
+	if err != nil {
+		return deployment, result, err
+	}
+
+	svc.dispatch(ctx, NewEvent(EventPublishCompleted, deploymentEventData(deployment, target)))
+
+	if pubErr == nil && result.HasChanges() {
+		svc.Log().Infof("Build %s published to %s, %d files changed", build.ID(), target.Name, len(result.Changes))
+	}
+	return deployment, result, pubErr
+}
+
+func (svc *BaseService) GetBuildDeployments(ctx context.Context, id string) ([]Deployment, error) {
+	return svc.repo.GetBuildDeployments(ctx, id)
+}
+
+// markRelease sets the release state of a build from the release tree.
+func (svc *BaseService) markRelease(ctx context.Context, build *Build, live uuid.UUID) {
+	build.Live = live != uuid.Nil && live == build.ID()
+	build.Retained = svc.gen.HasRelease(ctx, build.ID())
+}
+
+func (svc *BaseService) liveRelease(ctx context.Context) uuid.UUID {
+	live, err := svc.gen.LiveRelease(ctx)
+	if err != nil {
+		svc.Log().Errorf("Cannot read live release: %v", err)
+	}
+	return live
+}
+
+// GetBuildDiff compares the output of a build with the one of the previous
+// successful build. ErrNoRelease is returned if either output is no longer
+// retained.
+func (svc *BaseService) GetBuildDiff(ctx context.Context, build Build) (prev Build, diff ReleaseDiff, err error) {
+	builds, err := svc.repo.GetBuilds(ctx)
+	if err != nil {
+		return prev, diff, err
+	}
+
+	for _, b := range builds {
+		if b.Status == BuildSucceeded && b.StartedAt.Before(build.StartedAt) {
+			prev = b
+			break
+		}
+	}
+	if prev.IsZero() {
+		return prev, diff, ErrNoRelease
+	}
+
+	diff, err = svc.gen.DiffReleases(ctx, prev.ID(), build.ID())
+	return prev, diff, err
+}
+
+// PublishTarget related
+
+func (svc *BaseService) CreatePublishTarget(ctx context.Context, target PublishTarget) error {
+	err := svc.encryptCredentials(&target)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreatePublishTarget(ctx, target)
+}
+
+func (svc *BaseService) GetPublishTargets(ctx context.Context) ([]PublishTarget, error) {
+	return svc.repo.GetPublishTargets(ctx)
+}
+
+// GetPublishTarget returns a target with its credentials decrypted.
+func (svc *BaseService) GetPublishTarget(ctx context.Context, id string) (PublishTarget, error) {
+	target, err := svc.repo.GetPublishTarget(ctx, id)
+	if err != nil {
+		return target, err
+	}
+
+	err = svc.decryptCredentials(&target)
+	return target, err
+}
+
+func (svc *BaseService) GetPublishTargetByName(ctx context.Context, name string) (PublishTarget, error) {
+	target, err := svc.repo.GetPublishTargetByName(ctx, name)
+	if err != nil {
+		return target, err
+	}
+
+	err = svc.decryptCredentials(&target)
+	return target, err
+}
+
+// UpdatePublishTarget saves a target. Credentials left empty keep their
+// stored value so that secrets do not need to be entered on every edit,
+// unless the publisher kind changed.
+func (svc *BaseService) UpdatePublishTarget(ctx context.Context, target PublishTarget) error {
+	current, err := svc.GetPublishTarget(ctx, target.ID().String())
+	if err != nil {
+		return err
+	}
+
+	if target.Credentials == nil {
+		target.Credentials = map[string]string{}
+	}
+	if current.Kind == target.Kind {
+		for name, value := range current.Credentials {
+			if target.Credentials[name] == "" {
+				target.Credentials[name] = value
+			}
+		}
+	}
+
+	err = svc.encryptCredentials(&target)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdatePublishTarget(ctx, target)
+}
+
+func (svc *BaseService) DeletePublishTarget(ctx context.Context, id string) error {
+	return svc.repo.DeletePublishTarget(ctx, id)
+}
+
+func (svc *BaseService) GetTargetDeployments(ctx context.Context, targetID string) ([]Deployment, error) {
+	return svc.repo.GetTargetDeployments(ctx, targetID)
+}
+
+func (svc *BaseService) encryptCredentials(target *PublishTarget) error {
+	target.CredentialsEnc = nil
+
+	creds := map[string]string{}
+	for name, value := range target.Credentials {
+		if value != "" {
+			creds[name] = value
+		}
+	}
+	if len(creds) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	target.CredentialsEnc, err = seal(svc.Cfg(), data)
+	if err != nil {
+		return fmt.Errorf("error encrypting credentials for target %s: %w", target.Name, err)
+	}
+	return nil
+}
+
+func (svc *BaseService) decryptCredentials(target *PublishTarget) error {
+	target.Credentials = map[string]string{}
+	if len(target.CredentialsEnc) == 0 {
+		return nil
+	}
+
+	data, err := unseal(svc.Cfg(), target.CredentialsEnc)
+	if err != nil {
+		return fmt.Errorf("error decrypting credentials for target %s: %w", target.Name, err)
+	}
+
+	return json.Unmarshal(data, &target.Credentials)
+}
+
+// Webhook related
+
+// CreateWebhook saves a webhook. A secret is generated if none was given.
+func (svc *BaseService) CreateWebhook(ctx context.Context, hook Webhook) error {
+	if hook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return err
+		}
+		hook.Secret = secret
+	}
+
+	err := svc.encryptSecret(&hook)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.CreateWebhook(ctx, hook)
+}
+
+func (svc *BaseService) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	return svc.repo.GetWebhooks(ctx)
+}
+
+// GetWebhook returns a webhook with its secret decrypted.
+func (svc *BaseService) GetWebhook(ctx context.Context, id string) (Webhook, error) {
+	hook, err := svc.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return hook, err
+	}
+
+	if len(hook.SecretEnc) > 0 {
+		secret, err := unseal(svc.Cfg(), hook.SecretEnc)
+		if err != nil {
+			return hook, fmt.Errorf("error decrypting secret for webhook %s: %w", hook.Name, err)
+		}
+		hook.Secret = string(secret)
+	}
+	return hook, nil
+}
+
+// UpdateWebhook saves a webhook. An empty secret keeps the stored one.
+func (svc *BaseService) UpdateWebhook(ctx context.Context, hook Webhook) error {
+	if hook.Secret == "" {
+		current, err := svc.GetWebhook(ctx, hook.ID().String())
+		if err != nil {
+			return err
+		}
+		hook.Secret = current.Secret
+	}
+
+	err := svc.encryptSecret(&hook)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateWebhook(ctx, hook)
+}
+
+func (svc *BaseService) DeleteWebhook(ctx context.Context, id string) error {
+	return svc.repo.DeleteWebhook(ctx, id)
+}
+
+// GetWebhookDeliveries returns the latest deliveries of a webhook, or of all
+// of them if webhookID is empty.
+func (svc *BaseService) GetWebhookDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error) {
+	if webhookID == "" {
+		return svc.repo.GetWebhookDeliveries(ctx)
+	}
+	return svc.repo.GetDeliveriesByWebhook(ctx, webhookID)
+}
+
+// RedeliverWebhook sends the payload of a past delivery again to its webhook.
+func (svc *BaseService) RedeliverWebhook(ctx context.Context, deliveryID string) error {
+	delivery, err := svc.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	hook, err := svc.GetWebhook(ctx, delivery.WebhookID.String())
+	if err != nil {
+		return err
+	}
+
+	var event Event
+	err = json.Unmarshal([]byte(delivery.Payload), &event)
+	if err != nil {
+		return err
+	}
+
+	svc.dispatcher.Redeliver(ctx, hook, event)
+	return nil
+}
+
+// dispatch sends an event to the webhooks of the site of the context.
+func (svc *BaseService) dispatch(ctx context.Context, event Event) {
+	svc.dispatcher.Dispatch(ctx, event)
+}
+
+func (svc *BaseService) encryptSecret(hook *Webhook) error {
+	var err error
+	hook.SecretEnc, err = seal(svc.Cfg(), []byte(hook.Secret))
+	if err != nil {
+		return fmt.Errorf("error encrypting secret for webhook %s: %w", hook.Name, err)
+	}
+	return nil
+}
+
+// APIToken related
+
+// CreateAPIToken generates the token, stores its hash and returns the token.
+// It cannot be retrieved afterwards.
+func (svc *BaseService) CreateAPIToken(ctx context.Context, token APIToken) (string, error) {
+	secret, prefix, hash, err := newAPIToken()
+	if err != nil {
+		return "", fmt.Errorf("error generating API token %s: %w", token.Name, err)
+	}
+	token.Prefix = prefix
+	token.TokenHash = hash
+
+	err = svc.repo.CreateAPIToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (svc *BaseService) GetAPITokens(ctx context.Context) ([]APIToken, error) {
+	return svc.repo.GetAPITokens(ctx)
+}
+
+func (svc *BaseService) DeleteAPIToken(ctx context.Context, id string) error {
+	return svc.repo.DeleteAPIToken(ctx, id)
+}
+
+// AuthenticateAPIToken returns the stored token matching the secret and
+// records its use. ErrInvalidAPIToken is returned if there is none.
+func (svc *BaseService) AuthenticateAPIToken(ctx context.Context, secret string) (APIToken, error) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return APIToken{}, ErrInvalidAPIToken
+	}
+
+	token, err := svc.repo.GetAPITokenByHash(ctx, hashAPIToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return token, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return token, err
+	}
+
+	token.LastUsedAt = am.Now()
+	err = svc.repo.TouchAPIToken(ctx, token)
+	if err != nil {
+		svc.Log().Errorf("Cannot record use of API token %s: %v", token.Name, err)
+	}
+	return token, nil
+}
+
+// ReserveIdempotencyKey claims the key for a request. If it was already used
+// the stored key is returned along with ErrIdempotencyKeyExists so that the
+// caller can replay its response.
+func (svc *BaseService) ReserveIdempotencyKey(ctx context.Context, key IdempotencyKey) (IdempotencyKey, error) {
+	err := svc.repo.DeleteExpiredIdempotencyKeys(ctx, am.Now().Add(-idempotencyTTL))
+	if err != nil {
+		return key, err
+	}
+
+	err = svc.repo.CreateIdempotencyKey(ctx, key)
+	if errors.Is(err, ErrIdempotencyKeyExists) {
+		stored, getErr := svc.repo.GetIdempotencyKey(ctx, key.TokenID, key.Key)
+		if getErr != nil {
+			return key, getErr
+		}
+		return stored, err
+	}
+	return key, err
+}
+
+// CompleteIdempotencyKey stores the response to the request the key was
+// reserved for.
+func (svc *BaseService) CompleteIdempotencyKey(ctx context.Context, key IdempotencyKey) error {
+	return svc.repo.UpdateIdempotencyKey(ctx, key)
+}
+
+// ReleaseIdempotencyKey frees the key so that the request can be retried.
+func (svc *BaseService) ReleaseIdempotencyKey(ctx context.Context, key IdempotencyKey) error {
+	return svc.repo.DeleteIdempotencyKey(ctx, key.TokenID, key.Key)
+}
+
+func (svc *BaseService) GetLinkReport(ctx context.Context) (LinkReport, error) {
+	return svc.gen.LinkReport(ctx)
+}
+
+// Import related
+
+// ImportSite maps what was read from another generator or CMS into sections,
+// contents, terms and redirects, and copies its media into the static dir.
+// Items whose URL is already in use are skipped. The report is saved under
+// the reports dir, also on dry runs and failed imports. Everything is stored
+// in a single transaction, a failed import leaves the site as it was. Content
+// events are dispatched once the import is committed.
+func (svc *BaseService) ImportSite(ctx context.Context, src ImportSource, userID uuid.UUID, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{
+		Format:    src.Format,
+		Source:    src.Path,
+		DryRun:    opts.DryRun,
+		StartedAt: am.Now(),
+		Warnings:  src.Warnings,
+	}
+
+	txCtx, tx, err := svc.repo.BeginTx(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+
+	imp, err := newSiteImport(txCtx, svc, userID, opts, &report)
+	if err != nil {
+		return report, err
+	}
+
+	runErr := imp.run(txCtx, src)
+	if runErr == nil && !opts.DryRun {
+		runErr = tx.Commit()
+	}
+
+	// The report is saved even if the import fails so that it is known what
+	// made it fail.
+	report.FinishedAt = am.Now()
+	err = saveImportReport(svc.gen.ReportsDir(ctx), &report)
+	if runErr != nil {
+		return report, runErr
+	}
+	if err != nil {
+		return report, fmt.Errorf("cannot save import report: %w", err)
+	}
+
+	for _, event := range imp.events {
+		svc.dispatch(ctx, event)
+	}
+
+	svc.Log().Infof("Imported %d contents from %s", report.Contents, src.Path)
+	return report, nil
+}
+
+// Archive related
+
+// ExportSite writes every site entity and the static files to w as a site
+// archive.
+func (svc *BaseService) ExportSite(ctx context.Context, w io.Writer) (Archive, error) {
+	archive, err := svc.exportArchive(ctx)
+	if err != nil {
+		return archive, err
+	}
+
+	err = writeArchive(w, &archive, svc.gen.StaticDir(ctx))
+	if err != nil {
+		return archive, fmt.Errorf("cannot write archive: %w", err)
+	}
+	return archive, nil
+}
+
+// RestoreSite creates the entities of a site archive that the database does
+// not have yet and copies its static files. It works on empty databases as
+// well as on existing ones, where matching entities are kept as they are.
+func (svc *BaseService) RestoreSite(ctx context.Context, r io.Reader, userID uuid.UUID, opts RestoreOptions) (RestoreReport, error) {
+	archive, tr, err := readArchive(r)
+	if err != nil {
+		return RestoreReport{}, err
+	}
+
+	report := newRestoreReport(archive, opts)
+	rs := &siteRestore{
+		svc:    svc,
+		userID: userID,
+		opts:   opts,
+		report: &report,
+		ids:    map[string]uuid.UUID{},
+		groups: map[string]uuid.UUID{},
+	}
+
+	err = rs.run(ctx, archive)
+	if err != nil {
+		return report, err
+	}
+
+	err = rs.restoreFiles(tr, svc.gen.StaticDir(ctx))
+	if err != nil {
+		return report, err
+	}
+
+	svc.Log().Infof("Site archive version %d restored, %d contents created", archive.Version, report.Created["contents"])
+	return report, nil
+}
@@ -0,0 +1,255 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sftpDialTimeout   = 30 * time.Second
+	defSFTPKnownHosts = "~/.ssh/known_hosts"
+)
+
+// SFTPConfig holds the connection settings of an SFTP target. The host key
+// is always verified against KnownHosts.
+type SFTPConfig struct {
+	Addr       string // host:port
+	User       string
+	Password   string
+	KeyFile    string
+	KnownHosts string
+	Dir        string
+}
+
+// SFTPPublisher uploads the build output to a directory of a remote host.
+type SFTPPublisher struct {
+	cfg     SFTPConfig
+	connect func(ctx context.Context) (*sftp.Client, func() error, error)
+}
+
+func NewSFTPPublisher(cfg SFTPConfig) *SFTPPublisher {
+	p := &SFTPPublisher{cfg: cfg}
+	p.connect = p.dial
+	return p
+}
+
+func (p *SFTPPublisher) Publish(ctx context.Context, dir string, build Build, author Author, dryRun bool) (PublishResult, error) {
+	result := PublishResult{DryRun: dryRun}
+
+	local, err := listFiles(dir)
+	if err != nil {
+		return result, err
+	}
+
+	client, closeConn, err := p.connect(ctx)
+	if err != nil {
+		return result, fmt.Errorf("cannot connect to %s: %w", p.cfg.Addr, err)
+	}
+	defer closeConn()
+
+	// SFTP has no cheap way to hash remote files, so changes are computed
+	// against the manifest.
+	manifest, err := p.readManifest(client)
+	if err != nil {
+		return result, err
+	}
+
+	result.Changes = diffFiles(local, manifest.Files)
+	if dryRun || !result.HasChanges() {
+		return result, nil
+	}
+
+	for _, c := range result.Changes {
+		err = ctx.Err()
+		if err != nil {
+			return result, err
+		}
+
+		target := path.Join(p.cfg.Dir, c.Path)
+		if c.Op == ChangeDeleted {
+			err = client.Remove(target)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return result, fmt.Errorf("cannot delete %s: %w", target, err)
+			}
+			p.removeEmptyDirs(client, path.Dir(target))
+			continue
+		}
+
+		err = p.upload(client, filepath.Join(dir, filepath.FromSlash(c.Path)), target)
+		if err != nil {
+			return result, fmt.Errorf("cannot upload %s: %w", target, err)
+		}
+	}
+
+	return result, p.writeManifest(client, publishManifest{Files: local})
+}
+
+func (p *SFTPPublisher) upload(client *sftp.Client, src, dst string) error {
+	err := client.MkdirAll(path.Dir(dst))
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := client.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func (p *SFTPPublisher) readManifest(client *sftp.Client) (publishManifest, error) {
+	manifest := publishManifest{Files: map[string]string{}}
+
+	f, err := client.Open(path.Join(p.cfg.Dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	defer f.Close()
+
+	err = json.NewDecoder(f).Decode(&manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("cannot read publish manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest, nil
+}
+
+func (p *SFTPPublisher) writeManifest(client *sftp.Client, manifest publishManifest) error {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	err = client.MkdirAll(p.cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	f, err := client.Create(path.Join(p.cfg.Dir, manifestFile))
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// removeEmptyDirs removes dir and its parents up to the target directory
+// while they are empty.
+func (p *SFTPPublisher) removeEmptyDirs(client *sftp.Client, dir string) {
+	root := path.Clean(p.cfg.Dir)
+	for dir = path.Clean(dir); dir != root && strings.HasPrefix(dir, root+"/"); dir = path.Dir(dir) {
+		if client.RemoveDirectory(dir) != nil {
+			return
+		}
+	}
+}
+
+// dial opens an SSH connection to the target and starts an SFTP session on
+// it. The returned func closes both.
+func (p *SFTPPublisher) dial(ctx context.Context) (*sftp.Client, func() error, error) {
+	hostKeys, err := knownhosts.New(expandHome(p.knownHosts()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read known hosts: %w", err)
+	}
+
+	var methods []ssh.AuthMethod
+	if p.cfg.KeyFile != "" {
+		key, err := os.ReadFile(expandHome(p.cfg.KeyFile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if p.cfg.Password != "" {
+		methods = append(methods, ssh.Password(p.cfg.Password))
+	}
+
+	sshCfg := &ssh.ClientConfig{
+		User:            p.cfg.User,
+		Auth:            methods,
+		HostKeyCallback: hostKeys,
+		Timeout:         sftpDialTimeout,
+	}
+
+	dialer := net.Dialer{Timeout: sftpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.cfg.Addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, p.cfg.Addr, sshCfg)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	sshClient := ssh.NewClient(c, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, nil, err
+	}
+
+	return client, func() error {
+		client.Close()
+		return sshClient.Close()
+	}, nil
+}
+
+func (p *SFTPPublisher) knownHosts() string {
+	if p.cfg.KnownHosts != "" {
+		return p.cfg.KnownHosts
+	}
+	return defSFTPKnownHosts
+}
+
+func expandHome(name string) string {
+	if !strings.HasPrefix(name, "~/") {
+		return name
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, name[2:])
+}
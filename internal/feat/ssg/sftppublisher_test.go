@@ -0,0 +1,103 @@
+package ssg
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/sftp"
+)
+
+// pipeConn joins the two halves of a pair of pipes into a connection.
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// connectTo returns a connect func that serves SFTP from root in process.
+func connectTo(root string) func(ctx context.Context) (*sftp.Client, func() error, error) {
+	return func(ctx context.Context) (*sftp.Client, func() error, error) {
+		clientR, serverW := io.Pipe()
+		serverR, clientW := io.Pipe()
+
+		server, err := sftp.NewServer(pipeConn{serverR, serverW}, sftp.WithServerWorkingDirectory(root))
+		if err != nil {
+			return nil, nil, err
+		}
+		go server.Serve()
+
+		client, err := sftp.NewClientPipe(clientR, clientW)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, func() error {
+			server.Close()
+			return client.Close()
+		}, nil
+	}
+}
+
+func TestSFTPPublisherPublish(t *testing.T) {
+	ctx := context.Background()
+	remote := t.TempDir()
+	output := t.TempDir()
+	for name, content := range map[string]string{"/": "home", "/blog/post/": "post"} {
+		if err := writePage(output, name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Files on the host that were not published are left alone.
+	if err := os.WriteFile(filepath.Join(remote, "robots.txt"), []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewSFTPPublisher(SFTPConfig{Dir: "site"})
+	p.connect = connectTo(remote)
+	build := NewBuild(BuildTriggerManual, uuid.Nil)
+
+	res, err := p.Publish(ctx, output, build, Author{}, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(res.Changes) != 2 {
+		t.Errorf("Publish() changes = %v, want 2", res.Changes)
+	}
+	assertFile(t, filepath.Join(remote, "site", "blog", "post", indexFile), "post")
+
+	if err := os.RemoveAll(filepath.Join(output, "blog")); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = p.Publish(ctx, output, build, Author{}, true)
+	if err != nil {
+		t.Fatalf("Publish() dry run error = %v", err)
+	}
+	if len(res.Changes) != 1 || res.Changes[0].Op != ChangeDeleted {
+		t.Errorf("Publish() dry run changes = %v, want blog post deleted", res.Changes)
+	}
+	assertFile(t, filepath.Join(remote, "site", "blog", "post", indexFile), "post")
+
+	_, err = p.Publish(ctx, output, build, Author{}, false)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(remote, "site", "blog")); !os.IsNotExist(err) {
+		t.Errorf("stale blog dir still present, stat error = %v", err)
+	}
+	assertFile(t, filepath.Join(remote, "site", indexFile), "home")
+	assertFile(t, filepath.Join(remote, "robots.txt"), "keep")
+}
+
+func assertFile(t *testing.T, file, want string) {
+	t.Helper()
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("%s = %q, want %q", file, b, want)
+	}
+}
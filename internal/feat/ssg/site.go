@@ -0,0 +1,121 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	siteType = "site"
+
+	// DefaultSiteSlug is the slug of the site created by the migration that
+	// added sites. Content created before it belongs to that site.
+	DefaultSiteSlug = "default"
+)
+
+// DefaultSiteID is the ID of the default site. Requests and commands that do
+// not choose a site work on it.
+var DefaultSiteID = uuid.MustParse("6d1f2c4e-8b3a-4f7e-9c2d-5a1b3e7f9c01")
+
+type siteCtxKey struct{}
+
+// Site is an independent site managed by the instance. It owns its sections,
+// layouts, content, redirects, publish targets, webhooks and API tokens, and
+// it is built and published on its own.
+// A site can be owned by an org or by one of its teams.
+type Site struct {
+	*am.BaseModel
+	Name        string    `json:"name"`
+	SlugValue   string    `json:"slug"`
+	Description string    `json:"description"`
+	OrgID       uuid.UUID `json:"org_id"`
+	TeamID      uuid.UUID `json:"team_id"`
+}
+
+func NewSite(name, description string) Site {
+	return Site{
+		BaseModel:   am.NewModel(am.WithType(siteType)),
+		Name:        name,
+		SlugValue:   Slugify(name),
+		Description: description,
+	}
+}
+
+// DefaultSite returns the default site as created by the migration.
+func DefaultSite() Site {
+	site := NewSite("Default", "")
+	site.SetID(DefaultSiteID)
+	site.SlugValue = DefaultSiteSlug
+	return site
+}
+
+func (s Site) IsZero() bool {
+	return s.BaseModel == nil || s.BaseModel.IsZero()
+}
+
+func (s *Site) Slug() string {
+	return s.SlugValue
+}
+
+// IsDefault reports whether s is the default site.
+func (s Site) IsDefault() bool {
+	return !s.IsZero() && s.ID() == DefaultSiteID
+}
+
+// OwnerKey identifies the owner of the site as org:<id> or team:<id>. It is
+// empty if the site has no owner.
+func (s Site) OwnerKey() string {
+	switch {
+	case s.TeamID != uuid.Nil:
+		return ownerTeam + ":" + s.TeamID.String()
+	case s.OrgID != uuid.Nil:
+		return ownerOrg + ":" + s.OrgID.String()
+	}
+	return ""
+}
+
+// OptValue and OptLabel make sites selectable.
+func (s Site) OptValue() string {
+	return s.SlugValue
+}
+
+func (s Site) OptLabel() string {
+	return s.Name
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (s *Site) UnmarshalJSON(data []byte) error {
+	type Alias Site
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*s = Site(*temp)
+	if s.BaseModel == nil {
+		s.BaseModel = am.NewModel(am.WithType(siteType))
+	}
+	return nil
+}
+
+// WithSite returns a copy of ctx scoped to a site. Repo queries, builds and
+// their output directories all work on the site of the context.
+func WithSite(ctx context.Context, site Site) context.Context {
+	return context.WithValue(ctx, siteCtxKey{}, site)
+}
+
+// SiteFrom returns the site ctx is scoped to, the default site if none.
+func SiteFrom(ctx context.Context) Site {
+	site, ok := ctx.Value(siteCtxKey{}).(Site)
+	if !ok || site.IsZero() {
+		return DefaultSite()
+	}
+	return site
+}
+
+// SiteIDFrom returns the ID of the site ctx is scoped to.
+func SiteIDFrom(ctx context.Context) uuid.UUID {
+	return SiteFrom(ctx).ID()
+}
@@ -0,0 +1,129 @@
+package ssg
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// multiSiteRepo keeps sites and the site each section was created on.
+type multiSiteRepo struct {
+	Repo
+	sites    []Site
+	sections map[string][]Section
+}
+
+func (r *multiSiteRepo) GetSites(ctx context.Context) ([]Site, error) {
+	return r.sites, nil
+}
+
+func (r *multiSiteRepo) CreateSite(ctx context.Context, site Site) error {
+	r.sites = append(r.sites, site)
+	return nil
+}
+
+func (r *multiSiteRepo) GetSite(ctx context.Context, id string) (Site, error) {
+	for _, site := range r.sites {
+		if site.ID().String() == id {
+			return site, nil
+		}
+	}
+	return Site{}, errors.New("site not found")
+}
+
+func (r *multiSiteRepo) UpdateSite(ctx context.Context, site Site) error {
+	for i := range r.sites {
+		if r.sites[i].ID() == site.ID() {
+			r.sites[i] = site
+		}
+	}
+	return nil
+}
+
+func (r *multiSiteRepo) CreateSection(ctx context.Context, section Section) error {
+	slug := SiteFrom(ctx).SlugValue
+	r.sections[slug] = append(r.sections[slug], section)
+	return nil
+}
+
+func TestCreateSite(t *testing.T) {
+	ctx := context.Background()
+	repo := &multiSiteRepo{sites: []Site{DefaultSite()}, sections: map[string][]Section{}}
+	svc := newArchiveService(t, repo)
+
+	site := NewSite("Docs", "")
+	site.GenCreateValues()
+	if err := svc.CreateSite(ctx, site); err != nil {
+		t.Fatal(err)
+	}
+	if roots := repo.sections["docs"]; len(roots) != 1 || roots[0].Path != "/" {
+		t.Errorf("root section not created on the new site: %v", repo.sections)
+	}
+
+	other := NewSite("Docs", "")
+	other.GenCreateValues()
+	if err := svc.CreateSite(ctx, other); !errors.Is(err, ErrDuplicateSite) {
+		t.Errorf("expected ErrDuplicateSite, got %v", err)
+	}
+}
+
+func TestRenameSiteKeepsReleases(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.SSGOutputDir:   filepath.Join(root, "output"),
+		am.Key.SSGReleasesDir: filepath.Join(root, "releases"),
+	})
+	repo := &multiSiteRepo{sites: []Site{DefaultSite()}, sections: map[string][]Section{}}
+	g := NewGenerator(embed.FS{}, repo, am.WithCfg(cfg))
+	svc := &BaseService{Service: am.NewService("ssg-service", am.WithCfg(cfg)), repo: repo, gen: g}
+
+	site := NewSite("Docs", "")
+	site.GenCreateValues()
+	if err := svc.CreateSite(ctx, site); err != nil {
+		t.Fatal(err)
+	}
+	build := uuid.New()
+	siteCtx := WithSite(ctx, site)
+	if err := writePage(g.releaseDir(siteCtx, build), "/", []byte("docs")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.activate(siteCtx, build); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := NewSite("Manual", "")
+	renamed.SetID(site.ID())
+	if err := svc.UpdateSite(ctx, renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := repo.GetSite(ctx, site.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "Manual" || saved.SlugValue != "docs" {
+		t.Errorf("renamed site = %q with slug %q, want %q with slug %q", saved.Name, saved.SlugValue, "Manual", "docs")
+	}
+	if err := g.Rollback(WithSite(ctx, saved), build); err != nil {
+		t.Errorf("Rollback() after rename error = %v", err)
+	}
+}
+
+func TestSiteFrom(t *testing.T) {
+	if !SiteFrom(context.Background()).IsDefault() {
+		t.Errorf("context without site should be on the default site")
+	}
+
+	site := NewSite("Blog", "")
+	site.GenCreateValues()
+	if got := SiteIDFrom(WithSite(context.Background(), site)); got != site.ID() {
+		t.Errorf("site ID = %s, want %s", got, site.ID())
+	}
+}
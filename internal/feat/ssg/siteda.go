@@ -0,0 +1,21 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SiteDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	Name        string     `db:"name"`
+	Slug        string     `db:"slug"`
+	Description string     `db:"description"`
+	OrgID       string     `db:"org_id"`
+	TeamID      string     `db:"team_id"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
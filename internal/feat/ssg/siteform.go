@@ -0,0 +1,74 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// Owner option prefixes. The owner select holds org:<id> or team:<id>.
+const (
+	ownerOrg  = "org"
+	ownerTeam = "team"
+)
+
+type SiteForm struct {
+	*am.BaseForm
+	ID          string `form:"id"`
+	Name        string `form:"name" required:"true"`
+	Slug        string `form:"slug"`
+	Description string `form:"description"`
+	Owner       string `form:"owner"`
+}
+
+func NewSiteForm(r *http.Request) SiteForm {
+	return SiteForm{
+		BaseForm: am.NewBaseForm(r),
+	}
+}
+
+func SiteFormFromRequest(r *http.Request) (sf SiteForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return sf, err
+	}
+
+	return SiteForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Name:        strings.TrimSpace(r.Form.Get("name")),
+		Slug:        strings.TrimSpace(r.Form.Get("slug")),
+		Description: strings.TrimSpace(r.Form.Get("description")),
+		Owner:       r.Form.Get("owner"),
+	}, nil
+}
+
+func (form *SiteForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		validSiteSlug("slug", form.Slug, form.Name),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+// validSiteSlug checks that a slug can be used in directory names. An empty
+// slug is derived from the name.
+func validSiteSlug(field, val, name string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		switch {
+		case val != "" && val != Slugify(val):
+			v.AddFieldError(field, val, fmt.Sprintf("%s: only lowercase letters, digits and dashes are allowed", field))
+		case val == "" && name != "" && Slugify(name) == "":
+			v.AddFieldError(field, val, fmt.Sprintf("%s: cannot be derived from the name, please set one", field))
+		}
+		return v, nil
+	}
+}
@@ -0,0 +1,118 @@
+package ssg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	siteSettingsType = "site-settings"
+	defDateFormat    = "January 2, 2006"
+)
+
+// SiteSettings holds the site-wide values layouts render as .Site. Each site
+// has its own settings.
+type SiteSettings struct {
+	*am.BaseModel
+	Title       string       `json:"title"`
+	BaseURL     string       `json:"base_url"`
+	DefaultLang string       `json:"default_lang"` // Language the site is written in, reported to templates
+	AuthorName  string       `json:"author_name"`
+	AuthorEmail string       `json:"author_email"`
+	AuthorURL   string       `json:"author_url"`
+	SocialLinks []SocialLink `json:"social_links"`
+	Analytics   string       `json:"analytics"`    // Snippet added to the head of every page
+	DateFormat  string       `json:"date_format"`  // Go time layout used by FormatDate
+	Description string       `json:"description"`  // Default meta description of pages
+	SocialImage string       `json:"social_image"` // Default Open Graph and Twitter card image
+	Logo        string       `json:"logo"`         // Logo of the site in structured data
+	// Set from the data files of the site on build, not persisted.
+	Data map[string]any `json:"-"`
+	// Set from the menus of the site on build, by menu name, not persisted.
+	Menus map[string][]MenuEntry `json:"-"`
+}
+
+// SocialLink is a named link to a profile of the site elsewhere.
+type SocialLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func NewSiteSettings(title, defaultLang string) SiteSettings {
+	return SiteSettings{
+		BaseModel:   am.NewModel(am.WithType(siteSettingsType)),
+		Title:       title,
+		DefaultLang: defaultLang,
+		DateFormat:  defDateFormat,
+	}
+}
+
+func (s SiteSettings) IsZero() bool {
+	return s.BaseModel == nil || s.BaseModel.IsZero()
+}
+
+// IsDefault returns true for the settings a site is set up with, which nobody
+// saved since.
+func (s SiteSettings) IsDefault() bool {
+	return s.IsZero() || (s.CreatedBy() == uuid.Nil && s.UpdatedAt().Equal(s.CreatedAt()))
+}
+
+// AnalyticsHTML returns the analytics snippet unescaped. It is entered by
+// site admins and meant to be trusted markup.
+func (s SiteSettings) AnalyticsHTML() template.HTML {
+	return template.HTML(s.Analytics)
+}
+
+// FormatDate formats t with the date format of the site.
+func (s SiteSettings) FormatDate(t time.Time) string {
+	if s.DateFormat == "" {
+		return t.Format(defDateFormat)
+	}
+	return t.Format(s.DateFormat)
+}
+
+// Year returns the current year, as shown in copyright notices.
+func (s SiteSettings) Year() int {
+	return time.Now().Year()
+}
+
+// AbsURL returns the absolute URL of a site path. The path is returned as is
+// if the site has no base URL.
+func (s SiteSettings) AbsURL(path string) string {
+	if s.BaseURL == "" {
+		return path
+	}
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (s *SiteSettings) UnmarshalJSON(data []byte) error {
+	type Alias SiteSettings
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*s = SiteSettings(*temp)
+	if s.BaseModel == nil {
+		s.BaseModel = am.NewModel(am.WithType(siteSettingsType))
+	}
+	return nil
+}
+
+// loadSiteSettings returns the settings of the site of ctx. Sites that were
+// never configured get settings titled after the site.
+func loadSiteSettings(ctx context.Context, repo Repo, cfg *am.Config) (SiteSettings, error) {
+	settings, err := repo.GetSiteSettings(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewSiteSettings(SiteFrom(ctx).Name, DefaultLang(cfg)), nil
+	}
+	return settings, err
+}
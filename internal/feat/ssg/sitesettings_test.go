@@ -0,0 +1,57 @@
+package ssg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSocialLinks(t *testing.T) {
+	text := "Mastodon https://mastodon.social/@ana\n\nMy GitHub\thttps://github.com/ana\n"
+	links, err := parseSocialLinks(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SocialLink{
+		{Name: "Mastodon", URL: "https://mastodon.social/@ana"},
+		{Name: "My GitHub", URL: "https://github.com/ana"},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("links = %v, want %v", links, want)
+	}
+
+	for _, bad := range []string{"https://github.com/ana", "GitHub github.com/ana"} {
+		if _, err := parseSocialLinks(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestValidDateFormat(t *testing.T) {
+	tests := map[string]bool{
+		"January 2, 2006": true,
+		"02 Jan 2006":     true,
+		"2006-01-02":      true,
+		"dd/mm/yyyy":      false,
+		"":                false,
+	}
+
+	for format, valid := range tests {
+		v, _ := validDateFormat("date_format", format)(nil)
+		if v.HasErrors() == valid {
+			t.Errorf("validDateFormat(%q) valid = %v, want %v", format, !v.HasErrors(), valid)
+		}
+	}
+}
+
+func TestSiteSettingsAbsURL(t *testing.T) {
+	settings := NewSiteSettings("Blog", "en")
+	if got := settings.AbsURL("/posts/"); got != "/posts/" {
+		t.Errorf("AbsURL without base URL = %s", got)
+	}
+
+	settings.BaseURL = "https://example.com/"
+	if got := settings.AbsURL("/posts/"); got != "https://example.com/posts/" {
+		t.Errorf("AbsURL = %s", got)
+	}
+}
@@ -0,0 +1,29 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SiteSettingsDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
+	Title       string     `db:"title"`
+	BaseURL     string     `db:"base_url"`
+	DefaultLang string     `db:"default_lang"`
+	AuthorName  string     `db:"author_name"`
+	AuthorEmail string     `db:"author_email"`
+	AuthorURL   string     `db:"author_url"`
+	SocialLinks string     `db:"social_links"`
+	Analytics   string     `db:"analytics"`
+	DateFormat  string     `db:"date_format"`
+	Description string     `db:"description"`
+	SocialImage string     `db:"social_image"`
+	Logo        string     `db:"logo"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,153 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// SiteSettingsForm edits the settings of the current site. Social links are
+// entered one per line as a name followed by a URL. Langs holds the
+// configured languages the default one is chosen from.
+type SiteSettingsForm struct {
+	*am.BaseForm
+	ID          string `form:"id"`
+	Title       string `form:"title" required:"true"`
+	BaseURL     string `form:"base_url"`
+	DefaultLang string `form:"default_lang" required:"true"`
+	AuthorName  string `form:"author_name"`
+	AuthorEmail string `form:"author_email"`
+	AuthorURL   string `form:"author_url"`
+	SocialLinks string `form:"social_links"`
+	Analytics   string `form:"analytics"`
+	DateFormat  string `form:"date_format" required:"true"`
+	Description string `form:"description"`
+	SocialImage string `form:"social_image"`
+	Logo        string `form:"logo"`
+	Langs       []string
+}
+
+func SiteSettingsFormFromRequest(r *http.Request, langs []string) (sf SiteSettingsForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return sf, err
+	}
+
+	field := func(name string) string {
+		return strings.TrimSpace(r.Form.Get(name))
+	}
+
+	return SiteSettingsForm{
+		BaseForm:    am.NewBaseForm(r),
+		ID:          r.Form.Get("id"),
+		Title:       field("title"),
+		BaseURL:     field("base_url"),
+		DefaultLang: r.Form.Get("default_lang"),
+		AuthorName:  field("author_name"),
+		AuthorEmail: field("author_email"),
+		AuthorURL:   field("author_url"),
+		SocialLinks: field("social_links"),
+		Analytics:   field("analytics"),
+		DateFormat:  field("date_format"),
+		Description: field("description"),
+		SocialImage: field("social_image"),
+		Logo:        field("logo"),
+		Langs:       langs,
+	}, nil
+}
+
+func (form *SiteSettingsForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("title", form.Title, 1),
+		am.MaxLength("title", form.Title, 128),
+		validBaseURL("base_url", form.BaseURL),
+		validOption("default_lang", form.DefaultLang, form.Langs),
+		validEmail("author_email", form.AuthorEmail),
+		validBaseURL("author_url", form.AuthorURL),
+		validSocialLinks("social_links", form.SocialLinks),
+		validDateFormat("date_format", form.DateFormat),
+		am.MaxLength("description", form.Description, 320),
+		validMediaRef("social_image", form.SocialImage),
+		validMediaRef("logo", form.Logo),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+func validEmail(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if val == "" {
+			return v, nil
+		}
+		addr, err := mail.ParseAddress(val)
+		if err != nil || addr.Address != val {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be an email address", field))
+		}
+		return v, nil
+	}
+}
+
+func validSocialLinks(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		_, err := parseSocialLinks(val)
+		if err != nil {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: %s", field, err))
+		}
+		return v, nil
+	}
+}
+
+// validDateFormat checks that the format is a Go time layout, that is, that
+// it formats a date other than the reference one into something else than
+// itself.
+func validDateFormat(field, val string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		date := time.Date(1999, time.December, 31, 23, 59, 58, 0, time.UTC)
+		if val == "" || date.Format(val) == val {
+			v.AddFieldError(field, val, fmt.Sprintf("%s: must be a Go time layout such as %s", field, defDateFormat))
+		}
+		return v, nil
+	}
+}
+
+// parseSocialLinks reads one link per line, its name and then its URL.
+func parseSocialLinks(text string) ([]SocialLink, error) {
+	var links []SocialLink
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sep := strings.LastIndexAny(line, " \t")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected a name and a URL", i+1)
+		}
+		link := SocialLink{Name: strings.TrimSpace(line[:sep]), URL: line[sep+1:]}
+		if v, _ := validBaseURL("url", link.URL)(nil); v.HasErrors() {
+			return nil, fmt.Errorf("line %d: %s is not an http or https URL", i+1, link.URL)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// formatSocialLinks is the inverse of parseSocialLinks.
+func formatSocialLinks(links []SocialLink) string {
+	lines := make([]string, len(links))
+	for i, link := range links {
+		lines[i] = link.Name + " " + link.URL
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,63 @@
+package ssg
+
+import (
+	"encoding/json"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	termType = "term"
+)
+
+// Taxonomies content can be classified by.
+const (
+	TermCategory = "category"
+	TermTag      = "tag"
+)
+
+// Term is a category or tag content is classified under.
+type Term struct {
+	*am.BaseModel
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	SlugValue string `json:"slug"`
+}
+
+func NewTerm(kind, name string) Term {
+	return Term{
+		BaseModel: am.NewModel(am.WithType(termType)),
+		Kind:      kind,
+		Name:      name,
+		SlugValue: Slugify(name),
+	}
+}
+
+func (t Term) IsZero() bool {
+	return t.BaseModel == nil || t.BaseModel.IsZero()
+}
+
+func (t *Term) Slug() string {
+	return t.SlugValue
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (t *Term) UnmarshalJSON(data []byte) error {
+	type Alias Term
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*t = Term(*temp)
+	if t.BaseModel == nil {
+		t.BaseModel = am.NewModel(am.WithType(termType))
+	}
+	return nil
+}
+
+// ContentTerm links a content to a term it is classified under.
+type ContentTerm struct {
+	ContentID uuid.UUID
+	TermID    uuid.UUID
+}
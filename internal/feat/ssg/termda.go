@@ -0,0 +1,25 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TermDA struct {
+	ID        uuid.UUID  `db:"id"`
+	ShortID   string     `db:"short_id"`
+	SiteID    string     `db:"site_id"`
+	Kind      string     `db:"kind"`
+	Name      string     `db:"name"`
+	Slug      string     `db:"slug"`
+	CreatedBy *string    `db:"created_by"`
+	UpdatedBy *string    `db:"updated_by"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type ContentTermDA struct {
+	ContentID string `db:"content_id"`
+	TermID    string `db:"term_id"`
+}
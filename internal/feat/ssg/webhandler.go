@@ -15,16 +15,30 @@ const (
 type WebHandler struct {
 	*am.WebHandler
 	service Service
+	auth    auth.Service
 }
 
-func NewWebHandler(tm *am.TemplateManager, flash *am.FlashManager, service Service, options ...am.Option) *WebHandler {
+// NewWebHandler creates the ssg web handler. The auth service provides the
+// orgs and teams sites can be owned by.
+func NewWebHandler(tm *am.TemplateManager, flash *am.FlashManager, service Service, authService auth.Service, options ...am.Option) *WebHandler {
 	handler := am.NewWebHandler(tm, flash, options...)
 	return &WebHandler{
 		WebHandler: handler,
 		service:    service,
+		auth:       authService,
 	}
 }
 
+// langOpts returns the configured site languages as select options.
+func (h *WebHandler) langOpts() []am.SelectOpt {
+	langs := Languages(h.Cfg())
+	opts := make([]am.SelectOpt, len(langs))
+	for i, l := range langs {
+		opts[i] = am.SelectOpt{Value: l, Label: l}
+	}
+	return opts
+}
+
 // sampleUserInSession returns a fake user for now.
 func (h *WebHandler) sampleUserInSession(r *http.Request) auth.User {
 	user := auth.NewUser("fakeuser", "Fake User")
@@ -0,0 +1,163 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	apiTokenPath = "api-token"
+)
+
+func (h *WebHandler) NewAPIToken(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New API token form")
+	form := NewAPITokenForm(r)
+	h.renderAPITokenForm(w, r, form, "", http.StatusOK)
+}
+
+// CreateAPIToken creates the token and shows it. This is the only time it is
+// shown, only its hash is kept.
+func (h *WebHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create API token")
+	ctx := r.Context()
+
+	form, err := APITokenFormFromRequest(r)
+	if err != nil {
+		h.renderAPITokenForm(w, r, form, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderAPITokenForm(w, r, form, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	user := h.sampleUserInSession(r)
+	token := ToAPITokenFromForm(form, user.ID())
+	token.GenCreateValues(user.ID())
+
+	secret, err := h.service.CreateAPIToken(ctx, token)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Token  APIToken
+		Secret string
+	}{
+		Token:  token,
+		Secret: secret,
+	})
+	page.Name = "API Token Created"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(token)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "show-api-token")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.OK(w, r, &buf, http.StatusCreated)
+}
+
+// DeleteAPIToken revokes a token.
+func (h *WebHandler) DeleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete API token")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteAPIToken(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "API token revoked")
+	h.Redir(w, r, am.ListPath(ssgPath, apiTokenPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List API tokens")
+	ctx := r.Context()
+
+	tokens, err := h.service.GetAPITokens(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, tokens)
+	page.Name = "API Tokens"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(apiTokenPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-api-tokens")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+func (h *WebHandler) renderAPITokenForm(w http.ResponseWriter, r *http.Request, form APITokenForm, errorMessage string, statusCode int) {
+	token := NewAPIToken("", h.sampleUserInSession(r).ID())
+
+	page := am.NewPage(r, token)
+	page.SetForm(form)
+	page.Name = "New API Token"
+	page.IsNew = true
+	page.Form.SetAction(am.CreatePath(ssgPath, apiTokenPath))
+	page.Form.SetSubmitButtonText("Create")
+
+	page.AddSelect("scopes", stringOpts(apiTokenScopes))
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(token)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-api-token")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
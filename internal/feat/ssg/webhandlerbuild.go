@@ -0,0 +1,231 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	buildPath = "build"
+)
+
+func showBuildPath(id string) string {
+	return fmt.Sprintf("%s/show-%s?id=%s", ssgPath, buildPath, id)
+}
+
+func (h *WebHandler) ListBuilds(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List builds")
+	ctx := r.Context()
+
+	builds, err := h.service.GetBuilds(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, builds)
+	page.Name = "Builds"
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-builds")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// ShowBuild shows the report of a build and how its output differs from the
+// previous successful build.
+func (h *WebHandler) ShowBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show build")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	build, err := h.service.GetBuild(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	prev, diff, err := h.service.GetBuildDiff(ctx, build)
+	if err != nil && !errors.Is(err, ErrNoRelease) {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	hasDiff := err == nil
+
+	targets, err := h.service.GetPublishTargets(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	deployments, err := h.service.GetBuildDeployments(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Build       Build
+		Prev        Build
+		Diff        ReleaseDiff
+		HasDiff     bool
+		Targets     []PublishTarget
+		Deployments []Deployment
+		Staged      bool
+	}{
+		Build:       build,
+		Prev:        prev,
+		Diff:        diff,
+		HasDiff:     hasDiff,
+		Targets:     targets,
+		Deployments: deployments,
+		Staged:      isStaged(deployments),
+	})
+	page.Name = "Build"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(build)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "show-build")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// RollbackBuild makes the output of a previous build live again.
+func (h *WebHandler) RollbackBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Rollback build")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	build, err := h.service.RollbackBuild(ctx, id)
+	if errors.Is(err, ErrNoRelease) {
+		h.FlashError(w, r, "The output of this build is not available")
+		h.Redir(w, r, showBuildPath(id), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, "Cannot rollback site", http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashSuccess(w, r, "Site rolled back to build "+build.ShortID())
+	h.Redir(w, r, showBuildPath(id), http.StatusSeeOther)
+}
+
+// PublishBuild publishes the output of a build to a target, or reports what
+// would change when dry_run is set.
+func (h *WebHandler) PublishBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Publish build")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	targetID := r.FormValue("target_id")
+	if id == "" || targetID == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+	dryRun := r.FormValue("dry_run") == "true"
+
+	user := h.sampleUserInSession(r)
+	author := Author{Name: user.Name, Email: user.Email}
+
+	_, result, err := h.service.PublishBuild(ctx, id, targetID, user.ID(), author, dryRun)
+	h.flashPublish(w, r, id, result, err)
+	h.Redir(w, r, showBuildPath(id), http.StatusSeeOther)
+}
+
+// PromoteBuild publishes to production a build already published to staging.
+func (h *WebHandler) PromoteBuild(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Promote build")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	targetID := r.FormValue("target_id")
+	if id == "" || targetID == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	user := h.sampleUserInSession(r)
+	author := Author{Name: user.Name, Email: user.Email}
+
+	_, result, err := h.service.PromoteBuild(ctx, id, targetID, user.ID(), author)
+	switch {
+	case errors.Is(err, ErrNotProduction):
+		h.FlashError(w, r, "Builds can only be promoted to production targets")
+	case errors.Is(err, ErrNotStaged):
+		h.FlashError(w, r, "The build must be published to staging before promoting it")
+	default:
+		h.flashPublish(w, r, id, result, err)
+	}
+	h.Redir(w, r, showBuildPath(id), http.StatusSeeOther)
+}
+
+func (h *WebHandler) flashPublish(w http.ResponseWriter, r *http.Request, id string, result PublishResult, err error) {
+	switch {
+	case errors.Is(err, ErrPublishNotConfigured):
+		h.FlashError(w, r, "The publish target is not fully configured")
+	case errors.Is(err, ErrNoRelease):
+		h.FlashError(w, r, "The output of this build is not available")
+	case err != nil:
+		h.Log().Errorf("Cannot publish build %s: %v", id, err)
+		h.FlashError(w, r, "Publishing failed")
+	case result.DryRun:
+		h.FlashInfo(w, r, fmt.Sprintf("Dry run: %d files would change", len(result.Changes)))
+	case !result.HasChanges():
+		h.FlashInfo(w, r, "Nothing to publish, the target is up to date")
+	default:
+		h.FlashSuccess(w, r, fmt.Sprintf("Build published, %d files changed", len(result.Changes)))
+	}
+}
+
+// isStaged reports whether any of the deployments of a build succeeded on
+// staging, which is required to promote it.
+func isStaged(deployments []Deployment) bool {
+	for _, d := range deployments {
+		if d.Environment == EnvStaging && d.Status == DeploymentSucceeded {
+			return true
+		}
+	}
+	return false
+}
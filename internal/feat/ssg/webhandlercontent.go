@@ -2,9 +2,13 @@ package ssg
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
 )
 
 const (
@@ -12,6 +16,11 @@ const (
 	contentPathFmt = "%s/%s-content%s"
 )
 
+var statusOpts = []am.SelectOpt{
+	{Value: StatusDraft, Label: "Draft"},
+	{Value: StatusPublished, Label: "Published"},
+}
+
 const (
 	ActionNewContent    = "new-content"
 	ActionCreateContent = "create-content"
@@ -21,7 +30,23 @@ const (
 func (h *WebHandler) NewContent(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("New content form")
 	form := NewContentForm(r)
-	h.renderContentForm(w, r, form, NewContent("", ""), "", http.StatusOK)
+
+	var err error
+	form.TypeID = r.URL.Query().Get("type")
+	form.Type, err = h.contentTypeOf(r.Context(), form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	content := NewContent("", "")
+	form, content, err = h.restoreDraft(r.Context(), r, form, content, h.sampleUserInSession(r).ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.renderContentForm(w, r, form, content, "", http.StatusOK)
 }
 
 func (h *WebHandler) CreateContent(w http.ResponseWriter, r *http.Request) {
@@ -35,6 +60,12 @@ func (h *WebHandler) CreateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	form.Type, err = h.contentTypeOf(ctx, form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
 	err = form.Validate()
 	if err != nil || form.HasErrors() {
 		h.renderContentForm(w, r, form, NewContent("", ""), "Validation failed", http.StatusBadRequest)
@@ -49,11 +80,20 @@ func (h *WebHandler) CreateContent(w http.ResponseWriter, r *http.Request) {
 	content.GenCreateValues()
 
 	err = h.service.CreateContent(ctx, content)
+	if errors.Is(err, ErrDuplicateURL) {
+		h.rejectDuplicateURL(w, r, form, ToContentFromForm(form))
+		return
+	}
 	if err != nil {
 		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
 		return
 	}
 
+	err = h.service.DiscardContentDraft(ctx, uuid.Nil, user.ID())
+	if err != nil {
+		h.Log().Errorf("Cannot discard draft of new content: %v", err)
+	}
+
 	// If HTMX request, set HX-Redirect header and return
 	if am.IsHTMXRequest(r) {
 		redirectURL := am.EditPath(ssgPath, contentPath, content.ID())
@@ -77,6 +117,12 @@ func (h *WebHandler) UpdateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	form.Type, err = h.contentTypeOf(ctx, form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
 	err = form.Validate()
 	if err != nil || form.HasErrors() {
 		h.renderContentForm(w, r, form, NewContent("", ""), "Validation failed", http.StatusBadRequest)
@@ -89,17 +135,39 @@ func (h *WebHandler) UpdateContent(w http.ResponseWriter, r *http.Request) {
 	user := h.sampleUserInSession(r)
 	content.UserID = user.ID()
 
-	err = h.service.UpdateContent(ctx, content)
+	saved, err := h.service.UpdateContent(ctx, content)
+	if errors.Is(err, ErrContentConflict) {
+		h.rejectConflict(w, r, form, content, saved)
+		return
+	}
+	if errors.Is(err, ErrDuplicateURL) {
+		h.rejectDuplicateURL(w, r, form, content)
+		return
+	}
 	if err != nil {
 		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
 		return
 	}
 
+	// Autosaves keep the edit lock of the editor alive.
+	_, err = h.service.LockContent(ctx, content.ID(), user.ID())
+	if err != nil {
+		h.Log().Errorf("Cannot renew edit lock of content %s: %v", content.ID(), err)
+	}
+
+	err = h.service.DiscardContentDraft(ctx, content.ID(), user.ID())
+	if err != nil {
+		h.Log().Errorf("Cannot discard draft of content %s: %v", content.ID(), err)
+	}
+
 	if am.IsHTMXRequest(r) {
 		w.Header().Set("Content-Type", "text/html")
 		// Return a small HTML fragment with the current timestamp for auto-save feedback
+		// and the saved version for the next save to be based on.
 		// WIP: We weill find a nicer way to handle this later
-		_, _ = w.Write([]byte("<div id=\"save-status\" data-timestamp=\"" + am.Now().Format(am.TimeFormat) + "\"></div>"))
+		_, _ = fmt.Fprintf(w, "<div id=\"save-status\" data-timestamp=\"%s\"></div>"+
+			"<input type=\"hidden\" id=\"version\" name=\"version\" value=\"%d\" hx-swap-oob=\"true\" />",
+			am.Now().Format(am.TimeFormat), saved.Version)
 		return
 	}
 
@@ -107,6 +175,73 @@ func (h *WebHandler) UpdateContent(w http.ResponseWriter, r *http.Request) {
 	h.Redir(w, r, am.EditPath(ssgPath, contentPath, content.ID()), http.StatusSeeOther)
 }
 
+// rejectConflict shows the editor what changed in the content since the
+// version their form is based on, keeping what they typed. Autosaves get just
+// the conflict, in place of the save status, so that they can go on editing.
+func (h *WebHandler) rejectConflict(w http.ResponseWriter, r *http.Request, form ContentForm, content, saved Content) {
+	ctx := r.Context()
+
+	sections, err := h.service.GetSections(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	profiles, err := h.service.GetProfiles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.userNames(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	// Compare with the slug that would have been saved.
+	if content.SlugValue == "" {
+		content.SlugValue = Slugify(content.Heading)
+	}
+
+	form.Conflict = &ContentConflict{
+		ContentID: saved.ID(),
+		Version:   saved.Version,
+		By:        names[saved.UpdatedBy()],
+		At:        saved.UpdatedAt(),
+		Changes:   contentChanges(content, saved, sections, profiles, form.Type),
+	}
+
+	if !am.IsHTMXRequest(r) {
+		h.renderContentForm(w, r, form, content, "Conflict", http.StatusConflict)
+		return
+	}
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-content")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "content-conflict", form.Conflict)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	// htmx only swaps successful responses in.
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// rejectDuplicateURL renders the form back with an error on the slug field.
+func (h *WebHandler) rejectDuplicateURL(w http.ResponseWriter, r *http.Request, form ContentForm, content Content) {
+	v := form.Validation()
+	v.AddFieldError("slug", form.Slug, "slug: another content is already published at this URL")
+	form.SetValidation(&v)
+	h.renderContentForm(w, r, form, content, "Validation failed", http.StatusBadRequest)
+}
+
 func (h *WebHandler) renderContentForm(w http.ResponseWriter, r *http.Request, form ContentForm, content Content, errorMessage string, statusCode int) {
 	h.Log().Info("Render content form")
 	h.Log().Infof("renderContentForm - form: %+v", form)
@@ -141,6 +276,37 @@ func (h *WebHandler) renderContentForm(w http.ResponseWriter, r *http.Request, f
 	}
 
 	page.AddSelect("sections", am.ToSelectOpt(sections))
+	page.AddSelect("langs", h.langOpts())
+	page.AddSelect("statuses", statusOpts)
+
+	types, err := h.service.GetContentTypes(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	page.AddSelect("types", append([]am.SelectOpt{{Value: "", Label: "Plain content"}}, am.ToSelectOpt(types)...))
+
+	profiles, err := h.service.GetProfiles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	page.AddSelect("authors", am.ToSelectOpt(profiles))
+
+	if form.Type.hasField(FieldReference) {
+		contents, err := h.service.GetAllContent(ctx)
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		opts := []am.SelectOpt{{Value: "", Label: "None"}}
+		for _, c := range contents {
+			if c.ID() != content.ID() {
+				opts = append(opts, am.SelectOpt{Value: c.ID().String(), Label: c.Heading})
+			}
+		}
+		page.AddSelect("contents", opts)
+	}
 
 	menu := page.NewMenu(ssgPath)
 	menu.AddListItem(content)
@@ -181,9 +347,104 @@ func (h *WebHandler) EditContent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	form := ToContentForm(r, content)
+	form.Type, err = h.contentTypeOf(ctx, form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	user := h.sampleUserInSession(r)
+	form, content, err = h.restoreDraft(ctx, r, form, content, user.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	lock, err := h.service.LockContent(ctx, content.ID(), user.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	if lock.HeldByOther(user.ID(), am.Now()) {
+		names, err := h.userNames(ctx)
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		form.Lock = &LockNotice{By: names[lock.UserID], Since: lock.AcquiredAt, Until: lock.ExpiresAt}
+	}
+
+	h.renderContentForm(w, r, form, content, "", http.StatusOK)
+}
+
+// UnlockContent releases the edit lock the user holds on a content so that
+// others see it is free before the lock expires.
+func (h *WebHandler) UnlockContent(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Unlock content")
+	ctx := r.Context()
+
+	id := am.ParseUUID(r.FormValue("id"))
+	if id == uuid.Nil {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.UnlockContent(ctx, id, h.sampleUserInSession(r).ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, ssgPath+"/list-content", http.StatusSeeOther)
+}
+
+// NewTranslation renders a new content form prefilled with the source content
+// and linked to its translation group.
+func (h *WebHandler) NewTranslation(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New translation")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	lang := r.URL.Query().Get("lang")
+	if id == "" || lang == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	source, err := h.service.GetContent(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	content := NewContent(source.Heading, source.Body)
+	content.SectionID = source.SectionID
+	content.Lang = lang
+	content.TranslationID = source.TranslationKey()
+	content.TypeID = source.TypeID
+	content.Fields = source.Fields
+
+	form := ToContentForm(r, content)
+	form.ID = ""
+	form.Type, err = h.contentTypeOf(ctx, form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
 	h.renderContentForm(w, r, form, content, "", http.StatusOK)
 }
 
+// contentTypeOf returns the content type with the given ID, a zero one for
+// content without type.
+func (h *WebHandler) contentTypeOf(ctx context.Context, id string) (ContentType, error) {
+	if am.ParseUUID(id) == uuid.Nil {
+		return ContentType{}, nil
+	}
+	return h.service.GetContentType(ctx, id)
+}
+
 func (h *WebHandler) ListContent(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("List content")
 	ctx := r.Context()
@@ -194,7 +455,27 @@ func (h *WebHandler) ListContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page := am.NewPage(r, contents)
+	locks, err := h.service.GetContentLocks(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.userNames(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]ContentListItem, len(contents))
+	for i, c := range contents {
+		items[i] = ContentListItem{Content: c}
+		if lock, ok := locks[c.ID()]; ok {
+			items[i].Lock = &LockNotice{By: names[lock.UserID], Since: lock.AcquiredAt, Until: lock.ExpiresAt}
+		}
+	}
+
+	page := am.NewPage(r, items)
 	page.Form.SetAction(ssgPath)
 
 	menu := page.NewMenu(ssgPath)
@@ -216,3 +497,21 @@ func (h *WebHandler) ListContent(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(buf.Bytes())
 }
+
+// userNames returns the names of the users to show to other editors, their
+// username if they have no name.
+func (h *WebHandler) userNames(ctx context.Context) (map[uuid.UUID]string, error) {
+	users, err := h.auth.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(users))
+	for _, u := range users {
+		names[u.ID()] = u.Username
+		if u.Name != "" {
+			names[u.ID()] = u.Name
+		}
+	}
+	return names, nil
+}
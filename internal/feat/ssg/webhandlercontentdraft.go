@@ -0,0 +1,149 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+// SaveContentDraft autosaves the edit form into the draft of the user. The
+// content itself is left as it is until the form is saved.
+func (h *WebHandler) SaveContentDraft(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Save content draft")
+	ctx := r.Context()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	form := contentFormFromValues(r, r.Form)
+	contentID := am.ParseUUID(form.ID)
+	if contentID == uuid.Nil && form.Heading == "" && form.Body == "" {
+		// Nothing worth keeping yet in a new content.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	user := h.sampleUserInSession(r)
+	version, _ := strconv.Atoi(form.Version)
+	draft := NewContentDraft(contentID, user.ID(), version, r.Form)
+
+	err = h.service.SaveContentDraft(ctx, draft)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	// An editor typing away holds on to the content as much as one saving it.
+	if contentID != uuid.Nil {
+		_, err = h.service.LockContent(ctx, contentID, user.ID())
+		if err != nil {
+			h.Log().Errorf("Cannot renew edit lock of content %s: %v", contentID, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	_, _ = fmt.Fprintf(w, "<div id=\"draft-status\" class=\"text-sm text-gray-500\">Draft saved at %s</div>",
+		draft.SavedAt.Format("15:04:05 MST"))
+}
+
+// DiscardContentDraft drops the draft of the user and reopens the editor with
+// the saved content.
+func (h *WebHandler) DiscardContentDraft(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Discard content draft")
+	ctx := r.Context()
+
+	id := am.ParseUUID(r.FormValue("id"))
+	err := h.service.DiscardContentDraft(ctx, id, h.sampleUserInSession(r).ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	url := am.NewPath(ssgPath, contentPath)
+	if id != uuid.Nil {
+		url = am.EditPath(ssgPath, contentPath, id)
+	}
+
+	if am.IsHTMXRequest(r) {
+		w.Header().Set("HX-Redirect", url)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.FlashInfo(w, r, "Unsaved changes discarded")
+	h.Redir(w, r, url, http.StatusSeeOther)
+}
+
+// PreviewContent renders the edit form as the content would be published, for
+// the editor to show next to it. Problems are rendered in place of the page.
+func (h *WebHandler) PreviewContent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	form, err := ContentFormFromRequest(r)
+	if err != nil {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	form.Type, err = h.contentTypeOf(ctx, form.TypeID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	out, err := h.service.PreviewContent(ctx, ToContentFromForm(form))
+	if errors.Is(err, ErrNoPreview) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte("<p>No preview, the section of the content is not published in its language.</p>"))
+		return
+	}
+	if err != nil {
+		h.Log().Errorf("Cannot preview content %s: %v", form.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "<p>Cannot render the preview: %s</p>", template.HTMLEscapeString(err.Error()))
+		return
+	}
+
+	_, _ = w.Write(out)
+}
+
+// restoreDraft returns the form with the changes the user left unsaved in a
+// draft, if any, along with the content to render it with, so that they can
+// pick up where they left off. Drafts holding nothing but what is already
+// saved are dropped.
+func (h *WebHandler) restoreDraft(ctx context.Context, r *http.Request, form ContentForm, content Content, userID uuid.UUID) (ContentForm, Content, error) {
+	contentID := am.ParseUUID(form.ID)
+	draft, err := h.service.GetContentDraft(ctx, contentID, userID)
+	if err != nil || draft.IsZero() {
+		return form, content, err
+	}
+
+	restored := contentFormFromValues(r, draft.Values)
+	restored.ID = form.ID
+	restored.Type, err = h.contentTypeOf(ctx, restored.TypeID)
+	if err != nil {
+		return form, content, err
+	}
+
+	unsaved := ToContentFromForm(restored)
+	// Compare with the slug that would be saved.
+	if unsaved.SlugValue == "" && content.SlugValue != "" {
+		unsaved.SlugValue = Slugify(unsaved.Heading)
+	}
+	if len(contentChanges(unsaved, content, nil, nil, ContentType{})) == 0 {
+		return form, content, h.service.DiscardContentDraft(ctx, contentID, userID)
+	}
+
+	restored.Draft = &DraftNotice{SavedAt: draft.SavedAt, Stale: draft.Version != content.Version}
+	return restored, unsaved, nil
+}
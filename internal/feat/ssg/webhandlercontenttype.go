@@ -0,0 +1,192 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const contentTypePath = "content-type"
+
+func (h *WebHandler) NewContentType(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New content type form")
+	form := NewContentTypeForm(r)
+	h.renderContentTypeForm(w, r, form, NewContentType("", ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateContentType(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create content type")
+	ctx := r.Context()
+
+	form, err := ContentTypeFormFromRequest(r)
+	if err != nil {
+		h.renderContentTypeForm(w, r, form, ToContentTypeFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderContentTypeForm(w, r, form, ToContentTypeFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	ct := ToContentTypeFromForm(form)
+	ct.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateContentType(ctx, ct)
+	if errors.Is(err, ErrDuplicateContentType) {
+		h.rejectDuplicateContentType(w, r, form, ToContentTypeFromForm(form))
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Content type created")
+	h.Redir(w, r, am.ListPath(ssgPath, contentTypePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditContentType(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit content type")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	ct, err := h.service.GetContentType(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToContentTypeForm(r, ct)
+	h.renderContentTypeForm(w, r, form, ct, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateContentType(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update content type")
+	ctx := r.Context()
+
+	form, err := ContentTypeFormFromRequest(r)
+	if err != nil {
+		h.renderContentTypeForm(w, r, form, ToContentTypeFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderContentTypeForm(w, r, form, ToContentTypeFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	ct := ToContentTypeFromForm(form)
+	ct.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateContentType(ctx, ct)
+	if errors.Is(err, ErrDuplicateContentType) {
+		h.rejectDuplicateContentType(w, r, form, ct)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Content type updated")
+	h.Redir(w, r, am.ListPath(ssgPath, contentTypePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListContentTypes(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List content types")
+	ctx := r.Context()
+
+	types, err := h.service.GetContentTypes(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, types)
+	page.Name = "Content Types"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(contentTypePath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-content-types")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// rejectDuplicateContentType renders the form back with an error on the slug
+// field.
+func (h *WebHandler) rejectDuplicateContentType(w http.ResponseWriter, r *http.Request, form ContentTypeForm, ct ContentType) {
+	v := form.Validation()
+	v.AddFieldError("slug", form.Slug, "slug: another content type already uses it")
+	form.SetValidation(&v)
+	h.renderContentTypeForm(w, r, form, ct, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderContentTypeForm(w http.ResponseWriter, r *http.Request, form ContentTypeForm, ct ContentType, errorMessage string, statusCode int) {
+	layouts, err := h.service.GetAllLayouts(r.Context())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, ct)
+	page.SetForm(form)
+
+	if ct.IsZero() {
+		page.Name = "New Content Type"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, contentTypePath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Content Type"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, contentTypePath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	layoutOpts := append([]am.SelectOpt{{Value: "", Label: "Layout of the section"}}, am.ToSelectOpt(layouts)...)
+	page.AddSelect("layouts", layoutOpts)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(ct)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-content-type")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
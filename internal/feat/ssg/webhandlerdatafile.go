@@ -0,0 +1,242 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const dataFilePath = "data-file"
+
+func (h *WebHandler) NewDataFile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New data file form")
+	form := NewDataFileForm(r)
+	h.renderDataFileForm(w, r, form, NewDataFile("", DataJSON, ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateDataFile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create data file")
+	ctx := r.Context()
+
+	form, err := DataFileFormFromRequest(r)
+	if err != nil {
+		h.renderDataFileForm(w, r, form, ToDataFileFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderDataFileForm(w, r, form, ToDataFileFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	file := ToDataFileFromForm(form)
+	file.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateDataFile(ctx, file)
+	if errors.Is(err, ErrDuplicateDataFile) {
+		h.rejectDuplicateDataFile(w, r, form, ToDataFileFromForm(form))
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Data file created")
+	h.Redir(w, r, am.ListPath(ssgPath, dataFilePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditDataFile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit data file")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	file, err := h.service.GetDataFile(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToDataFileForm(r, file)
+	h.renderDataFileForm(w, r, form, file, "", http.StatusOK)
+}
+
+// UpdateDataFile saves a data file. Files synced from disk keep their origin
+// so the next sync still replaces them.
+func (h *WebHandler) UpdateDataFile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update data file")
+	ctx := r.Context()
+
+	form, err := DataFileFormFromRequest(r)
+	if err != nil {
+		h.renderDataFileForm(w, r, form, ToDataFileFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.service.GetDataFile(ctx, form.ID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	file := ToDataFileFromForm(form)
+	file.Source = stored.Source
+	file.Path = stored.Path
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderDataFileForm(w, r, form, file, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	file.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateDataFile(ctx, file)
+	if errors.Is(err, ErrDuplicateDataFile) {
+		h.rejectDuplicateDataFile(w, r, form, file)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Data file updated")
+	h.Redir(w, r, am.ListPath(ssgPath, dataFilePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteDataFile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete data file")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteDataFile(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Data file deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, dataFilePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListDataFiles(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List data files")
+	ctx := r.Context()
+
+	statuses, err := h.service.GetDataFileStatuses(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, statuses)
+	page.Name = "Data Files"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(dataFilePath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-data-files")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// SyncDataFiles updates the data files synced from the data dir of the site.
+func (h *WebHandler) SyncDataFiles(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Sync data files")
+	ctx := r.Context()
+
+	report, err := h.service.SyncDataFiles(ctx, h.sampleUserInSession(r).ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	msg := fmt.Sprintf("Data files synced: %d created, %d updated, %d deleted", len(report.Created), len(report.Updated), len(report.Deleted))
+	if len(report.Errors) > 0 {
+		h.FlashWarn(w, r, msg+". Skipped "+strings.Join(report.Errors, "; "))
+	} else {
+		h.FlashInfo(w, r, msg)
+	}
+	h.Redir(w, r, am.ListPath(ssgPath, dataFilePath), http.StatusSeeOther)
+}
+
+// rejectDuplicateDataFile renders the form back with an error on the name
+// field.
+func (h *WebHandler) rejectDuplicateDataFile(w http.ResponseWriter, r *http.Request, form DataFileForm, file DataFile) {
+	v := form.Validation()
+	v.AddFieldError("name", form.Name, "name: another data file already uses it")
+	form.SetValidation(&v)
+	h.renderDataFileForm(w, r, form, file, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderDataFileForm(w http.ResponseWriter, r *http.Request, form DataFileForm, file DataFile, errorMessage string, statusCode int) {
+	page := am.NewPage(r, file)
+	page.SetForm(form)
+
+	if file.IsZero() {
+		page.Name = "New Data File"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, dataFilePath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Data File"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, dataFilePath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	formats := make([]am.SelectOpt, len(dataFormats))
+	for i, f := range dataFormats {
+		formats[i] = am.SelectOpt{Value: f, Label: strings.ToUpper(f)}
+	}
+	page.AddSelect("formats", formats)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(file)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-data-file")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
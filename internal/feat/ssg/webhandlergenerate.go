@@ -0,0 +1,24 @@
+package ssg
+
+import (
+	"net/http"
+)
+
+func (h *WebHandler) GenerateSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Generate site")
+	ctx := r.Context()
+
+	user := h.sampleUserInSession(r)
+	build, err := h.service.GenerateSite(ctx, BuildTriggerManual, user.ID())
+	if build.IsZero() {
+		h.Err(w, err, "Cannot generate site", http.StatusInternalServerError)
+		return
+	}
+
+	if err != nil {
+		h.FlashError(w, r, "Site generation failed")
+	} else {
+		h.FlashInfo(w, r, "Site generated")
+	}
+	h.Redir(w, r, showBuildPath(build.ID().String()), http.StatusSeeOther)
+}
@@ -0,0 +1,53 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// ShowLinkReport shows the broken links and missing assets found in the last
+// generated site.
+func (h *WebHandler) ShowLinkReport(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show link report")
+	ctx := r.Context()
+
+	report, err := h.service.GetLinkReport(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, report)
+	page.Name = "Link Report"
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "show-link-report")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// LinkReportJSON returns the last link report as JSON so it can be consumed
+// by CI jobs and other tools.
+func (h *WebHandler) LinkReportJSON(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	report, err := h.service.GetLinkReport(ctx)
+	if err != nil {
+		am.Respond(w, http.StatusInternalServerError, am.NewErrorResponse(am.ErrCannotGetResource, am.ErrorCodeInternalError, err.Error()))
+		return
+	}
+
+	am.Respond(w, http.StatusOK, am.NewSuccessResponse("Link report", report))
+}
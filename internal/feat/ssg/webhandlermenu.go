@@ -0,0 +1,489 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	menuPath     = "menu"
+	menuItemPath = "menu-item"
+)
+
+func (h *WebHandler) NewMenu(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New menu form")
+	form := NewMenuForm(r)
+	h.renderMenuForm(w, r, form, NewMenu("", ""), nil, "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateMenu(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create menu")
+	ctx := r.Context()
+
+	form, err := MenuFormFromRequest(r)
+	if err != nil {
+		h.renderMenuForm(w, r, form, ToMenuFromForm(form), nil, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderMenuForm(w, r, form, ToMenuFromForm(form), nil, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	menu := ToMenuFromForm(form)
+	menu.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateMenu(ctx, menu)
+	if errors.Is(err, ErrDuplicateMenu) {
+		h.rejectDuplicateMenu(w, r, form, ToMenuFromForm(form), nil)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu created")
+	h.Redir(w, r, editMenuPath(menu.ID()), http.StatusSeeOther)
+}
+
+// EditMenu shows the menu along with its items, which can be reordered by
+// dragging them.
+func (h *WebHandler) EditMenu(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit menu")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	menu, err := h.service.GetMenu(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := h.service.GetMenuTree(ctx, menu.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToMenuForm(r, menu)
+	h.renderMenuForm(w, r, form, menu, tree, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateMenu(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update menu")
+	ctx := r.Context()
+
+	form, err := MenuFormFromRequest(r)
+	if err != nil {
+		h.renderMenuForm(w, r, form, ToMenuFromForm(form), nil, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	menu := ToMenuFromForm(form)
+	tree, err := h.service.GetMenuTree(ctx, menu.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderMenuForm(w, r, form, menu, tree, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	menu.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateMenu(ctx, menu)
+	if errors.Is(err, ErrDuplicateMenu) {
+		h.rejectDuplicateMenu(w, r, form, menu, tree)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu updated")
+	h.Redir(w, r, am.ListPath(ssgPath, menuPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteMenu(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete menu")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteMenu(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, menuPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListMenus(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List menus")
+	ctx := r.Context()
+
+	menus, err := h.service.GetMenus(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, menus)
+	page.Name = "Menus"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(menuPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-menus")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// ReorderMenuItems saves the order of the items of a menu after a drag and
+// drop. The form lists every item once, in document order, each one along
+// with its parent.
+func (h *WebHandler) ReorderMenuItems(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Reorder menu items")
+	ctx := r.Context()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	menuID := am.ParseUUID(r.Form.Get("menu_id"))
+	ids := r.Form["item"]
+	parents := r.Form["parent"]
+	if menuID == uuid.Nil || len(ids) != len(parents) {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	next := make(map[uuid.UUID]int)
+	moves := make([]MenuMove, len(ids))
+	for i, id := range ids {
+		parent := am.ParseUUID(parents[i])
+		moves[i] = MenuMove{ID: am.ParseUUID(id), ParentID: parent, Position: next[parent]}
+		next[parent]++
+	}
+
+	err = h.service.ReorderMenu(ctx, menuID, moves)
+	if errors.Is(err, ErrInvalidMenuOrder) {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu order saved")
+	h.Redir(w, r, editMenuPath(menuID), http.StatusSeeOther)
+}
+
+func (h *WebHandler) NewMenuItem(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New menu item form")
+
+	menuID := r.URL.Query().Get("menu_id")
+	if am.ParseUUID(menuID) == uuid.Nil {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	form := NewMenuItemForm(r, menuID)
+	h.renderMenuItemForm(w, r, form, NewMenuItem(am.ParseUUID(menuID), "", MenuItemSection), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateMenuItem(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create menu item")
+	ctx := r.Context()
+
+	form, err := MenuItemFormFromRequest(r)
+	if err != nil {
+		h.renderMenuItemForm(w, r, form, ToMenuItemFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderMenuItemForm(w, r, form, ToMenuItemFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	item := ToMenuItemFromForm(form)
+	item.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateMenuItem(ctx, item)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu item created")
+	h.Redir(w, r, editMenuPath(item.MenuID), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditMenuItem(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit menu item")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.GetMenuItem(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToMenuItemForm(r, item)
+	h.renderMenuItemForm(w, r, form, item, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateMenuItem(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update menu item")
+	ctx := r.Context()
+
+	form, err := MenuItemFormFromRequest(r)
+	if err != nil {
+		h.renderMenuItemForm(w, r, form, ToMenuItemFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderMenuItemForm(w, r, form, ToMenuItemFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	item := ToMenuItemFromForm(form)
+	item.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateMenuItem(ctx, item)
+	if errors.Is(err, ErrInvalidMenuOrder) {
+		v := form.Validation()
+		v.AddFieldError("parent_id", form.ParentID, "parent_id: an item cannot be nested in itself")
+		form.SetValidation(&v)
+		h.renderMenuItemForm(w, r, form, item, "Validation failed", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu item updated")
+	h.Redir(w, r, editMenuPath(item.MenuID), http.StatusSeeOther)
+}
+
+// DeleteMenuItem deletes an item, its own items move up to its place.
+func (h *WebHandler) DeleteMenuItem(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete menu item")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.GetMenuItem(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	err = h.service.DeleteMenuItem(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Menu item deleted")
+	h.Redir(w, r, editMenuPath(item.MenuID), http.StatusSeeOther)
+}
+
+// rejectDuplicateMenu renders the form back with an error on the name field.
+func (h *WebHandler) rejectDuplicateMenu(w http.ResponseWriter, r *http.Request, form MenuForm, menu Menu, tree []MenuItemNode) {
+	v := form.Validation()
+	v.AddFieldError("name", form.Name, "name: another menu already uses it")
+	form.SetValidation(&v)
+	h.renderMenuForm(w, r, form, menu, tree, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderMenuForm(w http.ResponseWriter, r *http.Request, form MenuForm, menu Menu, tree []MenuItemNode, errorMessage string, statusCode int) {
+	page := am.NewPage(r, struct {
+		Menu  Menu
+		Items []MenuItemNode
+	}{
+		Menu:  menu,
+		Items: tree,
+	})
+	page.SetForm(form)
+
+	if menu.IsZero() {
+		page.Name = "New Menu"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, menuPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Menu"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, menuPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("names", stringOpts(menuNames))
+
+	m := page.NewMenu(ssgPath)
+	m.AddListItem(menu)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-menu")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
+
+func (h *WebHandler) renderMenuItemForm(w http.ResponseWriter, r *http.Request, form MenuItemForm, item MenuItem, errorMessage string, statusCode int) {
+	ctx := r.Context()
+
+	tree, err := h.service.GetMenuTree(ctx, item.MenuID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	sections, err := h.service.GetSections(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	contents, err := h.service.GetAllContent(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, item)
+	page.SetForm(form)
+
+	if item.IsZero() {
+		page.Name = "New Menu Item"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, menuItemPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Menu Item"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, menuItemPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("parents", parentOpts(tree, item.ID()))
+	page.AddSelect("kinds", stringOpts(menuItemKinds))
+	page.AddSelect("sections", am.ToSelectOpt(sections))
+
+	opts := make([]am.SelectOpt, len(contents))
+	for i, c := range contents {
+		opts[i] = am.SelectOpt{Value: c.ID().String(), Label: c.Heading}
+	}
+	page.AddSelect("contents", opts)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddGenericItem("edit-menu", item.MenuID.String(), "Back")
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-menu-item")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
+
+// parentOpts lists the items an item can be nested in, indented by depth.
+// The item itself and its own items are left out.
+func parentOpts(tree []MenuItemNode, id uuid.UUID) []am.SelectOpt {
+	opts := []am.SelectOpt{{Value: "", Label: "Top level"}}
+	var walk func(nodes []MenuItemNode, indent string)
+	walk = func(nodes []MenuItemNode, indent string) {
+		for _, n := range nodes {
+			if n.ID() == id {
+				continue
+			}
+			label := n.Label
+			if label == "" {
+				label = n.Target
+			}
+			opts = append(opts, am.SelectOpt{Value: n.ID().String(), Label: indent + label})
+			walk(n.Children, indent+"— ")
+		}
+	}
+	walk(tree, "")
+	return opts
+}
+
+func editMenuPath(id uuid.UUID) string {
+	return am.EditPath(ssgPath, menuPath, id)
+}
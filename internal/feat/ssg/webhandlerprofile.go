@@ -0,0 +1,233 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	profilePath = "profile"
+)
+
+func (h *WebHandler) NewProfile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New profile form")
+	form := NewProfileForm(r)
+	h.renderProfileForm(w, r, form, NewProfile("", ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create profile")
+	ctx := r.Context()
+
+	form, err := ProfileFormFromRequest(r)
+	if err != nil {
+		h.renderProfileForm(w, r, form, ToProfileFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderProfileForm(w, r, form, ToProfileFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	profile := ToProfileFromForm(form)
+	profile.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateProfile(ctx, profile)
+	if errors.Is(err, ErrDuplicateProfile) || errors.Is(err, ErrUserHasProfile) {
+		h.rejectProfile(w, r, form, ToProfileFromForm(form), err)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Author created")
+	h.Redir(w, r, am.ListPath(ssgPath, profilePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditProfile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit profile")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.service.GetProfile(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToProfileForm(r, profile)
+	h.renderProfileForm(w, r, form, profile, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update profile")
+	ctx := r.Context()
+
+	form, err := ProfileFormFromRequest(r)
+	if err != nil {
+		h.renderProfileForm(w, r, form, ToProfileFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	profile := ToProfileFromForm(form)
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderProfileForm(w, r, form, profile, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	profile.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateProfile(ctx, profile)
+	if errors.Is(err, ErrDuplicateProfile) || errors.Is(err, ErrUserHasProfile) {
+		h.rejectProfile(w, r, form, profile, err)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Author updated")
+	h.Redir(w, r, am.ListPath(ssgPath, profilePath), http.StatusSeeOther)
+}
+
+// DeleteProfile deletes an author profile, the contents it was credited for
+// are left in place.
+func (h *WebHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete profile")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteProfile(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Author deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, profilePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List profiles")
+	ctx := r.Context()
+
+	profiles, err := h.service.GetProfiles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, profiles)
+	page.Name = "Authors"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(profilePath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-profiles")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// rejectProfile renders the form back with an error on the field the service
+// rejected.
+func (h *WebHandler) rejectProfile(w http.ResponseWriter, r *http.Request, form ProfileForm, profile Profile, err error) {
+	v := form.Validation()
+	switch {
+	case errors.Is(err, ErrUserHasProfile):
+		v.AddFieldError("user_id", form.UserID, "user: already has an author profile")
+	case form.Slug == "":
+		v.AddFieldError("name", form.Name, "name: another author already uses the slug made from it")
+	default:
+		v.AddFieldError("slug", form.Slug, "slug: another author already uses it")
+	}
+	form.SetValidation(&v)
+	h.renderProfileForm(w, r, form, profile, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderProfileForm(w http.ResponseWriter, r *http.Request, form ProfileForm, profile Profile, errorMessage string, statusCode int) {
+	ctx := r.Context()
+
+	users, err := h.auth.GetUsers(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, profile)
+	page.SetForm(form)
+
+	if profile.IsZero() {
+		page.Name = "New Author"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, profilePath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Author"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, profilePath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	// Only the names of users are listed, their account data stays private.
+	opts := []am.SelectOpt{{Value: "", Label: "None, a guest author"}}
+	for _, u := range users {
+		label := u.Username
+		if u.Name != "" {
+			label += " (" + u.Name + ")"
+		}
+		opts = append(opts, am.SelectOpt{Value: u.ID().String(), Label: label})
+	}
+	page.AddSelect("users", opts)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(profile)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-profile")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
@@ -0,0 +1,262 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	publishTargetPath = "publish-target"
+)
+
+func (h *WebHandler) NewPublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New publish target form")
+	form := NewPublishTargetForm(r)
+	h.renderPublishTargetForm(w, r, form, NewPublishTarget("", EnvStaging, PublishTargetGit), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreatePublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create publish target")
+	ctx := r.Context()
+
+	form, err := PublishTargetFormFromRequest(r)
+	if err != nil {
+		h.renderPublishTargetForm(w, r, form, ToPublishTargetFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderPublishTargetForm(w, r, form, ToPublishTargetFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	target := ToPublishTargetFromForm(form)
+	target.GenCreateValues()
+
+	err = h.service.CreatePublishTarget(ctx, target)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Publish target created")
+	h.Redir(w, r, am.ListPath(ssgPath, publishTargetPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditPublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit publish target")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	target, err := h.service.GetPublishTarget(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToPublishTargetForm(r, target)
+	h.renderPublishTargetForm(w, r, form, target, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdatePublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update publish target")
+	ctx := r.Context()
+
+	form, err := PublishTargetFormFromRequest(r)
+	if err != nil {
+		h.renderPublishTargetForm(w, r, form, ToPublishTargetFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.service.GetPublishTarget(ctx, form.ID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+	if current.Kind == form.Kind {
+		form.HasPassword = current.HasCredential(CredPassword)
+		form.HasAccessKey = current.HasCredential(CredAccessKey)
+		form.HasSecretKey = current.HasCredential(CredSecretKey)
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderPublishTargetForm(w, r, form, ToPublishTargetFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	target := ToPublishTargetFromForm(form)
+	target.GenUpdateValues()
+
+	err = h.service.UpdatePublishTarget(ctx, target)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Publish target updated")
+	h.Redir(w, r, am.ListPath(ssgPath, publishTargetPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeletePublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete publish target")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeletePublishTarget(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Publish target deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, publishTargetPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListPublishTargets(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List publish targets")
+	ctx := r.Context()
+
+	targets, err := h.service.GetPublishTargets(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, targets)
+	page.Name = "Publish Targets"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(publishTargetPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-publish-targets")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// ShowPublishTarget shows a target and its publish history.
+func (h *WebHandler) ShowPublishTarget(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show publish target")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	target, err := h.service.GetPublishTarget(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	deployments, err := h.service.GetTargetDeployments(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Target      PublishTarget
+		Deployments []Deployment
+	}{
+		Target:      target,
+		Deployments: deployments,
+	})
+	page.Name = target.Name
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(target)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "show-publish-target")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+func (h *WebHandler) renderPublishTargetForm(w http.ResponseWriter, r *http.Request, form PublishTargetForm, target PublishTarget, errorMessage string, statusCode int) {
+	page := am.NewPage(r, target)
+	page.SetForm(form)
+
+	if target.IsZero() {
+		page.Name = "New Publish Target"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, publishTargetPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Publish Target"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, publishTargetPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("environments", stringOpts(environments))
+	page.AddSelect("kinds", stringOpts(publishTargetKinds))
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(target)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-publish-target")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
+
+func stringOpts(values []string) []am.SelectOpt {
+	opts := make([]am.SelectOpt, len(values))
+	for i, v := range values {
+		opts[i] = am.SelectOpt{Value: v, Label: v}
+	}
+	return opts
+}
@@ -0,0 +1,217 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	redirectPath = "redirect"
+)
+
+func (h *WebHandler) NewRedirect(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New redirect form")
+	form := NewRedirectForm(r)
+	h.renderRedirectForm(w, r, form, NewRedirect("", "", http.StatusMovedPermanently), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateRedirect(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create redirect")
+	ctx := r.Context()
+
+	form, err := RedirectFormFromRequest(r)
+	if err != nil {
+		h.renderRedirectForm(w, r, form, ToRedirectFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderRedirectForm(w, r, form, ToRedirectFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	redirect := ToRedirectFromForm(form)
+	redirect.GenCreateValues()
+
+	err = h.service.CreateRedirect(ctx, redirect)
+	if errors.Is(err, ErrDuplicateRedirect) {
+		h.rejectDuplicateRedirect(w, r, form, ToRedirectFromForm(form))
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Redirect created")
+	h.Redir(w, r, am.ListPath(ssgPath, redirectPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditRedirect(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit redirect")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := h.service.GetRedirect(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToRedirectForm(r, redirect)
+	h.renderRedirectForm(w, r, form, redirect, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateRedirect(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update redirect")
+	ctx := r.Context()
+
+	form, err := RedirectFormFromRequest(r)
+	if err != nil {
+		h.renderRedirectForm(w, r, form, ToRedirectFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderRedirectForm(w, r, form, ToRedirectFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	redirect := ToRedirectFromForm(form)
+	redirect.GenUpdateValues()
+
+	err = h.service.UpdateRedirect(ctx, redirect)
+	if errors.Is(err, ErrDuplicateRedirect) {
+		h.rejectDuplicateRedirect(w, r, form, redirect)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Redirect updated")
+	h.Redir(w, r, am.ListPath(ssgPath, redirectPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteRedirect(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete redirect")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteRedirect(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Redirect deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, redirectPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListRedirects(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List redirects")
+	ctx := r.Context()
+
+	redirects, err := h.service.GetAllRedirects(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, redirects)
+	page.Name = "Redirects"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(redirectPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-redirects")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// rejectDuplicateRedirect renders the form back with an error on the source
+// path field.
+func (h *WebHandler) rejectDuplicateRedirect(w http.ResponseWriter, r *http.Request, form RedirectForm, redirect Redirect) {
+	v := form.Validation()
+	v.AddFieldError("source_path", form.SourcePath, "source_path: another redirect already handles this path")
+	form.SetValidation(&v)
+	h.renderRedirectForm(w, r, form, redirect, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderRedirectForm(w http.ResponseWriter, r *http.Request, form RedirectForm, redirect Redirect, errorMessage string, statusCode int) {
+	page := am.NewPage(r, redirect)
+	page.SetForm(form)
+
+	if redirect.IsZero() {
+		page.Name = "New Redirect"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, redirectPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Redirect"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, redirectPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("statuses", redirectStatusOpts())
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(redirect)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-redirect")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
+
+func redirectStatusOpts() []am.SelectOpt {
+	opts := make([]am.SelectOpt, len(redirectStatuses))
+	for i, code := range redirectStatuses {
+		value := strconv.Itoa(code)
+		opts[i] = am.SelectOpt{Value: value, Label: value + " " + http.StatusText(code)}
+	}
+	return opts
+}
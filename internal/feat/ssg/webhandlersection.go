@@ -64,6 +64,7 @@ func (h *WebHandler) newSection(w http.ResponseWriter, r *http.Request, form Sec
 	page.SetForm(form)
 	page.Form.SetAction(am.CreatePath(ssgPath, sectionPath))
 	page.Form.SetSubmitButtonText("Create")
+	page.AddSelect("langs", h.langOpts())
 
 	menu := page.NewMenu(ssgPath)
 	menu.AddListItem(section)
@@ -0,0 +1,374 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	seriesPath = "series"
+	// listSeriesPath is spelled out as ListPath would pluralize series.
+	listSeriesPath = ssgPath + "/list-series"
+)
+
+func (h *WebHandler) NewSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New series form")
+	form := NewSeriesForm(r)
+	h.renderSeriesForm(w, r, form, NewSeries("", ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create series")
+	ctx := r.Context()
+
+	form, err := SeriesFormFromRequest(r)
+	if err != nil {
+		h.renderSeriesForm(w, r, form, ToSeriesFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderSeriesForm(w, r, form, ToSeriesFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	series := ToSeriesFromForm(form)
+	series.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateSeries(ctx, series)
+	if errors.Is(err, ErrDuplicateSeries) {
+		h.rejectDuplicateSeries(w, r, form, ToSeriesFromForm(form))
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Series created")
+	h.Redir(w, r, editSeriesPath(series.ID()), http.StatusSeeOther)
+}
+
+// EditSeries shows the series along with its contents, which can be
+// reordered by dragging them.
+func (h *WebHandler) EditSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit series")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.service.GetSeries(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToSeriesForm(r, series)
+	h.renderSeriesForm(w, r, form, series, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update series")
+	ctx := r.Context()
+
+	form, err := SeriesFormFromRequest(r)
+	if err != nil {
+		h.renderSeriesForm(w, r, form, ToSeriesFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	series := ToSeriesFromForm(form)
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderSeriesForm(w, r, form, series, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	series.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateSeries(ctx, series)
+	if errors.Is(err, ErrDuplicateSeries) {
+		h.rejectDuplicateSeries(w, r, form, series)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Series updated")
+	h.Redir(w, r, listSeriesPath, http.StatusSeeOther)
+}
+
+// DeleteSeries deletes a series, its contents are left in place.
+func (h *WebHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete series")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteSeries(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Series deleted")
+	h.Redir(w, r, listSeriesPath, http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List series")
+	ctx := r.Context()
+
+	series, err := h.service.GetAllSeries(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, series)
+	page.Name = "Series"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(seriesPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-series")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// AddSeriesContent adds a content at the end of a series.
+func (h *WebHandler) AddSeriesContent(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Add series content")
+	ctx := r.Context()
+
+	seriesID := am.ParseUUID(r.FormValue("series_id"))
+	contentID := am.ParseUUID(r.FormValue("content_id"))
+	if seriesID == uuid.Nil || contentID == uuid.Nil {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.AddSeriesContent(ctx, seriesID, contentID)
+	if errors.Is(err, ErrContentInSeries) {
+		h.FlashWarn(w, r, "The content or one of its translations is already in a series")
+		h.Redir(w, r, editSeriesPath(seriesID), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Content added to the series")
+	h.Redir(w, r, editSeriesPath(seriesID), http.StatusSeeOther)
+}
+
+// RemoveSeriesContent takes a content out of a series.
+func (h *WebHandler) RemoveSeriesContent(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Remove series content")
+	ctx := r.Context()
+
+	seriesID := am.ParseUUID(r.FormValue("series_id"))
+	contentID := am.ParseUUID(r.FormValue("content_id"))
+	if seriesID == uuid.Nil || contentID == uuid.Nil {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.RemoveSeriesContent(ctx, seriesID, contentID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Content removed from the series")
+	h.Redir(w, r, editSeriesPath(seriesID), http.StatusSeeOther)
+}
+
+// ReorderSeries saves the order of the contents of a series after a drag and
+// drop. The form lists every content once, in order.
+func (h *WebHandler) ReorderSeries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Reorder series")
+	ctx := r.Context()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	seriesID := am.ParseUUID(r.Form.Get("series_id"))
+	if seriesID == uuid.Nil {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	order := make([]uuid.UUID, len(r.Form["content_id"]))
+	for i, id := range r.Form["content_id"] {
+		order[i] = am.ParseUUID(id)
+	}
+
+	err = h.service.ReorderSeries(ctx, seriesID, order)
+	if errors.Is(err, ErrInvalidSeriesOrder) {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Series order saved")
+	h.Redir(w, r, editSeriesPath(seriesID), http.StatusSeeOther)
+}
+
+// rejectDuplicateSeries renders the form back with an error on the slug
+// field, or on the name if the slug was taken from it.
+func (h *WebHandler) rejectDuplicateSeries(w http.ResponseWriter, r *http.Request, form SeriesForm, series Series) {
+	v := form.Validation()
+	if form.Slug == "" {
+		v.AddFieldError("name", form.Name, "name: another series already uses the slug made from it")
+	} else {
+		v.AddFieldError("slug", form.Slug, "slug: another series already uses it")
+	}
+	form.SetValidation(&v)
+	h.renderSeriesForm(w, r, form, series, "Validation failed", http.StatusBadRequest)
+}
+
+// renderSeriesForm renders the series form. Existing series also show their
+// contents and the contents that can be added to them.
+func (h *WebHandler) renderSeriesForm(w http.ResponseWriter, r *http.Request, form SeriesForm, series Series, errorMessage string, statusCode int) {
+	ctx := r.Context()
+
+	var parts []Content
+	var opts []am.SelectOpt
+	if !series.IsZero() {
+		var err error
+		parts, err = h.service.GetSeriesContents(ctx, series.ID())
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+
+		opts, err = h.seriesContentOpts(r)
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	page := am.NewPage(r, struct {
+		Series Series
+		Parts  []Content
+	}{
+		Series: series,
+		Parts:  parts,
+	})
+	page.SetForm(form)
+
+	if series.IsZero() {
+		page.Name = "New Series"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, seriesPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Series"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, seriesPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("contents", opts)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddGenericItem("list-series", "", "Back")
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-series")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
+
+// seriesContentOpts lists the contents that are not in a series yet, through
+// themselves or a translation.
+func (h *WebHandler) seriesContentOpts(r *http.Request) ([]am.SelectOpt, error) {
+	ctx := r.Context()
+
+	contents, err := h.service.GetAllContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := h.service.GetAllSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[uuid.UUID]bool)
+	for _, s := range all {
+		members, err := h.service.GetSeriesContents(ctx, s.ID())
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range members {
+			taken[c.TranslationKey()] = true
+		}
+	}
+
+	var opts []am.SelectOpt
+	for _, c := range contents {
+		if taken[c.TranslationKey()] {
+			continue
+		}
+		label := c.Heading
+		if c.Lang != "" {
+			label += " (" + c.Lang + ")"
+		}
+		opts = append(opts, am.SelectOpt{Value: c.ID().String(), Label: label})
+	}
+	return opts, nil
+}
+
+func editSeriesPath(id uuid.UUID) string {
+	return am.EditPath(ssgPath, seriesPath, id)
+}
@@ -0,0 +1,304 @@
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	sitePath = "site"
+
+	// siteCookie keeps the site chosen with the switcher.
+	siteCookie = "hermes_site"
+)
+
+func (h *WebHandler) NewSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New site form")
+	form := NewSiteForm(r)
+	h.renderSiteForm(w, r, form, NewSite("", ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create site")
+	ctx := r.Context()
+
+	form, err := SiteFormFromRequest(r)
+	if err != nil {
+		h.renderSiteForm(w, r, form, ToSiteFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderSiteForm(w, r, form, ToSiteFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	site := ToSiteFromForm(form)
+	site.GenCreateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.CreateSite(ctx, site)
+	if errors.Is(err, ErrDuplicateSite) {
+		h.rejectDuplicateSite(w, r, form, ToSiteFromForm(form))
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Site created")
+	h.Redir(w, r, am.ListPath(ssgPath, sitePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit site")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	site, err := h.service.GetSite(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToSiteForm(r, site)
+	h.renderSiteForm(w, r, form, site, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update site")
+	ctx := r.Context()
+
+	form, err := SiteFormFromRequest(r)
+	if err != nil {
+		h.renderSiteForm(w, r, form, ToSiteFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderSiteForm(w, r, form, ToSiteFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	site := ToSiteFromForm(form)
+	site.GenUpdateValues(h.sampleUserInSession(r).ID())
+
+	err = h.service.UpdateSite(ctx, site)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Site updated")
+	h.Redir(w, r, am.ListPath(ssgPath, sitePath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListSites(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List sites")
+	ctx := r.Context()
+
+	sites, err := h.service.GetSites(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	owners := map[string]string{}
+	for _, opt := range h.ownerOpts(ctx) {
+		owners[opt.Value] = opt.Label
+	}
+
+	page := am.NewPage(r, struct {
+		Sites   []Site
+		Owners  map[string]string
+		Current Site
+	}{
+		Sites:   sites,
+		Owners:  owners,
+		Current: SiteFrom(ctx),
+	})
+	page.Name = "Sites"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(sitePath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-sites")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// SiteSwitcher renders the site select of the header. It is loaded by the
+// header itself so that every page shows it.
+func (h *WebHandler) SiteSwitcher(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sites, err := h.service.GetSites(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, SiteFrom(ctx))
+	page.AddSelect("sites", am.ToSelectOpt(sites))
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "site-switcher")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// SwitchSite makes the chosen site the one the following requests work on.
+func (h *WebHandler) SwitchSite(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Switch site")
+	ctx := r.Context()
+
+	slug := r.FormValue("site")
+	site, err := h.service.GetSiteBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.Err(w, err, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     siteCookie,
+		Value:    site.SlugValue,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.FlashInfo(w, r, "Working on "+site.Name)
+	h.Redir(w, r, ssgPath+"/list-content", http.StatusSeeOther)
+}
+
+// SiteMw scopes requests to the site chosen with the switcher. The default
+// site is used if none was chosen or if the chosen one no longer exists.
+func (h *WebHandler) SiteMw(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		slug := DefaultSiteSlug
+		if c, err := r.Cookie(siteCookie); err == nil && c.Value != "" {
+			slug = c.Value
+		}
+
+		site, err := h.service.GetSiteBySlug(ctx, slug)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				h.Log().Errorf("Cannot get site %s: %v", slug, err)
+			}
+			site = DefaultSite()
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithSite(ctx, site)))
+	})
+}
+
+// ownerOpts returns the org and its teams as site owner options. Failing to
+// get them only leaves sites without owner options.
+func (h *WebHandler) ownerOpts(ctx context.Context) []am.SelectOpt {
+	opts := []am.SelectOpt{{Value: "", Label: "None"}}
+
+	org, err := h.auth.GetDefaultOrg(ctx)
+	if err != nil {
+		h.Log().Errorf("Cannot get default org: %v", err)
+		return opts
+	}
+	opts = append(opts, am.SelectOpt{Value: ownerOrg + ":" + org.ID().String(), Label: org.Name})
+
+	teams, err := h.auth.GetAllTeams(ctx, org.ID())
+	if err != nil {
+		h.Log().Errorf("Cannot get teams: %v", err)
+		return opts
+	}
+	for _, team := range teams {
+		opts = append(opts, am.SelectOpt{Value: ownerTeam + ":" + team.ID().String(), Label: org.Name + " / " + team.Name})
+	}
+	return opts
+}
+
+// rejectDuplicateSite renders the form back with an error on the slug field.
+func (h *WebHandler) rejectDuplicateSite(w http.ResponseWriter, r *http.Request, form SiteForm, site Site) {
+	v := form.Validation()
+	v.AddFieldError("slug", form.Slug, "slug: another site already uses it")
+	form.SetValidation(&v)
+	h.renderSiteForm(w, r, form, site, "Validation failed", http.StatusBadRequest)
+}
+
+func (h *WebHandler) renderSiteForm(w http.ResponseWriter, r *http.Request, form SiteForm, site Site, errorMessage string, statusCode int) {
+	page := am.NewPage(r, site)
+	page.SetForm(form)
+
+	if site.IsZero() {
+		page.Name = "New Site"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, sitePath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Site"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, sitePath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("owners", h.ownerOpts(r.Context()))
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(site)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-site")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
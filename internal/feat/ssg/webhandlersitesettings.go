@@ -0,0 +1,91 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+func (h *WebHandler) EditSiteSettings(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit site settings")
+	ctx := r.Context()
+
+	settings, err := h.service.GetSiteSettings(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToSiteSettingsForm(r, settings, Languages(h.Cfg()))
+	h.renderSiteSettingsForm(w, r, form, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateSiteSettings(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update site settings")
+	ctx := r.Context()
+
+	form, err := SiteSettingsFormFromRequest(r, Languages(h.Cfg()))
+	if err != nil {
+		h.renderSiteSettingsForm(w, r, form, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderSiteSettingsForm(w, r, form, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	settings := ToSiteSettingsFromForm(form)
+	userID := h.sampleUserInSession(r).ID()
+	if settings.IsZero() {
+		settings.GenCreateValues(userID)
+	} else {
+		settings.GenUpdateValues(userID)
+	}
+
+	err = h.service.UpdateSiteSettings(ctx, settings)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Site settings updated")
+	h.Redir(w, r, ssgPath+"/edit-settings", http.StatusSeeOther)
+}
+
+func (h *WebHandler) renderSiteSettingsForm(w http.ResponseWriter, r *http.Request, form SiteSettingsForm, errorMessage string, statusCode int) {
+	site := SiteFrom(r.Context())
+	page := am.NewPage(r, site)
+	page.SetForm(form)
+	page.Name = "Settings of " + site.Name
+	page.Form.SetAction(ssgPath + "/update-settings")
+	page.Form.SetSubmitButtonText("Update")
+
+	langs := make([]am.SelectOpt, len(form.Langs))
+	for i, lang := range form.Langs {
+		langs[i] = am.SelectOpt{Value: lang, Label: lang}
+	}
+	page.AddSelect("langs", langs)
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(site)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "edit-settings")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
@@ -0,0 +1,48 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+// ListTranslations shows, for every translation group, which languages are
+// missing or stale.
+func (h *WebHandler) ListTranslations(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List translations")
+	ctx := r.Context()
+
+	groups, err := h.service.GetTranslationGroups(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Langs  []string
+		Groups []TranslationGroup
+	}{
+		Langs:  Languages(h.Cfg()),
+		Groups: groups,
+	})
+	page.Name = "Translations"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(contentPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-translations")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
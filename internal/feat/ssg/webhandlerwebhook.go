@@ -0,0 +1,248 @@
+package ssg
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	webhookPath = "webhook"
+)
+
+func (h *WebHandler) NewWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New webhook form")
+	form := NewWebhookForm(r)
+	h.renderWebhookForm(w, r, form, NewWebhook("", ""), "", http.StatusOK)
+}
+
+func (h *WebHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create webhook")
+	ctx := r.Context()
+
+	form, err := WebhookFormFromRequest(r)
+	if err != nil {
+		h.renderWebhookForm(w, r, form, ToWebhookFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderWebhookForm(w, r, form, ToWebhookFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	hook := ToWebhookFromForm(form)
+	hook.GenCreateValues()
+
+	err = h.service.CreateWebhook(ctx, hook)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Webhook created")
+	h.Redir(w, r, am.ListPath(ssgPath, webhookPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EditWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Edit webhook")
+	ctx := r.Context()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.service.GetWebhook(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResource, http.StatusInternalServerError)
+		return
+	}
+
+	form := ToWebhookForm(r, hook)
+	h.renderWebhookForm(w, r, form, hook, "", http.StatusOK)
+}
+
+func (h *WebHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update webhook")
+	ctx := r.Context()
+
+	form, err := WebhookFormFromRequest(r)
+	if err != nil {
+		h.renderWebhookForm(w, r, form, ToWebhookFromForm(form), "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	err = form.Validate()
+	if err != nil || form.HasErrors() {
+		h.renderWebhookForm(w, r, form, ToWebhookFromForm(form), "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	hook := ToWebhookFromForm(form)
+	hook.GenUpdateValues()
+
+	err = h.service.UpdateWebhook(ctx, hook)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Webhook updated")
+	h.Redir(w, r, am.ListPath(ssgPath, webhookPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete webhook")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteWebhook(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Webhook deleted")
+	h.Redir(w, r, am.ListPath(ssgPath, webhookPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List webhooks")
+	ctx := r.Context()
+
+	hooks, err := h.service.GetWebhooks(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, hooks)
+	page.Name = "Webhooks"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddNewItem(webhookPath)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-webhooks")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// ListWebhookDeliveries shows the delivery log, of a single webhook if
+// webhook_id is set.
+func (h *WebHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List webhook deliveries")
+	ctx := r.Context()
+
+	webhookID := r.URL.Query().Get("webhook_id")
+
+	deliveries, err := h.service.GetWebhookDeliveries(ctx, webhookID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, deliveries)
+	page.Name = "Webhook Deliveries"
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(NewWebhook("", ""))
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "list-webhook-deliveries")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, http.StatusOK)
+}
+
+// RedeliverWebhook sends the event of a past delivery again.
+func (h *WebHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Redeliver webhook")
+	ctx := r.Context()
+
+	id := r.FormValue("id")
+	if id == "" {
+		h.Err(w, nil, am.ErrBadRequest, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.RedeliverWebhook(ctx, id)
+	if err != nil {
+		h.Err(w, err, "Cannot redeliver webhook", http.StatusInternalServerError)
+		return
+	}
+
+	h.FlashInfo(w, r, "Event queued for redelivery")
+	h.Redir(w, r, ssgPath+"/list-webhook-deliveries", http.StatusSeeOther)
+}
+
+func (h *WebHandler) renderWebhookForm(w http.ResponseWriter, r *http.Request, form WebhookForm, hook Webhook, errorMessage string, statusCode int) {
+	page := am.NewPage(r, hook)
+	page.SetForm(form)
+
+	if hook.IsZero() {
+		page.Name = "New Webhook"
+		page.IsNew = true
+		page.Form.SetAction(am.CreatePath(ssgPath, webhookPath))
+		page.Form.SetSubmitButtonText("Create")
+	} else {
+		page.Name = "Edit Webhook"
+		page.IsNew = false
+		page.Form.SetAction(am.UpdatePath(ssgPath, webhookPath))
+		page.Form.SetSubmitButtonText("Update")
+	}
+
+	page.AddSelect("events", stringOpts(webhookEvents))
+
+	menu := page.NewMenu(ssgPath)
+	menu.AddListItem(hook)
+
+	tmpl, err := h.Tmpl().Get(ssgFeat, "new-webhook")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	page.SetFlash(h.GetFlash(r))
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	h.OK(w, r, &buf, statusCode)
+}
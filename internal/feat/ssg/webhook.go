@@ -0,0 +1,145 @@
+package ssg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+const (
+	webhookType = "webhook"
+)
+
+// Webhook events.
+const (
+	EventContentCreated   = "content.created"
+	EventContentUpdated   = "content.updated"
+	EventContentPublished = "content.published"
+	EventBuildStarted     = "build.started"
+	EventBuildSucceeded   = "build.succeeded"
+	EventBuildFailed      = "build.failed"
+	EventPublishCompleted = "publish.completed"
+)
+
+var webhookEvents = []string{
+	EventContentCreated,
+	EventContentUpdated,
+	EventContentPublished,
+	EventBuildStarted,
+	EventBuildSucceeded,
+	EventBuildFailed,
+	EventPublishCompleted,
+}
+
+// Webhook is a subscription that gets the events it lists posted to its URL.
+// Payloads are signed with the secret so that receivers can verify them.
+type Webhook struct {
+	*am.BaseModel
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"-"` // Never serialized, stored encrypted in SecretEnc
+	SecretEnc []byte   `json:"-"`
+}
+
+func NewWebhook(name, url string, events ...string) Webhook {
+	return Webhook{
+		BaseModel: am.NewModel(am.WithType(webhookType)),
+		Name:      name,
+		URL:       url,
+		Events:    events,
+		Active:    true,
+	}
+}
+
+func (w Webhook) IsZero() bool {
+	return w.BaseModel == nil || w.BaseModel.IsZero()
+}
+
+// Subscribes reports whether the webhook wants the event.
+func (w Webhook) Subscribes(event string) bool {
+	if !w.Active {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) Slug() string {
+	return am.Normalize(w.Name) + "-" + w.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (w *Webhook) UnmarshalJSON(data []byte) error {
+	type Alias Webhook
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*w = Webhook(*temp)
+	if w.BaseModel == nil {
+		w.BaseModel = am.NewModel(am.WithType(webhookType))
+	}
+	return nil
+}
+
+// newWebhookSecret returns a random secret to sign payloads with.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Event payload data. Only the fields receivers are likely to act on are
+// sent, the rest can be fetched from Hermes.
+
+func contentEventData(content Content, url string) map[string]any {
+	return map[string]any{
+		"id":           content.ID(),
+		"heading":      content.Heading,
+		"slug":         content.Slug(),
+		"lang":         content.Lang,
+		"status":       content.Status,
+		"url":          url,
+		"section_id":   content.SectionID,
+		"published_at": content.PublishedAt,
+	}
+}
+
+func buildEventData(build Build) map[string]any {
+	return map[string]any{
+		"id":             build.ID(),
+		"trigger":        build.Trigger,
+		"status":         build.Status,
+		"started_at":     build.StartedAt,
+		"finished_at":    build.FinishedAt,
+		"pages_rendered": build.PagesRendered,
+		"warnings":       len(build.Warnings),
+		"errors":         build.Errors,
+	}
+}
+
+func deploymentEventData(deployment Deployment, target PublishTarget) map[string]any {
+	return map[string]any{
+		"id":          deployment.ID(),
+		"build_id":    deployment.BuildID,
+		"target":      deployment.TargetName,
+		"environment": deployment.Environment,
+		"base_url":    target.BaseURL,
+		"status":      deployment.Status,
+		"changes":     deployment.Changes,
+		"ref":         deployment.Ref,
+		"error":       deployment.Error,
+		"promoted":    deployment.IsPromotion(),
+	}
+}
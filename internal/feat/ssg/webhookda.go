@@ -0,0 +1,22 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDA struct {
+	ID        uuid.UUID  `db:"id"`
+	ShortID   string     `db:"short_id"`
+	SiteID    string     `db:"site_id"`
+	Name      string     `db:"name"`
+	URL       string     `db:"url"`
+	Events    string     `db:"events"`
+	Active    bool       `db:"active"`
+	SecretEnc []byte     `db:"secret_enc"`
+	CreatedBy *string    `db:"created_by"`
+	UpdatedBy *string    `db:"updated_by"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package ssg
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookDeliveryType = "webhook-delivery"
+)
+
+// WebhookDelivery records one attempt to post an event to a webhook.
+type WebhookDelivery struct {
+	*am.BaseModel
+	WebhookID   uuid.UUID `json:"webhook_id"`
+	WebhookName string    `json:"webhook_name"`
+	EventID     uuid.UUID `json:"event_id"`
+	Event       string    `json:"event"`
+	URL         string    `json:"url"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code"` // Zero if no response was received
+	Error       string    `json:"error"`
+	DurationMS  int64     `json:"duration_ms"`
+	Payload     string    `json:"payload"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	RetryAt     time.Time `json:"retry_at"` // Zero unless another attempt is pending
+}
+
+func NewWebhookDelivery(hook Webhook, event Event, attempt int) WebhookDelivery {
+	return WebhookDelivery{
+		BaseModel:   am.NewModel(am.WithType(webhookDeliveryType)),
+		WebhookID:   hook.ID(),
+		WebhookName: hook.Name,
+		EventID:     event.ID,
+		Event:       event.Type,
+		URL:         hook.URL,
+		Attempt:     attempt,
+		DeliveredAt: am.Now(),
+	}
+}
+
+func (d WebhookDelivery) IsZero() bool {
+	return d.BaseModel == nil || d.BaseModel.IsZero()
+}
+
+// Succeeded reports whether the receiver accepted the event.
+func (d WebhookDelivery) Succeeded() bool {
+	return d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// Pending reports whether the delivery is to be attempted again.
+func (d WebhookDelivery) Pending() bool {
+	return !d.RetryAt.IsZero()
+}
+
+func (d WebhookDelivery) Duration() time.Duration {
+	return time.Duration(d.DurationMS) * time.Millisecond
+}
+
+func (d *WebhookDelivery) Slug() string {
+	return d.DeliveredAt.Format("20060102-150405") + "-" + d.ShortID()
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (d *WebhookDelivery) UnmarshalJSON(data []byte) error {
+	type Alias WebhookDelivery
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*d = WebhookDelivery(*temp)
+	if d.BaseModel == nil {
+		d.BaseModel = am.NewModel(am.WithType(webhookDeliveryType))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryDA struct {
+	ID          uuid.UUID  `db:"id"`
+	ShortID     string     `db:"short_id"`
+	SiteID      string     `db:"site_id"`
+	WebhookID   string     `db:"webhook_id"`
+	WebhookName string     `db:"webhook_name"`
+	EventID     string     `db:"event_id"`
+	Event       string     `db:"event"`
+	URL         string     `db:"url"`
+	Attempt     int        `db:"attempt"`
+	StatusCode  int        `db:"status_code"`
+	Error       string     `db:"error"`
+	DurationMS  int64      `db:"duration_ms"`
+	Payload     string     `db:"payload"`
+	DeliveredAt *time.Time `db:"delivered_at"`
+	RetryAt     *time.Time `db:"retry_at"`
+	CreatedBy   *string    `db:"created_by"`
+	UpdatedBy   *string    `db:"updated_by"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,89 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adrianpk/hermes/internal/am"
+)
+
+type WebhookForm struct {
+	*am.BaseForm
+	ID     string   `form:"id"`
+	Name   string   `form:"name" required:"true"`
+	URL    string   `form:"url" required:"true"`
+	Events []string `form:"events"`
+	Active string   `form:"active"`
+	Secret string   `form:"secret"`
+}
+
+func NewWebhookForm(r *http.Request) WebhookForm {
+	return WebhookForm{
+		BaseForm: am.NewBaseForm(r),
+		Active:   "on",
+	}
+}
+
+func WebhookFormFromRequest(r *http.Request) (wf WebhookForm, err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return wf, err
+	}
+
+	return WebhookForm{
+		BaseForm: am.NewBaseForm(r),
+		ID:       r.Form.Get("id"),
+		Name:     strings.TrimSpace(r.Form.Get("name")),
+		URL:      strings.TrimSpace(r.Form.Get("url")),
+		Events:   r.Form["events"],
+		Active:   r.Form.Get("active"),
+		Secret:   strings.TrimSpace(r.Form.Get("secret")),
+	}, nil
+}
+
+func (form WebhookForm) IsActive() bool {
+	return form.Active != ""
+}
+
+// Subscribes reports whether the event is checked.
+func (form WebhookForm) Subscribes(event string) bool {
+	for _, e := range form.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (form *WebhookForm) Validate() error {
+	validate := am.ComposeValidators(
+		am.MinLength("name", form.Name, 1),
+		am.MaxLength("name", form.Name, 64),
+		am.MinLength("url", form.URL, 1),
+		validBaseURL("url", form.URL),
+		validEvents("events", form.Events),
+	)
+	v, err := validate(*form)
+	if err != nil {
+		return err
+	}
+	form.SetValidation(&v)
+	return nil
+}
+
+func validEvents(field string, events []string) am.Validator {
+	return func(_ any) (am.Validation, error) {
+		v := am.Validation{}
+		if len(events) == 0 {
+			v.AddFieldError(field, "", fmt.Sprintf("%s: select at least one event", field))
+			return v, nil
+		}
+		for _, e := range events {
+			if !contains(webhookEvents, e) {
+				v.AddFieldError(field, e, fmt.Sprintf("%s: unknown event %s", field, e))
+			}
+		}
+		return v, nil
+	}
+}
@@ -77,3 +77,18 @@ func (r *HermesRepo) BeginTx(ctx context.Context) (context.Context, am.Tx, error
 	ctxWithTx := am.WithTx(ctx, tx)
 	return ctxWithTx, tx, nil
 }
+
+// joinTx begins a transaction unless ctx already carries one. In that case the
+// returned one does nothing on commit and rollback, the outer transaction
+// decides.
+func (r *HermesRepo) joinTx(ctx context.Context) (context.Context, am.Tx, error) {
+	if _, ok := am.TxFromContext(ctx); ok {
+		return ctx, joinedTx{}, nil
+	}
+	return r.BeginTx(ctx)
+}
+
+type joinedTx struct{}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
@@ -2,16 +2,152 @@ package sqlite
 
 import (
 	"context"
+	"time"
 
+	"github.com/adrianpk/hermes/internal/am"
 	"github.com/adrianpk/hermes/internal/feat/ssg"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 const (
-	ssgAuth    = "ssg"
-	resContent = "content"
-	resSection = "section"
+	ssgAuth     = "ssg"
+	resContent  = "content"
+	resSection  = "section"
+	resRedirect = "redirect"
+	resBuild    = "build"
+	resTarget   = "publish_target"
+	resDeploy   = "deployment"
+	resWebhook  = "webhook"
+	resDelivery = "webhook_delivery"
+	resAPIToken = "api_token"
+	resIdemKey  = "api_idempotency_key"
+	resTerm     = "term"
+	resSite     = "site"
+	resSettings = "site_settings"
+	resCType    = "content_type"
+	resData     = "data_file"
+	resMenu     = "menu"
+	resMenuItem = "menu_item"
+	resSeries   = "series"
+	resPart     = "series_part"
+	resProfile  = "profile"
+	resAuthor   = "content_author"
+	resLock     = "content_lock"
+	resDraft    = "content_draft"
 )
 
+// siteID returns the site ssg queries are scoped to.
+func siteID(ctx context.Context) string {
+	return ssg.SiteIDFrom(ctx).String()
+}
+
+// Site related
+
+func (repo *HermesRepo) CreateSite(ctx context.Context, site ssg.Site) error {
+	query, err := repo.Query().Get(ssgAuth, resSite, "Create")
+	if err != nil {
+		return err
+	}
+
+	siteDA := ssg.ToSiteDA(site)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, siteDA)
+	return err
+}
+
+func (repo *HermesRepo) GetSites(ctx context.Context) ([]ssg.Site, error) {
+	query, err := repo.Query().Get(ssgAuth, resSite, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.SiteDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToSites(das), nil
+}
+
+func (repo *HermesRepo) GetSite(ctx context.Context, id string) (ssg.Site, error) {
+	query, err := repo.Query().Get(ssgAuth, resSite, "Get")
+	if err != nil {
+		return ssg.Site{}, err
+	}
+
+	var da ssg.SiteDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id)
+	if err != nil {
+		return ssg.Site{}, err
+	}
+
+	return ssg.ToSite(da), nil
+}
+
+func (repo *HermesRepo) GetSiteBySlug(ctx context.Context, slug string) (ssg.Site, error) {
+	query, err := repo.Query().Get(ssgAuth, resSite, "GetBySlug")
+	if err != nil {
+		return ssg.Site{}, err
+	}
+
+	var da ssg.SiteDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, slug)
+	if err != nil {
+		return ssg.Site{}, err
+	}
+
+	return ssg.ToSite(da), nil
+}
+
+func (repo *HermesRepo) UpdateSite(ctx context.Context, site ssg.Site) error {
+	query, err := repo.Query().Get(ssgAuth, resSite, "Update")
+	if err != nil {
+		return err
+	}
+
+	siteDA := ssg.ToSiteDA(site)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, siteDA)
+	return err
+}
+
+// SiteSettings related
+
+func (repo *HermesRepo) GetSiteSettings(ctx context.Context) (ssg.SiteSettings, error) {
+	query, err := repo.Query().Get(ssgAuth, resSettings, "Get")
+	if err != nil {
+		return ssg.SiteSettings{}, err
+	}
+
+	var da ssg.SiteSettingsDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, siteID(ctx))
+	if err != nil {
+		return ssg.SiteSettings{}, err
+	}
+
+	return ssg.ToSiteSettings(da), nil
+}
+
+// SaveSiteSettings creates the settings of the site or replaces them.
+func (repo *HermesRepo) SaveSiteSettings(ctx context.Context, settings ssg.SiteSettings) error {
+	query, err := repo.Query().Get(ssgAuth, resSettings, "Save")
+	if err != nil {
+		return err
+	}
+
+	settingsDA := ssg.ToSiteSettingsDA(settings)
+	settingsDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, settingsDA)
+	return err
+}
+
 // Content related
 
 func (repo *HermesRepo) CreateContent(ctx context.Context, content ssg.Content) error {
@@ -21,7 +157,9 @@ func (repo *HermesRepo) CreateContent(ctx context.Context, content ssg.Content)
 	}
 
 	contentDA := ssg.ToContentDA(content)
-	_, err = repo.db.NamedExecContext(ctx, query, contentDA)
+	contentDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, contentDA)
 	return err
 }
 
@@ -32,7 +170,8 @@ func (repo *HermesRepo) GetAllContent(ctx context.Context) ([]ssg.Content, error
 	}
 
 	var contentDAs []ssg.ContentDA
-	err = repo.db.SelectContext(ctx, &contentDAs, query)
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &contentDAs, query, siteID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +187,8 @@ func (repo *HermesRepo) GetContent(ctx context.Context, id string) (ssg.Content,
 	}
 
 	var contentDA ssg.ContentDA
-	err = repo.db.GetContext(ctx, &contentDA, query, id)
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &contentDA, query, id, siteID(ctx))
 	if err != nil {
 		return ssg.Content{}, err
 	}
@@ -56,6 +196,24 @@ func (repo *HermesRepo) GetContent(ctx context.Context, id string) (ssg.Content,
 	return ssg.ToContent(contentDA), nil
 }
 
+func (repo *HermesRepo) GetContentTranslations(ctx context.Context, translationID uuid.UUID) ([]ssg.Content, error) {
+	query, err := repo.Query().Get(ssgAuth, resContent, "GetTranslations")
+	if err != nil {
+		return nil, err
+	}
+
+	var contentDAs []ssg.ContentDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &contentDAs, query, translationID.String(), siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToContents(contentDAs), nil
+}
+
+// UpdateContent saves a content and bumps its version. ErrContentConflict is
+// returned if the saved version is not the one the content is based on.
 func (repo *HermesRepo) UpdateContent(ctx context.Context, content ssg.Content) error {
 	query, err := repo.Query().Get(ssgAuth, resContent, "Update")
 	if err != nil {
@@ -63,57 +221,1484 @@ func (repo *HermesRepo) UpdateContent(ctx context.Context, content ssg.Content)
 	}
 
 	contentDA := ssg.ToContentDA(content)
-	_, err = repo.db.NamedExecContext(ctx, query, contentDA)
+	contentDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	result, err := sqlx.NamedExecContext(ctx, exec, query, contentDA)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ssg.ErrContentConflict
+	}
+
+	return nil
+}
+
+// ContentLock related
+
+// AcquireContentLock takes the lock of a content if it is free, expired or
+// already held by the same user, in which case it is renewed. It returns the
+// lock as it ends up, held by someone else if they already had it.
+func (repo *HermesRepo) AcquireContentLock(ctx context.Context, lock ssg.ContentLock) (ssg.ContentLock, error) {
+	query, err := repo.Query().Get(ssgAuth, resLock, "Acquire")
+	if err != nil {
+		return ssg.ContentLock{}, err
+	}
+
+	lockDA := ssg.ToContentLockDA(lock)
+	lockDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, lockDA)
+	if err != nil {
+		return ssg.ContentLock{}, err
+	}
+
+	query, err = repo.Query().Get(ssgAuth, resLock, "Get")
+	if err != nil {
+		return ssg.ContentLock{}, err
+	}
+
+	var da ssg.ContentLockDA
+	err = sqlx.GetContext(ctx, exec, &da, query, lockDA.ContentID, lockDA.SiteID)
+	if err != nil {
+		return ssg.ContentLock{}, err
+	}
+
+	return ssg.ToContentLock(da), nil
+}
+
+func (repo *HermesRepo) GetContentLocks(ctx context.Context) ([]ssg.ContentLock, error) {
+	query, err := repo.Query().Get(ssgAuth, resLock, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.ContentLockDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToContentLocks(das), nil
+}
+
+// ReleaseContentLock drops the lock of a content if userID holds it.
+func (repo *HermesRepo) ReleaseContentLock(ctx context.Context, contentID, userID uuid.UUID) error {
+	query, err := repo.Query().Get(ssgAuth, resLock, "Release")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, contentID.String(), userID.String(), siteID(ctx))
 	return err
 }
 
-// Section related
+// ContentDraft related
 
-func (repo *HermesRepo) CreateSection(ctx context.Context, section ssg.Section) error {
-	query, err := repo.Query().Get(ssgAuth, resSection, "Create")
+// SaveContentDraft stores the draft of a user, replacing the one they had for
+// the same content.
+func (repo *HermesRepo) SaveContentDraft(ctx context.Context, draft ssg.ContentDraft) error {
+	query, err := repo.Query().Get(ssgAuth, resDraft, "Save")
 	if err != nil {
 		return err
 	}
 
-	sectionDA := ssg.ToSectionDA(section)
-	_, err = repo.db.NamedExecContext(ctx, query, sectionDA)
+	draftDA := ssg.ToContentDraftDA(draft)
+	draftDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, draftDA)
 	return err
 }
 
-func (repo *HermesRepo) GetSections(ctx context.Context) ([]ssg.Section, error) {
-	query, err := repo.Query().Get(ssgAuth, resSection, "GetAll")
+func (repo *HermesRepo) GetContentDraft(ctx context.Context, contentID, userID uuid.UUID) (ssg.ContentDraft, error) {
+	query, err := repo.Query().Get(ssgAuth, resDraft, "Get")
+	if err != nil {
+		return ssg.ContentDraft{}, err
+	}
+
+	var da ssg.ContentDraftDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, contentID.String(), userID.String(), siteID(ctx))
+	if err != nil {
+		return ssg.ContentDraft{}, err
+	}
+
+	return ssg.ToContentDraft(da), nil
+}
+
+func (repo *HermesRepo) DeleteContentDraft(ctx context.Context, contentID, userID uuid.UUID) error {
+	query, err := repo.Query().Get(ssgAuth, resDraft, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, contentID.String(), userID.String(), siteID(ctx))
+	return err
+}
+
+// ContentType related
+
+func (repo *HermesRepo) CreateContentType(ctx context.Context, ct ssg.ContentType) error {
+	query, err := repo.Query().Get(ssgAuth, resCType, "Create")
+	if err != nil {
+		return err
+	}
+
+	ctDA := ssg.ToContentTypeDA(ct)
+	ctDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, ctDA)
+	return err
+}
+
+func (repo *HermesRepo) GetContentTypes(ctx context.Context) ([]ssg.ContentType, error) {
+	query, err := repo.Query().Get(ssgAuth, resCType, "GetAll")
 	if err != nil {
 		return nil, err
 	}
-	var das []ssg.SectionDA
-	err = repo.db.SelectContext(ctx, &das, query)
+
+	var das []ssg.ContentTypeDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return ssg.ToSections(das), nil
+
+	return ssg.ToContentTypes(das), nil
 }
 
-// Layout related
+func (repo *HermesRepo) GetContentType(ctx context.Context, id string) (ssg.ContentType, error) {
+	query, err := repo.Query().Get(ssgAuth, resCType, "Get")
+	if err != nil {
+		return ssg.ContentType{}, err
+	}
 
-func (repo *HermesRepo) CreateLayout(ctx context.Context, layout ssg.Layout) error {
-	query, err := repo.Query().Get(ssgAuth, "layout", "Create")
+	var da ssg.ContentTypeDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.ContentType{}, err
+	}
+
+	return ssg.ToContentType(da), nil
+}
+
+func (repo *HermesRepo) UpdateContentType(ctx context.Context, ct ssg.ContentType) error {
+	query, err := repo.Query().Get(ssgAuth, resCType, "Update")
 	if err != nil {
 		return err
 	}
-	layoutDA := ssg.ToLayoutDA(layout)
-	_, err = repo.db.NamedExecContext(ctx, query, layoutDA)
+
+	ctDA := ssg.ToContentTypeDA(ct)
+	ctDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, ctDA)
 	return err
 }
 
-func (repo *HermesRepo) GetAllLayouts(ctx context.Context) ([]ssg.Layout, error) {
-	query, err := repo.Query().Get(ssgAuth, "layout", "GetAll")
+// DataFile related
+
+func (repo *HermesRepo) CreateDataFile(ctx context.Context, file ssg.DataFile) error {
+	query, err := repo.Query().Get(ssgAuth, resData, "Create")
+	if err != nil {
+		return err
+	}
+
+	fileDA := ssg.ToDataFileDA(file)
+	fileDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, fileDA)
+	return err
+}
+
+func (repo *HermesRepo) GetDataFiles(ctx context.Context) ([]ssg.DataFile, error) {
+	query, err := repo.Query().Get(ssgAuth, resData, "GetAll")
 	if err != nil {
 		return nil, err
 	}
-	var das []ssg.LayoutDA
-	err = repo.db.SelectContext(ctx, &das, query)
+
+	var das []ssg.DataFileDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return ssg.ToLayouts(das), nil
+
+	return ssg.ToDataFiles(das), nil
+}
+
+func (repo *HermesRepo) GetDataFile(ctx context.Context, id string) (ssg.DataFile, error) {
+	query, err := repo.Query().Get(ssgAuth, resData, "Get")
+	if err != nil {
+		return ssg.DataFile{}, err
+	}
+
+	var da ssg.DataFileDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.DataFile{}, err
+	}
+
+	return ssg.ToDataFile(da), nil
+}
+
+func (repo *HermesRepo) UpdateDataFile(ctx context.Context, file ssg.DataFile) error {
+	query, err := repo.Query().Get(ssgAuth, resData, "Update")
+	if err != nil {
+		return err
+	}
+
+	fileDA := ssg.ToDataFileDA(file)
+	fileDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, fileDA)
+	return err
+}
+
+func (repo *HermesRepo) DeleteDataFile(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resData, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// Menu related
+
+func (repo *HermesRepo) CreateMenu(ctx context.Context, menu ssg.Menu) error {
+	query, err := repo.Query().Get(ssgAuth, resMenu, "Create")
+	if err != nil {
+		return err
+	}
+
+	menuDA := ssg.ToMenuDA(menu)
+	menuDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, menuDA)
+	return err
+}
+
+func (repo *HermesRepo) GetMenus(ctx context.Context) ([]ssg.Menu, error) {
+	query, err := repo.Query().Get(ssgAuth, resMenu, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.MenuDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToMenus(das), nil
+}
+
+func (repo *HermesRepo) GetMenu(ctx context.Context, id string) (ssg.Menu, error) {
+	query, err := repo.Query().Get(ssgAuth, resMenu, "Get")
+	if err != nil {
+		return ssg.Menu{}, err
+	}
+
+	var da ssg.MenuDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Menu{}, err
+	}
+
+	return ssg.ToMenu(da), nil
+}
+
+func (repo *HermesRepo) UpdateMenu(ctx context.Context, menu ssg.Menu) error {
+	query, err := repo.Query().Get(ssgAuth, resMenu, "Update")
+	if err != nil {
+		return err
+	}
+
+	menuDA := ssg.ToMenuDA(menu)
+	menuDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, menuDA)
+	return err
+}
+
+// DeleteMenu deletes a menu along with its items.
+func (repo *HermesRepo) DeleteMenu(ctx context.Context, id string) error {
+	itemsQuery, err := repo.Query().Get(ssgAuth, resMenuItem, "DeleteByMenu")
+	if err != nil {
+		return err
+	}
+
+	query, err := repo.Query().Get(ssgAuth, resMenu, "Delete")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, itemsQuery, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, query, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *HermesRepo) CreateMenuItem(ctx context.Context, item ssg.MenuItem) error {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "Create")
+	if err != nil {
+		return err
+	}
+
+	itemDA := ssg.ToMenuItemDA(item)
+	itemDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, itemDA)
+	return err
+}
+
+// GetMenuItems returns the items of every menu of the site, in order.
+func (repo *HermesRepo) GetMenuItems(ctx context.Context) ([]ssg.MenuItem, error) {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.MenuItemDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToMenuItems(das), nil
+}
+
+func (repo *HermesRepo) GetMenuItem(ctx context.Context, id string) (ssg.MenuItem, error) {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "Get")
+	if err != nil {
+		return ssg.MenuItem{}, err
+	}
+
+	var da ssg.MenuItemDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.MenuItem{}, err
+	}
+
+	return ssg.ToMenuItem(da), nil
+}
+
+func (repo *HermesRepo) UpdateMenuItem(ctx context.Context, item ssg.MenuItem) error {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "Update")
+	if err != nil {
+		return err
+	}
+
+	itemDA := ssg.ToMenuItemDA(item)
+	itemDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, itemDA)
+	return err
+}
+
+// MoveMenuItems sets the parent and position of menu items all at once so
+// that a menu is never left half reordered.
+func (repo *HermesRepo) MoveMenuItems(ctx context.Context, moves []ssg.MenuMove) error {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "Move")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := am.Now()
+	for _, m := range moves {
+		_, err = repo.getExec(ctx).ExecContext(ctx, query, m.ParentID.String(), m.Position, now, m.ID.String(), siteID(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo *HermesRepo) DeleteMenuItem(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resMenuItem, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// Series related
+
+func (repo *HermesRepo) CreateSeries(ctx context.Context, series ssg.Series) error {
+	query, err := repo.Query().Get(ssgAuth, resSeries, "Create")
+	if err != nil {
+		return err
+	}
+
+	seriesDA := ssg.ToSeriesDA(series)
+	seriesDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, seriesDA)
+	return err
+}
+
+func (repo *HermesRepo) GetAllSeries(ctx context.Context) ([]ssg.Series, error) {
+	query, err := repo.Query().Get(ssgAuth, resSeries, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.SeriesDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToSeriesList(das), nil
+}
+
+func (repo *HermesRepo) GetSeries(ctx context.Context, id string) (ssg.Series, error) {
+	query, err := repo.Query().Get(ssgAuth, resSeries, "Get")
+	if err != nil {
+		return ssg.Series{}, err
+	}
+
+	var da ssg.SeriesDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Series{}, err
+	}
+
+	return ssg.ToSeries(da), nil
+}
+
+func (repo *HermesRepo) UpdateSeries(ctx context.Context, series ssg.Series) error {
+	query, err := repo.Query().Get(ssgAuth, resSeries, "Update")
+	if err != nil {
+		return err
+	}
+
+	seriesDA := ssg.ToSeriesDA(series)
+	seriesDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, seriesDA)
+	return err
+}
+
+// DeleteSeries deletes a series. Its contents are left in place.
+func (repo *HermesRepo) DeleteSeries(ctx context.Context, id string) error {
+	partsQuery, err := repo.Query().Get(ssgAuth, resPart, "DeleteBySeries")
+	if err != nil {
+		return err
+	}
+
+	query, err := repo.Query().Get(ssgAuth, resSeries, "Delete")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, partsQuery, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, query, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetSeriesParts returns the parts of every series of the site, in order.
+func (repo *HermesRepo) GetSeriesParts(ctx context.Context) ([]ssg.SeriesPart, error) {
+	query, err := repo.Query().Get(ssgAuth, resPart, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.SeriesPartDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToSeriesParts(das), nil
+}
+
+// SetSeriesParts replaces the contents of a series all at once so that a
+// series is never left half reordered.
+func (repo *HermesRepo) SetSeriesParts(ctx context.Context, seriesID uuid.UUID, contentIDs []uuid.UUID) error {
+	deleteQuery, err := repo.Query().Get(ssgAuth, resPart, "DeleteBySeries")
+	if err != nil {
+		return err
+	}
+
+	query, err := repo.Query().Get(ssgAuth, resPart, "Create")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, deleteQuery, seriesID.String(), siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	for i, id := range contentIDs {
+		partDA := ssg.ToSeriesPartDA(ssg.SeriesPart{SeriesID: seriesID, ContentID: id, Position: i})
+		partDA.SiteID = siteID(ctx)
+		_, err = sqlx.NamedExecContext(ctx, repo.getExec(ctx), query, partDA)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Profile related
+
+func (repo *HermesRepo) CreateProfile(ctx context.Context, profile ssg.Profile) error {
+	query, err := repo.Query().Get(ssgAuth, resProfile, "Create")
+	if err != nil {
+		return err
+	}
+
+	profileDA := ssg.ToProfileDA(profile)
+	profileDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, profileDA)
+	return err
+}
+
+func (repo *HermesRepo) GetProfiles(ctx context.Context) ([]ssg.Profile, error) {
+	query, err := repo.Query().Get(ssgAuth, resProfile, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.ProfileDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToProfiles(das), nil
+}
+
+func (repo *HermesRepo) GetProfile(ctx context.Context, id string) (ssg.Profile, error) {
+	query, err := repo.Query().Get(ssgAuth, resProfile, "Get")
+	if err != nil {
+		return ssg.Profile{}, err
+	}
+
+	var da ssg.ProfileDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Profile{}, err
+	}
+
+	return ssg.ToProfile(da), nil
+}
+
+func (repo *HermesRepo) UpdateProfile(ctx context.Context, profile ssg.Profile) error {
+	query, err := repo.Query().Get(ssgAuth, resProfile, "Update")
+	if err != nil {
+		return err
+	}
+
+	profileDA := ssg.ToProfileDA(profile)
+	profileDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, profileDA)
+	return err
+}
+
+// DeleteProfile deletes a profile along with its author credits. The
+// contents it was credited for are left in place.
+func (repo *HermesRepo) DeleteProfile(ctx context.Context, id string) error {
+	authorsQuery, err := repo.Query().Get(ssgAuth, resAuthor, "DeleteByProfile")
+	if err != nil {
+		return err
+	}
+
+	query, err := repo.Query().Get(ssgAuth, resProfile, "Delete")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, authorsQuery, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, query, id, siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetContentAuthors returns the author credits of every content of the site,
+// in byline order.
+func (repo *HermesRepo) GetContentAuthors(ctx context.Context) ([]ssg.ContentAuthor, error) {
+	query, err := repo.Query().Get(ssgAuth, resAuthor, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.ContentAuthorDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToContentAuthors(das), nil
+}
+
+// SetContentAuthors replaces the authors of a content all at once.
+func (repo *HermesRepo) SetContentAuthors(ctx context.Context, contentID uuid.UUID, profileIDs []uuid.UUID) error {
+	deleteQuery, err := repo.Query().Get(ssgAuth, resAuthor, "DeleteByContent")
+	if err != nil {
+		return err
+	}
+
+	query, err := repo.Query().Get(ssgAuth, resAuthor, "Create")
+	if err != nil {
+		return err
+	}
+
+	ctx, tx, err := repo.joinTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = repo.getExec(ctx).ExecContext(ctx, deleteQuery, contentID.String(), siteID(ctx))
+	if err != nil {
+		return err
+	}
+
+	for i, id := range profileIDs {
+		authorDA := ssg.ToContentAuthorDA(ssg.ContentAuthor{ContentID: contentID, ProfileID: id, Position: i})
+		authorDA.SiteID = siteID(ctx)
+		_, err = sqlx.NamedExecContext(ctx, repo.getExec(ctx), query, authorDA)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Section related
+
+func (repo *HermesRepo) CreateSection(ctx context.Context, section ssg.Section) error {
+	query, err := repo.Query().Get(ssgAuth, resSection, "Create")
+	if err != nil {
+		return err
+	}
+
+	sectionDA := ssg.ToSectionDA(section)
+	sectionDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, sectionDA)
+	return err
+}
+
+func (repo *HermesRepo) GetSections(ctx context.Context) ([]ssg.Section, error) {
+	query, err := repo.Query().Get(ssgAuth, resSection, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+	var das []ssg.SectionDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return ssg.ToSections(das), nil
+}
+
+// Layout related
+
+func (repo *HermesRepo) CreateLayout(ctx context.Context, layout ssg.Layout) error {
+	query, err := repo.Query().Get(ssgAuth, "layout", "Create")
+	if err != nil {
+		return err
+	}
+	layoutDA := ssg.ToLayoutDA(layout)
+	layoutDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, layoutDA)
+	return err
+}
+
+func (repo *HermesRepo) GetAllLayouts(ctx context.Context) ([]ssg.Layout, error) {
+	query, err := repo.Query().Get(ssgAuth, "layout", "GetAll")
+	if err != nil {
+		return nil, err
+	}
+	var das []ssg.LayoutDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return ssg.ToLayouts(das), nil
+}
+
+// Redirect related
+
+func (repo *HermesRepo) CreateRedirect(ctx context.Context, redirect ssg.Redirect) error {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "Create")
+	if err != nil {
+		return err
+	}
+
+	redirectDA := ssg.ToRedirectDA(redirect)
+	redirectDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, redirectDA)
+	return err
+}
+
+func (repo *HermesRepo) GetAllRedirects(ctx context.Context) ([]ssg.Redirect, error) {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.RedirectDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToRedirects(das), nil
+}
+
+func (repo *HermesRepo) GetRedirect(ctx context.Context, id string) (ssg.Redirect, error) {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "Get")
+	if err != nil {
+		return ssg.Redirect{}, err
+	}
+
+	var da ssg.RedirectDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Redirect{}, err
+	}
+
+	return ssg.ToRedirect(da), nil
+}
+
+func (repo *HermesRepo) UpdateRedirect(ctx context.Context, redirect ssg.Redirect) error {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "Update")
+	if err != nil {
+		return err
+	}
+
+	redirectDA := ssg.ToRedirectDA(redirect)
+	redirectDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, redirectDA)
+	return err
+}
+
+func (repo *HermesRepo) DeleteRedirect(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// RetargetRedirects points every redirect whose target is oldTarget to
+// newTarget so that redirects never chain.
+func (repo *HermesRepo) RetargetRedirects(ctx context.Context, oldTarget, newTarget string) error {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "Retarget")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, newTarget, am.Now(), oldTarget, siteID(ctx))
+	return err
+}
+
+func (repo *HermesRepo) DeleteRedirectBySource(ctx context.Context, sourcePath string) error {
+	query, err := repo.Query().Get(ssgAuth, resRedirect, "DeleteBySource")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, sourcePath, siteID(ctx))
+	return err
+}
+
+// Build related
+
+func (repo *HermesRepo) CreateBuild(ctx context.Context, build ssg.Build) error {
+	query, err := repo.Query().Get(ssgAuth, resBuild, "Create")
+	if err != nil {
+		return err
+	}
+
+	buildDA := ssg.ToBuildDA(build)
+	buildDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, buildDA)
+	return err
+}
+
+func (repo *HermesRepo) UpdateBuild(ctx context.Context, build ssg.Build) error {
+	query, err := repo.Query().Get(ssgAuth, resBuild, "Update")
+	if err != nil {
+		return err
+	}
+
+	buildDA := ssg.ToBuildDA(build)
+	buildDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, buildDA)
+	return err
+}
+
+func (repo *HermesRepo) GetBuilds(ctx context.Context) ([]ssg.Build, error) {
+	query, err := repo.Query().Get(ssgAuth, resBuild, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.BuildDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToBuilds(das), nil
+}
+
+func (repo *HermesRepo) GetBuild(ctx context.Context, id string) (ssg.Build, error) {
+	query, err := repo.Query().Get(ssgAuth, resBuild, "Get")
+	if err != nil {
+		return ssg.Build{}, err
+	}
+
+	var da ssg.BuildDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Build{}, err
+	}
+
+	return ssg.ToBuild(da), nil
+}
+
+// PublishTarget related
+
+func (repo *HermesRepo) CreatePublishTarget(ctx context.Context, target ssg.PublishTarget) error {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "Create")
+	if err != nil {
+		return err
+	}
+
+	targetDA := ssg.ToPublishTargetDA(target)
+	targetDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, targetDA)
+	return err
+}
+
+func (repo *HermesRepo) GetPublishTargets(ctx context.Context) ([]ssg.PublishTarget, error) {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.PublishTargetDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToPublishTargets(das), nil
+}
+
+func (repo *HermesRepo) GetPublishTarget(ctx context.Context, id string) (ssg.PublishTarget, error) {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "Get")
+	if err != nil {
+		return ssg.PublishTarget{}, err
+	}
+
+	var da ssg.PublishTargetDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.PublishTarget{}, err
+	}
+
+	return ssg.ToPublishTarget(da), nil
+}
+
+func (repo *HermesRepo) GetPublishTargetByName(ctx context.Context, name string) (ssg.PublishTarget, error) {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "GetByName")
+	if err != nil {
+		return ssg.PublishTarget{}, err
+	}
+
+	var da ssg.PublishTargetDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, name, siteID(ctx))
+	if err != nil {
+		return ssg.PublishTarget{}, err
+	}
+
+	return ssg.ToPublishTarget(da), nil
+}
+
+func (repo *HermesRepo) UpdatePublishTarget(ctx context.Context, target ssg.PublishTarget) error {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "Update")
+	if err != nil {
+		return err
+	}
+
+	targetDA := ssg.ToPublishTargetDA(target)
+	targetDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, targetDA)
+	return err
+}
+
+func (repo *HermesRepo) DeletePublishTarget(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resTarget, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// Deployment related
+
+func (repo *HermesRepo) CreateDeployment(ctx context.Context, deployment ssg.Deployment) error {
+	query, err := repo.Query().Get(ssgAuth, resDeploy, "Create")
+	if err != nil {
+		return err
+	}
+
+	deploymentDA := ssg.ToDeploymentDA(deployment)
+	deploymentDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, deploymentDA)
+	return err
+}
+
+func (repo *HermesRepo) UpdateDeployment(ctx context.Context, deployment ssg.Deployment) error {
+	query, err := repo.Query().Get(ssgAuth, resDeploy, "Update")
+	if err != nil {
+		return err
+	}
+
+	deploymentDA := ssg.ToDeploymentDA(deployment)
+	deploymentDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, deploymentDA)
+	return err
+}
+
+func (repo *HermesRepo) GetTargetDeployments(ctx context.Context, targetID string) ([]ssg.Deployment, error) {
+	query, err := repo.Query().Get(ssgAuth, resDeploy, "GetByTarget")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.DeploymentDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, targetID, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToDeployments(das), nil
+}
+
+func (repo *HermesRepo) GetBuildDeployments(ctx context.Context, buildID string) ([]ssg.Deployment, error) {
+	query, err := repo.Query().Get(ssgAuth, resDeploy, "GetByBuild")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.DeploymentDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, buildID, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToDeployments(das), nil
+}
+
+// Webhook related
+
+func (repo *HermesRepo) CreateWebhook(ctx context.Context, hook ssg.Webhook) error {
+	query, err := repo.Query().Get(ssgAuth, resWebhook, "Create")
+	if err != nil {
+		return err
+	}
+
+	hookDA := ssg.ToWebhookDA(hook)
+	hookDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, hookDA)
+	return err
+}
+
+func (repo *HermesRepo) GetWebhooks(ctx context.Context) ([]ssg.Webhook, error) {
+	query, err := repo.Query().Get(ssgAuth, resWebhook, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.WebhookDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToWebhooks(das), nil
+}
+
+func (repo *HermesRepo) GetWebhook(ctx context.Context, id string) (ssg.Webhook, error) {
+	query, err := repo.Query().Get(ssgAuth, resWebhook, "Get")
+	if err != nil {
+		return ssg.Webhook{}, err
+	}
+
+	var da ssg.WebhookDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.Webhook{}, err
+	}
+
+	return ssg.ToWebhook(da), nil
+}
+
+func (repo *HermesRepo) UpdateWebhook(ctx context.Context, hook ssg.Webhook) error {
+	query, err := repo.Query().Get(ssgAuth, resWebhook, "Update")
+	if err != nil {
+		return err
+	}
+
+	hookDA := ssg.ToWebhookDA(hook)
+	hookDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, hookDA)
+	return err
+}
+
+func (repo *HermesRepo) DeleteWebhook(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resWebhook, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// WebhookDelivery related
+
+func (repo *HermesRepo) CreateWebhookDelivery(ctx context.Context, delivery ssg.WebhookDelivery) error {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "Create")
+	if err != nil {
+		return err
+	}
+
+	deliveryDA := ssg.ToWebhookDeliveryDA(delivery)
+	deliveryDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, deliveryDA)
+	return err
+}
+
+func (repo *HermesRepo) GetWebhookDeliveries(ctx context.Context) ([]ssg.WebhookDelivery, error) {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.WebhookDeliveryDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToWebhookDeliveries(das), nil
+}
+
+func (repo *HermesRepo) GetDeliveriesByWebhook(ctx context.Context, webhookID string) ([]ssg.WebhookDelivery, error) {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "GetByWebhook")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.WebhookDeliveryDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, webhookID, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToWebhookDeliveries(das), nil
+}
+
+func (repo *HermesRepo) GetWebhookDelivery(ctx context.Context, id string) (ssg.WebhookDelivery, error) {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "Get")
+	if err != nil {
+		return ssg.WebhookDelivery{}, err
+	}
+
+	var da ssg.WebhookDeliveryDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, id, siteID(ctx))
+	if err != nil {
+		return ssg.WebhookDelivery{}, err
+	}
+
+	return ssg.ToWebhookDelivery(da), nil
+}
+
+// GetPendingWebhookDeliveries returns the deliveries of every site that are
+// due to be retried.
+func (repo *HermesRepo) GetPendingWebhookDeliveries(ctx context.Context) ([]ssg.WebhookDelivery, error) {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "GetPending")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.WebhookDeliveryDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToWebhookDeliveries(das), nil
+}
+
+func (repo *HermesRepo) ClearWebhookDeliveryRetry(ctx context.Context, id uuid.UUID) error {
+	query, err := repo.Query().Get(ssgAuth, resDelivery, "ClearRetry")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id.String())
+	return err
+}
+
+// APIToken related
+
+func (repo *HermesRepo) CreateAPIToken(ctx context.Context, token ssg.APIToken) error {
+	query, err := repo.Query().Get(ssgAuth, resAPIToken, "Create")
+	if err != nil {
+		return err
+	}
+
+	tokenDA := ssg.ToAPITokenDA(token)
+	tokenDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, tokenDA)
+	return err
+}
+
+func (repo *HermesRepo) GetAPITokens(ctx context.Context) ([]ssg.APIToken, error) {
+	query, err := repo.Query().Get(ssgAuth, resAPIToken, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.APITokenDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToAPITokens(das), nil
+}
+
+// GetAPITokenByHash looks the token up in every site, the one it acts on is
+// returned with it.
+func (repo *HermesRepo) GetAPITokenByHash(ctx context.Context, hash string) (ssg.APIToken, error) {
+	query, err := repo.Query().Get(ssgAuth, resAPIToken, "GetByHash")
+	if err != nil {
+		return ssg.APIToken{}, err
+	}
+
+	var da ssg.APITokenDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, hash)
+	if err != nil {
+		return ssg.APIToken{}, err
+	}
+
+	return ssg.ToAPIToken(da), nil
+}
+
+func (repo *HermesRepo) TouchAPIToken(ctx context.Context, token ssg.APIToken) error {
+	query, err := repo.Query().Get(ssgAuth, resAPIToken, "Touch")
+	if err != nil {
+		return err
+	}
+
+	tokenDA := ssg.ToAPITokenDA(token)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, tokenDA)
+	return err
+}
+
+func (repo *HermesRepo) DeleteAPIToken(ctx context.Context, id string) error {
+	query, err := repo.Query().Get(ssgAuth, resAPIToken, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id, siteID(ctx))
+	return err
+}
+
+// IdempotencyKey related
+
+// CreateIdempotencyKey reserves the key for its token. ErrIdempotencyKeyExists
+// is returned if it was already reserved.
+func (repo *HermesRepo) CreateIdempotencyKey(ctx context.Context, key ssg.IdempotencyKey) error {
+	query, err := repo.Query().Get(ssgAuth, resIdemKey, "Create")
+	if err != nil {
+		return err
+	}
+
+	keyDA := ssg.ToIdempotencyKeyDA(key)
+	keyDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	result, err := sqlx.NamedExecContext(ctx, exec, query, keyDA)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ssg.ErrIdempotencyKeyExists
+	}
+
+	return nil
+}
+
+func (repo *HermesRepo) GetIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) (ssg.IdempotencyKey, error) {
+	query, err := repo.Query().Get(ssgAuth, resIdemKey, "Get")
+	if err != nil {
+		return ssg.IdempotencyKey{}, err
+	}
+
+	var da ssg.IdempotencyKeyDA
+	exec := repo.getExec(ctx)
+	err = sqlx.GetContext(ctx, exec, &da, query, tokenID.String(), key, siteID(ctx))
+	if err != nil {
+		return ssg.IdempotencyKey{}, err
+	}
+
+	return ssg.ToIdempotencyKey(da), nil
+}
+
+func (repo *HermesRepo) UpdateIdempotencyKey(ctx context.Context, key ssg.IdempotencyKey) error {
+	query, err := repo.Query().Get(ssgAuth, resIdemKey, "Update")
+	if err != nil {
+		return err
+	}
+
+	keyDA := ssg.ToIdempotencyKeyDA(key)
+	keyDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, keyDA)
+	return err
+}
+
+func (repo *HermesRepo) DeleteIdempotencyKey(ctx context.Context, tokenID uuid.UUID, key string) error {
+	query, err := repo.Query().Get(ssgAuth, resIdemKey, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, tokenID.String(), key, siteID(ctx))
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys deletes the keys created before the cutoff.
+func (repo *HermesRepo) DeleteExpiredIdempotencyKeys(ctx context.Context, cutoff time.Time) error {
+	query, err := repo.Query().Get(ssgAuth, resIdemKey, "DeleteExpired")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, cutoff)
+	return err
+}
+
+// Term related
+
+func (repo *HermesRepo) CreateTerm(ctx context.Context, term ssg.Term) error {
+	query, err := repo.Query().Get(ssgAuth, resTerm, "Create")
+	if err != nil {
+		return err
+	}
+
+	termDA := ssg.ToTermDA(term)
+	termDA.SiteID = siteID(ctx)
+	exec := repo.getExec(ctx)
+	_, err = sqlx.NamedExecContext(ctx, exec, query, termDA)
+	return err
+}
+
+func (repo *HermesRepo) GetTerms(ctx context.Context) ([]ssg.Term, error) {
+	query, err := repo.Query().Get(ssgAuth, resTerm, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.TermDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToTerms(das), nil
+}
+
+func (repo *HermesRepo) GetContentTerms(ctx context.Context, contentID uuid.UUID) ([]ssg.Term, error) {
+	query, err := repo.Query().Get(ssgAuth, resTerm, "GetByContent")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.TermDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, contentID.String(), siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToTerms(das), nil
+}
+
+func (repo *HermesRepo) AddContentTerm(ctx context.Context, contentID, termID uuid.UUID) error {
+	query, err := repo.Query().Get(ssgAuth, resTerm, "AddToContent")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, contentID.String(), termID.String())
+	return err
+}
+
+// GetAllContentTerms returns the terms every content of the site is
+// classified under.
+func (repo *HermesRepo) GetAllContentTerms(ctx context.Context) ([]ssg.ContentTerm, error) {
+	query, err := repo.Query().Get(ssgAuth, resTerm, "GetAllLinks")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []ssg.ContentTermDA
+	exec := repo.getExec(ctx)
+	err = sqlx.SelectContext(ctx, exec, &das, query, siteID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ssg.ToContentTerms(das), nil
 }
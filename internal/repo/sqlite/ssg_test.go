@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianpk/hermes/internal/am"
+	"github.com/adrianpk/hermes/internal/feat/ssg"
+	"github.com/google/uuid"
+)
+
+// newTestRepo returns a repo on a new database migrated with the queries and
+// migrations of the assets dir.
+func newTestRepo(t *testing.T) *HermesRepo {
+	t.Helper()
+	ctx := context.Background()
+	assets := os.DirFS(filepath.Join("..", "..", ".."))
+
+	cfg := am.NewConfig()
+	cfg.SetValues(map[string]string{
+		am.Key.DBSQLiteDSN: "file:" + filepath.Join(t.TempDir(), "hermes.db") + "?mode=rwc",
+	})
+	log := am.WithLog(am.NewLogger("error"))
+
+	migrator := am.NewMigrator(assets, am.EngSQLite, am.WithCfg(cfg), log)
+	if err := migrator.Setup(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	qm := am.NewQueryManager(assets, am.EngSQLite, am.WithCfg(cfg), log)
+	qm.Load()
+
+	repo := NewHermesRepo(qm, am.WithCfg(cfg), log)
+	if err := repo.Setup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Stop(ctx) })
+	return repo
+}
+
+func TestUpdateContentVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	content := ssg.NewContent("Hello", "Body")
+	content.GenCreateValues()
+	if err := repo.CreateContent(ctx, content); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := repo.GetContent(ctx, content.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale := saved
+	saved.Heading = "Hello again"
+	if err := repo.UpdateContent(ctx, saved); err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+
+	stale.Heading = "Lost update"
+	if err := repo.UpdateContent(ctx, stale); !errors.Is(err, ssg.ErrContentConflict) {
+		t.Errorf("UpdateContent() of a stale version error = %v, want ErrContentConflict", err)
+	}
+
+	got, err := repo.GetContent(ctx, content.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Heading != "Hello again" || got.Version != saved.Version+1 {
+		t.Errorf("saved content = %q version %d, want %q version %d", got.Heading, got.Version, "Hello again", saved.Version+1)
+	}
+}
+
+func TestContentIsScopedToSite(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	other := ssg.NewSite("Other", "")
+	other.GenCreateValues()
+	otherCtx := ssg.WithSite(ctx, other)
+
+	content := ssg.NewContent("Hello", "Body")
+	content.GenCreateValues()
+	if err := repo.CreateContent(ctx, content); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := repo.GetAllContent(otherCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contents) != 0 {
+		t.Errorf("GetAllContent() of another site = %d contents, want none", len(contents))
+	}
+
+	if _, err := repo.GetContent(otherCtx, content.ID().String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetContent() from another site error = %v, want sql.ErrNoRows", err)
+	}
+
+	saved, err := repo.GetContent(ctx, content.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved.Heading = "Taken over"
+	if err := repo.UpdateContent(otherCtx, saved); !errors.Is(err, ssg.ErrContentConflict) {
+		t.Errorf("UpdateContent() from another site error = %v, want ErrContentConflict", err)
+	}
+}
+
+func TestWebhooksAndTokensAreScopedToSite(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	other := ssg.NewSite("Other", "")
+	other.GenCreateValues()
+	otherCtx := ssg.WithSite(ctx, other)
+
+	hook := ssg.NewWebhook("Deploy", "https://example.com/hook", ssg.EventBuildSucceeded)
+	hook.GenCreateValues()
+	if err := repo.CreateWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := repo.GetWebhooks(otherCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 0 {
+		t.Errorf("GetWebhooks() of another site = %d webhooks, want none", len(hooks))
+	}
+	if _, err := repo.GetWebhook(otherCtx, hook.ID().String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetWebhook() from another site error = %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.DeleteWebhook(otherCtx, hook.ID().String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetWebhook(ctx, hook.ID().String()); err != nil {
+		t.Errorf("GetWebhook() after a delete from another site error = %v", err)
+	}
+
+	token := ssg.NewAPIToken("CI", uuid.New(), ssg.ScopeBuildsRead)
+	token.GenCreateValues()
+	token.TokenHash = "hash"
+	if err := repo.CreateAPIToken(otherCtx, token); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := repo.GetAPITokens(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 0 {
+		t.Errorf("GetAPITokens() of another site = %d tokens, want none", len(tokens))
+	}
+
+	got, err := repo.GetAPITokenByHash(ctx, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SiteID != other.ID() {
+		t.Errorf("GetAPITokenByHash() site = %s, want %s", got.SiteID, other.ID())
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"embed"
+	"flag"
+	"os"
 
 	"github.com/adrianpk/hermes/internal/am"
 	"github.com/adrianpk/hermes/internal/core"
@@ -52,11 +54,16 @@ func main() {
 	app.MountWeb("/auth", authWebRouter)
 
 	// SSG feature
-	ssgService := ssg.NewService(repo)
-	ssgWebHandler := ssg.NewWebHandler(templateManager, fm, ssgService)
+	ssgGenerator := ssg.NewGenerator(assetsFS, repo)
+	ssgDispatcher := ssg.NewDispatcher(repo)
+	ssgService := ssg.NewService(repo, ssgGenerator, ssgDispatcher)
+	ssgWebHandler := ssg.NewWebHandler(templateManager, fm, ssgService, authService)
 	ssgWebRouter := ssg.NewWebRouter(ssgWebHandler, append(fm.Middlewares(), am.LogHeadersMw))
+	ssgAPIHandler := ssg.NewAPIHandler(ssgService, authService)
+	ssgAPIRouter := ssg.NewAPIRouter(ssgAPIHandler)
 	ssgSeeder := ssg.NewSeeder(assetsFS, engine, repo)
 	app.MountWeb("/ssg", ssgWebRouter)
+	app.MountAPI(version, "/ssg", ssgAPIRouter)
 
 	// Add deps
 	app.Add(migrator)
@@ -70,9 +77,13 @@ func main() {
 	app.Add(authWebHandler)
 	app.Add(authWebRouter)
 	app.Add(authSeeder)
+	app.Add(ssgGenerator)
+	app.Add(ssgDispatcher)
 	app.Add(ssgService)
 	app.Add(ssgWebHandler)
 	app.Add(ssgWebRouter)
+	app.Add(ssgAPIHandler)
+	app.Add(ssgAPIRouter)
 	app.Add(ssgSeeder)
 
 	err := app.Setup(ctx)
@@ -84,6 +95,18 @@ func main() {
 	// templateManager.Debug()
 	// queryManager.Debug()
 
+	// Arguments left after the flags name a command to run instead of the server.
+	if args := flag.Args(); len(args) > 0 {
+		err = ssg.NewCLI(ssgService, os.Stdout, cfg.StrValOrDef(am.Key.SSGSite, ssg.DefaultSiteSlug)).Run(ctx, args)
+		// Let the webhooks of the events the command raised be delivered.
+		_ = ssgDispatcher.Stop(ctx)
+		if err != nil {
+			log.Error("Command failed: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	err = app.Start(ctx)
 	if err != nil {
 		log.Error("Failed to start the app: ", err)